@@ -85,7 +85,7 @@ var _ = BeforeSuite(func() {
 		UserAgent: "pivnet-resource/integration-test",
 	}
 
-	pivnetClient = gp.NewClient(clientConfig, ls)
+	pivnetClient = gp.NewClient(clientConfig, nil, ls)
 })
 
 var _ = AfterSuite(func() {
@@ -372,8 +372,8 @@ var _ = Describe("Lifecycle test", func() {
 				files, err := ioutil.ReadDir(destDirectory)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				// one file is version; two files are metadata
-				expectedFileCount := totalFiles + 3
+				// one file is version; one is pivnet.env; two files are metadata
+				expectedFileCount := totalFiles + 4
 				Expect(err).ShouldNot(HaveOccurred())
 				Expect(files).To(HaveLen(expectedFileCount))
 
@@ -413,14 +413,15 @@ var _ = Describe("Lifecycle test", func() {
 				files, err = ioutil.ReadDir(destDirectory)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				// one file is version; two files are metadata
-				expectedFileCount = 3
+				// one file is version; one is pivnet.env; two files are metadata
+				expectedFileCount = 4
 				Expect(err).ShouldNot(HaveOccurred())
 				Expect(files).To(HaveLen(expectedFileCount))
 
 				Expect(files[0].Name()).To(Equal("metadata.json"))
 				Expect(files[1].Name()).To(Equal("metadata.yaml"))
-				Expect(files[2].Name()).To(Equal("version"))
+				Expect(files[2].Name()).To(Equal("pivnet.env"))
+				Expect(files[3].Name()).To(Equal("version"))
 
 				By("Expecting error with in command and mismatched globs")
 				inRequest = concourse.InRequest{
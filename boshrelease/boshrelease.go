@@ -0,0 +1,87 @@
+package boshrelease
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ManifestFileName is the name of the manifest at the root of a BOSH
+// release tarball.
+const ManifestFileName = "release.MF"
+
+// Manifest holds the identifying fields of a BOSH release manifest.
+type Manifest struct {
+	Name               string `yaml:"name"`
+	Version            string `yaml:"version"`
+	CommitHash         string `yaml:"commit_hash"`
+	UncommittedChanges bool   `yaml:"uncommitted_changes"`
+}
+
+type Reader struct {
+	dir string
+}
+
+// NewReader returns a Reader for BOSH release tarballs downloaded to dir.
+func NewReader(dir string) *Reader {
+	return &Reader{
+		dir: dir,
+	}
+}
+
+// Read returns the manifest of the BOSH release tarball with the provided
+// name in the reader's directory. A nil manifest is returned if the file is
+// a gzipped tarball which does not contain a release manifest.
+func (r Reader) Read(fileName string) (*Manifest, error) {
+	f, err := os.Open(filepath.Join(r.dir, fileName))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' as gzip: %s", fileName, err.Error())
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read '%s' as tar: %s", fileName, err.Error())
+		}
+
+		if path.Clean(header.Name) != ManifestFileName {
+			continue
+		}
+
+		contents, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, err
+		}
+
+		var manifest Manifest
+		err = yaml.Unmarshal(contents, &manifest)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse %s in '%s': %s",
+				ManifestFileName,
+				fileName,
+				err.Error(),
+			)
+		}
+
+		return &manifest, nil
+	}
+}
@@ -0,0 +1,13 @@
+package boshrelease_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestBOSHRelease(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "BOSH Release Suite")
+}
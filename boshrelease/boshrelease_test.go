@@ -0,0 +1,116 @@
+package boshrelease_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/boshrelease"
+)
+
+func writeTarball(path string, entries map[string]string) {
+	f, err := os.Create(path)
+	Expect(err).NotTo(HaveOccurred())
+	defer f.Close()
+
+	gzipWriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, contents := range entries {
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: int64(len(contents)),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = tarWriter.Write([]byte(contents))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Expect(tarWriter.Close()).To(Succeed())
+	Expect(gzipWriter.Close()).To(Succeed())
+}
+
+var _ = Describe("Reader", func() {
+	var (
+		dir    string
+		reader *boshrelease.Reader
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "boshrelease")
+		Expect(err).NotTo(HaveOccurred())
+
+		reader = boshrelease.NewReader(dir)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("reads the release manifest", func() {
+		writeTarball(filepath.Join(dir, "some-release.tgz"), map[string]string{
+			"./jobs/some-job.tgz": "some job",
+			"./release.MF": `---
+name: some-release
+version: 1.2.3
+commit_hash: abc123
+uncommitted_changes: false
+`,
+		})
+
+		manifest, err := reader.Read("some-release.tgz")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest).To(Equal(&boshrelease.Manifest{
+			Name:       "some-release",
+			Version:    "1.2.3",
+			CommitHash: "abc123",
+		}))
+	})
+
+	Context("when the tarball has no release manifest", func() {
+		BeforeEach(func() {
+			writeTarball(filepath.Join(dir, "not-a-release.tgz"), map[string]string{
+				"some-file": "some contents",
+			})
+		})
+
+		It("returns a nil manifest", func() {
+			manifest, err := reader.Read("not-a-release.tgz")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifest).To(BeNil())
+		})
+	})
+
+	Context("when the file is not gzipped", func() {
+		BeforeEach(func() {
+			err := ioutil.WriteFile(filepath.Join(dir, "some-file.tgz"), []byte("not gzip"), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error", func() {
+			_, err := reader.Read("some-file.tgz")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the release manifest cannot be parsed", func() {
+		BeforeEach(func() {
+			writeTarball(filepath.Join(dir, "some-release.tgz"), map[string]string{
+				"release.MF": "name: [",
+			})
+		})
+
+		It("returns an error", func() {
+			_, err := reader.Read("some-release.tgz")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("release.MF"))
+		})
+	})
+})
@@ -0,0 +1,283 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+)
+
+// A bundle is a gzipped tarball holding everything needed to republish or
+// import a release without access to Pivotal Network:
+//
+//	metadata.yaml, metadata.json, version  - as written by in
+//	EULA.txt                               - the text of the release EULA
+//	files/<name>                           - the downloaded product files
+//	SHA256SUMS                             - checksums of all of the above
+const (
+	FileName          = "bundle.tgz"
+	ChecksumsFileName = "SHA256SUMS"
+	EULAFileName      = "EULA.txt"
+	MetadataFileName  = "metadata.yaml"
+	FilesDir          = "files"
+)
+
+var metadataFileNames = []string{"metadata.yaml", "metadata.json", "version", EULAFileName}
+
+type Writer struct {
+	dir string
+}
+
+// NewWriter returns a Writer which bundles the contents of dir, as written
+// by in, into dir/bundle.tgz.
+func NewWriter(dir string) *Writer {
+	return &Writer{
+		dir: dir,
+	}
+}
+
+// Write creates the bundle from the metadata files and the provided
+// product files, all of which must be in the writer's directory.
+func (w Writer) Write(productFileNames []string) error {
+	sort.Strings(productFileNames)
+
+	bundleFile, err := os.Create(filepath.Join(w.dir, FileName))
+	if err != nil {
+		return err
+	}
+	defer bundleFile.Close()
+
+	gzipWriter := gzip.NewWriter(bundleFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	var checksums []string
+
+	addFile := func(name string, entryName string) error {
+		sum, err := addToTar(tarWriter, filepath.Join(w.dir, name), entryName)
+		if err != nil {
+			return err
+		}
+		checksums = append(checksums, fmt.Sprintf("%s  %s", sum, entryName))
+		return nil
+	}
+
+	for _, name := range metadataFileNames {
+		if _, err := os.Stat(filepath.Join(w.dir, name)); os.IsNotExist(err) {
+			continue
+		}
+
+		err = addFile(name, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range productFileNames {
+		err = addFile(name, path.Join(FilesDir, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	checksumContents := []byte(strings.Join(checksums, "\n") + "\n")
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name: ChecksumsFileName,
+		Mode: 0644,
+		Size: int64(len(checksumContents)),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tarWriter.Write(checksumContents)
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+func addToTar(tarWriter *tar.Writer, filePath string, entryName string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name: entryName,
+		Mode: 0644,
+		Size: info.Size(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tarWriter, hash), f)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Extract extracts the bundle into destDir and verifies the checksum of
+// every file listed in its SHA256SUMS.
+func Extract(bundlePath string, destDir string) error {
+	bundleFile, err := os.Open(bundlePath)
+	if err != nil {
+		return err
+	}
+	defer bundleFile.Close()
+
+	gzipReader, err := gzip.NewReader(bundleFile)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle '%s': %s", bundlePath, err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read bundle '%s': %s", bundlePath, err)
+		}
+
+		target := filepath.Join(destDir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(destDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in bundle: '%s'", header.Name)
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), os.ModePerm)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(f, tarReader)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return verify(destDir)
+}
+
+func verify(dir string) error {
+	checksumFile, err := os.Open(filepath.Join(dir, ChecksumsFileName))
+	if err != nil {
+		return fmt.Errorf("bundle is missing %s: %s", ChecksumsFileName, err)
+	}
+	defer checksumFile.Close()
+
+	scanner := bufio.NewScanner(checksumFile)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, "  ", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid line in %s: '%s'", ChecksumsFileName, line)
+		}
+		expected, name := parts[0], parts[1]
+
+		actual, err := sumFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return err
+		}
+
+		if actual != expected {
+			return fmt.Errorf(
+				"SHA256 comparison failed for bundled file: '%s'. Expected: '%s' - actual: '%s'",
+				name,
+				expected,
+				actual,
+			)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func sumFile(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ForRepublish converts metadata written by in, as found in an extracted
+// bundle, into metadata suitable for out. Product files are pointed at the
+// bundled files (relative to bundleDir within the sources directory) and keep
+// their original names. IDs which only make sense on the exporting side are
+// dropped.
+func ForRepublish(m metadata.Metadata, bundleDir string) metadata.Metadata {
+	if m.Release != nil {
+		release := *m.Release
+		release.ID = 0
+		release.ProductFiles = nil
+		m.Release = &release
+	}
+
+	var productFiles []metadata.ProductFile
+	for _, pf := range m.ProductFiles {
+		localFile := pf.LocalFile
+		if localFile == "" {
+			localFile = path.Base(pf.AWSObjectKey)
+		}
+
+		pf.UploadAs = pf.File
+		pf.File = filepath.Join(bundleDir, FilesDir, localFile)
+		pf.ID = 0
+		pf.AWSObjectKey = ""
+		pf.LocalFile = ""
+
+		productFiles = append(productFiles, pf)
+	}
+	m.ProductFiles = productFiles
+
+	// File groups reference product file IDs on the exporting side.
+	m.FileGroups = nil
+
+	return m
+}
@@ -0,0 +1,137 @@
+package bundle_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/bundle"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+)
+
+var _ = Describe("Bundle", func() {
+	var (
+		sourceDir string
+		destDir   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		sourceDir, err = ioutil.TempDir("", "bundle-source")
+		Expect(err).NotTo(HaveOccurred())
+
+		destDir, err = ioutil.TempDir("", "bundle-dest")
+		Expect(err).NotTo(HaveOccurred())
+
+		for name, contents := range map[string]string{
+			"metadata.yaml": "some metadata",
+			"version":       "1.2.3#some-fingerprint",
+			"EULA.txt":      "some eula text",
+			"some-file.zip": "some contents",
+		} {
+			err = ioutil.WriteFile(filepath.Join(sourceDir, name), []byte(contents), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(sourceDir)).To(Succeed())
+		Expect(os.RemoveAll(destDir)).To(Succeed())
+	})
+
+	It("round-trips the metadata and product files", func() {
+		err := bundle.NewWriter(sourceDir).Write([]string{"some-file.zip"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = bundle.Extract(filepath.Join(sourceDir, bundle.FileName), destDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		b, err := ioutil.ReadFile(filepath.Join(destDir, "metadata.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("some metadata"))
+
+		b, err = ioutil.ReadFile(filepath.Join(destDir, "EULA.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("some eula text"))
+
+		b, err = ioutil.ReadFile(filepath.Join(destDir, bundle.FilesDir, "some-file.zip"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("some contents"))
+
+		Expect(filepath.Join(destDir, bundle.ChecksumsFileName)).To(BeAnExistingFile())
+		Expect(filepath.Join(destDir, "metadata.json")).NotTo(BeAnExistingFile())
+	})
+
+	Context("when a product file does not exist", func() {
+		It("returns an error", func() {
+			err := bundle.NewWriter(sourceDir).Write([]string{"some-missing-file.zip"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the bundle does not exist", func() {
+		It("returns an error", func() {
+			err := bundle.Extract(filepath.Join(sourceDir, "missing.tgz"), destDir)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the bundle is not a gzipped tarball", func() {
+		It("returns an error", func() {
+			bundlePath := filepath.Join(sourceDir, "some-file.zip")
+
+			err := bundle.Extract(bundlePath, destDir)
+			Expect(err).To(MatchError(ContainSubstring("failed to read bundle")))
+		})
+	})
+
+	Describe("ForRepublish", func() {
+		It("points product files at the bundled files and drops exported IDs", func() {
+			m := metadata.Metadata{
+				Release: &metadata.Release{
+					ID:           1234,
+					Version:      "1.2.3",
+					ProductFiles: []metadata.ReleaseProductFile{{ID: 10}},
+				},
+				ProductFiles: []metadata.ProductFile{
+					{
+						ID:           10,
+						File:         "Some File",
+						AWSObjectKey: "product/some-file.zip",
+						SHA256:       "some-sha256",
+					},
+					{
+						ID:           20,
+						File:         "Release Notes",
+						AWSObjectKey: "product/b/release-notes.pdf",
+						LocalFile:    "release-notes-20.pdf",
+					},
+				},
+				FileGroups: []metadata.FileGroup{{ID: 30}},
+			}
+
+			republished := bundle.ForRepublish(m, "bundle-dir")
+
+			Expect(republished.Release.ID).To(Equal(0))
+			Expect(republished.Release.Version).To(Equal("1.2.3"))
+			Expect(republished.Release.ProductFiles).To(BeNil())
+			Expect(republished.FileGroups).To(BeNil())
+
+			Expect(republished.ProductFiles).To(Equal([]metadata.ProductFile{
+				{
+					File:     filepath.Join("bundle-dir", "files", "some-file.zip"),
+					UploadAs: "Some File",
+					SHA256:   "some-sha256",
+				},
+				{
+					File:     filepath.Join("bundle-dir", "files", "release-notes-20.pdf"),
+					UploadAs: "Release Notes",
+				},
+			}))
+
+			Expect(m.Release.ID).To(Equal(1234))
+		})
+	})
+})
@@ -0,0 +1,49 @@
+package capabilities
+
+import (
+	"net/http"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+// Features of the Pivotal Network API which are not available to every
+// product or token, e.g. partner-tier tokens cannot see file groups.
+const (
+	FileGroups            = "file_groups"
+	ReleaseDependencies   = "release_dependencies"
+	DependencySpecifiers  = "dependency_specifiers"
+	ReleaseUpgradePaths   = "release_upgrade_paths"
+	UpgradePathSpecifiers = "upgrade_path_specifiers"
+)
+
+// unavailableResponseCodes are the response codes with which Pivotal Network
+// refuses a request for each feature when it is not available: file groups
+// are forbidden to partner-tier tokens, and the dependency and upgrade path
+// endpoints are not found for products which are not enrolled in them.
+var unavailableResponseCodes = map[string]int{
+	FileGroups:            http.StatusForbidden,
+	ReleaseDependencies:   http.StatusNotFound,
+	DependencySpecifiers:  http.StatusNotFound,
+	ReleaseUpgradePaths:   http.StatusNotFound,
+	UpgradePathSpecifiers: http.StatusNotFound,
+}
+
+// IsUnsupported returns whether err is the response of Pivotal Network to a
+// request for the feature when it is not available to the product or token,
+// rather than a failure of the request. Any other response, e.g. a 403 for
+// release dependencies or a 404 for file groups, is a failure.
+func IsUnsupported(feature string, err error) bool {
+	code, ok := unavailableResponseCodes[feature]
+	if !ok {
+		return false
+	}
+
+	switch e := err.(type) {
+	case pivnet.ErrNotFound:
+		return code == http.StatusNotFound
+	case pivnet.ErrPivnetOther:
+		return code == http.StatusForbidden && e.ResponseCode == http.StatusForbidden
+	}
+
+	return false
+}
@@ -0,0 +1,58 @@
+package capabilities_test
+
+import (
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/capabilities"
+)
+
+var _ = Describe("IsUnsupported", func() {
+	var (
+		forbidden error
+		notFound  error
+	)
+
+	BeforeEach(func() {
+		forbidden = pivnet.ErrPivnetOther{ResponseCode: http.StatusForbidden}
+		notFound = pivnet.ErrNotFound{ErrPivnetOther: pivnet.ErrPivnetOther{ResponseCode: http.StatusNotFound}}
+	})
+
+	It("is true for forbidden file groups", func() {
+		Expect(capabilities.IsUnsupported(capabilities.FileGroups, forbidden)).To(BeTrue())
+		Expect(capabilities.IsUnsupported(capabilities.FileGroups, notFound)).To(BeFalse())
+	})
+
+	It("is true for not found dependencies and upgrade paths", func() {
+		for _, feature := range []string{
+			capabilities.ReleaseDependencies,
+			capabilities.DependencySpecifiers,
+			capabilities.ReleaseUpgradePaths,
+			capabilities.UpgradePathSpecifiers,
+		} {
+			Expect(capabilities.IsUnsupported(feature, notFound)).To(BeTrue())
+			Expect(capabilities.IsUnsupported(feature, forbidden)).To(BeFalse())
+		}
+	})
+
+	It("is false for features which are not known", func() {
+		Expect(capabilities.IsUnsupported("image_references", forbidden)).To(BeFalse())
+		Expect(capabilities.IsUnsupported("image_references", notFound)).To(BeFalse())
+	})
+
+	It("is false for other Pivotal Network errors", func() {
+		err := pivnet.ErrPivnetOther{ResponseCode: http.StatusInternalServerError}
+		Expect(capabilities.IsUnsupported(capabilities.FileGroups, err)).To(BeFalse())
+
+		unauthorized := pivnet.ErrUnauthorized{ErrPivnetOther: pivnet.ErrPivnetOther{ResponseCode: http.StatusUnauthorized}}
+		Expect(capabilities.IsUnsupported(capabilities.FileGroups, unauthorized)).To(BeFalse())
+	})
+
+	It("is false for other errors", func() {
+		Expect(capabilities.IsUnsupported(capabilities.FileGroups, errors.New("some error"))).To(BeFalse())
+		Expect(capabilities.IsUnsupported(capabilities.FileGroups, nil)).To(BeFalse())
+	})
+})
@@ -1,14 +1,16 @@
 package check
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"text/tabwriter"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
 	"github.com/pivotal-cf/pivnet-resource/versions"
 )
 
@@ -16,25 +18,33 @@ import (
 type filter interface {
 	ReleasesByReleaseType(releases []pivnet.Release, releaseType pivnet.ReleaseType) ([]pivnet.Release, error)
 	ReleasesByVersion(releases []pivnet.Release, version string) ([]pivnet.Release, error)
+	ReleasesByVersionConstraint(releases []pivnet.Release, constraint string) ([]pivnet.Release, error)
 }
 
 //go:generate counterfeiter --fake-name FakeSorter . sorter
 type sorter interface {
 	SortBySemver([]pivnet.Release) ([]pivnet.Release, error)
+	SortByReleaseDate([]pivnet.Release) ([]pivnet.Release, error)
+	SampleBySemver([]pivnet.Release, concourse.Sample) ([]pivnet.Release, error)
 }
 
 //go:generate counterfeiter --fake-name FakePivnetClient . pivnetClient
 type pivnetClient interface {
 	ReleaseTypes() ([]pivnet.ReleaseType, error)
 	ReleasesForProductSlug(string) ([]pivnet.Release, error)
+	RecentReleasesForProductSlug(productSlug string, limit int) ([]pivnet.Release, error)
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
 }
 
+// fastPathReleaseLimit is the number of the most recent releases listed by
+// the webhook fast path, which fit on a single page of the listing.
+const fastPathReleaseLimit = 100
+
 type CheckCommand struct {
 	logger        logger.Logger
 	binaryVersion string
-	filter        filter
 	pivnetClient  pivnetClient
-	semverSorter  sorter
+	matcher       *resolution.Matcher
 	logFilePath   string
 }
 
@@ -49,9 +59,8 @@ func NewCheckCommand(
 	return &CheckCommand{
 		logger:        logger,
 		binaryVersion: binaryVersion,
-		filter:        filter,
 		pivnetClient:  pivnetClient,
-		semverSorter:  semverSorter,
+		matcher:       resolution.NewMatcher(logger, filter, pivnetClient, semverSorter),
 		logFilePath:   logFilePath,
 	}
 }
@@ -64,55 +73,90 @@ func (c *CheckCommand) Run(input concourse.CheckRequest) (concourse.CheckRespons
 		return nil, err
 	}
 
-	releaseType := input.Source.ReleaseType
+	if input.Source.PinnedVersion != "" {
+		return c.pinned(input.Source)
+	}
 
-	err = c.validateReleaseType(releaseType)
-	if err != nil {
-		return nil, err
+	if input.Source.WebhookFastPath && input.Version.ProductVersion != "" && !input.Source.OnePerReleaseType {
+		out, ok, err := c.fastPath(input)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return out, nil
+		}
+
+		c.logger.Info(fmt.Sprintf(
+			"Version '%s' is not among the %d most recent releases - checking all releases",
+			input.Version.ProductVersion,
+			fastPathReleaseLimit,
+		))
 	}
 
-	productSlug := input.Source.ProductSlug
+	if input.Source.FastCheck {
+		c.logger.Info("Fast check enabled - listing releases only")
+	}
 
-	c.logger.Info("Getting all releases")
-	releases, err := c.pivnetClient.ReleasesForProductSlug(productSlug)
+	releases, found, err := c.matcher.Matching(input.Source)
 	if err != nil {
 		return nil, err
 	}
 
-	if releaseType != "" {
-		c.logger.Info(fmt.Sprintf("Filtering all releases by release type: '%s'", releaseType))
-		releases, err = c.filter.ReleasesByReleaseType(
-			releases,
-			pivnet.ReleaseType(releaseType),
-		)
-		if err != nil {
-			return nil, err
-		}
+	// A new product can be added to a pipeline before its first release, which
+	// then triggers the pipeline like any other new version.
+	if !found {
+		c.logger.Info(fmt.Sprintf("Product '%s' has no releases yet - no versions to emit", input.Source.ProductSlug))
+		return concourse.CheckResponse{}, nil
 	}
 
-	version := input.Source.ProductVersion
-	if version != "" {
-		c.logger.Info(fmt.Sprintf("Filtering all releases by product version: '%s'", version))
-		releases, err = c.filter.ReleasesByVersion(releases, version)
-		if err != nil {
-			return nil, err
-		}
+	if input.Source.OnePerReleaseType {
+		return c.latestPerReleaseType(releases, input.Source)
 	}
 
-	if input.Source.SortBy == concourse.SortBySemver {
-		c.logger.Info("Sorting all releases by semver")
-		releases, err = c.semverSorter.SortBySemver(releases)
-		if err != nil {
-			return nil, err
+	return c.newVersions(input, releases)
+}
+
+// fastPath returns the versions since the provided version, e.g. the version
+// of a release announced to a webhook, from only the most recent releases,
+// which are listed with a single request rather than listing every release.
+// It returns false if the provided version is not among them, in which case
+// all releases must be checked instead.
+func (c *CheckCommand) fastPath(input concourse.CheckRequest) (concourse.CheckResponse, bool, error) {
+	c.logger.Info(fmt.Sprintf("Checking most recent releases for version: '%s'", input.Version.ProductVersion))
+
+	releases, err := c.matcher.Recent(input.Source, fastPathReleaseLimit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	version, _ := resolution.SplitVersion(input.Version.ProductVersion)
+
+	var found bool
+	for _, r := range releases {
+		if r.Version == version {
+			found = true
+			break
 		}
 	}
 
-	vs, err := releaseVersions(releases)
+	if !found {
+		return nil, false, nil
+	}
+
+	out, err := c.newVersions(input, releases)
 	if err != nil {
-		// Untested because versions.CombineVersionAndFingerprint cannot be forced to return an error.
-		return concourse.CheckResponse{}, err
+		return nil, false, err
 	}
 
+	return out, true, nil
+}
+
+// newVersions returns the versions of the releases since the version of the
+// request, oldest first, or the latest version if there is none.
+func (c *CheckCommand) newVersions(input concourse.CheckRequest, releases []pivnet.Release) (concourse.CheckResponse, error) {
+	vs := releaseVersions(releases)
+
 	if len(vs) == 0 {
 		return concourse.CheckResponse{}, fmt.Errorf("cannot find specified release")
 	}
@@ -133,20 +177,220 @@ func (c *CheckCommand) Run(input concourse.CheckRequest) (concourse.CheckRespons
 
 	c.logger.Info(fmt.Sprintf("New versions: %v", reversedVersions))
 
+	releasesByVersion := map[string]pivnet.Release{}
+	for i, v := range vs {
+		releasesByVersion[v] = releases[i]
+	}
+
 	var out concourse.CheckResponse
+	var emitted []pivnet.Release
 	for _, v := range reversedVersions {
-		out = append(out, concourse.Version{ProductVersion: v})
+		out = append(out, concourse.ReleaseVersion(v, releasesByVersion[v], input.Source))
+		emitted = append(emitted, releasesByVersion[v])
 	}
 
 	if len(out) == 0 {
-		out = append(out, concourse.Version{ProductVersion: vs[0]})
+		out = append(out, concourse.ReleaseVersion(vs[0], releases[0], input.Source))
+		emitted = append(emitted, releases[0])
 	}
 
+	err = c.addProductFilesFingerprints(input.Source, out, emitted)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logEmitted(out, emitted)
+
 	c.logger.Info("Finishing check and returning ouput")
 
 	return out, nil
 }
 
+// MigrateVersions maps each of the provided versions, with or without a
+// fingerprint, to the current fingerprinted version of the same release.
+// Versions for which no release can be found are omitted.
+func (c *CheckCommand) MigrateVersions(input concourse.MigrateVersionsRequest) (concourse.MigrateVersionsResponse, error) {
+	c.logger.Info("Received input, starting version migration")
+
+	productSlug := input.Source.ProductSlug
+
+	c.logger.Info("Getting all releases")
+	releases, err := c.pivnetClient.ReleasesForProductSlug(productSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, previousSlug := range input.Source.PreviousSlugs {
+		previousReleases, err := c.pivnetClient.ReleasesForProductSlug(previousSlug)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, previousReleases...)
+	}
+
+	releasesByVersion := map[string]pivnet.Release{}
+	for _, r := range releases {
+		if _, ok := releasesByVersion[r.Version]; !ok {
+			releasesByVersion[r.Version] = r
+		}
+	}
+
+	out := concourse.MigrateVersionsResponse{}
+	for _, v := range input.Versions {
+		bareVersion, _ := resolution.SplitVersion(v.ProductVersion)
+
+		r, ok := releasesByVersion[bareVersion]
+		if !ok {
+			c.logger.Info(fmt.Sprintf("No release found for version: '%s' - skipping", v.ProductVersion))
+			continue
+		}
+
+		to := concourse.ReleaseVersion(resolution.Version(r), r, input.Source)
+		if to.ReleaseType == "" {
+			to.ReleaseType = v.ReleaseType
+		}
+
+		out = append(out, concourse.VersionMigration{
+			From: v,
+			To:   to,
+		})
+	}
+
+	c.logger.Info(fmt.Sprintf("Migrated versions: %v", out))
+
+	return out, nil
+}
+
+// pinned returns the pinned version as the only version. It has no
+// fingerprint, so the version stays the same if the release is re-published,
+// and get downloads the release as it is at the time.
+func (c *CheckCommand) pinned(source concourse.Source) (concourse.CheckResponse, error) {
+	r, err := c.matcher.Pinned(source)
+	if err != nil {
+		return nil, err
+	}
+
+	out := concourse.CheckResponse{
+		concourse.ReleaseVersion(r.Version, r, source),
+	}
+
+	err = c.addProductFilesFingerprints(source, out, []pivnet.Release{r})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logEmitted(out, []pivnet.Release{r})
+
+	c.logger.Info("Finishing check and returning ouput")
+
+	return out, nil
+}
+
+// latestPerReleaseType returns the newest release of each release type as a
+// separate version, ordered so that the newest of them all is last. The
+// release type is always included as it distinguishes the versions.
+func (c *CheckCommand) latestPerReleaseType(releases []pivnet.Release, source concourse.Source) (concourse.CheckResponse, error) {
+	c.logger.Info("Gathering latest version per release type")
+
+	seen := map[pivnet.ReleaseType]bool{}
+	var latest []pivnet.Release
+	for _, r := range releases {
+		if seen[r.ReleaseType] {
+			continue
+		}
+
+		seen[r.ReleaseType] = true
+		latest = append(latest, r)
+	}
+
+	if len(latest) == 0 {
+		return concourse.CheckResponse{}, fmt.Errorf("cannot find specified release")
+	}
+
+	var out concourse.CheckResponse
+	for i := len(latest) - 1; i >= 0; i-- {
+		version := concourse.ReleaseVersion(resolution.Version(latest[i]), latest[i], source)
+		version.ReleaseType = string(latest[i].ReleaseType)
+
+		out = append(out, version)
+	}
+
+	emitted := make([]pivnet.Release, len(latest))
+	for i := range latest {
+		emitted[i] = latest[len(latest)-1-i]
+	}
+
+	err := c.addProductFilesFingerprints(source, out, emitted)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info(fmt.Sprintf("Latest versions per release type: %v", out))
+
+	c.logEmitted(out, emitted)
+
+	return out, nil
+}
+
+// addProductFilesFingerprints sets the fingerprint of the product files of
+// each emitted release on its version, if the source requests it. The product
+// files are listed under the product slug, or failing that under each of the
+// previous slugs in turn, as the release may have been found under either.
+func (c *CheckCommand) addProductFilesFingerprints(source concourse.Source, out concourse.CheckResponse, releases []pivnet.Release) error {
+	if !source.ProductFilesFingerprint {
+		return nil
+	}
+
+	c.logger.Info("Fingerprinting product files of emitted releases")
+
+	slugs := append([]string{source.ProductSlug}, source.PreviousSlugs...)
+	for i, r := range releases {
+		var productFiles []pivnet.ProductFile
+		var err error
+		for _, slug := range slugs {
+			productFiles, err = c.pivnetClient.ProductFilesForRelease(slug, r.ID)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return fmt.Errorf(
+				"failed to get product files of release '%s' (ID: %d): %s",
+				r.Version,
+				r.ID,
+				err.Error(),
+			)
+		}
+
+		out[i].ProductFilesFingerprint = resolution.ProductFilesFingerprint(productFiles)
+	}
+
+	return nil
+}
+
+// logEmitted logs a table of the emitted versions along with the ID, type and
+// date of the release each was resolved from, in the order they are emitted,
+// as the versions alone do not show which releases were picked.
+func (c *CheckCommand) logEmitted(out concourse.CheckResponse, releases []pivnet.Release) {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "VERSION\tRELEASE ID\tRELEASE TYPE\tRELEASE DATE")
+	for i, v := range out {
+		fmt.Fprintf(
+			w,
+			"%s\t%d\t%s\t%s\n",
+			v.ProductVersion,
+			releases[i].ID,
+			releases[i].ReleaseType,
+			releases[i].ReleaseDate,
+		)
+	}
+	w.Flush()
+
+	c.logger.Debug(fmt.Sprintf("Emitted versions:\n%s", b.String()))
+}
+
 func (c *CheckCommand) removeExistingLogFiles() error {
 	logDir := filepath.Dir(c.logFilePath)
 	existingLogFiles, err := filepath.Glob(filepath.Join(logDir, "*.log*"))
@@ -173,49 +417,11 @@ func (c *CheckCommand) removeExistingLogFiles() error {
 	return nil
 }
 
-func (c *CheckCommand) validateReleaseType(releaseType string) error {
-	c.logger.Info(fmt.Sprintf("Validating release type: '%s'", releaseType))
-	releaseTypes, err := c.pivnetClient.ReleaseTypes()
-	if err != nil {
-		return err
-	}
-
-	releaseTypesAsStrings := make([]string, len(releaseTypes))
-	for i, r := range releaseTypes {
-		releaseTypesAsStrings[i] = string(r)
-	}
-
-	if releaseType != "" && !containsString(releaseTypesAsStrings, releaseType) {
-		releaseTypesPrintable := fmt.Sprintf("['%s']", strings.Join(releaseTypesAsStrings, "', '"))
-		return fmt.Errorf(
-			"provided release type: '%s' must be one of: %s",
-			releaseType,
-			releaseTypesPrintable,
-		)
-	}
-
-	return nil
-}
-
-func containsString(strings []string, str string) bool {
-	for _, s := range strings {
-		if str == s {
-			return true
-		}
-	}
-	return false
-}
-
-func releaseVersions(releases []pivnet.Release) ([]string, error) {
+func releaseVersions(releases []pivnet.Release) []string {
 	releaseVersions := make([]string, len(releases))
-
-	var err error
 	for i, r := range releases {
-		releaseVersions[i], err = versions.CombineVersionAndFingerprint(r.Version, r.SoftwareFilesUpdatedAt)
-		if err != nil {
-			return nil, err
-		}
+		releaseVersions[i] = resolution.Version(r)
 	}
 
-	return releaseVersions, nil
+	return releaseVersions
 }
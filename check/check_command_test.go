@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
@@ -14,10 +15,12 @@ import (
 	"github.com/pivotal-cf/pivnet-resource/check"
 	"github.com/pivotal-cf/pivnet-resource/check/checkfakes"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
 	"github.com/pivotal-cf/pivnet-resource/versions"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 )
 
 var _ = Describe("Check", func() {
@@ -150,6 +153,21 @@ var _ = Describe("Check", func() {
 			allReleases = []pivnet.Release{}
 		})
 
+		It("returns no versions without error", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).NotTo(BeNil())
+			Expect(response).To(BeEmpty())
+		})
+	})
+
+	Context("when no releases match the source", func() {
+		BeforeEach(func() {
+			checkRequest.Source.ReleaseType = string(releaseTypes[1])
+			filteredReleases = []pivnet.Release{}
+		})
+
 		It("returns an error", func() {
 			_, err := checkCommand.Run(checkRequest)
 			Expect(err).To(HaveOccurred())
@@ -158,6 +176,66 @@ var _ = Describe("Check", func() {
 		})
 	})
 
+	Context("when previous product slugs are provided", func() {
+		var (
+			previousReleases []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			checkRequest.Source.PreviousSlugs = []string{"first-previous-slug", "second-previous-slug"}
+
+			previousReleases = []pivnet.Release{
+				{
+					ID:                     4,
+					Version:                "0.9.0",
+					SoftwareFilesUpdatedAt: "time4",
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			fakePivnetClient.ReleasesForProductSlugStub = func(slug string) ([]pivnet.Release, error) {
+				switch slug {
+				case productSlug:
+					return allReleases, nil
+				case "second-previous-slug":
+					return previousReleases, nil
+				default:
+					return []pivnet.Release{}, nil
+				}
+			}
+		})
+
+		It("does not query previous slugs when the product slug has releases", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.ReleasesForProductSlugCallCount()).To(Equal(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+		})
+
+		Context("when the product slug has no releases", func() {
+			BeforeEach(func() {
+				allReleases = []pivnet.Release{}
+			})
+
+			It("returns releases from the first previous slug that has releases", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePivnetClient.ReleasesForProductSlugCallCount()).To(Equal(3))
+				Expect(fakePivnetClient.ReleasesForProductSlugArgsForCall(1)).To(Equal("first-previous-slug"))
+				Expect(fakePivnetClient.ReleasesForProductSlugArgsForCall(2)).To(Equal("second-previous-slug"))
+
+				expectedVersion, err := versions.CombineVersionAndFingerprint("0.9.0", "time4")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(HaveLen(1))
+				Expect(response[0].ProductVersion).To(Equal(expectedVersion))
+			})
+		})
+	})
+
 	Context("when log files already exist", func() {
 		var (
 			otherFilePath1 string
@@ -261,6 +339,24 @@ var _ = Describe("Check", func() {
 				Expect(response[2].ProductVersion).To(Equal(versionWithFingerprintA))
 			})
 		})
+
+		Context("when the release of the version has been re-published", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{
+					ProductVersion: "1.2.4#some-old-fingerprint",
+				}
+			})
+
+			It("returns every version since it, oldest first", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(HaveLen(3))
+				Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[2]))
+				Expect(response[1].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+				Expect(response[2].ProductVersion).To(Equal(versionsWithFingerprints[0]))
+			})
+		})
 	})
 
 	Context("when the release type is specified", func() {
@@ -280,6 +376,68 @@ var _ = Describe("Check", func() {
 			Expect(response[0].ProductVersion).To(Equal(versionWithFingerprintC))
 		})
 
+		Context("when verbose logging is enabled", func() {
+			var logBuffer *gbytes.Buffer
+
+			BeforeEach(func() {
+				logBuffer = gbytes.NewBuffer()
+				logger := log.New(logBuffer, "", log.LstdFlags)
+				fakeLogger = logshim.NewLogShim(logger, logger, true)
+			})
+
+			It("logs each release considered and why it was excluded", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logBuffer).To(gbytes.Say("Considering release: '1.2.3' \\(ID: 1"))
+				Expect(logBuffer).To(gbytes.Say("Considering release: '2.3.4' \\(ID: 2"))
+				Expect(logBuffer).To(gbytes.Say("Considering release: '1.2.4' \\(ID: 3"))
+				Expect(logBuffer).To(gbytes.Say("Excluded release: '1.2.3' \\(ID: 1\\) - release type is not: 'bar'"))
+				Expect(logBuffer).To(gbytes.Say("Excluded release: '1.2.4' \\(ID: 3\\) - release type is not: 'bar'"))
+				Expect(logBuffer).NotTo(gbytes.Say("Excluded release: '2.3.4'"))
+			})
+
+			It("logs a table of the emitted versions and their releases", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logBuffer).To(gbytes.Say("Emitted versions:"))
+				Expect(logBuffer).To(gbytes.Say("VERSION\\s+RELEASE ID\\s+RELEASE TYPE\\s+RELEASE DATE"))
+				Expect(logBuffer).To(gbytes.Say(regexp.QuoteMeta(versionsWithFingerprints[1]) + "\\s+2\\s+bar"))
+			})
+		})
+
+		Context("when sorting by semver", func() {
+			BeforeEach(func() {
+				checkRequest.Source.SortBy = concourse.SortBySemver
+				fakeSorter.SortBySemverStub = func(releases []pivnet.Release) ([]pivnet.Release, error) {
+					return releases, nil
+				}
+			})
+
+			It("sorts only the releases with that release type", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSorter.SortBySemverCallCount()).To(Equal(1))
+				Expect(fakeSorter.SortBySemverArgsForCall(0)).To(Equal([]pivnet.Release{allReleases[1]}))
+			})
+		})
+
+		Context("when the version is a release of another release type", func() {
+			BeforeEach(func() {
+				checkRequest.Version.ProductVersion = versionsWithFingerprints[0]
+			})
+
+			It("returns the most recent version with that release type", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(HaveLen(1))
+				Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+			})
+		})
+
 		Context("when the release type is invalid", func() {
 			BeforeEach(func() {
 				checkRequest.Source.ReleaseType = "not a valid release type"
@@ -345,6 +503,288 @@ var _ = Describe("Check", func() {
 		})
 	})
 
+	Context("when version metadata is requested", func() {
+		BeforeEach(func() {
+			checkRequest.Source.VersionMetadata = true
+
+			allReleases[0].EULA = &pivnet.EULA{Slug: "some-eula"}
+		})
+
+		It("includes the release type and EULA slug in each version", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{
+					ProductVersion: versionsWithFingerprints[0],
+					ReleaseType:    string(releaseTypes[0]),
+					EULASlug:       "some-eula",
+				},
+			}))
+		})
+
+		Context("when the version is not the latest", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{
+					ProductVersion: versionsWithFingerprints[2],
+				}
+			})
+
+			It("includes the metadata of each new version", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(Equal(concourse.CheckResponse{
+					{ProductVersion: versionsWithFingerprints[2], ReleaseType: string(releaseTypes[2])},
+					{ProductVersion: versionsWithFingerprints[1], ReleaseType: string(releaseTypes[1])},
+					{
+						ProductVersion: versionsWithFingerprints[0],
+						ReleaseType:    string(releaseTypes[0]),
+						EULASlug:       "some-eula",
+					},
+				}))
+			})
+		})
+	})
+
+	Context("when a EULA fingerprint is requested", func() {
+		BeforeEach(func() {
+			checkRequest.Source.EULAFingerprint = true
+			checkRequest.Version = concourse.Version{
+				ProductVersion: versionsWithFingerprints[0],
+				EULASlug:       "some-eula",
+			}
+
+			allReleases[0].EULA = &pivnet.EULA{Slug: "some-other-eula"}
+		})
+
+		It("emits the latest version again with its new EULA slug", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{
+					ProductVersion: versionsWithFingerprints[0],
+					EULASlug:       "some-other-eula",
+				},
+			}))
+		})
+	})
+
+	Context("when the webhook fast path is enabled", func() {
+		var (
+			recentReleases []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			checkRequest.Source.WebhookFastPath = true
+			checkRequest.Version = concourse.Version{
+				ProductVersion: versionsWithFingerprints[1], // 2.3.4#time2
+			}
+
+			recentReleases = allReleases[:2]
+		})
+
+		JustBeforeEach(func() {
+			fakePivnetClient.RecentReleasesForProductSlugReturns(recentReleases, nil)
+		})
+
+		It("returns the versions since the version from only the most recent releases", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{ProductVersion: versionsWithFingerprints[1]},
+				{ProductVersion: versionsWithFingerprints[0]},
+			}))
+
+			Expect(fakePivnetClient.RecentReleasesForProductSlugCallCount()).To(Equal(1))
+			invokedProductSlug, limit := fakePivnetClient.RecentReleasesForProductSlugArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal(productSlug))
+			Expect(limit).To(Equal(100))
+
+			Expect(fakePivnetClient.ReleasesForProductSlugCallCount()).To(Equal(0))
+			Expect(fakePivnetClient.ReleaseTypesCallCount()).To(Equal(0))
+		})
+
+		Context("when the version is not among the most recent releases", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{
+					ProductVersion: versionsWithFingerprints[2], // 1.2.4#time3
+				}
+			})
+
+			It("checks all releases", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(HaveLen(3))
+				Expect(fakePivnetClient.ReleasesForProductSlugCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when listing the most recent releases returns an error", func() {
+			JustBeforeEach(func() {
+				fakePivnetClient.RecentReleasesForProductSlugReturns(nil, errors.New("recent releases error"))
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(MatchError("recent releases error"))
+			})
+		})
+
+		Context("when no version is provided", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{}
+			})
+
+			It("checks all releases", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePivnetClient.RecentReleasesForProductSlugCallCount()).To(Equal(0))
+				Expect(fakePivnetClient.ReleasesForProductSlugCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Context("when fast_check is set", func() {
+		BeforeEach(func() {
+			checkRequest.Source.FastCheck = true
+			checkRequest.Source.ReleaseType = string(releaseTypes[0])
+			checkRequest.Version = concourse.Version{
+				ProductVersion: versionsWithFingerprints[2],
+			}
+		})
+
+		It("lists only the releases of the product", func() {
+			_, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.ReleasesForProductSlugCallCount()).To(Equal(1))
+			Expect(fakePivnetClient.ReleaseTypesCallCount()).To(Equal(0))
+			Expect(fakePivnetClient.ProductFilesForReleaseCallCount()).To(Equal(0))
+		})
+
+		Context("when a version is pinned", func() {
+			BeforeEach(func() {
+				checkRequest.Source.PinnedVersion = allReleases[0].Version
+			})
+
+			It("lists only the releases of the product", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePivnetClient.ReleaseTypesCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when a product files fingerprint is requested", func() {
+		var (
+			productFilesByReleaseID map[int][]pivnet.ProductFile
+		)
+
+		BeforeEach(func() {
+			checkRequest.Source.ProductFilesFingerprint = true
+
+			productFilesByReleaseID = map[int][]pivnet.ProductFile{
+				1: {{ID: 10, SHA256: "some-sha256"}},
+				2: {{ID: 20, SHA256: "other-sha256"}},
+				3: {{ID: 30, SHA256: "another-sha256"}},
+			}
+
+			fakePivnetClient.ProductFilesForReleaseStub = func(productSlug string, releaseID int) ([]pivnet.ProductFile, error) {
+				return productFilesByReleaseID[releaseID], nil
+			}
+		})
+
+		It("includes the fingerprint of the product files of the release in the version", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{
+					ProductVersion:          versionsWithFingerprints[0],
+					ProductFilesFingerprint: resolution.ProductFilesFingerprint(productFilesByReleaseID[1]),
+				},
+			}))
+
+			Expect(fakePivnetClient.ProductFilesForReleaseCallCount()).To(Equal(1))
+			invokedProductSlug, invokedReleaseID := fakePivnetClient.ProductFilesForReleaseArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal(checkRequest.Source.ProductSlug))
+			Expect(invokedReleaseID).To(Equal(1))
+		})
+
+		Context("when the version is not the latest", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{
+					ProductVersion: versionsWithFingerprints[2],
+				}
+			})
+
+			It("includes the fingerprint in each new version", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(HaveLen(3))
+				Expect(response[0].ProductFilesFingerprint).To(Equal(resolution.ProductFilesFingerprint(productFilesByReleaseID[3])))
+				Expect(response[1].ProductFilesFingerprint).To(Equal(resolution.ProductFilesFingerprint(productFilesByReleaseID[2])))
+				Expect(response[2].ProductFilesFingerprint).To(Equal(resolution.ProductFilesFingerprint(productFilesByReleaseID[1])))
+			})
+		})
+
+		Context("when getting the product files returns an error", func() {
+			BeforeEach(func() {
+				fakePivnetClient.ProductFilesForReleaseStub = nil
+				fakePivnetClient.ProductFilesForReleaseReturns(nil, errors.New("some product files error"))
+			})
+
+			It("returns an error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(MatchError("failed to get product files of release '1.2.3' (ID: 1): some product files error"))
+			})
+		})
+	})
+
+	Context("when one version per release type is requested", func() {
+		BeforeEach(func() {
+			checkRequest.Source.OnePerReleaseType = true
+
+			allReleases = append(allReleases, pivnet.Release{
+				ID:                     4,
+				Version:                "1.2.2",
+				ReleaseType:            releaseTypes[0],
+				SoftwareFilesUpdatedAt: "time4",
+			})
+		})
+
+		It("returns the newest version of each release type, newest last", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{ProductVersion: versionsWithFingerprints[2], ReleaseType: string(releaseTypes[2])},
+				{ProductVersion: versionsWithFingerprints[1], ReleaseType: string(releaseTypes[1])},
+				{ProductVersion: versionsWithFingerprints[0], ReleaseType: string(releaseTypes[0])},
+			}))
+		})
+
+		Context("when no releases are returned", func() {
+			BeforeEach(func() {
+				allReleases = []pivnet.Release{}
+			})
+
+			It("returns no versions without error", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(BeEmpty())
+			})
+		})
+	})
+
 	Context("when sorting by semver", func() {
 		var (
 			semverOrderedReleases []pivnet.Release
@@ -397,4 +837,189 @@ var _ = Describe("Check", func() {
 			})
 		})
 	})
+
+	Context("when sampling is configured", func() {
+		BeforeEach(func() {
+			checkRequest.Source.Sample = concourse.SampleMinor
+
+			fakeSorter.SampleBySemverReturns([]pivnet.Release{allReleases[1], allReleases[0]}, nil)
+		})
+
+		It("returns only the sampled versions", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(HaveLen(1))
+			Expect(response[0].ProductVersion).To(Equal(versionsWithFingerprints[1]))
+
+			Expect(fakeSorter.SampleBySemverCallCount()).To(Equal(1))
+			releases, sample := fakeSorter.SampleBySemverArgsForCall(0)
+			Expect(releases).To(Equal(allReleases))
+			Expect(sample).To(Equal(concourse.SampleMinor))
+		})
+
+		Context("when sampling returns an error", func() {
+			var sampleErr error
+
+			BeforeEach(func() {
+				sampleErr = errors.New("sample error")
+
+				fakeSorter.SampleBySemverReturns(nil, sampleErr)
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(Equal(sampleErr))
+			})
+		})
+	})
+
+	It("does not sample by default", func() {
+		_, err := checkCommand.Run(checkRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeSorter.SampleBySemverCallCount()).To(Equal(0))
+	})
+
+	Context("when a pinned version is specified", func() {
+		BeforeEach(func() {
+			checkRequest.Source.PinnedVersion = "1.2.4"
+		})
+
+		It("returns only the pinned version, without a fingerprint", func() {
+			response, err := checkCommand.Run(checkRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.CheckResponse{
+				{ProductVersion: "1.2.4"},
+			}))
+
+			Expect(fakeFilter.ReleasesByVersionCallCount()).To(Equal(0))
+		})
+
+		Context("when a newer version is provided", func() {
+			BeforeEach(func() {
+				checkRequest.Version = concourse.Version{ProductVersion: versionsWithFingerprints[1]}
+			})
+
+			It("still returns the pinned version", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(Equal(concourse.CheckResponse{
+					{ProductVersion: "1.2.4"},
+				}))
+			})
+		})
+
+		Context("when the release type of the pinned version does not match", func() {
+			BeforeEach(func() {
+				checkRequest.Source.ReleaseType = string(releaseTypes[0])
+			})
+
+			It("returns an error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(MatchError(fmt.Sprintf(
+					"pinned_version '1.2.4' has release type: '%s', not: '%s'",
+					releaseTypes[2],
+					releaseTypes[0],
+				)))
+			})
+		})
+
+		Context("when the pinned version does not exist", func() {
+			BeforeEach(func() {
+				checkRequest.Source.PinnedVersion = "9.9.9"
+			})
+
+			It("returns an error", func() {
+				_, err := checkCommand.Run(checkRequest)
+				Expect(err).To(MatchError("cannot find pinned_version '9.9.9'"))
+			})
+		})
+
+		Context("when the pinned version is under a previous slug", func() {
+			BeforeEach(func() {
+				checkRequest.Source.PinnedVersion = "0.9.0"
+				checkRequest.Source.PreviousSlugs = []string{"some-previous-slug"}
+			})
+
+			JustBeforeEach(func() {
+				fakePivnetClient.ReleasesForProductSlugStub = func(slug string) ([]pivnet.Release, error) {
+					if slug == "some-previous-slug" {
+						return []pivnet.Release{{ID: 4, Version: "0.9.0"}}, nil
+					}
+					return allReleases, nil
+				}
+			})
+
+			It("returns the pinned version", func() {
+				response, err := checkCommand.Run(checkRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(Equal(concourse.CheckResponse{
+					{ProductVersion: "0.9.0"},
+				}))
+				Expect(fakePivnetClient.ReleasesForProductSlugArgsForCall(1)).To(Equal("some-previous-slug"))
+			})
+		})
+	})
+
+	Describe("MigrateVersions", func() {
+		var (
+			migrateRequest concourse.MigrateVersionsRequest
+		)
+
+		BeforeEach(func() {
+			migrateRequest = concourse.MigrateVersionsRequest{
+				Source: checkRequest.Source,
+				Versions: []concourse.Version{
+					{ProductVersion: "1.2.3"},
+					{ProductVersion: "2.3.4#some-old-fingerprint"},
+				},
+			}
+		})
+
+		It("maps each version to the current fingerprinted version", func() {
+			response, err := checkCommand.MigrateVersions(migrateRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response).To(Equal(concourse.MigrateVersionsResponse{
+				{
+					From: concourse.Version{ProductVersion: "1.2.3"},
+					To:   concourse.Version{ProductVersion: versionsWithFingerprints[0]},
+				},
+				{
+					From: concourse.Version{ProductVersion: "2.3.4#some-old-fingerprint"},
+					To:   concourse.Version{ProductVersion: versionsWithFingerprints[1]},
+				},
+			}))
+		})
+
+		Context("when no release exists for a version", func() {
+			BeforeEach(func() {
+				migrateRequest.Versions = append(migrateRequest.Versions, concourse.Version{
+					ProductVersion: "9.9.9",
+				})
+			})
+
+			It("omits it", func() {
+				response, err := checkCommand.MigrateVersions(migrateRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response).To(HaveLen(2))
+			})
+		})
+
+		Context("when getting releases returns an error", func() {
+			BeforeEach(func() {
+				releasesErr = fmt.Errorf("some error")
+			})
+
+			It("returns the error", func() {
+				_, err := checkCommand.MigrateVersions(migrateRequest)
+				Expect(err).To(Equal(releasesErr))
+			})
+		})
+	})
 })
@@ -28,6 +28,16 @@ type FakeFilter struct {
 		result1 []go_pivnet.Release
 		result2 error
 	}
+	ReleasesByVersionConstraintStub        func(releases []go_pivnet.Release, constraint string) ([]go_pivnet.Release, error)
+	releasesByVersionConstraintMutex       sync.RWMutex
+	releasesByVersionConstraintArgsForCall []struct {
+		releases   []go_pivnet.Release
+		constraint string
+	}
+	releasesByVersionConstraintReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -112,6 +122,46 @@ func (fake *FakeFilter) ReleasesByVersionReturns(result1 []go_pivnet.Release, re
 	}{result1, result2}
 }
 
+func (fake *FakeFilter) ReleasesByVersionConstraint(releases []go_pivnet.Release, constraint string) ([]go_pivnet.Release, error) {
+	var releasesCopy []go_pivnet.Release
+	if releases != nil {
+		releasesCopy = make([]go_pivnet.Release, len(releases))
+		copy(releasesCopy, releases)
+	}
+	fake.releasesByVersionConstraintMutex.Lock()
+	fake.releasesByVersionConstraintArgsForCall = append(fake.releasesByVersionConstraintArgsForCall, struct {
+		releases   []go_pivnet.Release
+		constraint string
+	}{releasesCopy, constraint})
+	fake.recordInvocation("ReleasesByVersionConstraint", []interface{}{releasesCopy, constraint})
+	fake.releasesByVersionConstraintMutex.Unlock()
+	if fake.ReleasesByVersionConstraintStub != nil {
+		return fake.ReleasesByVersionConstraintStub(releases, constraint)
+	} else {
+		return fake.releasesByVersionConstraintReturns.result1, fake.releasesByVersionConstraintReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ReleasesByVersionConstraintCallCount() int {
+	fake.releasesByVersionConstraintMutex.RLock()
+	defer fake.releasesByVersionConstraintMutex.RUnlock()
+	return len(fake.releasesByVersionConstraintArgsForCall)
+}
+
+func (fake *FakeFilter) ReleasesByVersionConstraintArgsForCall(i int) ([]go_pivnet.Release, string) {
+	fake.releasesByVersionConstraintMutex.RLock()
+	defer fake.releasesByVersionConstraintMutex.RUnlock()
+	return fake.releasesByVersionConstraintArgsForCall[i].releases, fake.releasesByVersionConstraintArgsForCall[i].constraint
+}
+
+func (fake *FakeFilter) ReleasesByVersionConstraintReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesByVersionConstraintStub = nil
+	fake.releasesByVersionConstraintReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -119,6 +169,8 @@ func (fake *FakeFilter) Invocations() map[string][][]interface{} {
 	defer fake.releasesByReleaseTypeMutex.RUnlock()
 	fake.releasesByVersionMutex.RLock()
 	defer fake.releasesByVersionMutex.RUnlock()
+	fake.releasesByVersionConstraintMutex.RLock()
+	defer fake.releasesByVersionConstraintMutex.RUnlock()
 	return fake.invocations
 }
 
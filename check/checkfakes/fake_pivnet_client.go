@@ -24,6 +24,26 @@ type FakePivnetClient struct {
 		result1 []go_pivnet.Release
 		result2 error
 	}
+	ProductFilesForReleaseStub        func(productSlug string, releaseID int) ([]go_pivnet.ProductFile, error)
+	productFilesForReleaseMutex       sync.RWMutex
+	productFilesForReleaseArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	productFilesForReleaseReturns struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}
+	RecentReleasesForProductSlugStub        func(productSlug string, limit int) ([]go_pivnet.Release, error)
+	recentReleasesForProductSlugMutex       sync.RWMutex
+	recentReleasesForProductSlugArgsForCall []struct {
+		productSlug string
+		limit       int
+	}
+	recentReleasesForProductSlugReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -88,6 +108,76 @@ func (fake *FakePivnetClient) ReleasesForProductSlugReturns(result1 []go_pivnet.
 	}{result1, result2}
 }
 
+func (fake *FakePivnetClient) ProductFilesForRelease(productSlug string, releaseID int) ([]go_pivnet.ProductFile, error) {
+	fake.productFilesForReleaseMutex.Lock()
+	fake.productFilesForReleaseArgsForCall = append(fake.productFilesForReleaseArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ProductFilesForRelease", []interface{}{productSlug, releaseID})
+	fake.productFilesForReleaseMutex.Unlock()
+	if fake.ProductFilesForReleaseStub != nil {
+		return fake.ProductFilesForReleaseStub(productSlug, releaseID)
+	} else {
+		return fake.productFilesForReleaseReturns.result1, fake.productFilesForReleaseReturns.result2
+	}
+}
+
+func (fake *FakePivnetClient) ProductFilesForReleaseCallCount() int {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return len(fake.productFilesForReleaseArgsForCall)
+}
+
+func (fake *FakePivnetClient) ProductFilesForReleaseArgsForCall(i int) (string, int) {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return fake.productFilesForReleaseArgsForCall[i].productSlug, fake.productFilesForReleaseArgsForCall[i].releaseID
+}
+
+func (fake *FakePivnetClient) ProductFilesForReleaseReturns(result1 []go_pivnet.ProductFile, result2 error) {
+	fake.ProductFilesForReleaseStub = nil
+	fake.productFilesForReleaseReturns = struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) RecentReleasesForProductSlug(productSlug string, limit int) ([]go_pivnet.Release, error) {
+	fake.recentReleasesForProductSlugMutex.Lock()
+	fake.recentReleasesForProductSlugArgsForCall = append(fake.recentReleasesForProductSlugArgsForCall, struct {
+		productSlug string
+		limit       int
+	}{productSlug, limit})
+	fake.recordInvocation("RecentReleasesForProductSlug", []interface{}{productSlug, limit})
+	fake.recentReleasesForProductSlugMutex.Unlock()
+	if fake.RecentReleasesForProductSlugStub != nil {
+		return fake.RecentReleasesForProductSlugStub(productSlug, limit)
+	} else {
+		return fake.recentReleasesForProductSlugReturns.result1, fake.recentReleasesForProductSlugReturns.result2
+	}
+}
+
+func (fake *FakePivnetClient) RecentReleasesForProductSlugCallCount() int {
+	fake.recentReleasesForProductSlugMutex.RLock()
+	defer fake.recentReleasesForProductSlugMutex.RUnlock()
+	return len(fake.recentReleasesForProductSlugArgsForCall)
+}
+
+func (fake *FakePivnetClient) RecentReleasesForProductSlugArgsForCall(i int) (string, int) {
+	fake.recentReleasesForProductSlugMutex.RLock()
+	defer fake.recentReleasesForProductSlugMutex.RUnlock()
+	return fake.recentReleasesForProductSlugArgsForCall[i].productSlug, fake.recentReleasesForProductSlugArgsForCall[i].limit
+}
+
+func (fake *FakePivnetClient) RecentReleasesForProductSlugReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.RecentReleasesForProductSlugStub = nil
+	fake.recentReleasesForProductSlugReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -95,6 +185,10 @@ func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	defer fake.releaseTypesMutex.RUnlock()
 	fake.releasesForProductSlugMutex.RLock()
 	defer fake.releasesForProductSlugMutex.RUnlock()
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	fake.recentReleasesForProductSlugMutex.RLock()
+	defer fake.recentReleasesForProductSlugMutex.RUnlock()
 	return fake.invocations
 }
 
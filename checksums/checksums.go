@@ -0,0 +1,94 @@
+package checksums
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	SHA384 = "sha384"
+	SHA512 = "sha512"
+)
+
+// Algorithms are the additional checksum algorithms which can be configured
+// via checksum_algorithms. SHA256 and MD5 are always computed.
+var Algorithms = []string{
+	SHA384,
+	SHA512,
+}
+
+var newHashes = map[string]func() hash.Hash{
+	SHA384: sha512.New384,
+	SHA512: sha512.New,
+}
+
+// Validate returns an error if any of the algorithms is not supported.
+func Validate(algorithms []string) error {
+	for _, algorithm := range algorithms {
+		if _, ok := newHashes[algorithm]; !ok {
+			return fmt.Errorf(
+				"checksum algorithm '%s' must be one of: ['%s']",
+				algorithm,
+				strings.Join(Algorithms, "', '"),
+			)
+		}
+	}
+
+	return nil
+}
+
+type FileSummer struct {
+	algorithms []string
+}
+
+// NewFileSummer returns a FileSummer computing the provided algorithms,
+// which must already have been validated.
+func NewFileSummer(algorithms []string) *FileSummer {
+	return &FileSummer{
+		algorithms: algorithms,
+	}
+}
+
+// SumFile reads the file once and returns its checksum for each algorithm,
+// keyed by algorithm. No file is read if no algorithms are configured.
+func (s FileSummer) SumFile(filepath string) (map[string]string, error) {
+	if len(s.algorithms) == 0 {
+		return nil, nil
+	}
+
+	hashes := map[string]hash.Hash{}
+	var writers []io.Writer
+	for _, algorithm := range s.algorithms {
+		newHash, ok := newHashes[algorithm]
+		if !ok {
+			return nil, fmt.Errorf("unsupported checksum algorithm: '%s'", algorithm)
+		}
+
+		h := newHash()
+		hashes[algorithm] = h
+		writers = append(writers, h)
+	}
+
+	f, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(io.MultiWriter(writers...), f)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := map[string]string{}
+	for algorithm, h := range hashes {
+		sums[algorithm] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	return sums, nil
+}
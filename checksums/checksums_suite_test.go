@@ -0,0 +1,13 @@
+package checksums_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestChecksums(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Checksums Suite")
+}
@@ -0,0 +1,66 @@
+package checksums_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/checksums"
+)
+
+var _ = Describe("Checksums", func() {
+	Describe("Validate", func() {
+		It("accepts supported algorithms", func() {
+			Expect(checksums.Validate([]string{"sha384", "sha512"})).To(Succeed())
+		})
+
+		It("rejects unsupported algorithms", func() {
+			err := checksums.Validate([]string{"sha512", "blake2b"})
+			Expect(err).To(MatchError(ContainSubstring("'blake2b' must be one of")))
+		})
+	})
+
+	Describe("FileSummer", func() {
+		var (
+			dir      string
+			filePath string
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "checksums")
+			Expect(err).NotTo(HaveOccurred())
+
+			filePath = filepath.Join(dir, "some-file")
+			err = ioutil.WriteFile(filePath, []byte("some contents"), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("returns the checksum for each algorithm", func() {
+			sums, err := checksums.NewFileSummer([]string{"sha384", "sha512"}).SumFile(filePath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sums).To(Equal(map[string]string{
+				"sha384": "cf2e85a394109a9240373fed3e1e892a5c988f530b744eb4a3e911944b2b43d7566b4e9b0ff5adb465201053da5618aa",
+				"sha512": "0cd219361ef550c93b33508224426ebd1372afcd4b71a863cb6dc4d6c5d907b363457c18c5378a6ec68f4caa156f75330ce69e2e8d559952cfaa53e8622dd49b",
+			}))
+		})
+
+		It("returns nothing when no algorithms are configured", func() {
+			sums, err := checksums.NewFileSummer(nil).SumFile(filepath.Join(dir, "does-not-exist"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sums).To(BeEmpty())
+		})
+
+		It("returns an error when the file does not exist", func() {
+			_, err := checksums.NewFileSummer([]string{"sha512"}).SumFile(filepath.Join(dir, "does-not-exist"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,137 @@
+package chunksum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ManifestSuffix is appended to the name of a file to form the name of
+	// its chunk manifest.
+	ManifestSuffix = ".sha256chunks.json"
+
+	DefaultChunkSize int64 = 64 * 1024 * 1024
+)
+
+type Chunk struct {
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest holds the SHA256 of each fixed-size chunk of a file, allowing
+// partial or resumed downloads to be verified piecewise.
+type Manifest struct {
+	File      string  `json:"file"`
+	Size      int64   `json:"size"`
+	ChunkSize int64   `json:"chunk_size"`
+	SHA256    string  `json:"sha256"`
+	Chunks    []Chunk `json:"chunks"`
+}
+
+type ManifestWriter struct {
+	chunkSize int64
+	threshold int64
+}
+
+// NewManifestWriter returns a ManifestWriter which writes manifests for files
+// larger than threshold bytes. A threshold of zero disables manifests.
+func NewManifestWriter(chunkSize int64, threshold int64) ManifestWriter {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	return ManifestWriter{
+		chunkSize: chunkSize,
+		threshold: threshold,
+	}
+}
+
+// WriteManifest writes the chunk manifest for exactGlob alongside it in
+// sourcesDir and returns the glob of the manifest. If the file does not
+// exceed the threshold no manifest is written and the returned glob is empty.
+func (w ManifestWriter) WriteManifest(sourcesDir string, exactGlob string) (string, error) {
+	if w.threshold <= 0 {
+		return "", nil
+	}
+
+	fullFilepath := filepath.Join(sourcesDir, exactGlob)
+
+	info, err := os.Stat(fullFilepath)
+	if err != nil {
+		return "", err
+	}
+
+	if info.Size() <= w.threshold {
+		return "", nil
+	}
+
+	manifest, err := w.Sum(fullFilepath)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		// Untested as it is too hard to force json.Marshal to return an error
+		return "", err
+	}
+
+	manifestGlob := exactGlob + ManifestSuffix
+
+	err = ioutil.WriteFile(filepath.Join(sourcesDir, manifestGlob), b, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	return manifestGlob, nil
+}
+
+// Sum computes the manifest for the file at path.
+func (w ManifestWriter) Sum(path string) (Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer f.Close()
+
+	whole := sha256.New()
+	reader := io.TeeReader(f, whole)
+
+	manifest := Manifest{
+		File:      filepath.Base(path),
+		ChunkSize: w.chunkSize,
+	}
+
+	for {
+		chunkHash := sha256.New()
+
+		n, err := io.CopyN(chunkHash, reader, w.chunkSize)
+		if err != nil && err != io.EOF {
+			return Manifest{}, fmt.Errorf("failed to read chunk at offset %d: %s", manifest.Size, err)
+		}
+
+		if n > 0 {
+			manifest.Chunks = append(manifest.Chunks, Chunk{
+				Offset: manifest.Size,
+				Size:   n,
+				SHA256: hex.EncodeToString(chunkHash.Sum(nil)),
+			})
+			manifest.Size += n
+		}
+
+		if err == io.EOF || n < w.chunkSize {
+			break
+		}
+	}
+
+	manifest.SHA256 = hex.EncodeToString(whole.Sum(nil))
+
+	return manifest, nil
+}
@@ -0,0 +1,13 @@
+package chunksum_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestChunksum(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Chunksum Suite")
+}
@@ -0,0 +1,111 @@
+package chunksum_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/chunksum"
+)
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+var _ = Describe("ManifestWriter", func() {
+	var (
+		sourcesDir string
+		contents   []byte
+
+		chunkSize int64
+		threshold int64
+
+		writer chunksum.ManifestWriter
+	)
+
+	BeforeEach(func() {
+		var err error
+		sourcesDir, err = ioutil.TempDir("", "chunksum")
+		Expect(err).NotTo(HaveOccurred())
+
+		contents = []byte("0123456789")
+		err = ioutil.WriteFile(filepath.Join(sourcesDir, "some-file"), contents, os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		chunkSize = 4
+		threshold = 5
+	})
+
+	JustBeforeEach(func() {
+		writer = chunksum.NewManifestWriter(chunkSize, threshold)
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(sourcesDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("writes a manifest of per-chunk hashes alongside the file", func() {
+		manifestGlob, err := writer.WriteManifest(sourcesDir, "some-file")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifestGlob).To(Equal("some-file" + chunksum.ManifestSuffix))
+
+		b, err := ioutil.ReadFile(filepath.Join(sourcesDir, manifestGlob))
+		Expect(err).NotTo(HaveOccurred())
+
+		var manifest chunksum.Manifest
+		err = json.Unmarshal(b, &manifest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(manifest).To(Equal(chunksum.Manifest{
+			File:      "some-file",
+			Size:      10,
+			ChunkSize: 4,
+			SHA256:    sha256Hex(contents),
+			Chunks: []chunksum.Chunk{
+				{Offset: 0, Size: 4, SHA256: sha256Hex(contents[0:4])},
+				{Offset: 4, Size: 4, SHA256: sha256Hex(contents[4:8])},
+				{Offset: 8, Size: 2, SHA256: sha256Hex(contents[8:10])},
+			},
+		}))
+	})
+
+	Context("when the file does not exceed the threshold", func() {
+		BeforeEach(func() {
+			threshold = 10
+		})
+
+		It("does not write a manifest", func() {
+			manifestGlob, err := writer.WriteManifest(sourcesDir, "some-file")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifestGlob).To(BeEmpty())
+
+			Expect(filepath.Join(sourcesDir, "some-file"+chunksum.ManifestSuffix)).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("when the threshold is zero", func() {
+		BeforeEach(func() {
+			threshold = 0
+		})
+
+		It("does not write a manifest", func() {
+			manifestGlob, err := writer.WriteManifest(sourcesDir, "some-file")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(manifestGlob).To(BeEmpty())
+		})
+	})
+
+	Context("when the file does not exist", func() {
+		It("returns an error", func() {
+			_, err := writer.WriteManifest(sourcesDir, "some-missing-file")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
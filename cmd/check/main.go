@@ -4,26 +4,42 @@ import (
 	"encoding/json"
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
-	"github.com/pivotal-cf/go-pivnet/logshim"
 	"github.com/pivotal-cf/pivnet-resource/check"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/config"
 	"github.com/pivotal-cf/pivnet-resource/filter"
 	"github.com/pivotal-cf/pivnet-resource/gp"
+	"github.com/pivotal-cf/pivnet-resource/local"
+	"github.com/pivotal-cf/pivnet-resource/logging"
+	"github.com/pivotal-cf/pivnet-resource/schema"
 	"github.com/pivotal-cf/pivnet-resource/semver"
 	"github.com/pivotal-cf/pivnet-resource/sorter"
 	"github.com/pivotal-cf/pivnet-resource/useragent"
 	"github.com/pivotal-cf/pivnet-resource/validator"
 	"github.com/robdimsdale/sanitizer"
+	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 )
 
+const migrateVersionsFlag = "--migrate-versions"
+
 var (
 	// version is deliberately left uninitialized so it can be set at compile-time
 	version string
 )
 
+// checkClient is satisfied by both the Pivotal Network client and the local
+// client used when local_source is set.
+type checkClient interface {
+	ReleaseTypes() ([]pivnet.ReleaseType, error)
+	ReleasesForProductSlug(string) ([]pivnet.Release, error)
+	RecentReleasesForProductSlug(productSlug string, limit int) ([]pivnet.Release, error)
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
+}
+
 type AuthResp struct {
 	Token string `json: "token"`
 }
@@ -33,7 +49,23 @@ func main() {
 		version = "dev"
 	}
 
+	// When invoked with --schema, the schema of the request is printed
+	// instead of reading a request.
+	if len(os.Args) > 1 && os.Args[1] == schema.Flag {
+		err := schema.Print(os.Stdout, schema.CheckRequest)
+		if err != nil {
+			log.Fatalf("Exiting with error: %s", err)
+		}
+		return
+	}
+
+	// When invoked with --migrate-versions, check reads a list of versions and
+	// prints the current fingerprinted version of each, rather than checking
+	// for new versions.
+	migrateVersions := len(os.Args) > 1 && os.Args[1] == migrateVersionsFlag
+
 	var input concourse.CheckRequest
+	var migrateInput concourse.MigrateVersionsRequest
 
 	logFile, err := ioutil.TempFile("", "pivnet-check.log")
 	if err != nil {
@@ -44,52 +76,99 @@ func main() {
 
 	logger.Printf("PivNet Resource version: %s", version)
 
-	err = json.NewDecoder(os.Stdin).Decode(&input)
+	if migrateVersions {
+		err = json.NewDecoder(os.Stdin).Decode(&migrateInput)
+		input.Source = migrateInput.Source
+	} else {
+		var warnings []string
+		warnings, err = schema.Decode(os.Stdin, schema.CheckRequest, &input)
+		for _, w := range warnings {
+			log.Printf("WARNING: %s", w)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Exiting with error: %s", err)
+	}
+
+	err = input.Source.ReadCredentialFiles()
+	if err != nil {
+		log.Fatalf("Exiting with error: %s", err)
+	}
+
+	err = config.ApplyChannel(&input.Source)
 	if err != nil {
 		log.Fatalf("Exiting with error: %s", err)
 	}
+	migrateInput.Source = input.Source
 
 	sanitized := concourse.SanitizedSource(input.Source)
 	logger.SetOutput(sanitizer.NewSanitizer(sanitized, logFile))
 
-	verbose := false
-	ls := logshim.NewLogShim(logger, logger, verbose)
-
 	err = validator.NewCheckValidator(input).Validate()
 	if err != nil {
 		log.Fatalf("Exiting with error: %s", err)
 	}
 
-	var endpoint string
-	if input.Source.Endpoint != "" {
-		endpoint = input.Source.Endpoint
-	} else {
-		endpoint = pivnet.DefaultHost
+	cfg, err := config.FromCheckRequest(input)
+	if err != nil {
+		log.Fatalf("Exiting with error: %s", err)
 	}
 
-	apiToken := input.Source.APIToken
+	// In verbose mode, diagnostics are also written to stderr so that they are
+	// visible in the output of a failing or unexpectedly empty check.
+	verbose := cfg.Verbose
+	if verbose {
+		logger.SetOutput(sanitizer.NewSanitizer(sanitized, io.MultiWriter(logFile, os.Stderr)))
+	}
 
-	client := NewPivnetClientWithToken(
-		apiToken,
-		endpoint,
-		input.Source.SkipSSLValidation,
-		useragent.UserAgent(version, "check", input.Source.ProductSlug),
-		ls,
-	)
+	ls := logging.NewLogger(logger, verbose)
+
+	logger.Printf("Resolved config: %s", cfg)
+
+	var client checkClient
+	if input.Source.LocalSource != "" {
+		logger.Printf("Reading releases from local source: %s", input.Source.LocalSource)
+		client = local.NewClient(input.Source.LocalSource, ls)
+	} else {
+		apiToken := cfg.APIToken
+
+		var transport http.RoundTripper = gp.NewTransport(cfg.TransportConfig())
+
+		if input.Source.CheckCacheDir != "" {
+			logger.Printf("Caching responses in: %s", input.Source.CheckCacheDir)
+			transport = gp.NewConditionalTransport(transport, input.Source.CheckCacheDir, apiToken, ls)
+		}
+
+		client = NewPivnetClientWithToken(
+			apiToken,
+			cfg.Endpoint,
+			cfg.SkipSSLValidation,
+			gp.NewSlugRedirectTransport(transport, input.Source.StrictSlug, ls),
+			useragent.UserAgent(version, "check", input.Source.ProductSlug),
+			ls,
+		)
+	}
 
 	f := filter.NewFilter(ls)
 
 	semverConverter := semver.NewSemverConverter(ls)
 	s := sorter.NewSorter(ls, semverConverter)
 
-	response, err := check.NewCheckCommand(
+	checkCommand := check.NewCheckCommand(
 		ls,
 		version,
 		f,
 		client,
 		s,
 		logFile.Name(),
-	).Run(input)
+	)
+
+	var response interface{}
+	if migrateVersions {
+		response, err = checkCommand.MigrateVersions(migrateInput)
+	} else {
+		response, err = checkCommand.Run(input)
+	}
 	if err != nil {
 		log.Fatalf("Exiting with error: %s", err)
 	}
@@ -100,7 +179,7 @@ func main() {
 	}
 }
 
-func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bool, userAgent string, logger logger.Logger) *gp.Client {
+func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bool, transport http.RoundTripper, userAgent string, logger logger.Logger) *gp.Client {
 	clientConfig := pivnet.ClientConfig{
 		Host:              host,
 		Token:             apiToken,
@@ -110,6 +189,7 @@ func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bo
 
 	return gp.NewClient(
 		clientConfig,
+		transport,
 		logger,
 	)
 }
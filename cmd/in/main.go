@@ -2,27 +2,77 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 
 	"github.com/fatih/color"
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
-	"github.com/pivotal-cf/go-pivnet/logshim"
 	"github.com/pivotal-cf/go-pivnet/md5sum"
 	"github.com/pivotal-cf/go-pivnet/sha256sum"
+	"github.com/pivotal-cf/pivnet-resource/boshrelease"
+	"github.com/pivotal-cf/pivnet-resource/bundle"
+	"github.com/pivotal-cf/pivnet-resource/checksums"
+	"github.com/pivotal-cf/pivnet-resource/compression"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/config"
 	"github.com/pivotal-cf/pivnet-resource/downloader"
+	"github.com/pivotal-cf/pivnet-resource/events"
+	"github.com/pivotal-cf/pivnet-resource/filecache"
 	"github.com/pivotal-cf/pivnet-resource/filter"
 	"github.com/pivotal-cf/pivnet-resource/gp"
 	"github.com/pivotal-cf/pivnet-resource/in"
 	"github.com/pivotal-cf/pivnet-resource/in/filesystem"
+	"github.com/pivotal-cf/pivnet-resource/local"
+	"github.com/pivotal-cf/pivnet-resource/logging"
+	"github.com/pivotal-cf/pivnet-resource/progress"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
+	"github.com/pivotal-cf/pivnet-resource/schema"
+	"github.com/pivotal-cf/pivnet-resource/semver"
+	"github.com/pivotal-cf/pivnet-resource/sorter"
+	"github.com/pivotal-cf/pivnet-resource/sparse"
 	"github.com/pivotal-cf/pivnet-resource/ui"
 	"github.com/pivotal-cf/pivnet-resource/useragent"
 	"github.com/pivotal-cf/pivnet-resource/validator"
 	"github.com/robdimsdale/sanitizer"
 )
 
+// inClient is satisfied by both the Pivotal Network client and the local
+// client used when local_source is set.
+type inClient interface {
+	GetRelease(productSlug string, version string) (pivnet.Release, error)
+	GetReleaseByID(productSlug string, releaseID int) (pivnet.Release, error)
+	AcceptEULA(productSlug string, releaseID int) error
+	EULA(eulaSlug string) (pivnet.EULA, error)
+	FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error)
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
+	ProductFileForRelease(productSlug string, releaseID int, productFileID int) (pivnet.ProductFile, error)
+	ReleaseDependencies(productSlug string, releaseID int) ([]pivnet.ReleaseDependency, error)
+	DependencySpecifiers(productSlug string, releaseID int) ([]pivnet.DependencySpecifier, error)
+	ReleaseUpgradePaths(productSlug string, releaseID int) ([]pivnet.ReleaseUpgradePath, error)
+	UpgradePathSpecifiers(productSlug string, releaseID int) ([]pivnet.UpgradePathSpecifier, error)
+	DownloadProductFile(writer *os.File, productSlug string, releaseID int, productFileID int, progressWriter io.Writer) error
+	ReleaseTypes() ([]pivnet.ReleaseType, error)
+	ReleasesForProductSlug(string) ([]pivnet.Release, error)
+	RecentReleasesForProductSlug(productSlug string, limit int) ([]pivnet.Release, error)
+}
+
+// productFileClient downloads product files, either from a single host or
+// failing over across mirror_endpoints.
+type productFileClient interface {
+	DownloadProductFile(writer *os.File, productSlug string, releaseID int, productFileID int, progressWriter io.Writer) error
+}
+
+// inDownloader is satisfied by both the downloader of whole files and the
+// sparse downloader used when head_bytes or zip_members is set.
+type inDownloader interface {
+	Download(productFiles []pivnet.ProductFile, productSlug string, releaseID int) ([]string, map[int]error, error)
+}
+
 var (
 	// version is deliberately left uninitialized so it can be set at compile-time
 	version string
@@ -33,6 +83,16 @@ func main() {
 		version = "dev"
 	}
 
+	// When invoked with --schema, the schema of the request is printed
+	// instead of reading a request.
+	if len(os.Args) > 1 && os.Args[1] == schema.Flag {
+		err := schema.Print(os.Stdout, schema.InRequest)
+		if err != nil {
+			log.Fatalf("Exiting with error: %s", err)
+		}
+		return
+	}
+
 	color.NoColor = false
 
 	logWriter := os.Stderr
@@ -53,19 +113,43 @@ func main() {
 	downloadDir := os.Args[1]
 
 	var input concourse.InRequest
-	err := json.NewDecoder(os.Stdin).Decode(&input)
+	warnings, err := schema.Decode(os.Stdin, schema.InRequest, &input)
+	if err != nil {
+		uiPrinter.PrintErrorln(err)
+		os.Exit(1)
+	}
+
+	for _, warning := range warnings {
+		uiPrinter.PrintDeprecationln(warning)
+	}
+
+	err = input.Source.ReadCredentialFiles()
+	if err != nil {
+		uiPrinter.PrintErrorln(err)
+		os.Exit(1)
+	}
+
+	err = config.ApplyChannel(&input.Source)
 	if err != nil {
 		uiPrinter.PrintErrorln(err)
 		os.Exit(1)
 	}
 
 	sanitized := concourse.SanitizedSource(input.Source)
-	logger.SetOutput(sanitizer.NewSanitizer(sanitized, logWriter))
+	logOutput := logging.NewSyncWriter(sanitizer.NewSanitizer(sanitized, logWriter))
+	logger.SetOutput(logOutput)
 
-	verbose := input.Source.Verbose
-	ls := logshim.NewLogShim(logger, logger, verbose)
+	cfg, err := config.FromInRequest(input)
+	if err != nil {
+		uiPrinter.PrintErrorln(err)
+		os.Exit(1)
+	}
+
+	verbose := cfg.Verbose
+	ls := logging.NewLogger(logger, verbose)
 
 	ls.Debug("Verbose output enabled")
+	ls.Debug(fmt.Sprintf("Resolved config: %s", cfg))
 	logger.Printf("Creating download directory: %s", downloadDir)
 
 	err = os.MkdirAll(downloadDir, os.ModePerm)
@@ -80,28 +164,89 @@ func main() {
 		os.Exit(1)
 	}
 
-	var endpoint string
-	if input.Source.Endpoint != "" {
-		endpoint = input.Source.Endpoint
+	// The transport is shared so that once an HTTP/2 error has been seen,
+	// e.g. due to an intercepting proxy, every request uses HTTP/1.1.
+	transport := gp.NewHTTP2FallbackTransport(cfg.TransportConfig(), ls)
+
+	var client inClient
+	var pivnetClient *gp.Client
+	var downloadClient productFileClient
+	if input.Source.LocalSource != "" {
+		logger.Printf("Reading releases from local source: %s", input.Source.LocalSource)
+		client = local.NewClient(input.Source.LocalSource, ls)
+		downloadClient = client
 	} else {
-		endpoint = pivnet.DefaultHost
-	}
+		apiToken := cfg.APIToken
 
-	apiToken := input.Source.APIToken
+		if len(apiToken) < 20 {
+			uiPrinter.PrintDeprecationln("The use of static Pivnet API tokens is deprecated and will be removed. Please see https://network.pivotal.io/docs/api#how-to-authenticate for details.")
+		}
 
-	if len(apiToken) < 20 {
-		uiPrinter.PrintDeprecationln("The use of static Pivnet API tokens is deprecated and will be removed. Please see https://network.pivotal.io/docs/api#how-to-authenticate for details.")
+		pivnetClient = NewPivnetClientWithToken(
+			apiToken,
+			cfg.Endpoint,
+			cfg.SkipSSLValidation,
+			gp.NewSlugRedirectTransport(transport, input.Source.StrictSlug, ls),
+			useragent.UserAgent(version, "get", input.Source.ProductSlug),
+			ls,
+		)
+		client = pivnetClient
+		downloadClient = pivnetClient
+
+		if len(input.Source.MirrorEndpoints) > 0 {
+			hosts := []downloader.Host{{Endpoint: cfg.Endpoint, Client: pivnetClient}}
+			for _, endpoint := range input.Source.MirrorEndpoints {
+				hosts = append(hosts, downloader.Host{
+					Endpoint: endpoint,
+					Client: NewPivnetClientWithToken(
+						apiToken,
+						endpoint,
+						cfg.SkipSSLValidation,
+						gp.NewSlugRedirectTransport(transport, input.Source.StrictSlug, ls),
+						useragent.UserAgent(version, "get", input.Source.ProductSlug),
+						ls,
+					),
+				})
+			}
+			downloadClient = downloader.NewFailoverClient(hosts, ls)
+		}
 	}
 
-	client := NewPivnetClientWithToken(
-		apiToken,
-		endpoint,
-		input.Source.SkipSSLValidation,
-		useragent.UserAgent(version, "get", input.Source.ProductSlug),
-		ls,
-	)
+	var eventWriter io.Writer = ioutil.Discard
+	if input.Params.OutputEvents {
+		eventWriter = logWriter
+	}
+	eventEmitter := events.NewEmitter(eventWriter)
+
+	fileCache := filecache.NewCache(input.Source.DownloadCacheDir)
+
+	progressWriter := progress.NewLogWriter(ls, progress.DefaultInterval)
+
+	var d inDownloader = downloader.NewDownloader(downloadClient, downloadDir, ls, progressWriter, eventEmitter, fileCache, nil)
+	if pivnetClient != nil && input.Source.DownloadCacheDir != "" {
+		// Product files without a SHA256 are revalidated against the
+		// download cache with conditional requests to their signed
+		// download URLs.
+		conditionalGetter := downloader.NewConditionalGetter(
+			pivnetClient,
+			&http.Client{Transport: transport},
+		)
+		d = downloader.NewDownloader(downloadClient, downloadDir, ls, progressWriter, eventEmitter, fileCache, conditionalGetter)
+	}
 
-	d := downloader.NewDownloader(client, downloadDir, ls, logWriter)
+	if input.Params.SparseDownload() {
+		// Ranged reads go directly to the signed download URL, which does
+		// not require the Pivotal Network token.
+		httpClient := &http.Client{Transport: transport}
+		d = sparse.NewDownloader(
+			pivnetClient,
+			httpClient,
+			downloadDir,
+			ls,
+			input.Params.HeadBytes,
+			input.Params.ZipMembers,
+		)
+	}
 
 	fs := sha256sum.NewFileSummer()
 	md5fs := md5sum.NewFileSummer()
@@ -110,6 +255,13 @@ func main() {
 
 	fileWriter := filesystem.NewFileWriter(downloadDir, ls)
 	archive := &in.Archive{}
+	bundleWriter := bundle.NewWriter(downloadDir)
+	boshReleaseReader := boshrelease.NewReader(downloadDir)
+	checksumSummer := checksums.NewFileSummer(input.Source.ChecksumAlgorithms)
+
+	// The latest version is resolved when none is provided, as by check.
+	s := sorter.NewSorter(ls, semver.NewSemverConverter(ls))
+	latestResolver := resolution.NewMatcher(ls, f, client, s)
 
 	response, err := in.NewInCommand(
 		ls,
@@ -120,6 +272,13 @@ func main() {
 		md5fs,
 		fileWriter,
 		archive,
+		eventEmitter,
+		bundleWriter,
+		boshReleaseReader,
+		checksumSummer,
+		fileCache,
+		latestResolver,
+		compression.NewCompressor(),
 	).Run(input)
 	if err != nil {
 		uiPrinter.PrintErrorln(err)
@@ -133,7 +292,7 @@ func main() {
 	}
 }
 
-func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bool, userAgent string, logger logger.Logger) *gp.Client {
+func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bool, transport http.RoundTripper, userAgent string, logger logger.Logger) *gp.Client {
 	clientConfig := pivnet.ClientConfig{
 		Host:              host,
 		Token:             apiToken,
@@ -143,6 +302,7 @@ func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bo
 
 	return gp.NewClient(
 		clientConfig,
+		transport,
 		logger,
 	)
 }
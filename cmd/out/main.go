@@ -2,9 +2,12 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"time"
 
@@ -12,26 +15,45 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/pivotal-cf/go-pivnet"
-	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/md5sum"
 	"github.com/pivotal-cf/go-pivnet/sha256sum"
+	"github.com/pivotal-cf/pivnet-resource/bundle"
+	"github.com/pivotal-cf/pivnet-resource/checksums"
+	"github.com/pivotal-cf/pivnet-resource/chunksum"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/config"
 	"github.com/pivotal-cf/pivnet-resource/filter"
 	"github.com/pivotal-cf/pivnet-resource/globs"
 	"github.com/pivotal-cf/pivnet-resource/gp"
+	"github.com/pivotal-cf/pivnet-resource/gpgsign"
+	"github.com/pivotal-cf/pivnet-resource/includedfiles"
+	"github.com/pivotal-cf/pivnet-resource/logging"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 	"github.com/pivotal-cf/pivnet-resource/out"
 	"github.com/pivotal-cf/pivnet-resource/out/release"
+	"github.com/pivotal-cf/pivnet-resource/publishlock"
 	"github.com/pivotal-cf/pivnet-resource/s3"
+	"github.com/pivotal-cf/pivnet-resource/sbom"
+	"github.com/pivotal-cf/pivnet-resource/scan"
+	"github.com/pivotal-cf/pivnet-resource/schema"
 	"github.com/pivotal-cf/pivnet-resource/semver"
+	"github.com/pivotal-cf/pivnet-resource/transferstats"
 	"github.com/pivotal-cf/pivnet-resource/ui"
 	"github.com/pivotal-cf/pivnet-resource/uploader"
+	"github.com/pivotal-cf/pivnet-resource/uploadstate"
 	"github.com/pivotal-cf/pivnet-resource/useragent"
 	"github.com/pivotal-cf/pivnet-resource/validator"
 	"github.com/robdimsdale/sanitizer"
-	"github.com/pivotal-cf/go-pivnet/logger"
 )
 
+// uploadedFilesState is satisfied by the upload state tracker, and is nil
+// unless the put resumes interrupted puts.
+type uploadedFilesState interface {
+	Uploaded(file string, sha256 string) (int, bool)
+	RecordUploaded(file string, sha256 string, productFileID int) error
+}
+
 var (
 	// version is deliberately left uninitialized so it can be set at compile-time
 	version string
@@ -42,6 +64,16 @@ func main() {
 		version = "dev"
 	}
 
+	// When invoked with --schema, the schema of the request is printed
+	// instead of reading a request.
+	if len(os.Args) > 1 && os.Args[1] == schema.Flag {
+		err := schema.Print(os.Stdout, schema.OutRequest)
+		if err != nil {
+			log.Fatalf("Exiting with error: %s", err)
+		}
+		return
+	}
+
 	color.NoColor = false
 
 	logWriter := os.Stderr
@@ -68,27 +100,135 @@ func main() {
 	}
 
 	var input concourse.OutRequest
-	err = json.NewDecoder(os.Stdin).Decode(&input)
+	warnings, err := schema.Decode(os.Stdin, schema.OutRequest, &input)
+	if err != nil {
+		uiPrinter.PrintErrorln(err)
+		os.Exit(1)
+	}
+
+	for _, warning := range warnings {
+		uiPrinter.PrintDeprecationln(warning)
+	}
+
+	err = input.Source.ReadCredentialFiles()
+	if err != nil {
+		uiPrinter.PrintErrorln(err)
+		os.Exit(1)
+	}
+
+	err = config.ApplyChannel(&input.Source)
 	if err != nil {
 		uiPrinter.PrintErrorln(err)
 		os.Exit(1)
 	}
 
 	sanitized := concourse.SanitizedSource(input.Source)
-	logger.SetOutput(sanitizer.NewSanitizer(sanitized, logWriter))
+	logOutput := logging.NewSyncWriter(sanitizer.NewSanitizer(sanitized, logWriter))
+	logger.SetOutput(logOutput)
 
-	verbose := input.Source.Verbose
-	ls := logshim.NewLogShim(logger, logger, verbose)
+	cfg, err := config.FromOutRequest(input)
+	if err != nil {
+		uiPrinter.PrintErrorln(err)
+		os.Exit(1)
+	}
+
+	verbose := cfg.Verbose
+	ls := logging.NewLogger(logger, verbose)
 	ls.Debug("Verbose output enabled")
+	ls.Debug(fmt.Sprintf("Resolved config: %s", cfg))
+
+	var bundleDir string
+	if input.Params.Bundle != "" {
+		bundleDir, err = extractBundle(sourcesDir, input.Params.Bundle)
+		if err != nil {
+			uiPrinter.PrintErrorlnf("params.bundle could not be extracted: %s", err.Error())
+			os.Exit(1)
+		}
+
+		input.Params.MetadataFile = filepath.Join(bundleDir, bundle.MetadataFileName)
+		input.Params.FileGlob = filepath.Join(bundleDir, bundle.FilesDir, "*")
+	}
+
+	var m metadata.Metadata
+	if input.Params.MetadataFile == "" {
+		uiPrinter.PrintErrorlnf("params.metadata_file must be provided")
+		os.Exit(1)
+	}
+
+	metadataFilepath := filepath.Join(sourcesDir, input.Params.MetadataFile)
+	metadataBytes, err := ioutil.ReadFile(metadataFilepath)
+	if err != nil {
+		uiPrinter.PrintErrorlnf("params.metadata_file could not be read: %s", err.Error())
+		os.Exit(1)
+	}
 
-	var endpoint string
-	if input.Source.Endpoint != "" {
-		endpoint = input.Source.Endpoint
-	} else {
-		endpoint = pivnet.DefaultHost
+	err = yaml.Unmarshal(metadataBytes, &m)
+	if err != nil {
+		uiPrinter.PrintErrorlnf("params.metadata_file could not be parsed: %s", err.Error())
+		os.Exit(1)
 	}
 
-	apiToken := input.Source.APIToken
+	if bundleDir != "" {
+		m = bundle.ForRepublish(m, bundleDir)
+	}
+
+	deprecations, err := m.Validate()
+	if err != nil {
+		uiPrinter.PrintErrorlnf("params.metadata_file is invalid: %s", err.Error())
+		os.Exit(1)
+	}
+
+	for _, deprecation := range deprecations {
+		uiPrinter.PrintDeprecationln(deprecation)
+	}
+
+	if input.Params.VersionPrefix != "" || input.Params.VersionSuffix != "" {
+		version, err := metadata.AffixVersion(
+			m.Release.Version,
+			input.Params.VersionPrefix,
+			input.Params.VersionSuffix,
+		)
+		if err != nil {
+			uiPrinter.PrintErrorlnf("params.metadata_file is invalid: %s", err.Error())
+			os.Exit(1)
+		}
+		ls.Info(fmt.Sprintf("Applied version affixes to version: '%s', resulting in: '%s'", m.Release.Version, version))
+		m.Release.Version = version
+	}
+
+	if input.Params.DescriptionTemplate != "" {
+		descriptionFiles := map[string]string{}
+		for name, path := range input.Params.DescriptionFiles {
+			b, err := ioutil.ReadFile(filepath.Join(sourcesDir, path))
+			if err != nil {
+				uiPrinter.PrintErrorlnf("params.description_files.%s could not be read: %s", name, err.Error())
+				os.Exit(1)
+			}
+			descriptionFiles[name] = string(b)
+		}
+
+		description, err := metadata.RenderDescription(input.Params.DescriptionTemplate, m, descriptionFiles)
+		if err != nil {
+			uiPrinter.PrintErrorlnf("params.description_template could not be rendered: %s", err.Error())
+			os.Exit(1)
+		}
+
+		ls.Info("Rendered release description from description_template")
+		m.Release.Description = description
+	}
+
+	semverConverter := semver.NewSemverConverter(ls)
+
+	preflight := validator.NewPreflightValidator(input, m, sourcesDir, semverConverter)
+	err = preflight.Validate()
+	if err != nil {
+		uiPrinter.PrintErrorln(err)
+		os.Exit(1)
+	}
+
+	apiToken := cfg.APIToken
+
+	transport := gp.NewTransport(cfg.TransportConfig())
 
 	if len(apiToken) < 20 {
 		uiPrinter.PrintDeprecationln("The use of static Pivnet API tokens is deprecated and will be removed. Please see https://network.pivotal.io/docs/api#how-to-authenticate for details.")
@@ -96,83 +236,173 @@ func main() {
 
 	client := NewPivnetClientWithToken(
 		apiToken,
-		endpoint,
-		input.Source.SkipSSLValidation,
+		cfg.Endpoint,
+		cfg.SkipSSLValidation,
+		gp.NewSlugRedirectTransport(transport, input.Source.StrictSlug, ls),
 		useragent.UserAgent(version, "put", input.Source.ProductSlug),
 		ls,
 	)
 
 	federationToken, err := client.GetFederationToken(input.Source.ProductSlug)
 	if err != nil {
-		uiPrinter.PrintErrorlnf("Unable to generate Federation Token")
+		uiPrinter.PrintErrorlnf("Unable to generate Federation Token: %s", err)
 		os.Exit(1)
 	}
 
+	if federationToken.Bucket == "" || federationToken.Region == "" {
+		uiPrinter.PrintErrorlnf(
+			"Federation Token of product '%s' has no bucket or region - the product must be configured for uploads on Pivotal Network",
+			input.Source.ProductSlug,
+		)
+		os.Exit(1)
+	}
+
+	// The progress output of S3 transfers is written in whole lines, so that
+	// it does not tear the lines of the logger.
+	s3Stderr := logOutput.LineWriter()
+
+	// The uploads to every bucket are recorded in the metadata of the put.
+	stats := transferstats.NewStats()
+
 	s3Client := s3.NewClient(s3.NewClientConfig{
-		AccessKeyID:       federationToken.AccessKeyID,
-		SecretAccessKey:   federationToken.SecretAccessKey,
-		SessionToken:      federationToken.SessionToken,
+		CredentialsProvider: s3.NewFederationTokenProvider(
+			client,
+			input.Source.ProductSlug,
+			federationToken,
+		),
 		RegionName:        federationToken.Region,
 		Bucket:            federationToken.Bucket,
-		Stderr:            os.Stderr,
+		Stderr:            s3Stderr,
 		Logger:            ls,
-		SkipSSLValidation: input.Source.SkipSSLValidation,
+		SkipSSLValidation: cfg.SkipSSLValidation,
+		Transport:         transport,
+		StorageClass:      input.Params.StorageClass,
+		RetryBudget:       input.Params.S3RetryBudget,
+		UseDualStack:      input.Params.UseDualStack,
+		Stats:             stats,
 	})
 
 	prefixFetcher := uploader.NewPrefixFetcher(client, input.Source.ProductSlug)
 	filePrefix, err := prefixFetcher.GetPrefix()
 	if err != nil {
-		uiPrinter.PrintErrorlnf("Could not find product prefix")
+		uiPrinter.PrintErrorln(err)
 		os.Exit(1)
 	}
 
+	// The destination is discovered from Pivotal Network rather than
+	// configured, so it is logged to show where files are uploaded.
+	ls.Info(fmt.Sprintf(
+		"Uploading to bucket '%s' in region '%s' under prefix '%s', as configured for product '%s' on Pivotal Network",
+		federationToken.Bucket,
+		federationToken.Region,
+		filePrefix,
+		input.Source.ProductSlug,
+	))
+
 	uploaderClient := uploader.NewClient(uploader.Config{
-		FilepathPrefix: 	filePrefix,
-		SourcesDir:     	sourcesDir,
-		Transport:      	s3Client,
+		FilepathPrefix: filePrefix,
+		SourcesDir:     sourcesDir,
+		Transport:      s3Client,
 	})
 
-	globber := globs.NewGlobber(globs.GlobberConfig{
-		FileGlob:   input.Params.FileGlob,
-		SourcesDir: sourcesDir,
-		Logger:     ls,
-	})
+	targetClients := map[string]*uploader.Client{}
+	for name, target := range input.Source.S3Targets {
+		targetPrefix := target.Prefix
+		if targetPrefix == "" {
+			targetPrefix = filePrefix
+		}
+
+		targetClients[name] = uploader.NewClient(uploader.Config{
+			FilepathPrefix: targetPrefix,
+			SourcesDir:     sourcesDir,
+			Transport: s3.NewClient(s3.NewClientConfig{
+				CredentialsProvider: s3.NewStaticCredentialsProvider(
+					target.AccessKeyID,
+					target.SecretAccessKey,
+					"",
+				),
+				RegionName:        target.Region,
+				Bucket:            target.Bucket,
+				Stderr:            s3Stderr,
+				Logger:            ls,
+				SkipSSLValidation: cfg.SkipSSLValidation,
+				Transport:         transport,
+				StorageClass:      input.Params.StorageClass,
+				RetryBudget:       input.Params.S3RetryBudget,
+				UseDualStack:      input.Params.UseDualStack,
+				Stats:             stats,
+			}),
+		})
+	}
 
-	skipUpload := input.Params.FileGlob == ""
+	uploadRouter := uploader.NewRouter(uploaderClient, targetClients, m)
 
-	var m metadata.Metadata
-	if input.Params.MetadataFile == "" {
-		uiPrinter.PrintErrorlnf("params.metadata_file must be provided")
-		os.Exit(1)
+	publishLockExpiry := 2 * time.Hour
+	if input.Params.PublishLockExpiry > 0 {
+		publishLockExpiry = time.Duration(input.Params.PublishLockExpiry) * time.Second
 	}
-
-	metadataFilepath := filepath.Join(sourcesDir, input.Params.MetadataFile)
-	metadataBytes, err := ioutil.ReadFile(metadataFilepath)
+	publishLock, err := publishlock.NewLocker(publishlock.Config{
+		Store:        s3Client,
+		Key:          path.Join(filePrefix, "publish-locks", m.Release.Version+".lock"),
+		Owner:        publishLockOwner(),
+		Expiry:       publishLockExpiry,
+		Wait:         time.Duration(input.Params.PublishLockWait) * time.Second,
+		PollInterval: 5 * time.Second,
+		Logger:       ls,
+	})
 	if err != nil {
-		uiPrinter.PrintErrorlnf("params.metadata_file could not be read: %s", err.Error())
+		uiPrinter.PrintErrorln(err)
 		os.Exit(1)
 	}
 
-	err = yaml.Unmarshal(metadataBytes, &m)
-	if err != nil {
-		uiPrinter.PrintErrorlnf("params.metadata_file could not be parsed: %s", err.Error())
-		os.Exit(1)
-	}
+	uploadState := uploadstate.NewTracker(uploadstate.Config{
+		Store:  s3Client,
+		Key:    path.Join(filePrefix, "publish-state", m.Release.Version, uploadstate.FileName),
+		Logger: ls,
+	})
 
-	deprecations, err := m.Validate()
-	if err != nil {
-		uiPrinter.PrintErrorlnf("params.metadata_file is invalid: %s", err.Error())
-		os.Exit(1)
+	// Uploads are only recorded, and skipped if already recorded, when
+	// resuming, so a nil interface is passed to the uploader otherwise.
+	var uploadedFiles uploadedFilesState
+	if input.Params.Resume {
+		uploadedFiles = uploadState
 	}
 
-	for _, deprecation := range deprecations {
-		uiPrinter.PrintDeprecationln(deprecation)
-	}
+	// Symlinks are followed unless follow_symlinks is explicitly false.
+	followSymlinks := input.Params.FollowSymlinks == nil || *input.Params.FollowSymlinks
+
+	globber := globs.NewGlobber(globs.GlobberConfig{
+		FileGlob:     input.Params.FileGlob,
+		Exclusions:   input.Params.FileGlobExclusions,
+		SourcesDir:   sourcesDir,
+		SkipSymlinks: !followSymlinks,
+		Logger:       ls,
+	})
+
+	skipUpload := input.Params.FileGlob == ""
 
 	validation := validator.NewOutValidator(input)
-	semverConverter := semver.NewSemverConverter(ls)
 	sha256Summer := sha256sum.NewFileSummer()
-	md5summer := md5sum.NewFileSummer()
+
+	// MD5 is not FIPS-approved, so files are uploaded with only their SHA256.
+	var md5summer interface {
+		SumFile(filepath string) (string, error)
+	}
+	if !cfg.FIPS {
+		md5summer = md5sum.NewFileSummer()
+	}
+
+	checksumSummer := checksums.NewFileSummer(input.Source.ChecksumAlgorithms)
+	chunkManifestWriter := chunksum.NewManifestWriter(
+		input.Params.ChunkManifestChunkSize,
+		input.Params.ChunkManifestThreshold,
+	)
+
+	signer := gpgsign.NewSigner(input.Source.GPGPrivateKey, input.Source.GPGPassphrase)
+
+	includedFilesLister := includedfiles.NewLister(input.Params.AutoIncludedFiles)
+
+	scanner := scan.NewScanner(input.Params.ScanCommand, sourcesDir)
 
 	f := filter.NewFilter(ls)
 
@@ -188,18 +418,30 @@ func main() {
 	)
 
 	asyncTimeout := 1 * time.Hour
+	if input.Params.IngestionTimeout > 0 {
+		asyncTimeout = time.Duration(input.Params.IngestionTimeout) * time.Second
+	}
 	pollFrequency := 5 * time.Second
 	releaseUploader := release.NewReleaseUploader(
-		uploaderClient,
+		uploadRouter,
 		client,
 		ls,
 		sha256Summer,
 		md5summer,
+		chunkManifestWriter,
+		signer,
+		includedFilesLister,
+		sbom.NewDetector(),
+		checksumSummer,
 		m,
 		sourcesDir,
 		input.Source.ProductSlug,
+		input.Params.DocsURLTemplate,
+		input.Params.CleanupStagingObjects,
 		asyncTimeout,
 		pollFrequency,
+		input.Params.AttachConcurrency,
+		uploadedFiles,
 	)
 
 	releaseUserGroupsUpdater := release.NewUserGroupsUpdater(
@@ -252,6 +494,24 @@ func main() {
 		m,
 		sourcesDir,
 		input.Source.ProductSlug,
+		input.Source,
+	)
+
+	publishVerifier := release.NewPublishVerifier(
+		ls,
+		client,
+		sha256Summer,
+		m,
+		sourcesDir,
+		input.Source.ProductSlug,
+	)
+
+	releaseSnapshotter := release.NewReleaseSnapshotter(
+		ls,
+		client,
+		sourcesDir,
+		input.Params.SnapshotFile,
+		input.Source.ProductSlug,
 	)
 
 	outCmd := out.NewOutCommand(out.OutCommandConfig{
@@ -269,11 +529,18 @@ func main() {
 		ReleaseUpgradePathsAdder:     releaseUpgradePathsAdder,
 		UpgradePathSpecifiersCreator: upgradePathSpecifiersCreator,
 		Finalizer:                    releaseFinalizer,
+		PublishVerifier:              publishVerifier,
+		Snapshotter:                  releaseSnapshotter,
+		PublishLock:                  publishLock,
+		UploadState:                  uploadState,
+		Scanner:                      scanner,
+		TransferStats:                stats,
 		M:                            m,
 		SkipUpload:                   skipUpload,
 	})
 
 	response, err := outCmd.Run(input)
+	s3Stderr.Flush()
 	if err != nil {
 		uiPrinter.PrintErrorln(err)
 		os.Exit(1)
@@ -286,7 +553,7 @@ func main() {
 	}
 }
 
-func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bool, userAgent string, logger logger.Logger) *gp.Client {
+func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bool, transport http.RoundTripper, userAgent string, logger logger.Logger) *gp.Client {
 	clientConfig := pivnet.ClientConfig{
 		Host:              host,
 		Token:             apiToken,
@@ -296,6 +563,40 @@ func NewPivnetClientWithToken(apiToken string, host string, skipSSLValidation bo
 
 	return gp.NewClient(
 		clientConfig,
+		transport,
 		logger,
 	)
-}
\ No newline at end of file
+}
+
+// publishLockOwner describes the build running the put, from the build
+// metadata Concourse provides to resources, for the messages of other puts
+// waiting on its publish lock.
+func publishLockOwner() string {
+	if os.Getenv("BUILD_JOB_NAME") == "" {
+		return fmt.Sprintf("one-off build %s", os.Getenv("BUILD_ID"))
+	}
+
+	return fmt.Sprintf(
+		"%s/%s/%s #%s",
+		os.Getenv("BUILD_TEAM_NAME"),
+		os.Getenv("BUILD_PIPELINE_NAME"),
+		os.Getenv("BUILD_JOB_NAME"),
+		os.Getenv("BUILD_NAME"),
+	)
+}
+
+// extractBundle extracts the bundle into a new directory within the sources
+// directory and returns the path of that directory relative to it.
+func extractBundle(sourcesDir string, bundlePath string) (string, error) {
+	dir, err := ioutil.TempDir(sourcesDir, "bundle")
+	if err != nil {
+		return "", err
+	}
+
+	err = bundle.Extract(filepath.Join(sourcesDir, bundlePath), dir)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Rel(sourcesDir, dir)
+}
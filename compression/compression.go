@@ -0,0 +1,135 @@
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// Formats are the formats which downloaded files can be compressed with via
+// compress.
+var Formats = []string{
+	Gzip,
+	Zstd,
+}
+
+var extensions = map[string]string{
+	Gzip: ".gz",
+	Zstd: ".zst",
+}
+
+// Validate returns an error if the format is not supported.
+func Validate(format string) error {
+	if _, ok := extensions[format]; !ok {
+		return fmt.Errorf(
+			"compress format '%s' must be one of: ['%s']",
+			format,
+			strings.Join(Formats, "', '"),
+		)
+	}
+
+	return nil
+}
+
+type Compressor struct{}
+
+func NewCompressor() Compressor {
+	return Compressor{}
+}
+
+// Compress compresses the file at path into the same directory, with the
+// extension of the format appended to its name, and removes the file. It
+// returns the path of the compressed file.
+//
+// The aim is to reduce the size of the volume streamed between workers, so
+// gzip favours speed over ratio. zstd is run multithreaded with the zstd
+// binary, which must be on the PATH.
+func (c Compressor) Compress(format string, path string) (string, error) {
+	extension, ok := extensions[format]
+	if !ok {
+		return "", fmt.Errorf("unsupported compress format: '%s'", format)
+	}
+
+	compressedPath := path + extension
+
+	var err error
+	switch format {
+	case Gzip:
+		err = compressGzip(path, compressedPath)
+	case Zstd:
+		err = compressZstd(path, compressedPath)
+	}
+	if err != nil {
+		os.Remove(compressedPath)
+		return "", fmt.Errorf("could not compress '%s' with %s: %s", path, format, err)
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		return "", err
+	}
+
+	return compressedPath, nil
+}
+
+func compressGzip(path string, compressedPath string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(compressedPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	w, err := gzip.NewWriterLevel(dst, gzip.BestSpeed)
+	if err != nil {
+		return err
+	}
+	w.Name = info.Name()
+	w.ModTime = info.ModTime()
+
+	_, err = io.Copy(w, src)
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return dst.Close()
+}
+
+func compressZstd(path string, compressedPath string) error {
+	cmd := exec.Command("zstd", "--quiet", "--force", "--threads=0", "-o", compressedPath, path)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(output.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package compression_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/compression"
+)
+
+var _ = Describe("Compression", func() {
+	Describe("Validate", func() {
+		It("accepts the supported formats", func() {
+			for _, format := range compression.Formats {
+				Expect(compression.Validate(format)).To(Succeed())
+			}
+		})
+
+		It("rejects other formats", func() {
+			err := compression.Validate("bzip2")
+			Expect(err).To(MatchError("compress format 'bzip2' must be one of: ['gzip', 'zstd']"))
+		})
+	})
+
+	Describe("Compress", func() {
+		var (
+			dir  string
+			path string
+
+			compressor compression.Compressor
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "compression")
+			Expect(err).NotTo(HaveOccurred())
+
+			path = filepath.Join(dir, "some-file.pivotal")
+			err = ioutil.WriteFile(path, []byte("some-content"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			compressor = compression.NewCompressor()
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("compresses the file with gzip and removes it", func() {
+			compressedPath, err := compressor.Compress(compression.Gzip, path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(compressedPath).To(Equal(path + ".gz"))
+			Expect(path).NotTo(BeAnExistingFile())
+
+			f, err := os.Open(compressedPath)
+			Expect(err).NotTo(HaveOccurred())
+			defer f.Close()
+
+			r, err := gzip.NewReader(f)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Name).To(Equal("some-file.pivotal"))
+
+			contents, err := ioutil.ReadAll(r)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some-content"))
+		})
+
+		It("compresses the file with zstd and removes it", func() {
+			if _, err := exec.LookPath("zstd"); err != nil {
+				Skip("zstd is not on the PATH")
+			}
+
+			compressedPath, err := compressor.Compress(compression.Zstd, path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(compressedPath).To(Equal(path + ".zst"))
+			Expect(path).NotTo(BeAnExistingFile())
+
+			contents, err := exec.Command("zstd", "--decompress", "--stdout", compressedPath).Output()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("some-content"))
+		})
+
+		Context("when the file does not exist", func() {
+			It("returns an error without leaving a compressed file", func() {
+				Expect(os.Remove(path)).To(Succeed())
+
+				_, err := compressor.Compress(compression.Gzip, path)
+				Expect(err).To(MatchError(ContainSubstring("could not compress")))
+
+				Expect(path + ".gz").NotTo(BeAnExistingFile())
+			})
+		})
+
+		Context("when the format is not supported", func() {
+			It("returns an error and keeps the file", func() {
+				_, err := compressor.Compress("bzip2", path)
+				Expect(err).To(MatchError("unsupported compress format: 'bzip2'"))
+
+				Expect(path).To(BeAnExistingFile())
+			})
+		})
+	})
+})
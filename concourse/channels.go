@@ -0,0 +1,136 @@
+package concourse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Channel is a named combination of release types, availability and version
+// constraint, so that a policy for which releases to consume is defined once
+// and selected with a single channel option.
+type Channel struct {
+	// ReleaseTypes are the release types of the channel. Any release type is
+	// in the channel if none are provided.
+	ReleaseTypes      []string     `json:"release_types"`
+	Availability      Availability `json:"availability"`
+	VersionConstraint string       `json:"version_constraint"`
+}
+
+// DefaultChannels are the channels available unless redefined by a channels
+// file.
+var DefaultChannels = map[string]Channel{
+	"stable": {
+		ReleaseTypes: []string{
+			"Major Release",
+			"Minor Release",
+			"Maintenance Release",
+			"Security Release",
+		},
+		Availability: AvailabilityAllUsers,
+	},
+	"edge": {
+		Availability: AvailabilitySelectedUserGroups,
+	},
+}
+
+// LoadChannels returns the default channels along with those defined in the
+// JSON file at path, keyed by name, which replace any default channels of the
+// same name. Only the default channels are returned if path is empty.
+func LoadChannels(path string) (map[string]Channel, error) {
+	channels := map[string]Channel{}
+	for name, c := range DefaultChannels {
+		channels[name] = c
+	}
+
+	if path == "" {
+		return channels, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read channels file: %s", err.Error())
+	}
+
+	var defined map[string]Channel
+	err = json.Unmarshal(b, &defined)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse channels file '%s': %s", path, err.Error())
+	}
+
+	for name, c := range defined {
+		switch c.Availability {
+		case "", AvailabilityAllUsers, AvailabilitySelectedUserGroups, AvailabilityAdminsOnly:
+		default:
+			return nil, fmt.Errorf(
+				"availability of channel '%s' must be one of: ['%s', '%s', '%s']",
+				name,
+				AvailabilityAllUsers,
+				AvailabilitySelectedUserGroups,
+				AvailabilityAdminsOnly,
+			)
+		}
+
+		channels[name] = c
+	}
+
+	return channels, nil
+}
+
+// ApplyChannel applies the channel of the source, if any, to its release
+// type, availability and version constraint. Options given alongside the
+// channel narrow it: a release_type must be one of the release types of the
+// channel, an availability replaces that of the channel, and a
+// version_constraint must be satisfied as well as that of the channel.
+func (s *Source) ApplyChannel(channels map[string]Channel) error {
+	if s.Channel == "" {
+		return nil
+	}
+
+	c, ok := channels[s.Channel]
+	if !ok {
+		var names []string
+		for name := range channels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return fmt.Errorf("channel '%s' must be one of: ['%s']", s.Channel, strings.Join(names, "', '"))
+	}
+
+	if s.ReleaseType == "" {
+		s.ChannelReleaseTypes = c.ReleaseTypes
+	} else if len(c.ReleaseTypes) > 0 && !containsString(c.ReleaseTypes, s.ReleaseType) {
+		return fmt.Errorf(
+			"release_type '%s' must be one of the release types of channel '%s': ['%s']",
+			s.ReleaseType,
+			s.Channel,
+			strings.Join(c.ReleaseTypes, "', '"),
+		)
+	}
+
+	if s.Availability == "" {
+		s.Availability = c.Availability
+	}
+
+	if c.VersionConstraint != "" {
+		if s.VersionConstraint == "" {
+			s.VersionConstraint = c.VersionConstraint
+		} else {
+			s.VersionConstraint = c.VersionConstraint + ", " + s.VersionConstraint
+		}
+	}
+
+	return nil
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package concourse_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+var _ = Describe("Channels", func() {
+	Describe("LoadChannels", func() {
+		var (
+			channelsDir string
+		)
+
+		BeforeEach(func() {
+			var err error
+			channelsDir, err = ioutil.TempDir("", "pivnet-resource-channels")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(channelsDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		writeChannels := func(contents string) string {
+			path := filepath.Join(channelsDir, "channels.json")
+			err := ioutil.WriteFile(path, []byte(contents), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+			return path
+		}
+
+		It("returns the default channels if no file is provided", func() {
+			channels, err := concourse.LoadChannels("")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(channels).To(Equal(concourse.DefaultChannels))
+		})
+
+		It("adds the channels of the file, replacing default channels of the same name", func() {
+			path := writeChannels(`{
+				"stable": {"release_types": ["Major Release"], "version_constraint": ">= 2.0"},
+				"lts": {"availability": "all_users", "version_constraint": "~> 1.12"}
+			}`)
+
+			channels, err := concourse.LoadChannels(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(channels).To(Equal(map[string]concourse.Channel{
+				"stable": {ReleaseTypes: []string{"Major Release"}, VersionConstraint: ">= 2.0"},
+				"edge":   concourse.DefaultChannels["edge"],
+				"lts":    {Availability: concourse.AvailabilityAllUsers, VersionConstraint: "~> 1.12"},
+			}))
+		})
+
+		Context("when the file does not exist", func() {
+			It("returns an error", func() {
+				_, err := concourse.LoadChannels(filepath.Join(channelsDir, "missing.json"))
+				Expect(err).To(MatchError(ContainSubstring("failed to read channels file")))
+			})
+		})
+
+		Context("when the file is not valid JSON", func() {
+			It("returns an error", func() {
+				path := writeChannels("stable: {}")
+
+				_, err := concourse.LoadChannels(path)
+				Expect(err).To(MatchError(ContainSubstring("failed to parse channels file")))
+			})
+		})
+
+		Context("when a channel has an unknown availability", func() {
+			It("returns an error", func() {
+				path := writeChannels(`{"lts": {"availability": "everyone"}}`)
+
+				_, err := concourse.LoadChannels(path)
+				Expect(err).To(MatchError(ContainSubstring("availability of channel 'lts' must be one of")))
+			})
+		})
+	})
+
+	Describe("ApplyChannel", func() {
+		var (
+			channels map[string]concourse.Channel
+			source   concourse.Source
+		)
+
+		BeforeEach(func() {
+			channels = map[string]concourse.Channel{
+				"stable": {
+					ReleaseTypes:      []string{"Major Release", "Security Release"},
+					Availability:      concourse.AvailabilityAllUsers,
+					VersionConstraint: ">= 2.0",
+				},
+				"edge": {},
+			}
+
+			source = concourse.Source{Channel: "stable"}
+		})
+
+		It("applies the release types, availability and version constraint of the channel", func() {
+			err := source.ApplyChannel(channels)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(source.ChannelReleaseTypes).To(Equal([]string{"Major Release", "Security Release"}))
+			Expect(source.Availability).To(Equal(concourse.AvailabilityAllUsers))
+			Expect(source.VersionConstraint).To(Equal(">= 2.0"))
+		})
+
+		Context("when no channel is provided", func() {
+			BeforeEach(func() {
+				source.Channel = ""
+			})
+
+			It("leaves the source unchanged", func() {
+				err := source.ApplyChannel(channels)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(source).To(Equal(concourse.Source{}))
+			})
+		})
+
+		Context("when the channel is not known", func() {
+			BeforeEach(func() {
+				source.Channel = "nightly"
+			})
+
+			It("returns an error", func() {
+				err := source.ApplyChannel(channels)
+				Expect(err).To(MatchError("channel 'nightly' must be one of: ['edge', 'stable']"))
+			})
+		})
+
+		Context("when a release type is also provided", func() {
+			BeforeEach(func() {
+				source.ReleaseType = "Security Release"
+			})
+
+			It("narrows the channel to the release type", func() {
+				err := source.ApplyChannel(channels)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(source.ReleaseType).To(Equal("Security Release"))
+				Expect(source.ChannelReleaseTypes).To(BeEmpty())
+			})
+
+			Context("when the release type is not in the channel", func() {
+				BeforeEach(func() {
+					source.ReleaseType = "Alpha Release"
+				})
+
+				It("returns an error", func() {
+					err := source.ApplyChannel(channels)
+					Expect(err).To(MatchError(ContainSubstring("release_type 'Alpha Release' must be one of the release types of channel 'stable'")))
+				})
+			})
+		})
+
+		Context("when an availability is also provided", func() {
+			BeforeEach(func() {
+				source.Availability = concourse.AvailabilitySelectedUserGroups
+			})
+
+			It("replaces the availability of the channel", func() {
+				err := source.ApplyChannel(channels)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(source.Availability).To(Equal(concourse.AvailabilitySelectedUserGroups))
+			})
+		})
+
+		Context("when a version constraint is also provided", func() {
+			BeforeEach(func() {
+				source.VersionConstraint = "< 3.0"
+			})
+
+			It("requires both version constraints", func() {
+				err := source.ApplyChannel(channels)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(source.VersionConstraint).To(Equal(">= 2.0, < 3.0"))
+			})
+		})
+	})
+})
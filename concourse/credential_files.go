@@ -0,0 +1,71 @@
+package concourse
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// ReadCredentialFiles sets each credential of the source which is given as
+// the path of a file, e.g. a secret mounted from CredHub or Vault, to the
+// contents of that file with surrounding whitespace removed. Giving a
+// credential both inline and as a file is an error.
+func (s *Source) ReadCredentialFiles() error {
+	err := readCredentialFile("api_token", &s.APIToken, s.APITokenFile)
+	if err != nil {
+		return err
+	}
+
+	err = readCredentialFile("gpg_private_key", &s.GPGPrivateKey, s.GPGPrivateKeyFile)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range s.S3Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := s.S3Targets[name]
+		prefix := fmt.Sprintf("s3_targets.%s.", name)
+
+		err = readCredentialFile(prefix+"access_key_id", &target.AccessKeyID, target.AccessKeyIDFile)
+		if err != nil {
+			return err
+		}
+
+		err = readCredentialFile(prefix+"secret_access_key", &target.SecretAccessKey, target.SecretAccessKeyFile)
+		if err != nil {
+			return err
+		}
+
+		s.S3Targets[name] = target
+	}
+
+	return nil
+}
+
+func readCredentialFile(key string, value *string, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	if *value != "" {
+		return fmt.Errorf("only one of %s and %s_file may be provided", key, key)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s_file: %s", key, err.Error())
+	}
+
+	*value = strings.TrimSpace(string(b))
+	if *value == "" {
+		return fmt.Errorf("%s_file '%s' is empty", key, path)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package concourse_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+var _ = Describe("ReadCredentialFiles", func() {
+	var (
+		secretsDir string
+		source     concourse.Source
+	)
+
+	writeSecret := func(name string, contents string) string {
+		path := filepath.Join(secretsDir, name)
+		err := ioutil.WriteFile(path, []byte(contents), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		secretsDir, err = ioutil.TempDir("", "pivnet-resource-secrets")
+		Expect(err).NotTo(HaveOccurred())
+
+		source = concourse.Source{
+			APIToken: "some-api-token",
+			S3Targets: map[string]concourse.S3Target{
+				"docs": {
+					Bucket:          "some-bucket",
+					AccessKeyID:     "some-access-key-id",
+					SecretAccessKey: "some-secret-access-key",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(secretsDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("leaves inline credentials unchanged", func() {
+		err := source.ReadCredentialFiles()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(source.APIToken).To(Equal("some-api-token"))
+		Expect(source.S3Targets["docs"].AccessKeyID).To(Equal("some-access-key-id"))
+		Expect(source.S3Targets["docs"].SecretAccessKey).To(Equal("some-secret-access-key"))
+	})
+
+	Context("when credentials are given as files", func() {
+		BeforeEach(func() {
+			source.APIToken = ""
+			source.APITokenFile = writeSecret("api-token", "token-from-file\n")
+			source.GPGPrivateKeyFile = writeSecret("gpg-private-key", "key-from-file\n")
+
+			source.S3Targets["docs"] = concourse.S3Target{
+				Bucket:              "some-bucket",
+				AccessKeyIDFile:     writeSecret("access-key-id", "key-id-from-file\n"),
+				SecretAccessKeyFile: writeSecret("secret-access-key", "  secret-from-file  "),
+			}
+		})
+
+		It("reads each credential from its file without surrounding whitespace", func() {
+			err := source.ReadCredentialFiles()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(source.APIToken).To(Equal("token-from-file"))
+			Expect(source.GPGPrivateKey).To(Equal("key-from-file"))
+			Expect(source.S3Targets["docs"].Bucket).To(Equal("some-bucket"))
+			Expect(source.S3Targets["docs"].AccessKeyID).To(Equal("key-id-from-file"))
+			Expect(source.S3Targets["docs"].SecretAccessKey).To(Equal("secret-from-file"))
+		})
+
+		Context("when a file does not exist", func() {
+			BeforeEach(func() {
+				source.APITokenFile = filepath.Join(secretsDir, "missing")
+			})
+
+			It("returns an error", func() {
+				err := source.ReadCredentialFiles()
+				Expect(err).To(MatchError(ContainSubstring("failed to read api_token_file")))
+			})
+		})
+
+		Context("when a file is empty", func() {
+			BeforeEach(func() {
+				target := source.S3Targets["docs"]
+				target.SecretAccessKeyFile = writeSecret("empty", "\n")
+				source.S3Targets["docs"] = target
+			})
+
+			It("returns an error", func() {
+				err := source.ReadCredentialFiles()
+				Expect(err).To(MatchError(ContainSubstring("s3_targets.docs.secret_access_key_file")))
+				Expect(err).To(MatchError(ContainSubstring("is empty")))
+			})
+		})
+	})
+
+	Context("when a credential is given both inline and as a file", func() {
+		BeforeEach(func() {
+			source.APITokenFile = writeSecret("api-token", "token-from-file")
+		})
+
+		It("returns an error", func() {
+			err := source.ReadCredentialFiles()
+			Expect(err).To(MatchError("only one of api_token and api_token_file may be provided"))
+		})
+	})
+})
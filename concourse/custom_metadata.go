@@ -0,0 +1,20 @@
+package concourse
+
+import "sort"
+
+// CustomMetadata returns a "custom_metadata.<key>" entry for each of the
+// custom release metadata, ordered by key.
+func CustomMetadata(custom map[string]string) []Metadata {
+	keys := make([]string, 0, len(custom))
+	for k := range custom {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []Metadata
+	for _, k := range keys {
+		out = append(out, Metadata{Name: "custom_metadata." + k, Value: custom[k]})
+	}
+
+	return out
+}
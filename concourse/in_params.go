@@ -0,0 +1,51 @@
+package concourse
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// UnmarshalJSON accepts globs either as a list or as a map of each glob to
+// the subdirectory in which to place the files it matches. For the latter,
+// Globs is set to the globs of the map in order and GlobSubdirs to the map.
+func (p *InParams) UnmarshalJSON(b []byte) error {
+	type inParams InParams
+	aux := struct {
+		*inParams
+		Globs json.RawMessage `json:"globs"`
+	}{inParams: (*inParams)(p)}
+
+	err := json.Unmarshal(b, &aux)
+	if err != nil {
+		return err
+	}
+
+	raw := bytes.TrimSpace(aux.Globs)
+	if len(raw) == 0 {
+		return nil
+	}
+
+	if raw[0] != '{' {
+		return json.Unmarshal(raw, &p.Globs)
+	}
+
+	err = json.Unmarshal(raw, &p.GlobSubdirs)
+	if err != nil {
+		return err
+	}
+
+	p.Globs = make([]string, 0, len(p.GlobSubdirs))
+	for glob := range p.GlobSubdirs {
+		p.Globs = append(p.Globs, glob)
+	}
+	sort.Strings(p.Globs)
+
+	return nil
+}
+
+// SparseDownload returns whether only part of each product file is
+// downloaded, rather than the whole file.
+func (p InParams) SparseDownload() bool {
+	return p.HeadBytes > 0 || len(p.ZipMembers) > 0
+}
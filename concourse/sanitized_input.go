@@ -7,5 +7,19 @@ func SanitizedSource(source Source) map[string]string {
 		s[source.APIToken] = "***REDACTED-PIVNET_API_TOKEN***"
 	}
 
+	if source.GPGPrivateKey != "" {
+		s[source.GPGPrivateKey] = "***REDACTED-GPG_PRIVATE_KEY***"
+	}
+
+	if source.GPGPassphrase != "" {
+		s[source.GPGPassphrase] = "***REDACTED-GPG_PASSPHRASE***"
+	}
+
+	for _, target := range source.S3Targets {
+		if target.SecretAccessKey != "" {
+			s[target.SecretAccessKey] = "***REDACTED-AWS_SECRET_ACCESS_KEY***"
+		}
+	}
+
 	return s
 }
@@ -5,18 +5,99 @@ type SortBy string
 const (
 	SortByNone   SortBy = "none"
 	SortBySemver SortBy = "semver"
+
+	SortByReleaseDate SortBy = "release_date"
+)
+
+type Sample string
+
+const (
+	SampleNone  Sample = ""
+	SamplePatch Sample = "patch"
+	SampleMinor Sample = "minor"
+)
+
+// Availability is the least broad availability of the releases whose
+// versions check emits.
+type Availability string
+
+const (
+	AvailabilityAllUsers           Availability = "all_users"
+	AvailabilitySelectedUserGroups Availability = "selected_user_groups"
+	AvailabilityAdminsOnly         Availability = "admins_only"
+)
+
+// ReleaseDateLayout is the layout of the release dates of releases on Pivotal
+// Network, and of released_after.
+const ReleaseDateLayout = "2006-01-02"
+
+type EULAAction string
+
+const (
+	EULAActionAccept  EULAAction = "accept"
+	EULAActionPreview EULAAction = "preview"
 )
 
 type Source struct {
-	APIToken          string `json:"api_token"`
-	ProductSlug       string `json:"product_slug"`
-	ProductVersion    string `json:"product_version"`
-	Endpoint          string `json:"endpoint"`
-	ReleaseType       string `json:"release_type"`
-	SortBy            SortBy `json:"sort_by"`
-	SkipSSLValidation bool   `json:"skip_ssl_verification"`
-	CopyMetadata      bool   `json:"copy_metadata"`
-	Verbose           bool   `json:"verbose"`
+	APIToken                string       `json:"api_token"`
+	APITokenFile            string       `json:"api_token_file"`
+	ProductSlug             string       `json:"product_slug"`
+	ProductVersion          string       `json:"product_version"`
+	VersionConstraint       string       `json:"version_constraint"`
+	PinnedVersion           string       `json:"pinned_version"`
+	Endpoint                string       `json:"endpoint"`
+	ReleaseType             string       `json:"release_type"`
+	Channel                 string       `json:"channel"`
+	SortBy                  SortBy       `json:"sort_by"`
+	SkipSSLValidation       bool         `json:"skip_ssl_verification"`
+	CopyMetadata            bool         `json:"copy_metadata"`
+	Verbose                 bool         `json:"verbose"`
+	MaxIdleConns            int          `json:"max_idle_conns"`
+	MaxIdleConnsPerHost     int          `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost         int          `json:"max_conns_per_host"`
+	TLSHandshakeTimeout     int          `json:"tls_handshake_timeout"`
+	PreviousSlugs           []string     `json:"previous_slugs"`
+	StrictSlug              bool         `json:"strict_slug"`
+	MirrorEndpoints         []string     `json:"mirror_endpoints"`
+	OnePerReleaseType       bool         `json:"one_per_release_type"`
+	LocalSource             string       `json:"local_source"`
+	ChecksumAlgorithms      []string     `json:"checksum_algorithms"`
+	DownloadCacheDir        string       `json:"download_cache_dir"`
+	FIPS                    bool         `json:"fips"`
+	CheckCacheDir           string       `json:"check_cache_dir"`
+	Sample                  Sample       `json:"sample"`
+	CheckLimit              int          `json:"check_limit"`
+	WebhookFastPath         bool         `json:"webhook_fast_path"`
+	FastCheck               bool         `json:"fast_check"`
+	IncludeUnreleased       bool         `json:"include_unreleased"`
+	Availability            Availability `json:"availability"`
+	ReleasedAfter           string       `json:"released_after"`
+	VersionMetadata         bool         `json:"version_metadata"`
+	EULAFingerprint         bool         `json:"eula_fingerprint"`
+	VersionReleaseID        bool         `json:"version_release_id"`
+	ProductFilesFingerprint bool         `json:"product_files_fingerprint"`
+	GPGPrivateKey           string       `json:"gpg_private_key"`
+	GPGPrivateKeyFile       string       `json:"gpg_private_key_file"`
+	GPGPassphrase           string       `json:"gpg_passphrase"`
+
+	S3Targets map[string]S3Target `json:"s3_targets"`
+
+	// ChannelReleaseTypes are the release types of the channel, set by
+	// ApplyChannel unless release_type narrows the channel to one of them.
+	ChannelReleaseTypes []string `json:"-"`
+}
+
+// S3Target is a bucket, other than the Pivotal Network bucket, to which the
+// files of a file group can be uploaded.
+type S3Target struct {
+	Bucket          string `json:"bucket"`
+	Region          string `json:"region"`
+	Prefix          string `json:"prefix"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+
+	AccessKeyIDFile     string `json:"access_key_id_file"`
+	SecretAccessKeyFile string `json:"secret_access_key_file"`
 }
 
 type CheckRequest struct {
@@ -26,10 +107,32 @@ type CheckRequest struct {
 
 type Version struct {
 	ProductVersion string `json:"product_version"`
+	ReleaseType    string `json:"release_type,omitempty"`
+	EULASlug       string `json:"eula_slug,omitempty"`
+
+	// ReleaseID is the ID of the release, if the version_release_id of the
+	// source is set. Concourse versions are strings, so it is formatted as one.
+	ReleaseID string `json:"release_id,omitempty"`
+
+	// ProductFilesFingerprint is the fingerprint of the product files of the
+	// release, if the product_files_fingerprint of the source is set.
+	ProductFilesFingerprint string `json:"product_files_fingerprint,omitempty"`
 }
 
 type CheckResponse []Version
 
+type MigrateVersionsRequest struct {
+	Source   Source    `json:"source"`
+	Versions []Version `json:"versions"`
+}
+
+type VersionMigration struct {
+	From Version `json:"from"`
+	To   Version `json:"to"`
+}
+
+type MigrateVersionsResponse []VersionMigration
+
 type InRequest struct {
 	Source  Source   `json:"source"`
 	Version Version  `json:"version"`
@@ -37,8 +140,37 @@ type InRequest struct {
 }
 
 type InParams struct {
-	Globs  []string `json:"globs"`
-	Unpack bool     `json:"unpack"`
+	Globs               []string          `json:"globs"`
+	GlobSubdirs         map[string]string `json:"-"`
+	Unpack              bool              `json:"unpack"`
+	OutputEvents        bool              `json:"output_events"`
+	Bundle              bool              `json:"bundle"`
+	BOSHReleaseMetadata bool              `json:"bosh_release_metadata"`
+	AllowPartial        bool              `json:"allow_partial"`
+	EULAAction          EULAAction        `json:"eula_action"`
+	Accept              bool              `json:"accept"`
+	HeadBytes           int64             `json:"head_bytes"`
+	ZipMembers          []string          `json:"zip_members"`
+	Compress            string            `json:"compress"`
+	PlatformFilter      string            `json:"platform_filter"`
+	DryRun              bool              `json:"dry_run"`
+	Stemcell            *StemcellParams   `json:"stemcell"`
+}
+
+// StemcellParams select the stemcell, among the stemcell releases the release
+// depends on, which get downloads alongside the release.
+type StemcellParams struct {
+	// OS is the operating system of the stemcell, e.g. ubuntu-jammy, which
+	// is the suffix of the slug of its product, e.g. stemcells-ubuntu-jammy.
+	OS string `json:"os"`
+
+	// MinVersion is the lowest version of the stemcell which may be
+	// downloaded, e.g. 1.200, or any version if it is empty.
+	MinVersion string `json:"min_version"`
+
+	// Globs are the globs of the stemcell files to download, e.g. the
+	// stemcell for one IaaS. All files are downloaded if omitted.
+	Globs []string `json:"globs"`
 }
 
 type InResponse struct {
@@ -57,9 +189,36 @@ type OutRequest struct {
 }
 
 type OutParams struct {
-	FileGlob       string `json:"file_glob"`
-	MetadataFile   string `json:"metadata_file"`
-	Override       bool   `json:"override"`
+	FileGlob                        string            `json:"file_glob"`
+	FileGlobExclusions              []string          `json:"file_glob_exclusions"`
+	FollowSymlinks                  *bool             `json:"follow_symlinks"`
+	MetadataFile                    string            `json:"metadata_file"`
+	Override                        bool              `json:"override"`
+	RequireVersionGreaterThanLatest bool              `json:"require_version_greater_than_latest"`
+	StorageClass                    string            `json:"storage_class"`
+	DocsURLTemplate                 string            `json:"docs_url_template"`
+	ChunkManifestThreshold          int64             `json:"chunk_manifest_threshold"`
+	ChunkManifestChunkSize          int64             `json:"chunk_manifest_chunk_size"`
+	Bundle                          string            `json:"bundle"`
+	S3RetryBudget                   int               `json:"s3_retry_budget"`
+	UseDualStack                    bool              `json:"use_dualstack"`
+	VerifyPublish                   bool              `json:"verify_publish"`
+	SnapshotFile                    string            `json:"snapshot_file"`
+	CleanupStagingObjects           bool              `json:"cleanup_staging_objects"`
+	IngestionTimeout                int               `json:"ingestion_timeout"`
+	AttachConcurrency               int               `json:"attach_concurrency"`
+	ExpectedFileCount               int               `json:"expected_file_count"`
+	PublishLock                     bool              `json:"publish_lock"`
+	PublishLockExpiry               int               `json:"publish_lock_expiry"`
+	PublishLockWait                 int               `json:"publish_lock_wait"`
+	Resume                          bool              `json:"resume"`
+	ScanCommand                     string            `json:"scan_command"`
+	AutoIncludedFiles               bool              `json:"auto_included_files"`
+	VersionPrefix                   string            `json:"version_prefix"`
+	VersionSuffix                   string            `json:"version_suffix"`
+	RequiredFields                  []string          `json:"required_fields"`
+	DescriptionTemplate             string            `json:"description_template"`
+	DescriptionFiles                map[string]string `json:"description_files"`
 }
 
 type OutResponse struct {
@@ -0,0 +1,35 @@
+package concourse
+
+import (
+	"strconv"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+// ReleaseVersion returns the version of the release with the provided
+// product version. When version_metadata is set in the source the release
+// type and EULA slug of the release are included as additional fields of the
+// version, so that downstream jobs can gate on them. When eula_fingerprint is
+// set the EULA slug alone is included, so that swapping the EULA of a release
+// produces a new version. When version_release_id is set the ID of the
+// release is included, so that the release can be found even if its version
+// is edited or reused.
+func ReleaseVersion(productVersion string, release pivnet.Release, source Source) Version {
+	v := Version{
+		ProductVersion: productVersion,
+	}
+
+	if source.VersionMetadata {
+		v.ReleaseType = string(release.ReleaseType)
+	}
+
+	if (source.VersionMetadata || source.EULAFingerprint) && release.EULA != nil {
+		v.EULASlug = release.EULA.Slug
+	}
+
+	if source.VersionReleaseID && release.ID != 0 {
+		v.ReleaseID = strconv.Itoa(release.ID)
+	}
+
+	return v
+}
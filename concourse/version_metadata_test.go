@@ -0,0 +1,98 @@
+package concourse_test
+
+import (
+	pivnet "github.com/pivotal-cf/go-pivnet"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+var _ = Describe("ReleaseVersion", func() {
+	var (
+		release pivnet.Release
+		source  concourse.Source
+	)
+
+	BeforeEach(func() {
+		source = concourse.Source{}
+
+		release = pivnet.Release{
+			Version:     "1.2.3",
+			ReleaseType: "Security Release",
+			EULA:        &pivnet.EULA{Slug: "some-eula"},
+		}
+	})
+
+	It("returns only the product version", func() {
+		Expect(concourse.ReleaseVersion("1.2.3#some-fingerprint", release, source)).To(Equal(concourse.Version{
+			ProductVersion: "1.2.3#some-fingerprint",
+		}))
+	})
+
+	Context("when the release ID is requested", func() {
+		BeforeEach(func() {
+			source.VersionReleaseID = true
+			release.ID = 1234
+		})
+
+		It("includes the release ID as a string", func() {
+			Expect(concourse.ReleaseVersion("1.2.3#some-fingerprint", release, source)).To(Equal(concourse.Version{
+				ProductVersion: "1.2.3#some-fingerprint",
+				ReleaseID:      "1234",
+			}))
+		})
+	})
+
+	Context("when version metadata is requested", func() {
+		BeforeEach(func() {
+			source.VersionMetadata = true
+		})
+
+		It("includes the release type and EULA slug", func() {
+			Expect(concourse.ReleaseVersion("1.2.3#some-fingerprint", release, source)).To(Equal(concourse.Version{
+				ProductVersion: "1.2.3#some-fingerprint",
+				ReleaseType:    "Security Release",
+				EULASlug:       "some-eula",
+			}))
+		})
+
+		Context("when the release has no EULA", func() {
+			BeforeEach(func() {
+				release.EULA = nil
+			})
+
+			It("omits the EULA slug", func() {
+				Expect(concourse.ReleaseVersion("1.2.3", release, source)).To(Equal(concourse.Version{
+					ProductVersion: "1.2.3",
+					ReleaseType:    "Security Release",
+				}))
+			})
+		})
+	})
+
+	Context("when a EULA fingerprint is requested", func() {
+		BeforeEach(func() {
+			source.EULAFingerprint = true
+		})
+
+		It("includes only the EULA slug", func() {
+			Expect(concourse.ReleaseVersion("1.2.3#some-fingerprint", release, source)).To(Equal(concourse.Version{
+				ProductVersion: "1.2.3#some-fingerprint",
+				EULASlug:       "some-eula",
+			}))
+		})
+
+		Context("when the release has no EULA", func() {
+			BeforeEach(func() {
+				release.EULA = nil
+			})
+
+			It("returns only the product version", func() {
+				Expect(concourse.ReleaseVersion("1.2.3", release, source)).To(Equal(concourse.Version{
+					ProductVersion: "1.2.3",
+				}))
+			})
+		})
+	})
+})
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+)
+
+const (
+	EndpointEnvVar          = "PIVNET_RESOURCE_ENDPOINT"
+	SkipSSLValidationEnvVar = "PIVNET_RESOURCE_SKIP_SSL_VERIFICATION"
+	VerboseEnvVar           = "PIVNET_RESOURCE_VERBOSE"
+	ChannelsFileEnvVar      = "PIVNET_RESOURCE_CHANNELS_FILE"
+
+	redacted = "***REDACTED***"
+)
+
+// Config is the runtime configuration shared by check, in and out.
+// It is built from the request source and params, with environment
+// overrides and defaults applied.
+type Config struct {
+	APIToken            string              `json:"api_token"`
+	ProductSlug         string              `json:"product_slug"`
+	Endpoint            string              `json:"endpoint"`
+	SkipSSLValidation   bool                `json:"skip_ssl_verification"`
+	Verbose             bool                `json:"verbose"`
+	SortBy              concourse.SortBy    `json:"sort_by"`
+	MaxIdleConns        int                 `json:"max_idle_conns"`
+	MaxIdleConnsPerHost int                 `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int                 `json:"max_conns_per_host"`
+	TLSHandshakeTimeout time.Duration       `json:"tls_handshake_timeout"`
+	FIPS                bool                `json:"fips"`
+	Source              concourse.Source    `json:"-"`
+	InParams            concourse.InParams  `json:"in_params,omitempty"`
+	OutParams           concourse.OutParams `json:"out_params,omitempty"`
+}
+
+func FromCheckRequest(input concourse.CheckRequest) (Config, error) {
+	return newConfig(input.Source)
+}
+
+func FromInRequest(input concourse.InRequest) (Config, error) {
+	c, err := newConfig(input.Source)
+	if err != nil {
+		return Config{}, err
+	}
+
+	c.InParams = input.Params
+	return c, nil
+}
+
+func FromOutRequest(input concourse.OutRequest) (Config, error) {
+	c, err := newConfig(input.Source)
+	if err != nil {
+		return Config{}, err
+	}
+
+	c.OutParams = input.Params
+	return c, nil
+}
+
+// ApplyChannel applies the channel of the source, if any, as defined by the
+// channels file at ChannelsFileEnvVar or by default, so that check, in and
+// out select releases alike.
+func ApplyChannel(source *concourse.Source) error {
+	channels, err := concourse.LoadChannels(os.Getenv(ChannelsFileEnvVar))
+	if err != nil {
+		return err
+	}
+
+	return source.ApplyChannel(channels)
+}
+
+func newConfig(source concourse.Source) (Config, error) {
+	c := Config{
+		APIToken:            source.APIToken,
+		ProductSlug:         source.ProductSlug,
+		Endpoint:            source.Endpoint,
+		SkipSSLValidation:   source.SkipSSLValidation,
+		Verbose:             source.Verbose,
+		SortBy:              source.SortBy,
+		MaxIdleConns:        source.MaxIdleConns,
+		MaxIdleConnsPerHost: source.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     source.MaxConnsPerHost,
+		TLSHandshakeTimeout: time.Duration(source.TLSHandshakeTimeout) * time.Second,
+		FIPS:                source.FIPS,
+		Source:              source,
+	}
+
+	err := c.applyEnvOverrides()
+	if err != nil {
+		return Config{}, err
+	}
+
+	c.applyDefaults()
+
+	err = c.validate()
+	if err != nil {
+		return Config{}, err
+	}
+
+	return c, nil
+}
+
+func (c *Config) applyEnvOverrides() error {
+	if endpoint := os.Getenv(EndpointEnvVar); endpoint != "" {
+		c.Endpoint = endpoint
+	}
+
+	if v := os.Getenv(SkipSSLValidationEnvVar); v != "" {
+		skip, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean: %s", SkipSSLValidationEnvVar, err)
+		}
+		c.SkipSSLValidation = skip
+	}
+
+	if v := os.Getenv(VerboseEnvVar); v != "" {
+		verbose, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s must be a boolean: %s", VerboseEnvVar, err)
+		}
+		c.Verbose = verbose
+	}
+
+	return nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Endpoint == "" {
+		c.Endpoint = pivnet.DefaultHost
+	}
+
+	if c.SortBy == "" {
+		c.SortBy = concourse.SortByNone
+	}
+}
+
+func (c Config) validate() error {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%s must be a valid URL: '%s'", "endpoint", c.Endpoint)
+	}
+
+	if c.FIPS && c.SkipSSLValidation {
+		return fmt.Errorf("%s cannot be used with %s", "fips", "skip_ssl_verification")
+	}
+
+	switch c.SortBy {
+	case concourse.SortByNone, concourse.SortBySemver, concourse.SortByReleaseDate:
+	default:
+		return fmt.Errorf(
+			"%s must be one of: ['%s', '%s', '%s']",
+			"sort_by",
+			concourse.SortByNone,
+			concourse.SortBySemver,
+			concourse.SortByReleaseDate,
+		)
+	}
+
+	return nil
+}
+
+// TransportConfig returns the configuration for the shared HTTP transport.
+func (c Config) TransportConfig() gp.TransportConfig {
+	return gp.TransportConfig{
+		MaxIdleConns:        c.MaxIdleConns,
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.MaxConnsPerHost,
+		TLSHandshakeTimeout: c.TLSHandshakeTimeout,
+		SkipSSLValidation:   c.SkipSSLValidation,
+		FIPS:                c.FIPS,
+	}
+}
+
+// String returns the config as JSON with credentials redacted, so that it is
+// safe to log.
+func (c Config) String() string {
+	if c.APIToken != "" {
+		c.APIToken = redacted
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		// Untested as it is too hard to force json.Marshal to return an error
+		return fmt.Sprintf("unable to marshal config: %s", err)
+	}
+
+	return string(b)
+}
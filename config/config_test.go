@@ -0,0 +1,189 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/config"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		source concourse.Source
+	)
+
+	BeforeEach(func() {
+		source = concourse.Source{
+			APIToken:    "some-api-token",
+			ProductSlug: "some-product-slug",
+		}
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(config.EndpointEnvVar)
+		os.Unsetenv(config.SkipSSLValidationEnvVar)
+		os.Unsetenv(config.VerboseEnvVar)
+		os.Unsetenv(config.ChannelsFileEnvVar)
+	})
+
+	It("applies defaults", func() {
+		c, err := config.FromCheckRequest(concourse.CheckRequest{Source: source})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Endpoint).To(Equal(pivnet.DefaultHost))
+		Expect(c.SortBy).To(Equal(concourse.SortByNone))
+	})
+
+	It("converts the TLS handshake timeout to a duration", func() {
+		source.TLSHandshakeTimeout = 15
+
+		c, err := config.FromCheckRequest(concourse.CheckRequest{Source: source})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.TransportConfig().TLSHandshakeTimeout).To(Equal(15 * time.Second))
+	})
+
+	It("includes the params of the request", func() {
+		params := concourse.InParams{Globs: []string{"*.pivotal"}}
+
+		c, err := config.FromInRequest(concourse.InRequest{Source: source, Params: params})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.InParams).To(Equal(params))
+	})
+
+	Context("when environment overrides are set", func() {
+		BeforeEach(func() {
+			source.Endpoint = "https://source.example.com"
+
+			os.Setenv(config.EndpointEnvVar, "https://env.example.com")
+			os.Setenv(config.SkipSSLValidationEnvVar, "true")
+			os.Setenv(config.VerboseEnvVar, "true")
+		})
+
+		It("prefers the environment over the source", func() {
+			c, err := config.FromCheckRequest(concourse.CheckRequest{Source: source})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.Endpoint).To(Equal("https://env.example.com"))
+			Expect(c.SkipSSLValidation).To(BeTrue())
+			Expect(c.Verbose).To(BeTrue())
+		})
+
+		Context("when a boolean override cannot be parsed", func() {
+			BeforeEach(func() {
+				os.Setenv(config.VerboseEnvVar, "not-a-bool")
+			})
+
+			It("returns an error", func() {
+				_, err := config.FromCheckRequest(concourse.CheckRequest{Source: source})
+				Expect(err).To(MatchError(ContainSubstring(config.VerboseEnvVar)))
+			})
+		})
+	})
+
+	Context("when the endpoint is not a valid URL", func() {
+		BeforeEach(func() {
+			source.Endpoint = "not a url"
+		})
+
+		It("returns an error", func() {
+			_, err := config.FromCheckRequest(concourse.CheckRequest{Source: source})
+			Expect(err).To(MatchError(ContainSubstring("endpoint must be a valid URL")))
+		})
+	})
+
+	Context("when fips is set with skip_ssl_verification", func() {
+		BeforeEach(func() {
+			source.FIPS = true
+			source.SkipSSLValidation = true
+		})
+
+		It("returns an error", func() {
+			_, err := config.FromCheckRequest(concourse.CheckRequest{Source: source})
+			Expect(err).To(MatchError(ContainSubstring("fips cannot be used with skip_ssl_verification")))
+		})
+	})
+
+	Context("when sort_by is not a known value", func() {
+		BeforeEach(func() {
+			source.SortBy = "alphabetical"
+		})
+
+		It("returns an error", func() {
+			_, err := config.FromCheckRequest(concourse.CheckRequest{Source: source})
+			Expect(err).To(MatchError(ContainSubstring("sort_by must be one of")))
+		})
+	})
+
+	Describe("String", func() {
+		It("redacts the API token", func() {
+			c, err := config.FromCheckRequest(concourse.CheckRequest{Source: source})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(c.String()).NotTo(ContainSubstring("some-api-token"))
+			Expect(c.String()).To(ContainSubstring("***REDACTED***"))
+			Expect(c.String()).To(ContainSubstring("some-product-slug"))
+		})
+	})
+
+	Describe("ApplyChannel", func() {
+		BeforeEach(func() {
+			source.Channel = "stable"
+		})
+
+		It("applies the default channel of the source", func() {
+			err := config.ApplyChannel(&source)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(source.ChannelReleaseTypes).To(Equal(concourse.DefaultChannels["stable"].ReleaseTypes))
+			Expect(source.Availability).To(Equal(concourse.AvailabilityAllUsers))
+		})
+
+		Context("when a channels file is set", func() {
+			var tempDir string
+
+			BeforeEach(func() {
+				var err error
+				tempDir, err = ioutil.TempDir("", "pivnet-resource-config")
+				Expect(err).NotTo(HaveOccurred())
+
+				channelsFile := filepath.Join(tempDir, "channels.json")
+				err = ioutil.WriteFile(channelsFile, []byte(`{"stable": {"version_constraint": "~> 2.7"}}`), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+
+				os.Setenv(config.ChannelsFileEnvVar, channelsFile)
+			})
+
+			AfterEach(func() {
+				err := os.RemoveAll(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("applies the channel as defined in the file", func() {
+				err := config.ApplyChannel(&source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(source.ChannelReleaseTypes).To(BeEmpty())
+				Expect(source.VersionConstraint).To(Equal("~> 2.7"))
+			})
+		})
+
+		Context("when the channels file cannot be read", func() {
+			BeforeEach(func() {
+				os.Setenv(config.ChannelsFileEnvVar, "/path/to/missing/channels.json")
+			})
+
+			It("returns an error", func() {
+				err := config.ApplyChannel(&source)
+				Expect(err).To(MatchError(ContainSubstring("failed to read channels file")))
+			})
+		})
+	})
+})
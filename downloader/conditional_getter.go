@@ -0,0 +1,86 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pivotal-cf/pivnet-resource/filecache"
+)
+
+//go:generate counterfeiter --fake-name FakeURLClient . urlClient
+type urlClient interface {
+	ProductFileDownloadURL(productSlug string, releaseID int, productFileID int) (string, error)
+}
+
+// ConditionalGetter downloads product files from their signed download URLs
+// so that the download can be made conditional on the ETag and Last-Modified
+// headers of a previous download, which go-pivnet does not expose.
+type ConditionalGetter struct {
+	client     urlClient
+	httpClient *http.Client
+}
+
+func NewConditionalGetter(client urlClient, httpClient *http.Client) ConditionalGetter {
+	return ConditionalGetter{
+		client:     client,
+		httpClient: httpClient,
+	}
+}
+
+// Get writes the product file to writer and returns its validators and true,
+// unless it has not changed since the provided validators were recorded, in
+// which case nothing is written and it returns the provided validators and
+// false. Empty validators always download the product file.
+func (g ConditionalGetter) Get(
+	writer io.Writer,
+	productSlug string,
+	releaseID int,
+	productFileID int,
+	validators filecache.Validators,
+) (filecache.Validators, bool, error) {
+	url, err := g.client.ProductFileDownloadURL(productSlug, releaseID, productFileID)
+	if err != nil {
+		return filecache.Validators{}, false, err
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return filecache.Validators{}, false, err
+	}
+
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return filecache.Validators{}, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return validators, false, nil
+	case http.StatusOK:
+	default:
+		return filecache.Validators{}, false, fmt.Errorf(
+			"failed to download product file %d: unexpected status code %d",
+			productFileID,
+			resp.StatusCode,
+		)
+	}
+
+	_, err = io.Copy(writer, resp.Body)
+	if err != nil {
+		return filecache.Validators{}, false, err
+	}
+
+	return filecache.Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}, true, nil
+}
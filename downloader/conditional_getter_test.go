@@ -0,0 +1,136 @@
+package downloader_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/pivotal-cf/pivnet-resource/downloader"
+	"github.com/pivotal-cf/pivnet-resource/downloader/downloaderfakes"
+	"github.com/pivotal-cf/pivnet-resource/filecache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConditionalGetter", func() {
+	var (
+		fakeURLClient *downloaderfakes.FakeURLClient
+		server        *httptest.Server
+		requests      []*http.Request
+		status        int
+
+		getter downloader.ConditionalGetter
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		status = http.StatusOK
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+
+			w.Header().Set("ETag", `"some-etag"`)
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+			w.WriteHeader(status)
+
+			if status == http.StatusOK {
+				w.Write([]byte("some-content"))
+			}
+		}))
+
+		fakeURLClient = &downloaderfakes.FakeURLClient{}
+		fakeURLClient.ProductFileDownloadURLReturns(server.URL+"/some-signed-url", nil)
+
+		getter = downloader.NewConditionalGetter(fakeURLClient, http.DefaultClient)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("downloads the product file and returns its validators", func() {
+		var buf bytes.Buffer
+		validators, modified, err := getter.Get(&buf, "some-product-slug", 1234, 1337, filecache.Validators{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(modified).To(BeTrue())
+		Expect(buf.String()).To(Equal("some-content"))
+		Expect(validators).To(Equal(filecache.Validators{
+			ETag:         `"some-etag"`,
+			LastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+		}))
+
+		slug, releaseID, productFileID := fakeURLClient.ProductFileDownloadURLArgsForCall(0)
+		Expect(slug).To(Equal("some-product-slug"))
+		Expect(releaseID).To(Equal(1234))
+		Expect(productFileID).To(Equal(1337))
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].URL.Path).To(Equal("/some-signed-url"))
+		Expect(requests[0].Header.Get("If-None-Match")).To(BeEmpty())
+		Expect(requests[0].Header.Get("If-Modified-Since")).To(BeEmpty())
+	})
+
+	Context("when validators are provided", func() {
+		var validators filecache.Validators
+
+		BeforeEach(func() {
+			validators = filecache.Validators{
+				ETag:         `"some-old-etag"`,
+				LastModified: "Tue, 20 Oct 2015 07:28:00 GMT",
+			}
+		})
+
+		It("makes the download conditional on them", func() {
+			var buf bytes.Buffer
+			_, modified, err := getter.Get(&buf, "some-product-slug", 1234, 1337, validators)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified).To(BeTrue())
+
+			Expect(requests[0].Header.Get("If-None-Match")).To(Equal(`"some-old-etag"`))
+			Expect(requests[0].Header.Get("If-Modified-Since")).To(Equal("Tue, 20 Oct 2015 07:28:00 GMT"))
+		})
+
+		Context("when the product file has not changed", func() {
+			BeforeEach(func() {
+				status = http.StatusNotModified
+			})
+
+			It("writes nothing and returns the provided validators", func() {
+				var buf bytes.Buffer
+				returned, modified, err := getter.Get(&buf, "some-product-slug", 1234, 1337, validators)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(modified).To(BeFalse())
+				Expect(buf.Len()).To(Equal(0))
+				Expect(returned).To(Equal(validators))
+			})
+		})
+	})
+
+	Context("when the download returns an unexpected status", func() {
+		BeforeEach(func() {
+			status = http.StatusForbidden
+		})
+
+		It("returns an error", func() {
+			var buf bytes.Buffer
+			_, _, err := getter.Get(&buf, "some-product-slug", 1234, 1337, filecache.Validators{})
+			Expect(err).To(MatchError("failed to download product file 1337: unexpected status code 403"))
+		})
+	})
+
+	Context("when getting the download URL returns an error", func() {
+		BeforeEach(func() {
+			fakeURLClient.ProductFileDownloadURLReturns("", errors.New("some url error"))
+		})
+
+		It("returns the error", func() {
+			var buf bytes.Buffer
+			_, _, err := getter.Get(&buf, "some-product-slug", 1234, 1337, filecache.Validators{})
+			Expect(err).To(MatchError("some url error"))
+
+			Expect(requests).To(BeEmpty())
+		})
+	})
+})
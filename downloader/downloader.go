@@ -5,10 +5,22 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/events"
+	"github.com/pivotal-cf/pivnet-resource/filecache"
+	"github.com/pivotal-cf/pivnet-resource/filenames"
+)
+
+const (
+	progressInterval = 5 * time.Second
+
+	// maxAttempts is the number of times each product file is attempted
+	// before it is reported as failed.
+	maxAttempts = 3
+	retryDelay  = time.Second
 )
 
 //go:generate counterfeiter --fake-name FakeClient . client
@@ -16,11 +28,31 @@ type client interface {
 	DownloadProductFile(writer *os.File, productSlug string, releaseID int, productFileID int, progressWriter io.Writer) error
 }
 
+//go:generate counterfeiter --fake-name FakeEventEmitter . eventEmitter
+type eventEmitter interface {
+	Emit(event events.Event)
+}
+
+//go:generate counterfeiter --fake-name FakeCache . cache
+type cache interface {
+	Link(sha256 string, destination string) (bool, error)
+	LinkValidated(key string, destination string) (filecache.Validators, bool, error)
+	AddValidated(key string, validators filecache.Validators, path string) error
+}
+
+//go:generate counterfeiter --fake-name FakeConditionalGetter . conditionalGetter
+type conditionalGetter interface {
+	Get(writer io.Writer, productSlug string, releaseID int, productFileID int, validators filecache.Validators) (filecache.Validators, bool, error)
+}
+
 type Downloader struct {
-	client         client
-	downloadDir    string
-	logger         logger.Logger
-	progressWriter io.Writer
+	client            client
+	downloadDir       string
+	logger            logger.Logger
+	progressWriter    io.Writer
+	eventEmitter      eventEmitter
+	cache             cache
+	conditionalGetter conditionalGetter
 }
 
 func NewDownloader(
@@ -28,38 +60,89 @@ func NewDownloader(
 	downloadDir string,
 	logger logger.Logger,
 	progressWriter io.Writer,
+	eventEmitter eventEmitter,
+	cache cache,
+	conditionalGetter conditionalGetter,
 ) *Downloader {
 	return &Downloader{
-		client:         client,
-		downloadDir:    downloadDir,
-		logger:         logger,
-		progressWriter: progressWriter,
+		client:            client,
+		downloadDir:       downloadDir,
+		logger:            logger,
+		progressWriter:    progressWriter,
+		eventEmitter:      eventEmitter,
+		cache:             cache,
+		conditionalGetter: conditionalGetter,
 	}
 }
 
+// Download downloads each of the product files which is not in the cache,
+// retrying each file independently. A file which still fails after all of
+// its attempts does not prevent the remaining files from being downloaded;
+// its final error is returned keyed by product file ID along with the paths
+// of the files which were downloaded or linked from the cache.
+//
+// If a conditional getter is provided, product files without a SHA256, which
+// cannot be cached by it, are cached along with the validators of their
+// download, and are only downloaded again if they have changed.
 func (d Downloader) Download(
 	pfs []pivnet.ProductFile,
 	productSlug string,
 	releaseID int,
-) ([]string, error) {
+) ([]string, map[int]error, error) {
 	d.logger.Debug("Ensuring download directory exists")
 
 	err := os.MkdirAll(d.downloadDir, os.ModePerm)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	d.logger.Debug("Acquiring lock on download directory")
+	unlock, err := lockDir(d.downloadDir)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer unlock()
+
+	localNames := filenames.ForProductFiles(pfs)
 
 	var fileNames []string
+	failures := map[int]error{}
 	for _, pf := range pfs {
-		parts := strings.Split(pf.AWSObjectKey, "/")
-		fileName := parts[len(parts)-1]
+		downloadPath := filepath.Join(d.downloadDir, localNames[pf.ID])
 
-		downloadPath := filepath.Join(d.downloadDir, fileName)
+		linked, err := d.cache.Link(pf.SHA256, downloadPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if linked {
+			d.logger.Info(fmt.Sprintf(
+				"Linked: '%s' to file: '%s' from the download cache",
+				pf.Name,
+				downloadPath,
+			))
+			fileNames = append(fileNames, downloadPath)
+			continue
+		}
+
+		if d.conditionalGetter != nil && pf.SHA256 == "" {
+			err = d.downloadConditionally(pf, productSlug, releaseID, downloadPath)
+			if failure, ok := err.(downloadFailure); ok {
+				failures[pf.ID] = failure.err
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+
+			fileNames = append(fileNames, downloadPath)
+			continue
+		}
 
 		d.logger.Debug(fmt.Sprintf("Creating file: '%s'", downloadPath))
 		file, err := os.Create(downloadPath)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		d.logger.Info(fmt.Sprintf(
@@ -68,15 +151,208 @@ func (d Downloader) Download(
 			downloadPath,
 		))
 
-		err = d.client.DownloadProductFile(file, productSlug, releaseID, pf.ID, d.progressWriter)
+		d.eventEmitter.Emit(events.Event{
+			Type:       events.DownloadStarted,
+			File:       downloadPath,
+			TotalBytes: int64(pf.Size),
+		})
+
+		err = d.withRetries(pf, func() error {
+			return d.downloadFile(file, pf, productSlug, releaseID)
+		})
+
+		file.Close()
+
 		if err != nil {
-			d.logger.Info(fmt.Sprintf("Download failed: %s",
-				err.Error(),
-			))
-			return nil, err
+			failures[pf.ID] = err
+			os.Remove(downloadPath)
+			continue
 		}
+
 		fileNames = append(fileNames, downloadPath)
 	}
 
-	return fileNames, nil
+	return fileNames, failures, nil
+}
+
+// downloadFailure is the final error of a product file which failed to
+// download, as opposed to an error which prevents any further downloads.
+type downloadFailure struct {
+	err error
+}
+
+func (f downloadFailure) Error() string {
+	return f.err.Error()
+}
+
+// downloadConditionally places the cached copy of the product file at
+// downloadPath if it has not changed since it was cached, and downloads it
+// otherwise, caching the download along with its validators.
+func (d Downloader) downloadConditionally(
+	pf pivnet.ProductFile,
+	productSlug string,
+	releaseID int,
+	downloadPath string,
+) error {
+	key := fmt.Sprintf("%s/%d/%d", productSlug, releaseID, pf.ID)
+
+	validators, cached, err := d.cache.LinkValidated(key, downloadPath)
+	if err != nil {
+		return err
+	}
+
+	// The download is written alongside the cached copy, which is linked
+	// into the cache and so must not be written to.
+	tempPath := downloadPath + ".download"
+
+	d.logger.Debug(fmt.Sprintf("Creating file: '%s'", tempPath))
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempPath)
+
+	if cached {
+		d.logger.Info(fmt.Sprintf(
+			"Revalidating: '%s' from the download cache",
+			pf.Name,
+		))
+	} else {
+		d.logger.Info(fmt.Sprintf(
+			"Downloading: '%s' to file: '%s'",
+			pf.Name,
+			downloadPath,
+		))
+	}
+
+	d.eventEmitter.Emit(events.Event{
+		Type:       events.DownloadStarted,
+		File:       downloadPath,
+		TotalBytes: int64(pf.Size),
+	})
+
+	var (
+		newValidators filecache.Validators
+		modified      bool
+	)
+	err = d.withRetries(pf, func() error {
+		err := resetFile(file)
+		if err != nil {
+			return err
+		}
+
+		done := make(chan struct{})
+		go d.reportProgress(tempPath, int64(pf.Size), done)
+
+		newValidators, modified, err = d.conditionalGetter.Get(file, productSlug, releaseID, pf.ID, validators)
+		close(done)
+
+		return err
+	})
+
+	file.Close()
+
+	if err != nil {
+		os.Remove(downloadPath)
+		return downloadFailure{err: err}
+	}
+
+	if !modified {
+		d.logger.Info(fmt.Sprintf(
+			"Linked: '%s' to file: '%s' from the download cache as it is unchanged",
+			pf.Name,
+			downloadPath,
+		))
+		return nil
+	}
+
+	err = os.Rename(tempPath, downloadPath)
+	if err != nil {
+		return err
+	}
+
+	return d.cache.AddValidated(key, newValidators, downloadPath)
+}
+
+// withRetries makes up to maxAttempts attempts at downloading the product
+// file, returning the error of the final attempt if they all fail.
+func (d Downloader) withRetries(pf pivnet.ProductFile, attempt func() error) error {
+	for i := 1; ; i++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		d.logger.Info(fmt.Sprintf(
+			"Download of '%s' failed (attempt %d of %d): %s",
+			pf.Name,
+			i,
+			maxAttempts,
+			err.Error(),
+		))
+
+		if i == maxAttempts {
+			return err
+		}
+
+		time.Sleep(retryDelay)
+	}
+}
+
+// downloadFile makes a single attempt at downloading the product file,
+// discarding any partial download left by a previous attempt.
+func (d Downloader) downloadFile(
+	file *os.File,
+	pf pivnet.ProductFile,
+	productSlug string,
+	releaseID int,
+) error {
+	err := resetFile(file)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go d.reportProgress(file.Name(), int64(pf.Size), done)
+
+	err = d.client.DownloadProductFile(file, productSlug, releaseID, pf.ID, d.progressWriter)
+	close(done)
+
+	return err
+}
+
+func resetFile(file *os.File) error {
+	err := file.Truncate(0)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	return err
+}
+
+// reportProgress periodically emits the number of bytes written to
+// downloadPath until done is closed.
+func (d Downloader) reportProgress(downloadPath string, totalBytes int64, done <-chan struct{}) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(downloadPath)
+			if err != nil {
+				continue
+			}
+
+			d.eventEmitter.Emit(events.Event{
+				Type:       events.Progress,
+				File:       downloadPath,
+				Bytes:      info.Size(),
+				TotalBytes: totalBytes,
+			})
+		}
+	}
 }
@@ -2,16 +2,20 @@ package downloader_test
 
 import (
 	"errors"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"syscall"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
 	"github.com/pivotal-cf/pivnet-resource/downloader"
 	"github.com/pivotal-cf/pivnet-resource/downloader/downloaderfakes"
+	"github.com/pivotal-cf/pivnet-resource/events"
+	"github.com/pivotal-cf/pivnet-resource/filecache"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -19,14 +23,22 @@ import (
 
 var _ = Describe("Downloader", func() {
 	var (
-		fakeClient *downloaderfakes.FakeClient
-		d          *downloader.Downloader
-		dir        string
-		fakeLogger logger.Logger
+		fakeClient       *downloaderfakes.FakeClient
+		fakeEventEmitter *downloaderfakes.FakeEventEmitter
+		fakeCache        *downloaderfakes.FakeCache
+		fakeGetter       *downloaderfakes.FakeConditionalGetter
+		conditionalGet   bool
+		d                *downloader.Downloader
+		dir              string
+		fakeLogger       logger.Logger
 	)
 
 	BeforeEach(func() {
 		fakeClient = &downloaderfakes.FakeClient{}
+		fakeEventEmitter = &downloaderfakes.FakeEventEmitter{}
+		fakeCache = &downloaderfakes.FakeCache{}
+		fakeGetter = &downloaderfakes.FakeConditionalGetter{}
+		conditionalGet = false
 
 		logger := log.New(GinkgoWriter, "", log.LstdFlags)
 		fakeLogger = logshim.NewLogShim(logger, logger, true)
@@ -37,7 +49,11 @@ var _ = Describe("Downloader", func() {
 	})
 
 	JustBeforeEach(func() {
-		d = downloader.NewDownloader(fakeClient, dir, fakeLogger, GinkgoWriter)
+		if conditionalGet {
+			d = downloader.NewDownloader(fakeClient, dir, fakeLogger, GinkgoWriter, fakeEventEmitter, fakeCache, fakeGetter)
+		} else {
+			d = downloader.NewDownloader(fakeClient, dir, fakeLogger, GinkgoWriter, fakeEventEmitter, fakeCache, nil)
+		}
 	})
 
 	AfterEach(func() {
@@ -76,7 +92,7 @@ var _ = Describe("Downloader", func() {
 		})
 
 		It("downloads all of the product files", func() {
-			filepaths, err := d.Download(productFiles, productSlug, releaseID)
+			filepaths, failures, err := d.Download(productFiles, productSlug, releaseID)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(3))
@@ -103,38 +119,321 @@ var _ = Describe("Downloader", func() {
 			Expect(w).To(Equal(GinkgoWriter))
 
 			Expect(len(filepaths)).To(Equal(3))
+			Expect(failures).To(BeEmpty())
 
 			Expect(filepaths).Should(ContainElement(filepath.Join(dir, "file-0")))
 			Expect(filepaths).Should(ContainElement(filepath.Join(dir, "file-1")))
 			Expect(filepaths).Should(ContainElement(filepath.Join(dir, "file-2")))
 		})
 
+		Context("when a product file is in the cache", func() {
+			BeforeEach(func() {
+				productFiles[1].SHA256 = "some-sha256"
+
+				fakeCache.LinkStub = func(sha256 string, destination string) (bool, error) {
+					return sha256 == "some-sha256", nil
+				}
+			})
+
+			It("links the file from the cache instead of downloading it", func() {
+				filepaths, _, err := d.Download(productFiles, productSlug, releaseID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(2))
+				for i := 0; i < fakeClient.DownloadProductFileCallCount(); i++ {
+					_, _, _, productFileID, _ := fakeClient.DownloadProductFileArgsForCall(i)
+					Expect(productFileID).NotTo(Equal(productFiles[1].ID))
+				}
+
+				Expect(fakeCache.LinkCallCount()).To(Equal(3))
+				sha256, destination := fakeCache.LinkArgsForCall(1)
+				Expect(sha256).To(Equal("some-sha256"))
+				Expect(destination).To(Equal(filepath.Join(dir, "file-1")))
+
+				Expect(filepaths).To(Equal([]string{
+					filepath.Join(dir, "file-0"),
+					filepath.Join(dir, "file-1"),
+					filepath.Join(dir, "file-2"),
+				}))
+			})
+
+			Context("when linking the file returns an error", func() {
+				BeforeEach(func() {
+					fakeCache.LinkReturns(false, errors.New("some link error"))
+				})
+
+				It("returns the error", func() {
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).To(MatchError("some link error"))
+				})
+			})
+		})
+
+		Context("when product files share a name", func() {
+			BeforeEach(func() {
+				productFiles[1].AWSObjectKey = "bucket/other-path/file-0"
+			})
+
+			It("downloads them to names suffixed with the product file ID", func() {
+				filepaths, failures, err := d.Download(productFiles, productSlug, releaseID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failures).To(BeEmpty())
+
+				Expect(filepaths).To(Equal([]string{
+					filepath.Join(dir, "file-0-1337"),
+					filepath.Join(dir, "file-0-1234"),
+					filepath.Join(dir, "file-2"),
+				}))
+			})
+		})
+
+		It("emits a download started event for each product file", func() {
+			_, _, err := d.Download(productFiles, productSlug, releaseID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeEventEmitter.EmitCallCount()).To(Equal(3))
+
+			event := fakeEventEmitter.EmitArgsForCall(0)
+			Expect(event.Type).To(Equal(events.DownloadStarted))
+			Expect(event.File).To(Equal(filepath.Join(dir, "file-0")))
+		})
+
+		It("holds an exclusive lock on the download directory while downloading", func() {
+			var lockErr error
+			fakeClient.DownloadProductFileStub = func(*os.File, string, int, int, io.Writer) error {
+				f, err := os.Open(dir)
+				Expect(err).NotTo(HaveOccurred())
+				defer f.Close()
+
+				lockErr = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+				return nil
+			}
+
+			_, _, err := d.Download(productFiles, productSlug, releaseID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(lockErr).To(Equal(syscall.EWOULDBLOCK))
+		})
+
+		It("releases the lock on the download directory when done", func() {
+			_, _, err := d.Download(productFiles, productSlug, releaseID)
+			Expect(err).NotTo(HaveOccurred())
+
+			f, err := os.Open(dir)
+			Expect(err).NotTo(HaveOccurred())
+			defer f.Close()
+
+			err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		Context("when the pivnet client returns an error", func() {
+			var (
+				expectedErr error
+			)
+
+			BeforeEach(func() {
+				expectedErr = errors.New("download file error")
+			})
+
+			Context("when every attempt at a file fails", func() {
+				BeforeEach(func() {
+					fakeClient.DownloadProductFileStub = func(_ *os.File, _ string, _ int, productFileID int, _ io.Writer) error {
+						if productFileID == productFiles[1].ID {
+							return expectedErr
+						}
+						return nil
+					}
+				})
+
+				It("reports the file as failed and downloads the remaining files", func() {
+					filepaths, failures, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(failures).To(Equal(map[int]error{productFiles[1].ID: expectedErr}))
+					Expect(filepaths).To(Equal([]string{
+						filepath.Join(dir, "file-0"),
+						filepath.Join(dir, "file-2"),
+					}))
+
+					Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(5))
+					Expect(filepath.Join(dir, "file-1")).NotTo(BeAnExistingFile())
+				})
+			})
+
+			Context("when a later attempt succeeds", func() {
+				BeforeEach(func() {
+					attempts := 0
+					fakeClient.DownloadProductFileStub = func(file *os.File, _ string, _ int, _ int, _ io.Writer) error {
+						attempts++
+						if attempts == 1 {
+							_, err := file.WriteString("partial download")
+							Expect(err).NotTo(HaveOccurred())
+							return expectedErr
+						}
+						return nil
+					}
+				})
+
+				It("discards the partial download and succeeds", func() {
+					filepaths, failures, err := d.Download(productFiles[:1], productSlug, releaseID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(failures).To(BeEmpty())
+					Expect(filepaths).To(Equal([]string{filepath.Join(dir, "file-0")}))
+					Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(2))
+
+					contents, err := ioutil.ReadFile(filepath.Join(dir, "file-0"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(contents).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("when a conditional getter is provided", func() {
+			var downloadPath string
+
 			BeforeEach(func() {
-				productFiles = []pivnet.ProductFile{
-					{
-						Name:         "pf-0",
-						AWSObjectKey: "bucket/path/file-0",
-					},
+				conditionalGet = true
+
+				productFiles = productFiles[:1]
+				downloadPath = filepath.Join(dir, "file-0")
+
+				fakeGetter.GetStub = func(w io.Writer, _ string, _ int, _ int, _ filecache.Validators) (filecache.Validators, bool, error) {
+					_, err := w.Write([]byte("some-new-content"))
+					Expect(err).NotTo(HaveOccurred())
+
+					return filecache.Validators{ETag: `"some-new-etag"`}, true, nil
 				}
 			})
 
-			Context("when the pivnet client returns other errors", func() {
-				var (
-					expectedErr error
-				)
+			It("downloads the file with the getter and caches it with its validators", func() {
+				filepaths, failures, err := d.Download(productFiles, productSlug, releaseID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(failures).To(BeEmpty())
+				Expect(filepaths).To(Equal([]string{downloadPath}))
+
+				Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(0))
+
+				Expect(fakeCache.LinkValidatedCallCount()).To(Equal(1))
+				key, destination := fakeCache.LinkValidatedArgsForCall(0)
+				Expect(key).To(Equal("some-product-slug/1234/1337"))
+				Expect(destination).To(Equal(downloadPath))
+
+				Expect(fakeGetter.GetCallCount()).To(Equal(1))
+				_, slug, relID, productFileID, validators := fakeGetter.GetArgsForCall(0)
+				Expect(slug).To(Equal(productSlug))
+				Expect(relID).To(Equal(releaseID))
+				Expect(productFileID).To(Equal(1337))
+				Expect(validators).To(Equal(filecache.Validators{}))
+
+				contents, err := ioutil.ReadFile(downloadPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("some-new-content"))
+				Expect(downloadPath + ".download").NotTo(BeAnExistingFile())
+
+				Expect(fakeCache.AddValidatedCallCount()).To(Equal(1))
+				key, validators, path := fakeCache.AddValidatedArgsForCall(0)
+				Expect(key).To(Equal("some-product-slug/1234/1337"))
+				Expect(validators).To(Equal(filecache.Validators{ETag: `"some-new-etag"`}))
+				Expect(path).To(Equal(downloadPath))
+			})
 
+			Context("when the file is cached", func() {
 				BeforeEach(func() {
-					expectedErr = errors.New("download file error")
-					fakeClient.DownloadProductFileReturns(expectedErr)
+					fakeCache.LinkValidatedStub = func(key string, destination string) (filecache.Validators, bool, error) {
+						err := ioutil.WriteFile(destination, []byte("some-cached-content"), os.ModePerm)
+						Expect(err).NotTo(HaveOccurred())
+
+						return filecache.Validators{ETag: `"some-etag"`}, true, nil
+					}
+				})
+
+				Context("when the file has not changed", func() {
+					BeforeEach(func() {
+						fakeGetter.GetStub = nil
+						fakeGetter.GetReturns(filecache.Validators{ETag: `"some-etag"`}, false, nil)
+					})
+
+					It("keeps the cached file without caching it again", func() {
+						filepaths, failures, err := d.Download(productFiles, productSlug, releaseID)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(failures).To(BeEmpty())
+						Expect(filepaths).To(Equal([]string{downloadPath}))
+
+						_, _, _, _, validators := fakeGetter.GetArgsForCall(0)
+						Expect(validators).To(Equal(filecache.Validators{ETag: `"some-etag"`}))
+
+						contents, err := ioutil.ReadFile(downloadPath)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(contents)).To(Equal("some-cached-content"))
+						Expect(downloadPath + ".download").NotTo(BeAnExistingFile())
+
+						Expect(fakeCache.AddValidatedCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the file has changed", func() {
+					It("replaces the cached file with the download", func() {
+						_, _, err := d.Download(productFiles, productSlug, releaseID)
+						Expect(err).NotTo(HaveOccurred())
+
+						contents, err := ioutil.ReadFile(downloadPath)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(contents)).To(Equal("some-new-content"))
+
+						Expect(fakeCache.AddValidatedCallCount()).To(Equal(1))
+						_, validators, _ := fakeCache.AddValidatedArgsForCall(0)
+						Expect(validators).To(Equal(filecache.Validators{ETag: `"some-new-etag"`}))
+					})
+				})
+
+				Context("when every attempt at the file fails", func() {
+					BeforeEach(func() {
+						fakeGetter.GetStub = nil
+						fakeGetter.GetReturns(filecache.Validators{}, false, errors.New("some download error"))
+					})
+
+					It("reports the file as failed and removes the cached copy", func() {
+						filepaths, failures, err := d.Download(productFiles, productSlug, releaseID)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(filepaths).To(BeEmpty())
+						Expect(failures).To(Equal(map[int]error{1337: errors.New("some download error")}))
+
+						Expect(fakeGetter.GetCallCount()).To(Equal(3))
+						for i := 0; i < 3; i++ {
+							_, _, _, _, validators := fakeGetter.GetArgsForCall(i)
+							Expect(validators).To(Equal(filecache.Validators{ETag: `"some-etag"`}))
+						}
+
+						Expect(downloadPath).NotTo(BeAnExistingFile())
+						Expect(downloadPath + ".download").NotTo(BeAnExistingFile())
+					})
+				})
+			})
+
+			Context("when the product file has a SHA256", func() {
+				BeforeEach(func() {
+					productFiles[0].SHA256 = "some-sha256"
 				})
 
-				It("raises an error", func() {
-					_, err := d.Download(productFiles, productSlug, releaseID)
+				It("downloads the file with the pivnet client", func() {
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).NotTo(HaveOccurred())
 
-					Expect(err).Should(HaveOccurred())
-					Expect(err).To(Equal(expectedErr))
 					Expect(fakeClient.DownloadProductFileCallCount()).To(Equal(1))
+					Expect(fakeGetter.GetCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when looking up the cached file returns an error", func() {
+				BeforeEach(func() {
+					fakeCache.LinkValidatedReturns(filecache.Validators{}, false, errors.New("some cache error"))
+				})
+
+				It("returns the error", func() {
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
+					Expect(err).To(MatchError("some cache error"))
 				})
 			})
 		})
@@ -145,7 +444,7 @@ var _ = Describe("Downloader", func() {
 			})
 
 			It("creates the directory", func() {
-				_, err := d.Download(productFiles, productSlug, releaseID)
+				_, _, err := d.Download(productFiles, productSlug, releaseID)
 				Expect(err).NotTo(HaveOccurred())
 
 				_, err = os.Open(dir)
@@ -158,7 +457,7 @@ var _ = Describe("Downloader", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := d.Download(productFiles, productSlug, releaseID)
+					_, _, err := d.Download(productFiles, productSlug, releaseID)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -170,7 +469,7 @@ var _ = Describe("Downloader", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := d.Download(productFiles, productSlug, releaseID)
+				_, _, err := d.Download(productFiles, productSlug, releaseID)
 				Expect(err).To(HaveOccurred())
 			})
 		})
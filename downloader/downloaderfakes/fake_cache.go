@@ -0,0 +1,174 @@
+// This file was generated by counterfeiter
+package downloaderfakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/pivnet-resource/filecache"
+)
+
+type FakeCache struct {
+	LinkStub        func(sha256 string, destination string) (bool, error)
+	linkMutex       sync.RWMutex
+	linkArgsForCall []struct {
+		sha256      string
+		destination string
+	}
+	linkReturns struct {
+		result1 bool
+		result2 error
+	}
+	LinkValidatedStub        func(key string, destination string) (filecache.Validators, bool, error)
+	linkValidatedMutex       sync.RWMutex
+	linkValidatedArgsForCall []struct {
+		key         string
+		destination string
+	}
+	linkValidatedReturns struct {
+		result1 filecache.Validators
+		result2 bool
+		result3 error
+	}
+	AddValidatedStub        func(key string, validators filecache.Validators, path string) error
+	addValidatedMutex       sync.RWMutex
+	addValidatedArgsForCall []struct {
+		key        string
+		validators filecache.Validators
+		path       string
+	}
+	addValidatedReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCache) Link(sha256 string, destination string) (bool, error) {
+	fake.linkMutex.Lock()
+	fake.linkArgsForCall = append(fake.linkArgsForCall, struct {
+		sha256      string
+		destination string
+	}{sha256, destination})
+	fake.recordInvocation("Link", []interface{}{sha256, destination})
+	fake.linkMutex.Unlock()
+	if fake.LinkStub != nil {
+		return fake.LinkStub(sha256, destination)
+	} else {
+		return fake.linkReturns.result1, fake.linkReturns.result2
+	}
+}
+
+func (fake *FakeCache) LinkCallCount() int {
+	fake.linkMutex.RLock()
+	defer fake.linkMutex.RUnlock()
+	return len(fake.linkArgsForCall)
+}
+
+func (fake *FakeCache) LinkArgsForCall(i int) (string, string) {
+	fake.linkMutex.RLock()
+	defer fake.linkMutex.RUnlock()
+	return fake.linkArgsForCall[i].sha256, fake.linkArgsForCall[i].destination
+}
+
+func (fake *FakeCache) LinkReturns(result1 bool, result2 error) {
+	fake.LinkStub = nil
+	fake.linkReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCache) LinkValidated(key string, destination string) (filecache.Validators, bool, error) {
+	fake.linkValidatedMutex.Lock()
+	fake.linkValidatedArgsForCall = append(fake.linkValidatedArgsForCall, struct {
+		key         string
+		destination string
+	}{key, destination})
+	fake.recordInvocation("LinkValidated", []interface{}{key, destination})
+	fake.linkValidatedMutex.Unlock()
+	if fake.LinkValidatedStub != nil {
+		return fake.LinkValidatedStub(key, destination)
+	} else {
+		return fake.linkValidatedReturns.result1, fake.linkValidatedReturns.result2, fake.linkValidatedReturns.result3
+	}
+}
+
+func (fake *FakeCache) LinkValidatedCallCount() int {
+	fake.linkValidatedMutex.RLock()
+	defer fake.linkValidatedMutex.RUnlock()
+	return len(fake.linkValidatedArgsForCall)
+}
+
+func (fake *FakeCache) LinkValidatedArgsForCall(i int) (string, string) {
+	fake.linkValidatedMutex.RLock()
+	defer fake.linkValidatedMutex.RUnlock()
+	return fake.linkValidatedArgsForCall[i].key, fake.linkValidatedArgsForCall[i].destination
+}
+
+func (fake *FakeCache) LinkValidatedReturns(result1 filecache.Validators, result2 bool, result3 error) {
+	fake.LinkValidatedStub = nil
+	fake.linkValidatedReturns = struct {
+		result1 filecache.Validators
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeCache) AddValidated(key string, validators filecache.Validators, path string) error {
+	fake.addValidatedMutex.Lock()
+	fake.addValidatedArgsForCall = append(fake.addValidatedArgsForCall, struct {
+		key        string
+		validators filecache.Validators
+		path       string
+	}{key, validators, path})
+	fake.recordInvocation("AddValidated", []interface{}{key, validators, path})
+	fake.addValidatedMutex.Unlock()
+	if fake.AddValidatedStub != nil {
+		return fake.AddValidatedStub(key, validators, path)
+	} else {
+		return fake.addValidatedReturns.result1
+	}
+}
+
+func (fake *FakeCache) AddValidatedCallCount() int {
+	fake.addValidatedMutex.RLock()
+	defer fake.addValidatedMutex.RUnlock()
+	return len(fake.addValidatedArgsForCall)
+}
+
+func (fake *FakeCache) AddValidatedArgsForCall(i int) (string, filecache.Validators, string) {
+	fake.addValidatedMutex.RLock()
+	defer fake.addValidatedMutex.RUnlock()
+	return fake.addValidatedArgsForCall[i].key, fake.addValidatedArgsForCall[i].validators, fake.addValidatedArgsForCall[i].path
+}
+
+func (fake *FakeCache) AddValidatedReturns(result1 error) {
+	fake.AddValidatedStub = nil
+	fake.addValidatedReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCache) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.linkMutex.RLock()
+	defer fake.linkMutex.RUnlock()
+	fake.linkValidatedMutex.RLock()
+	defer fake.linkValidatedMutex.RUnlock()
+	fake.addValidatedMutex.RLock()
+	defer fake.addValidatedMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCache) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,87 @@
+// This file was generated by counterfeiter
+package downloaderfakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/pivotal-cf/pivnet-resource/filecache"
+)
+
+type FakeConditionalGetter struct {
+	GetStub        func(writer io.Writer, productSlug string, releaseID int, productFileID int, validators filecache.Validators) (filecache.Validators, bool, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		writer        io.Writer
+		productSlug   string
+		releaseID     int
+		productFileID int
+		validators    filecache.Validators
+	}
+	getReturns struct {
+		result1 filecache.Validators
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeConditionalGetter) Get(writer io.Writer, productSlug string, releaseID int, productFileID int, validators filecache.Validators) (filecache.Validators, bool, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		writer        io.Writer
+		productSlug   string
+		releaseID     int
+		productFileID int
+		validators    filecache.Validators
+	}{writer, productSlug, releaseID, productFileID, validators})
+	fake.recordInvocation("Get", []interface{}{writer, productSlug, releaseID, productFileID, validators})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(writer, productSlug, releaseID, productFileID, validators)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2, fake.getReturns.result3
+	}
+}
+
+func (fake *FakeConditionalGetter) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeConditionalGetter) GetArgsForCall(i int) (io.Writer, string, int, int, filecache.Validators) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].writer, fake.getArgsForCall[i].productSlug, fake.getArgsForCall[i].releaseID, fake.getArgsForCall[i].productFileID, fake.getArgsForCall[i].validators
+}
+
+func (fake *FakeConditionalGetter) GetReturns(result1 filecache.Validators, result2 bool, result3 error) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 filecache.Validators
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeConditionalGetter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeConditionalGetter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,62 @@
+// This file was generated by counterfeiter
+package downloaderfakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/pivnet-resource/events"
+)
+
+type FakeEventEmitter struct {
+	EmitStub        func(event events.Event)
+	emitMutex       sync.RWMutex
+	emitArgsForCall []struct {
+		event events.Event
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEventEmitter) Emit(event events.Event) {
+	fake.emitMutex.Lock()
+	fake.emitArgsForCall = append(fake.emitArgsForCall, struct {
+		event events.Event
+	}{event})
+	fake.recordInvocation("Emit", []interface{}{event})
+	fake.emitMutex.Unlock()
+	if fake.EmitStub != nil {
+		fake.EmitStub(event)
+	}
+}
+
+func (fake *FakeEventEmitter) EmitCallCount() int {
+	fake.emitMutex.RLock()
+	defer fake.emitMutex.RUnlock()
+	return len(fake.emitArgsForCall)
+}
+
+func (fake *FakeEventEmitter) EmitArgsForCall(i int) events.Event {
+	fake.emitMutex.RLock()
+	defer fake.emitMutex.RUnlock()
+	return fake.emitArgsForCall[i].event
+}
+
+func (fake *FakeEventEmitter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.emitMutex.RLock()
+	defer fake.emitMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeEventEmitter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,78 @@
+// This file was generated by counterfeiter
+package downloaderfakes
+
+import (
+	"sync"
+)
+
+type FakeURLClient struct {
+	ProductFileDownloadURLStub        func(productSlug string, releaseID int, productFileID int) (string, error)
+	productFileDownloadURLMutex       sync.RWMutex
+	productFileDownloadURLArgsForCall []struct {
+		productSlug   string
+		releaseID     int
+		productFileID int
+	}
+	productFileDownloadURLReturns struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeURLClient) ProductFileDownloadURL(productSlug string, releaseID int, productFileID int) (string, error) {
+	fake.productFileDownloadURLMutex.Lock()
+	fake.productFileDownloadURLArgsForCall = append(fake.productFileDownloadURLArgsForCall, struct {
+		productSlug   string
+		releaseID     int
+		productFileID int
+	}{productSlug, releaseID, productFileID})
+	fake.recordInvocation("ProductFileDownloadURL", []interface{}{productSlug, releaseID, productFileID})
+	fake.productFileDownloadURLMutex.Unlock()
+	if fake.ProductFileDownloadURLStub != nil {
+		return fake.ProductFileDownloadURLStub(productSlug, releaseID, productFileID)
+	} else {
+		return fake.productFileDownloadURLReturns.result1, fake.productFileDownloadURLReturns.result2
+	}
+}
+
+func (fake *FakeURLClient) ProductFileDownloadURLCallCount() int {
+	fake.productFileDownloadURLMutex.RLock()
+	defer fake.productFileDownloadURLMutex.RUnlock()
+	return len(fake.productFileDownloadURLArgsForCall)
+}
+
+func (fake *FakeURLClient) ProductFileDownloadURLArgsForCall(i int) (string, int, int) {
+	fake.productFileDownloadURLMutex.RLock()
+	defer fake.productFileDownloadURLMutex.RUnlock()
+	return fake.productFileDownloadURLArgsForCall[i].productSlug, fake.productFileDownloadURLArgsForCall[i].releaseID, fake.productFileDownloadURLArgsForCall[i].productFileID
+}
+
+func (fake *FakeURLClient) ProductFileDownloadURLReturns(result1 string, result2 error) {
+	fake.ProductFileDownloadURLStub = nil
+	fake.productFileDownloadURLReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeURLClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.productFileDownloadURLMutex.RLock()
+	defer fake.productFileDownloadURLMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeURLClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
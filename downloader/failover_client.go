@@ -0,0 +1,119 @@
+package downloader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+	"github.com/pivotal-cf/pivnet-resource/logging"
+)
+
+// Host is an API host from which product files can be downloaded.
+type Host struct {
+	Endpoint string
+	Client   client
+}
+
+// FailoverClient downloads product files from a prioritized list of hosts,
+// e.g. Pivotal Network and then a mirror. Each download is attempted from the
+// healthy hosts in priority order, followed by the hosts which have failed, so
+// that once a host fails the remaining files are downloaded from the next
+// host without waiting for the failed host again.
+//
+// Only the files are downloaded from the other hosts. The release and its
+// product files, including their checksums, are still read from the first
+// host, so the files are verified against its metadata.
+type FailoverClient struct {
+	hosts  []Host
+	logger logger.Logger
+
+	mu        *sync.Mutex
+	unhealthy map[string]bool
+}
+
+func NewFailoverClient(hosts []Host, logger logger.Logger) *FailoverClient {
+	return &FailoverClient{
+		hosts:     hosts,
+		logger:    logger,
+		mu:        &sync.Mutex{},
+		unhealthy: map[string]bool{},
+	}
+}
+
+func (c *FailoverClient) DownloadProductFile(
+	writer *os.File,
+	productSlug string,
+	releaseID int,
+	productFileID int,
+	progressWriter io.Writer,
+) error {
+	var failures []string
+	for i, host := range c.orderedHosts() {
+		if i > 0 {
+			c.logger.Info(fmt.Sprintf("Downloading product file %d from: '%s'", productFileID, host.Endpoint))
+		}
+
+		// A failed download may have written part of the file.
+		err := resetFile(writer)
+		if err != nil {
+			return err
+		}
+
+		err = host.Client.DownloadProductFile(writer, productSlug, releaseID, productFileID, progressWriter)
+		if err == nil {
+			c.setHealthy(host.Endpoint, true)
+			return nil
+		}
+
+		// The EULA must be accepted whichever host the file is downloaded
+		// from, so the host is not at fault.
+		if _, ok := err.(gp.ErrEULAAcceptanceRequired); ok {
+			return err
+		}
+
+		logging.Warn(c.logger, fmt.Sprintf(
+			"Could not download product file %d from: '%s': %s",
+			productFileID,
+			host.Endpoint,
+			err.Error(),
+		))
+		c.setHealthy(host.Endpoint, false)
+
+		failures = append(failures, fmt.Sprintf("%s: %s", host.Endpoint, err.Error()))
+	}
+
+	return fmt.Errorf("could not download product file %d from any host - %s", productFileID, strings.Join(failures, "; "))
+}
+
+// orderedHosts returns the healthy hosts followed by the unhealthy hosts,
+// each in priority order.
+func (c *FailoverClient) orderedHosts() []Host {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var healthy, unhealthy []Host
+	for _, host := range c.hosts {
+		if c.unhealthy[host.Endpoint] {
+			unhealthy = append(unhealthy, host)
+		} else {
+			healthy = append(healthy, host)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (c *FailoverClient) setHealthy(endpoint string, healthy bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if healthy {
+		delete(c.unhealthy, endpoint)
+	} else {
+		c.unhealthy[endpoint] = true
+	}
+}
@@ -0,0 +1,155 @@
+package downloader_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/downloader"
+	"github.com/pivotal-cf/pivnet-resource/downloader/downloaderfakes"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FailoverClient", func() {
+	var (
+		fakePrimary *downloaderfakes.FakeClient
+		fakeMirror  *downloaderfakes.FakeClient
+		fakeLogger  logger.Logger
+
+		file *os.File
+
+		client *downloader.FailoverClient
+	)
+
+	writeContent := func(content string) func(*os.File, string, int, int, io.Writer) error {
+		return func(writer *os.File, productSlug string, releaseID int, productFileID int, progressWriter io.Writer) error {
+			_, err := writer.Write([]byte(content))
+			return err
+		}
+	}
+
+	BeforeEach(func() {
+		fakePrimary = &downloaderfakes.FakeClient{}
+		fakeMirror = &downloaderfakes.FakeClient{}
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		var err error
+		file, err = ioutil.TempFile("", "pivnet-resource-failover")
+		Expect(err).NotTo(HaveOccurred())
+
+		client = downloader.NewFailoverClient([]downloader.Host{
+			{Endpoint: "https://primary.example.com", Client: fakePrimary},
+			{Endpoint: "https://mirror.example.com", Client: fakeMirror},
+		}, fakeLogger)
+	})
+
+	AfterEach(func() {
+		file.Close()
+		Expect(os.Remove(file.Name())).To(Succeed())
+	})
+
+	readFile := func() string {
+		contents, err := ioutil.ReadFile(file.Name())
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	It("downloads from the first host", func() {
+		fakePrimary.DownloadProductFileStub = writeContent("from-primary")
+
+		err := client.DownloadProductFile(file, "some-product-slug", 1234, 1337, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(readFile()).To(Equal("from-primary"))
+		Expect(fakeMirror.DownloadProductFileCallCount()).To(Equal(0))
+
+		_, productSlug, releaseID, productFileID, _ := fakePrimary.DownloadProductFileArgsForCall(0)
+		Expect(productSlug).To(Equal("some-product-slug"))
+		Expect(releaseID).To(Equal(1234))
+		Expect(productFileID).To(Equal(1337))
+	})
+
+	Context("when the first host fails", func() {
+		BeforeEach(func() {
+			fakePrimary.DownloadProductFileStub = func(writer *os.File, productSlug string, releaseID int, productFileID int, progressWriter io.Writer) error {
+				writer.Write([]byte("partial"))
+				return errors.New("some primary error")
+			}
+			fakeMirror.DownloadProductFileStub = writeContent("from-mirror")
+		})
+
+		It("downloads the whole file from the next host", func() {
+			err := client.DownloadProductFile(file, "some-product-slug", 1234, 1337, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(readFile()).To(Equal("from-mirror"))
+		})
+
+		It("downloads later files from the next host first", func() {
+			err := client.DownloadProductFile(file, "some-product-slug", 1234, 1337, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = client.DownloadProductFile(file, "some-product-slug", 1234, 1338, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePrimary.DownloadProductFileCallCount()).To(Equal(1))
+			Expect(fakeMirror.DownloadProductFileCallCount()).To(Equal(2))
+		})
+
+		Context("when every host fails", func() {
+			BeforeEach(func() {
+				fakeMirror.DownloadProductFileStub = nil
+				fakeMirror.DownloadProductFileReturns(errors.New("some mirror error"))
+			})
+
+			It("returns the error of each host", func() {
+				err := client.DownloadProductFile(file, "some-product-slug", 1234, 1337, GinkgoWriter)
+				Expect(err).To(MatchError(
+					"could not download product file 1337 from any host - " +
+						"https://primary.example.com: some primary error; " +
+						"https://mirror.example.com: some mirror error",
+				))
+			})
+
+			It("tries the failed hosts again for later files", func() {
+				client.DownloadProductFile(file, "some-product-slug", 1234, 1337, GinkgoWriter)
+
+				fakePrimary.DownloadProductFileStub = writeContent("from-primary")
+
+				err := client.DownloadProductFile(file, "some-product-slug", 1234, 1338, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(readFile()).To(Equal("from-primary"))
+			})
+		})
+	})
+
+	Context("when the EULA of the release has not been accepted", func() {
+		var eulaErr gp.ErrEULAAcceptanceRequired
+
+		BeforeEach(func() {
+			eulaErr = gp.ErrEULAAcceptanceRequired{
+				ReleaseID: 1234,
+				URL:       "https://primary.example.com/products/some-product-slug/releases/1234",
+				Err:       errors.New("some eula error"),
+			}
+			fakePrimary.DownloadProductFileReturns(eulaErr)
+		})
+
+		It("returns the error without downloading from the next host", func() {
+			err := client.DownloadProductFile(file, "some-product-slug", 1234, 1337, GinkgoWriter)
+			Expect(err).To(Equal(eulaErr))
+
+			Expect(fakeMirror.DownloadProductFileCallCount()).To(Equal(0))
+		})
+	})
+})
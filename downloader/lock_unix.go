@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package downloader
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockDir takes an exclusive advisory lock on dir, blocking until it is
+// available. The returned function releases the lock.
+func lockDir(dir string) (func() error, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() error {
+		defer f.Close()
+		return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	}, nil
+}
@@ -0,0 +1,6 @@
+package downloader
+
+// lockDir is a no-op on windows as flock is not available.
+func lockDir(dir string) (func() error, error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,53 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+type Type string
+
+const (
+	DownloadStarted Type = "download_started"
+	Progress        Type = "progress"
+	Verified        Type = "verified"
+	Completed       Type = "completed"
+)
+
+type Event struct {
+	Type       Type      `json:"type"`
+	Timestamp  time.Time `json:"timestamp"`
+	File       string    `json:"file,omitempty"`
+	Bytes      int64     `json:"bytes,omitempty"`
+	TotalBytes int64     `json:"total_bytes,omitempty"`
+	Checksum   string    `json:"checksum,omitempty"`
+	Version    string    `json:"version,omitempty"`
+}
+
+// Emitter writes events as JSON Lines, one event per line.
+// It is safe for concurrent use.
+type Emitter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewEmitter(writer io.Writer) *Emitter {
+	return &Emitter{
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+func (e *Emitter) Emit(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Errors are deliberately ignored: events are best-effort and must never
+	// cause the resource to fail.
+	_ = e.encoder.Encode(event)
+}
@@ -0,0 +1,60 @@
+package events_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/events"
+)
+
+var _ = Describe("Emitter", func() {
+	var (
+		buffer  *bytes.Buffer
+		emitter *events.Emitter
+	)
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		emitter = events.NewEmitter(buffer)
+	})
+
+	It("writes one JSON object per line", func() {
+		emitter.Emit(events.Event{Type: events.DownloadStarted, File: "file-0"})
+		emitter.Emit(events.Event{Type: events.Completed})
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+
+		var event events.Event
+		err := json.Unmarshal([]byte(lines[0]), &event)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(event.Type).To(Equal(events.DownloadStarted))
+		Expect(event.File).To(Equal("file-0"))
+	})
+
+	It("sets the timestamp when one is not provided", func() {
+		emitter.Emit(events.Event{Type: events.Progress})
+
+		var event events.Event
+		err := json.Unmarshal(buffer.Bytes(), &event)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(event.Timestamp).NotTo(BeZero())
+	})
+
+	It("preserves a provided timestamp", func() {
+		timestamp := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+		emitter.Emit(events.Event{Type: events.Progress, Timestamp: timestamp})
+
+		var event events.Event
+		err := json.Unmarshal(buffer.Bytes(), &event)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(event.Timestamp).To(Equal(timestamp))
+	})
+})
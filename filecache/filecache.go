@@ -0,0 +1,165 @@
+package filecache
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Cache stores downloaded files in a worker-local directory keyed by their
+// SHA256, so that later downloads of the same file can be hard-linked into
+// place instead of being downloaded or copied again. A Cache with an empty
+// directory caches nothing.
+type Cache struct {
+	dir string
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{
+		dir: dir,
+	}
+}
+
+// Link places the cached file with the provided SHA256 at destination,
+// replacing any existing file. It returns false if the file is not cached.
+// The file is hard-linked where possible and copied otherwise, e.g. when the
+// cache is on a different filesystem to the destination.
+func (c Cache) Link(sha256 string, destination string) (bool, error) {
+	if c.dir == "" || !isSHA256(sha256) {
+		return false, nil
+	}
+
+	return c.link(c.path(sha256), destination)
+}
+
+// link places the file cached at cachedPath at destination. It returns false
+// if no file is cached there.
+func (c Cache) link(cachedPath string, destination string) (bool, error) {
+	_, err := os.Stat(cachedPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = os.Remove(destination)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	err = os.Link(cachedPath, destination)
+	if err == nil {
+		return true, nil
+	}
+
+	err = copyFile(cachedPath, destination)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Add stores the file at path in the cache under the provided SHA256, which
+// must already have been verified. Files which are already cached are left
+// untouched.
+func (c Cache) Add(sha256 string, path string) error {
+	if c.dir == "" || !isSHA256(sha256) {
+		return nil
+	}
+
+	return c.add(c.path(sha256), path)
+}
+
+// add stores the file at path in the cache at cachedPath, unless a file is
+// already stored there.
+func (c Cache) add(cachedPath string, path string) error {
+	_, err := os.Stat(cachedPath)
+	if err == nil {
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	err = os.MkdirAll(c.dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return c.place(cachedPath, path)
+}
+
+// place stores the file at path in the cache at cachedPath, replacing any
+// file already stored there. Files are placed under a temporary name and
+// renamed into place so that concurrent readers never see a partially
+// written file.
+func (c Cache) place(cachedPath string, path string) error {
+	tempFile, err := ioutil.TempFile(c.dir, ".add-")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	tempFile.Close()
+	defer os.Remove(tempPath)
+
+	err = os.Remove(tempPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Link(path, tempPath)
+	if err != nil {
+		err = copyFile(path, tempPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tempPath, cachedPath)
+}
+
+func (c Cache) path(sha256 string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("sha256-%s", sha256))
+}
+
+// isSHA256 guards against checksums which could escape the cache directory
+// when used in a path.
+func isSHA256(sum string) bool {
+	if len(sum) != 64 {
+		return false
+	}
+
+	for _, r := range sum {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func copyFile(source string, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,100 @@
+package filecache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotal-cf/pivnet-resource/filecache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		tempDir        string
+		cacheDir       string
+		downloadedPath string
+		destination    string
+		sha256         string
+
+		cache *filecache.Cache
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "pivnet-resource-filecache")
+		Expect(err).NotTo(HaveOccurred())
+
+		cacheDir = filepath.Join(tempDir, "cache")
+		downloadedPath = filepath.Join(tempDir, "downloaded")
+		destination = filepath.Join(tempDir, "destination")
+		sha256 = strings.Repeat("ab", 32)
+
+		err = ioutil.WriteFile(downloadedPath, []byte("some-content"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		cache = filecache.NewCache(cacheDir)
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("does not link files which are not cached", func() {
+		linked, err := cache.Link(sha256, destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(linked).To(BeFalse())
+
+		Expect(destination).NotTo(BeAnExistingFile())
+	})
+
+	It("hard-links cached files into place", func() {
+		err := cache.Add(sha256, downloadedPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(destination, []byte("some-partial-download"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		linked, err := cache.Link(sha256, destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(linked).To(BeTrue())
+
+		contents, err := ioutil.ReadFile(destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-content"))
+
+		downloadedInfo, err := os.Stat(downloadedPath)
+		Expect(err).NotTo(HaveOccurred())
+		destinationInfo, err := os.Stat(destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.SameFile(downloadedInfo, destinationInfo)).To(BeTrue())
+	})
+
+	It("ignores checksums which are not SHA256s", func() {
+		err := cache.Add("../escaped", downloadedPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cacheDir).NotTo(BeADirectory())
+	})
+
+	Context("when no directory is provided", func() {
+		BeforeEach(func() {
+			cacheDir = ""
+		})
+
+		It("caches nothing", func() {
+			err := cache.Add(sha256, downloadedPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			linked, err := cache.Link(sha256, destination)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(linked).To(BeFalse())
+		})
+	})
+})
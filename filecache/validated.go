@@ -0,0 +1,115 @@
+package filecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Validators are the cache validators of a downloaded file, as returned in
+// the ETag and Last-Modified headers of its download, with which a later
+// download can be made conditional on the file having changed.
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (v Validators) IsEmpty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// LinkValidated places the file cached under key at destination, as Link
+// does, and returns the validators it was cached with. It returns false if no
+// file is cached under key.
+//
+// Unlike files cached by SHA256, files cached under a key, e.g. of a product
+// file without a SHA256, may change and must be revalidated before use.
+func (c Cache) LinkValidated(key string, destination string) (Validators, bool, error) {
+	if c.dir == "" {
+		return Validators{}, false, nil
+	}
+
+	b, err := ioutil.ReadFile(c.validatorsPath(key))
+	if os.IsNotExist(err) {
+		return Validators{}, false, nil
+	}
+	if err != nil {
+		return Validators{}, false, err
+	}
+
+	var validators Validators
+	err = json.Unmarshal(b, &validators)
+	if err != nil || validators.IsEmpty() {
+		// A corrupt index entry is treated as a cache miss, as the file is
+		// downloaded again and the entry replaced.
+		return Validators{}, false, nil
+	}
+
+	linked, err := c.link(c.validatedPath(key), destination)
+	if err != nil || !linked {
+		return Validators{}, false, err
+	}
+
+	return validators, true, nil
+}
+
+// AddValidated stores the file at path in the cache under key, along with
+// its validators, replacing any file previously cached under key. Files
+// without validators are not cached as they could never be revalidated.
+func (c Cache) AddValidated(key string, validators Validators, path string) error {
+	if c.dir == "" || validators.IsEmpty() {
+		return nil
+	}
+
+	err := os.MkdirAll(c.dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	// The file is placed before its validators so that the validators are
+	// never read alongside an older file.
+	err = c.place(c.validatedPath(key), path)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(validators)
+	if err != nil {
+		// Untested as it is too hard to force json.Marshal to return an error
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(c.dir, ".add-")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath)
+
+	_, err = tempFile.Write(b)
+	if err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, c.validatorsPath(key))
+}
+
+// validatedPath returns the path of the file cached under key. Keys are
+// hashed so that they cannot escape the cache directory.
+func (c Cache) validatedPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, "validated-"+hex.EncodeToString(sum[:]))
+}
+
+func (c Cache) validatorsPath(key string) string {
+	return c.validatedPath(key) + ".json"
+}
@@ -0,0 +1,120 @@
+package filecache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/pivnet-resource/filecache"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache of validated files", func() {
+	const key = "some-product-slug/1234/1337"
+
+	var (
+		tempDir        string
+		cacheDir       string
+		downloadedPath string
+		destination    string
+		validators     filecache.Validators
+
+		cache *filecache.Cache
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = ioutil.TempDir("", "pivnet-resource-filecache")
+		Expect(err).NotTo(HaveOccurred())
+
+		cacheDir = filepath.Join(tempDir, "cache")
+		downloadedPath = filepath.Join(tempDir, "downloaded")
+		destination = filepath.Join(tempDir, "destination")
+		validators = filecache.Validators{
+			ETag:         `"some-etag"`,
+			LastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+		}
+
+		err = ioutil.WriteFile(downloadedPath, []byte("some-content"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		cache = filecache.NewCache(cacheDir)
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("does not link files which are not cached", func() {
+		_, linked, err := cache.LinkValidated(key, destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(linked).To(BeFalse())
+
+		Expect(destination).NotTo(BeAnExistingFile())
+	})
+
+	It("links cached files into place along with their validators", func() {
+		err := cache.AddValidated(key, validators, downloadedPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		cachedValidators, linked, err := cache.LinkValidated(key, destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(linked).To(BeTrue())
+		Expect(cachedValidators).To(Equal(validators))
+
+		contents, err := ioutil.ReadFile(destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-content"))
+	})
+
+	It("replaces files previously cached under the same key", func() {
+		err := cache.AddValidated(key, validators, downloadedPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		newPath := filepath.Join(tempDir, "new")
+		err = ioutil.WriteFile(newPath, []byte("some-new-content"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		newValidators := filecache.Validators{ETag: `"some-new-etag"`}
+		err = cache.AddValidated(key, newValidators, newPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		cachedValidators, linked, err := cache.LinkValidated(key, destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(linked).To(BeTrue())
+		Expect(cachedValidators).To(Equal(newValidators))
+
+		contents, err := ioutil.ReadFile(destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(contents)).To(Equal("some-new-content"))
+	})
+
+	It("does not cache files without validators", func() {
+		err := cache.AddValidated(key, filecache.Validators{}, downloadedPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, linked, err := cache.LinkValidated(key, destination)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(linked).To(BeFalse())
+	})
+
+	Context("when no directory is provided", func() {
+		BeforeEach(func() {
+			cacheDir = ""
+		})
+
+		It("caches nothing", func() {
+			err := cache.AddValidated(key, validators, downloadedPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, linked, err := cache.LinkValidated(key, destination)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(linked).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,45 @@
+package filenames
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+// Base returns the name of the file in the product file's AWS object key.
+func Base(pf pivnet.ProductFile) string {
+	parts := strings.Split(pf.AWSObjectKey, "/")
+	return parts[len(parts)-1]
+}
+
+// ForProductFiles returns the local file name to use for each product file,
+// keyed by product file ID.
+//
+// Product files whose names collide with another product file are suffixed
+// with their ID (e.g. release-notes-1234.pdf) so that neither overwrites the
+// other. Product files appearing more than once (e.g. in both the release and
+// a file group) do not count as a collision.
+func ForProductFiles(pfs []pivnet.ProductFile) map[int]string {
+	idsByName := map[string]map[int]bool{}
+	for _, pf := range pfs {
+		name := Base(pf)
+		if idsByName[name] == nil {
+			idsByName[name] = map[int]bool{}
+		}
+		idsByName[name][pf.ID] = true
+	}
+
+	names := map[int]string{}
+	for _, pf := range pfs {
+		name := Base(pf)
+		if len(idsByName[name]) > 1 {
+			ext := filepath.Ext(name)
+			name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), pf.ID, ext)
+		}
+		names[pf.ID] = name
+	}
+
+	return names
+}
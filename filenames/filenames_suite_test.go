@@ -0,0 +1,13 @@
+package filenames_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestFilenames(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Filenames Suite")
+}
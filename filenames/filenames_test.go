@@ -0,0 +1,53 @@
+package filenames_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/filenames"
+)
+
+var _ = Describe("Filenames", func() {
+	Describe("ForProductFiles", func() {
+		It("uses the last component of the AWS object key", func() {
+			names := filenames.ForProductFiles([]pivnet.ProductFile{
+				{ID: 1234, AWSObjectKey: "product/some-file.zip"},
+				{ID: 3456, AWSObjectKey: "product/other-file.zip"},
+			})
+
+			Expect(names).To(Equal(map[int]string{
+				1234: "some-file.zip",
+				3456: "other-file.zip",
+			}))
+		})
+
+		Context("when product files share a name", func() {
+			It("suffixes each of them with the product file ID", func() {
+				names := filenames.ForProductFiles([]pivnet.ProductFile{
+					{ID: 1234, AWSObjectKey: "product/a/release-notes.pdf"},
+					{ID: 3456, AWSObjectKey: "product/b/release-notes.pdf"},
+					{ID: 5678, AWSObjectKey: "product/some-file.zip"},
+				})
+
+				Expect(names).To(Equal(map[int]string{
+					1234: "release-notes-1234.pdf",
+					3456: "release-notes-3456.pdf",
+					5678: "some-file.zip",
+				}))
+			})
+		})
+
+		Context("when the same product file appears more than once", func() {
+			It("does not treat it as a collision", func() {
+				names := filenames.ForProductFiles([]pivnet.ProductFile{
+					{ID: 1234, AWSObjectKey: "product/some-file.zip"},
+					{ID: 1234, AWSObjectKey: "product/some-file.zip"},
+				})
+
+				Expect(names).To(Equal(map[int]string{
+					1234: "some-file.zip",
+				}))
+			})
+		})
+	})
+})
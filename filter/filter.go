@@ -2,12 +2,14 @@ package filter
 
 import (
 	"fmt"
-	"path/filepath"
 	"regexp"
 	"strings"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	globpatterns "github.com/pivotal-cf/pivnet-resource/globs"
+	"github.com/pivotal-cf/pivnet-resource/platform"
+	"github.com/pivotal-cf/pivnet-resource/semver"
 )
 
 type Filter struct {
@@ -32,17 +34,45 @@ func (f Filter) ReleasesByReleaseType(releases []pivnet.Release, releaseType piv
 	return filteredReleases, nil
 }
 
-// ReleasesByVersion returns all releases that match the provided version regex
+// ReleasesByVersion returns all releases that match the provided version regex.
+// The regex is unanchored, so e.g. ^1\.8\. is needed to match only 1.8.x.
 func (f Filter) ReleasesByVersion(releases []pivnet.Release, version string) ([]pivnet.Release, error) {
+	re, err := regexp.Compile(version)
+	if err != nil {
+		return nil, err
+	}
+
+	filteredReleases := make([]pivnet.Release, 0)
+
+	for _, release := range releases {
+		if re.MatchString(release.Version) {
+			filteredReleases = append(filteredReleases, release)
+		}
+	}
+
+	return filteredReleases, nil
+}
+
+// ReleasesByVersionConstraint returns all releases whose versions satisfy the
+// provided constraint, with the syntax of semver.ParseConstraint. Releases
+// whose versions are not semver, even with zeros appended, never satisfy it.
+func (f Filter) ReleasesByVersionConstraint(releases []pivnet.Release, constraint string) ([]pivnet.Release, error) {
+	c, err := semver.ParseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	converter := semver.NewSemverConverter(f.l)
+
 	filteredReleases := make([]pivnet.Release, 0)
 
 	for _, release := range releases {
-		match, err := regexp.MatchString(version, release.Version)
+		v, err := converter.ToValidSemver(release.Version)
 		if err != nil {
-			return nil, err
+			continue
 		}
 
-		if match {
+		if c.Check(v) {
 			filteredReleases = append(filteredReleases, release)
 		}
 	}
@@ -50,32 +80,55 @@ func (f Filter) ReleasesByVersion(releases []pivnet.Release, version string) ([]
 	return filteredReleases, nil
 }
 
+// ProductFileKeysByGlobs returns the product files whose file names match the
+// globs, with the semantics of globs.Patterns. It returns an error if the
+// globs match no product files, unless no globs are provided.
 func (f Filter) ProductFileKeysByGlobs(
 	productFiles []pivnet.ProductFile,
 	globs []string,
 ) ([]pivnet.ProductFile, error) {
 	f.l.Debug("filter.ProductFilesKeysByGlobs", logger.Data{"globs": globs})
 
+	patterns, err := globpatterns.NewPatterns(globs)
+	if err != nil {
+		return nil, err
+	}
+
 	filtered := []pivnet.ProductFile{}
-	for _, pattern := range globs {
-		for _, p := range productFiles {
-			parts := strings.Split(p.AWSObjectKey, "/")
-			fileName := parts[len(parts)-1]
-
-			matched, err := filepath.Match(pattern, fileName)
-			if err != nil {
-				return nil, err
-			}
-
-			if matched {
-				filtered = append(filtered, p)
-			}
-		}
+	for _, p := range productFiles {
+		parts := strings.Split(p.AWSObjectKey, "/")
+		fileName := parts[len(parts)-1]
 
+		if patterns.Match(fileName) {
+			filtered = append(filtered, p)
+		}
 	}
 
 	if len(filtered) == 0 && len(globs) != 0 {
-		return nil, fmt.Errorf("no match for glob(s): '%s'", strings.Join(globs, ", "))
+		return nil, patterns.NoMatchError()
+	}
+
+	return filtered, nil
+}
+
+// ProductFilesByPlatform returns the product files which can be used on the
+// platform, with the semantics of platform.Platform.Matches. It returns an
+// error if no product files match, unless no product files are provided.
+func (f Filter) ProductFilesByPlatform(
+	productFiles []pivnet.ProductFile,
+	p platform.Platform,
+) ([]pivnet.ProductFile, error) {
+	f.l.Debug("filter.ProductFilesByPlatform", logger.Data{"platform": p.String()})
+
+	filtered := []pivnet.ProductFile{}
+	for _, productFile := range productFiles {
+		if p.Matches(productFile) {
+			filtered = append(filtered, productFile)
+		}
+	}
+
+	if len(filtered) == 0 && len(productFiles) != 0 {
+		return nil, fmt.Errorf("no product files match platform '%s'", p)
 	}
 
 	return filtered, nil
@@ -7,6 +7,7 @@ import (
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
 	"github.com/pivotal-cf/pivnet-resource/filter"
+	"github.com/pivotal-cf/pivnet-resource/platform"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -175,6 +176,21 @@ var _ = Describe("Filter", func() {
 				})
 			})
 
+			Context("when the regex is anchored", func() {
+				BeforeEach(func() {
+					releases = append(releases, pivnet.Release{ID: 6, Version: "other-version3.3"})
+					version = `^version3\..*`
+				})
+
+				It("returns only the releases whose versions start with the match", func() {
+					filteredReleases, err := f.ReleasesByVersion(releases, version)
+
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(filteredReleases).To(Equal([]pivnet.Release{releases[3], releases[4]}))
+				})
+			})
+
 			Context("when the regex is invalid", func() {
 				BeforeEach(func() {
 					version = "some(invalid^regex"
@@ -189,6 +205,70 @@ var _ = Describe("Filter", func() {
 		})
 	})
 
+	Describe("ReleasesByVersionConstraint", func() {
+		var (
+			constraint string
+			releases   []pivnet.Release
+		)
+
+		BeforeEach(func() {
+			constraint = ">= 1.8, < 2.0"
+
+			releases = []pivnet.Release{
+				{ID: 1, Version: "1.7.3"},
+				{ID: 2, Version: "1.8"},
+				{ID: 3, Version: "1.9.1"},
+				{ID: 4, Version: "2.0.0"},
+				{ID: 5, Version: "not-semver"},
+			}
+		})
+
+		It("returns the releases whose versions satisfy the constraint", func() {
+			filteredReleases, err := f.ReleasesByVersionConstraint(releases, constraint)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filteredReleases).To(Equal([]pivnet.Release{releases[1], releases[2]}))
+		})
+
+		Context("when the constraint is pessimistic", func() {
+			BeforeEach(func() {
+				constraint = "~> 1.8"
+			})
+
+			It("returns the releases up to the next major version", func() {
+				filteredReleases, err := f.ReleasesByVersionConstraint(releases, constraint)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filteredReleases).To(Equal([]pivnet.Release{releases[1], releases[2]}))
+			})
+		})
+
+		Context("when no releases satisfy the constraint", func() {
+			BeforeEach(func() {
+				constraint = "> 3"
+			})
+
+			It("returns empty slice without error", func() {
+				filteredReleases, err := f.ReleasesByVersionConstraint(releases, constraint)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filteredReleases).NotTo(BeNil())
+				Expect(filteredReleases).To(HaveLen(0))
+			})
+		})
+
+		Context("when the constraint is invalid", func() {
+			BeforeEach(func() {
+				constraint = ">= 1.8, <<"
+			})
+
+			It("returns an error", func() {
+				_, err := f.ReleasesByVersionConstraint(releases, constraint)
+				Expect(err).To(MatchError("invalid clause: '<<'"))
+			})
+		})
+	})
+
 	Describe("ProductFileKeysByGlobs", func() {
 		var (
 			productFiles []pivnet.ProductFile
@@ -245,6 +325,38 @@ var _ = Describe("Filter", func() {
 			})
 		})
 
+		Context("when an exclusion is passed", func() {
+			BeforeEach(func() {
+				globs = []string{"file-*", "!*-1"}
+			})
+
+			It("does not return the files it matches", func() {
+				filtered, err := f.ProductFileKeysByGlobs(
+					productFiles,
+					globs,
+				)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filtered).To(Equal([]pivnet.ProductFile{productFiles[0], productFiles[2]}))
+			})
+		})
+
+		Context("when a file matches more than one glob", func() {
+			BeforeEach(func() {
+				globs = []string{"file-*", "*-1"}
+			})
+
+			It("returns it once", func() {
+				filtered, err := f.ProductFileKeysByGlobs(
+					productFiles,
+					globs,
+				)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(filtered).To(Equal(productFiles))
+			})
+		})
+
 		Context("when a bad pattern is passed", func() {
 			BeforeEach(func() {
 				globs = []string{"["}
@@ -293,4 +405,52 @@ var _ = Describe("Filter", func() {
 			})
 		})
 	})
+
+	Describe("ProductFilesByPlatform", func() {
+		var (
+			productFiles []pivnet.ProductFile
+		)
+
+		BeforeEach(func() {
+			productFiles = []pivnet.ProductFile{
+				{
+					ID:           1234,
+					AWSObjectKey: "/some/remote/path/to/tool-linux-amd64",
+				},
+				{
+					ID:           2345,
+					AWSObjectKey: "/some/remote/path/to/tool-windows-amd64.exe",
+				},
+				{
+					ID:           3456,
+					AWSObjectKey: "/some/remote/path/to/tool.tgz",
+				},
+			}
+		})
+
+		It("returns the product files which can be used on the platform", func() {
+			filtered, err := f.ProductFilesByPlatform(
+				productFiles,
+				platform.Platform{OS: platform.Linux, Arch: platform.AMD64},
+			)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filtered).To(Equal([]pivnet.ProductFile{productFiles[0], productFiles[2]}))
+		})
+
+		Context("when no product files can be used on the platform", func() {
+			BeforeEach(func() {
+				productFiles = productFiles[:2]
+			})
+
+			It("returns an error", func() {
+				_, err := f.ProductFilesByPlatform(
+					productFiles,
+					platform.Platform{OS: platform.Darwin},
+				)
+
+				Expect(err).To(MatchError("no product files match platform 'darwin'"))
+			})
+		})
+	})
 })
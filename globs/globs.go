@@ -2,42 +2,72 @@ package globs
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/pivotal-cf/go-pivnet/logger"
 )
 
 type Globber struct {
-	fileGlob   string
-	sourcesDir string
+	fileGlob     string
+	exclusions   []string
+	sourcesDir   string
+	skipSymlinks bool
 
 	logger logger.Logger
 }
 
 type GlobberConfig struct {
-	FileGlob   string
+	FileGlob string
+
+	// Exclusions are patterns, without ExclusionPrefix, of the files matching
+	// FileGlob which are not matched. They match either the path of a file,
+	// as FileGlob does, or its name, e.g. both 'files/*.sha256' and '*.sha256'
+	// exclude 'files/some-file.sha256'.
+	Exclusions []string
+
 	SourcesDir string
 
+	// SkipSymlinks omits matched files which are symlinks rather than
+	// following them, i.e. follow_symlinks: false.
+	SkipSymlinks bool
+
 	Logger logger.Logger
 }
 
 func NewGlobber(config GlobberConfig) *Globber {
 	return &Globber{
-		fileGlob:   config.FileGlob,
-		sourcesDir: config.SourcesDir,
+		fileGlob:     config.FileGlob,
+		exclusions:   config.Exclusions,
+		sourcesDir:   config.SourcesDir,
+		skipSymlinks: config.SkipSymlinks,
 
 		logger: config.Logger,
 	}
 }
 
+// ExactGlobs returns the paths, relative to the sources dir, of the files
+// matching the file glob and none of the exclusions, with the semantics of
+// Patterns. It returns an error if no files are matched.
+//
+// Matched symlinks are followed, so that the files they link to are
+// uploaded under the names of the links, unless symlinks are skipped. A
+// symlink which is dangling or part of a cycle is an error rather than being
+// uploaded.
 func (g Globber) ExactGlobs() ([]string, error) {
-	matches, err := filepath.Glob(filepath.Join(g.sourcesDir, g.fileGlob))
+	globs := []string{g.fileGlob}
+	for _, exclusion := range g.exclusions {
+		globs = append(globs, ExclusionPrefix+exclusion)
+	}
+
+	patterns, err := NewPatterns(globs)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("no matches found for pattern: '%s'", g.fileGlob)
+	matches, err := filepath.Glob(filepath.Join(g.sourcesDir, g.fileGlob))
+	if err != nil {
+		return nil, err
 	}
 
 	absPathSourcesDir, err := filepath.Abs(g.sourcesDir)
@@ -57,8 +87,36 @@ func (g Globber) ExactGlobs() ([]string, error) {
 			panic(err)
 		}
 
+		if patterns.Excludes(filepath.ToSlash(exactGlob)) || patterns.Excludes(filepath.Base(exactGlob)) {
+			g.logger.Debug(fmt.Sprintf("Excluding file: '%s'", exactGlob))
+			continue
+		}
+
+		info, err := os.Lstat(match)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			if g.skipSymlinks {
+				g.logger.Info(fmt.Sprintf("Skipping symlink: '%s'", exactGlob))
+				continue
+			}
+
+			target, err := resolveSymlink(match)
+			if err != nil {
+				return nil, err
+			}
+
+			g.logger.Debug(fmt.Sprintf("Following symlink: '%s' to: '%s'", exactGlob, target))
+		}
+
 		exactGlobs = append(exactGlobs, exactGlob)
 	}
 
+	if len(exactGlobs) == 0 {
+		return nil, patterns.NoMatchError()
+	}
+
 	return exactGlobs, nil
 }
@@ -1,6 +1,7 @@
 package globs_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
@@ -62,9 +63,50 @@ var _ = Describe("Globber", func() {
 
 			It("returns an error", func() {
 				_, err := globber.ExactGlobs()
-				Expect(err).To(HaveOccurred())
+				Expect(err).To(MatchError("no match for glob(s): 'this-will-match-nothing'"))
+			})
+		})
+
+		Context("when exclusions are provided", func() {
+			BeforeEach(func() {
+				for _, name := range []string{"file-1", "file-1.sha256"} {
+					_, err := os.Create(filepath.Join(myFilesDir, name))
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				globberConfig.Exclusions = []string{"*.sha256", "my_files/file-0"}
+				globber = globs.NewGlobber(globberConfig)
+			})
+
+			It("omits files whose name or path matches an exclusion", func() {
+				filenamePaths, err := globber.ExactGlobs()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filenamePaths).To(Equal([]string{"my_files/file-1"}))
+			})
+
+			Context("when every matching file is excluded", func() {
+				BeforeEach(func() {
+					globberConfig.Exclusions = []string{"file-*"}
+					globber = globs.NewGlobber(globberConfig)
+				})
+
+				It("returns an error", func() {
+					_, err := globber.ExactGlobs()
+					Expect(err).To(MatchError("no match for glob(s): 'my_files/*, !file-*'"))
+				})
+			})
+
+			Context("when an exclusion is malformed", func() {
+				BeforeEach(func() {
+					globberConfig.Exclusions = []string{"["}
+					globber = globs.NewGlobber(globberConfig)
+				})
 
-				Expect(err.Error()).To(ContainSubstring("no matches"))
+				It("returns an error", func() {
+					_, err := globber.ExactGlobs()
+					Expect(err).To(MatchError("syntax error in pattern"))
+				})
 			})
 		})
 
@@ -84,5 +126,100 @@ var _ = Describe("Globber", func() {
 				Expect(filenamePaths[1]).To(Equal("my_files/file-1"))
 			})
 		})
+
+		Context("when a matching file is a symlink", func() {
+			BeforeEach(func() {
+				err := os.Symlink("file-0", filepath.Join(myFilesDir, "link-0"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("follows it", func() {
+				filenamePaths, err := globber.ExactGlobs()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filenamePaths).To(Equal([]string{"my_files/file-0", "my_files/link-0"}))
+			})
+
+			Context("when symlinks are skipped", func() {
+				BeforeEach(func() {
+					globberConfig.SkipSymlinks = true
+					globber = globs.NewGlobber(globberConfig)
+				})
+
+				It("omits it", func() {
+					filenamePaths, err := globber.ExactGlobs()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(filenamePaths).To(Equal([]string{"my_files/file-0"}))
+				})
+			})
+
+			Context("when it is excluded", func() {
+				BeforeEach(func() {
+					err := os.Symlink("missing", filepath.Join(myFilesDir, "link-1"))
+					Expect(err).NotTo(HaveOccurred())
+
+					globberConfig.Exclusions = []string{"link-1"}
+					globber = globs.NewGlobber(globberConfig)
+				})
+
+				It("is not followed", func() {
+					filenamePaths, err := globber.ExactGlobs()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(filenamePaths).To(Equal([]string{"my_files/file-0", "my_files/link-0"}))
+				})
+			})
+		})
+
+		Context("when a matching symlink is dangling", func() {
+			BeforeEach(func() {
+				err := os.Symlink("missing", filepath.Join(myFilesDir, "link-0"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error", func() {
+				_, err := globber.ExactGlobs()
+				Expect(err).To(MatchError(fmt.Sprintf(
+					"symlink '%s' is dangling: '%s' does not exist",
+					filepath.Join(myFilesDir, "link-0"),
+					filepath.Join(myFilesDir, "missing"),
+				)))
+			})
+		})
+
+		Context("when a matching symlink is part of a cycle", func() {
+			BeforeEach(func() {
+				err := os.Symlink("link-1", filepath.Join(myFilesDir, "link-0"))
+				Expect(err).NotTo(HaveOccurred())
+
+				err = os.Symlink("link-0", filepath.Join(myFilesDir, "link-1"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error", func() {
+				_, err := globber.ExactGlobs()
+				Expect(err).To(MatchError(fmt.Sprintf(
+					"symlink '%s' is part of a cycle",
+					filepath.Join(myFilesDir, "link-0"),
+				)))
+			})
+		})
+
+		Context("when a matching symlink links to a directory", func() {
+			BeforeEach(func() {
+				err := os.Symlink(tempDir, filepath.Join(myFilesDir, "link-0"))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error", func() {
+				_, err := globber.ExactGlobs()
+				Expect(err).To(MatchError(fmt.Sprintf(
+					"symlink '%s' links to directory '%s'",
+					filepath.Join(myFilesDir, "link-0"),
+					tempDir,
+				)))
+			})
+		})
 	})
 })
@@ -0,0 +1,83 @@
+package globs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ExclusionPrefix marks a pattern as excluding the names it matches.
+const ExclusionPrefix = "!"
+
+// Patterns are the glob patterns of both get and put, which share their
+// syntax, that of path.Match, and their semantics:
+//
+//   - a name is matched if it matches any inclusion and no exclusion
+//   - patterns with ExclusionPrefix, e.g. '!*.txt', are exclusions
+//   - patterns consisting only of exclusions include everything else, i.e.
+//     they are treated as though '*' were also provided
+//   - no patterns at all match nothing
+type Patterns struct {
+	patterns []string
+	include  []string
+	exclude  []string
+}
+
+// NewPatterns returns an error if any of the patterns is malformed.
+func NewPatterns(patterns []string) (Patterns, error) {
+	p := Patterns{
+		patterns: patterns,
+	}
+
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, ExclusionPrefix) {
+			pattern = strings.TrimPrefix(pattern, ExclusionPrefix)
+			if pattern == "" {
+				return Patterns{}, fmt.Errorf("exclusion '%s' must have a pattern", ExclusionPrefix)
+			}
+			p.exclude = append(p.exclude, pattern)
+		} else {
+			p.include = append(p.include, pattern)
+		}
+
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return Patterns{}, err
+		}
+	}
+
+	if len(p.include) == 0 && len(p.exclude) > 0 {
+		p.include = []string{"*"}
+	}
+
+	return p, nil
+}
+
+// Match returns whether name, with '/' separators, is matched by the
+// patterns.
+func (p Patterns) Match(name string) bool {
+	return matchAny(p.include, name) && !p.Excludes(name)
+}
+
+// Excludes returns whether name is matched by any of the exclusions.
+func (p Patterns) Excludes(name string) bool {
+	return matchAny(p.exclude, name)
+}
+
+// NoMatchError is returned by both get and put when the patterns match
+// nothing.
+func (p Patterns) NoMatchError() error {
+	return fmt.Errorf("no match for glob(s): '%s'", strings.Join(p.patterns, ", "))
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// Patterns are validated by NewPatterns, so cannot return an error.
+		matched, _ := path.Match(pattern, name)
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
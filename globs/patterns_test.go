@@ -0,0 +1,94 @@
+package globs_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/globs"
+)
+
+var _ = Describe("Patterns", func() {
+	var patterns []string
+
+	match := func(name string) bool {
+		p, err := globs.NewPatterns(patterns)
+		Expect(err).NotTo(HaveOccurred())
+
+		return p.Match(name)
+	}
+
+	BeforeEach(func() {
+		patterns = []string{"*.pivotal", "*.tgz"}
+	})
+
+	It("matches names matching any of the patterns", func() {
+		Expect(match("some-product.pivotal")).To(BeTrue())
+		Expect(match("some-stemcell.tgz")).To(BeTrue())
+		Expect(match("release-notes.pdf")).To(BeFalse())
+	})
+
+	It("does not match across path separators", func() {
+		Expect(match("tiles/some-product.pivotal")).To(BeFalse())
+	})
+
+	Context("when exclusions are provided", func() {
+		BeforeEach(func() {
+			patterns = []string{"*.pivotal", "!*-beta.pivotal"}
+		})
+
+		It("does not match names matching any of the exclusions", func() {
+			Expect(match("some-product.pivotal")).To(BeTrue())
+			Expect(match("some-product-beta.pivotal")).To(BeFalse())
+		})
+	})
+
+	Context("when only exclusions are provided", func() {
+		BeforeEach(func() {
+			patterns = []string{"!*.pdf"}
+		})
+
+		It("matches every other name", func() {
+			Expect(match("some-product.pivotal")).To(BeTrue())
+			Expect(match("release-notes.pdf")).To(BeFalse())
+		})
+	})
+
+	Context("when no patterns are provided", func() {
+		BeforeEach(func() {
+			patterns = []string{}
+		})
+
+		It("matches nothing", func() {
+			Expect(match("some-product.pivotal")).To(BeFalse())
+		})
+	})
+
+	Context("when a pattern is malformed", func() {
+		It("returns an error", func() {
+			_, err := globs.NewPatterns([]string{"*.pivotal", "["})
+			Expect(err).To(MatchError("syntax error in pattern"))
+		})
+
+		Context("when it is an exclusion", func() {
+			It("returns an error", func() {
+				_, err := globs.NewPatterns([]string{"!["})
+				Expect(err).To(MatchError("syntax error in pattern"))
+			})
+		})
+	})
+
+	Context("when an exclusion has no pattern", func() {
+		It("returns an error", func() {
+			_, err := globs.NewPatterns([]string{"*", "!"})
+			Expect(err).To(MatchError("exclusion '!' must have a pattern"))
+		})
+	})
+
+	Describe("NoMatchError", func() {
+		It("names all of the patterns", func() {
+			p, err := globs.NewPatterns([]string{"*.pivotal", "!*-beta.pivotal"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(p.NoMatchError()).To(MatchError("no match for glob(s): '*.pivotal, !*-beta.pivotal'"))
+		})
+	})
+})
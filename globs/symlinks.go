@@ -0,0 +1,55 @@
+package globs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// resolveSymlink returns the path of the file which the symlink at path
+// ultimately links to, following each link in the chain. It returns an error
+// if the chain is a cycle or ends in a file which does not exist or is a
+// directory, as none of these can be uploaded.
+func resolveSymlink(path string) (string, error) {
+	visited := map[string]bool{}
+
+	current := path
+	for {
+		absPath, err := filepath.Abs(current)
+		if err != nil {
+			return "", err
+		}
+
+		if visited[absPath] {
+			return "", fmt.Errorf("symlink '%s' is part of a cycle", path)
+		}
+		visited[absPath] = true
+
+		target, err := os.Readlink(current)
+		if err != nil {
+			return "", err
+		}
+
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(current), target)
+		}
+
+		info, err := os.Lstat(target)
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("symlink '%s' is dangling: '%s' does not exist", path, target)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if info.IsDir() {
+			return "", fmt.Errorf("symlink '%s' links to directory '%s'", path, target)
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			return target, nil
+		}
+
+		current = target
+	}
+}
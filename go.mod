@@ -2,7 +2,7 @@ module github.com/pivotal-cf/pivnet-resource
 
 require (
 	github.com/StackExchange/wmi v0.0.0-20180725035823-b12b22c5341f // indirect
-	github.com/aws/aws-sdk-go v0.0.0-20171017211306-a28db88bdcd8 // indirect
+	github.com/aws/aws-sdk-go v0.0.0-20171017211306-a28db88bdcd8
 	github.com/blang/semver v3.5.1+incompatible
 	github.com/cheggaaa/pb v1.0.26 // indirect
 	github.com/concourse/s3-resource v1.0.0
@@ -0,0 +1,111 @@
+package gp
+
+import (
+	"sync"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+// lookupCache memoizes the release and product lookups made by a client so
+// that the same lookup is not repeated by each phase of a single check, get
+// or put. Releases of a product are forgotten whenever the client modifies
+// the product, as modifications such as adding product files change the
+// releases returned by Pivotal Network.
+type lookupCache struct {
+	mu sync.Mutex
+
+	releaseTypes []pivnet.ReleaseType
+	products     map[string]pivnet.Product
+	releaseLists map[string][]pivnet.Release
+	releasesByID map[string]map[int]pivnet.Release
+}
+
+func newLookupCache() *lookupCache {
+	return &lookupCache{
+		products:     map[string]pivnet.Product{},
+		releaseLists: map[string][]pivnet.Release{},
+		releasesByID: map[string]map[int]pivnet.Release{},
+	}
+}
+
+func (c *lookupCache) releaseTypesOrFetch(fetch func() ([]pivnet.ReleaseType, error)) ([]pivnet.ReleaseType, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.releaseTypes == nil {
+		releaseTypes, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		c.releaseTypes = releaseTypes
+	}
+
+	return append([]pivnet.ReleaseType{}, c.releaseTypes...), nil
+}
+
+func (c *lookupCache) productOrFetch(productSlug string, fetch func() (pivnet.Product, error)) (pivnet.Product, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	product, ok := c.products[productSlug]
+	if !ok {
+		var err error
+		product, err = fetch()
+		if err != nil {
+			return pivnet.Product{}, err
+		}
+		c.products[productSlug] = product
+	}
+
+	return product, nil
+}
+
+// releaseListOrFetch returns a copy of the releases of the product, as
+// callers may sort or filter the releases in place.
+func (c *lookupCache) releaseListOrFetch(productSlug string, fetch func() ([]pivnet.Release, error)) ([]pivnet.Release, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	releases, ok := c.releaseLists[productSlug]
+	if !ok {
+		var err error
+		releases, err = fetch()
+		if err != nil {
+			return nil, err
+		}
+		c.releaseLists[productSlug] = releases
+	}
+
+	return append([]pivnet.Release{}, releases...), nil
+}
+
+func (c *lookupCache) releaseOrFetch(productSlug string, releaseID int, fetch func() (pivnet.Release, error)) (pivnet.Release, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	release, ok := c.releasesByID[productSlug][releaseID]
+	if !ok {
+		var err error
+		release, err = fetch()
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+
+		if c.releasesByID[productSlug] == nil {
+			c.releasesByID[productSlug] = map[int]pivnet.Release{}
+		}
+		c.releasesByID[productSlug][releaseID] = release
+	}
+
+	return release, nil
+}
+
+// forgetReleases forgets the releases of the product, so that they are
+// looked up again after the product has been modified.
+func (c *lookupCache) forgetReleases(productSlug string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.releaseLists, productSlug)
+	delete(c.releasesByID, productSlug)
+}
@@ -0,0 +1,186 @@
+package gp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/logging"
+)
+
+// ConditionalTransport caches the responses to GET requests in a directory,
+// along with their ETag and Last-Modified headers, and makes later requests
+// for the same URL conditional on them. A response which has not changed is
+// served from the cache, so that frequent checks which find nothing new are
+// cheap for Pivotal Network and count for less against its rate limits.
+//
+// Responses are cached per scope, e.g. the API token, so that clients with
+// different access never share them. Failing to read or write the cache only
+// makes requests unconditional.
+type ConditionalTransport struct {
+	transport http.RoundTripper
+	dir       string
+	scope     string
+	logger    logger.Logger
+}
+
+type conditionalEntry struct {
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+func NewConditionalTransport(transport http.RoundTripper, dir string, scope string, logger logger.Logger) *ConditionalTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &ConditionalTransport{
+		transport: transport,
+		dir:       dir,
+		scope:     scope,
+		logger:    logger,
+	}
+}
+
+func (t *ConditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.transport.RoundTrip(req)
+	}
+
+	path := t.entryPath(req)
+
+	entry, cached := t.readEntry(path)
+	if cached {
+		conditional := new(http.Request)
+		*conditional = *req
+		conditional.Header = make(http.Header, len(req.Header)+2)
+		for k, v := range req.Header {
+			conditional.Header[k] = v
+		}
+
+		if entry.ETag != "" {
+			conditional.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			conditional.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+
+		req = conditional
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+
+		t.logger.Debug(fmt.Sprintf("Not modified since the last request: '%s'", req.URL.Path))
+
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        entry.Header,
+			Body:          ioutil.NopCloser(bytes.NewReader(entry.Body)),
+			ContentLength: int64(len(entry.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	err = t.writeEntry(path, conditionalEntry{
+		ETag:         etag,
+		LastModified: lastModified,
+		Header:       resp.Header,
+		Body:         body,
+	})
+	if err != nil {
+		logging.Warn(t.logger, fmt.Sprintf("Could not cache the response to '%s': %s", req.URL.Path, err.Error()))
+	}
+
+	return resp, nil
+}
+
+// entryPath returns the path at which the response to the request is cached.
+// Keys are hashed so that they cannot escape the cache directory, nor reveal
+// the scope.
+func (t *ConditionalTransport) entryPath(req *http.Request) string {
+	sum := sha256.Sum256([]byte(t.scope + "\n" + req.URL.String()))
+	return filepath.Join(t.dir, "response-"+hex.EncodeToString(sum[:])+".json")
+}
+
+// readEntry returns the cached response at path. A missing or corrupt entry
+// is a cache miss, as the response is requested again and the entry replaced.
+func (t *ConditionalTransport) readEntry(path string) (conditionalEntry, bool) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return conditionalEntry{}, false
+	}
+
+	var entry conditionalEntry
+	err = json.Unmarshal(b, &entry)
+	if err != nil || (entry.ETag == "" && entry.LastModified == "") {
+		return conditionalEntry{}, false
+	}
+
+	return entry, true
+}
+
+// writeEntry replaces the cached response at path, via a temporary file so
+// that concurrent checks never read a partially written entry.
+func (t *ConditionalTransport) writeEntry(path string, entry conditionalEntry) error {
+	err := os.MkdirAll(t.dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(t.dir, ".response-")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath)
+
+	_, err = tempFile.Write(b)
+	if err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
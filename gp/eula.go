@@ -0,0 +1,62 @@
+package gp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+// eulaAcceptanceRequiredCode is the error returned by Pivotal Network for a
+// release whose EULA has not been accepted.
+const eulaAcceptanceRequiredCode = "eula_acceptance_required"
+
+// ErrEULAAcceptanceRequired is returned instead of the response of Pivotal
+// Network to a download, or to an acceptance, of a release whose EULA has not
+// been accepted. EULAs in some categories cannot be accepted through the API
+// and must be clicked through on the website by each user.
+type ErrEULAAcceptanceRequired struct {
+	ReleaseID int
+
+	// URL is the page of the release on which the EULA can be accepted.
+	URL string
+
+	Err error
+}
+
+func (e ErrEULAAcceptanceRequired) Error() string {
+	return fmt.Sprintf(
+		"EULA of release with ID: %d has not been accepted and cannot be accepted automatically - accept it at %s and retry (%s)",
+		e.ReleaseID,
+		e.URL,
+		e.Err.Error(),
+	)
+}
+
+func (c Client) eulaAcceptanceRequired(productSlug string, releaseID int, err error) ErrEULAAcceptanceRequired {
+	return ErrEULAAcceptanceRequired{
+		ReleaseID: releaseID,
+		URL:       fmt.Sprintf("%s/products/%s/releases/%d", c.host, productSlug, releaseID),
+		Err:       err,
+	}
+}
+
+// isEULAAcceptanceRequired returns whether err is the response of Pivotal
+// Network to a request for a release whose EULA has not been accepted.
+func isEULAAcceptanceRequired(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch e := err.(type) {
+	case pivnet.ErrUnavailableForLegalReasons:
+		return true
+	case pivnet.ErrPivnetOther:
+		if e.ResponseCode == http.StatusUnavailableForLegalReasons {
+			return true
+		}
+	}
+
+	return strings.Contains(err.Error(), eulaAcceptanceRequiredCode)
+}
@@ -0,0 +1,90 @@
+package gp_test
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EULA acceptance", func() {
+	var (
+		server     *httptest.Server
+		statusCode int
+
+		client *gp.Client
+	)
+
+	BeforeEach(func() {
+		statusCode = http.StatusUnavailableForLegalReasons
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(statusCode)
+			w.Write([]byte(`{"status":451,"message":"eula_acceptance_required"}`))
+		}))
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		client = gp.NewClient(
+			pivnet.ClientConfig{Host: server.URL, Token: "some-token"},
+			nil,
+			logshim.NewLogShim(logger, logger, true),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	expectEULAAcceptanceRequired := func(err error) {
+		Expect(err).To(BeAssignableToTypeOf(gp.ErrEULAAcceptanceRequired{}))
+
+		eulaErr := err.(gp.ErrEULAAcceptanceRequired)
+		Expect(eulaErr.ReleaseID).To(Equal(1234))
+		Expect(eulaErr.URL).To(Equal(server.URL + "/products/some-product/releases/1234"))
+	}
+
+	Context("when downloading a product file of a release whose EULA has not been accepted", func() {
+		It("returns where to accept the EULA", func() {
+			file, err := ioutil.TempFile("", "pivnet-resource-eula")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(file.Name())
+			defer file.Close()
+
+			err = client.DownloadProductFile(file, "some-product", 1234, 1337, GinkgoWriter)
+			expectEULAAcceptanceRequired(err)
+		})
+
+		It("returns where to accept the EULA instead of the download URL", func() {
+			_, err := client.ProductFileDownloadURL("some-product", 1234, 1337)
+			expectEULAAcceptanceRequired(err)
+		})
+	})
+
+	Context("when the EULA cannot be accepted through the API", func() {
+		It("returns where to accept the EULA", func() {
+			err := client.AcceptEULA("some-product", 1234)
+			expectEULAAcceptanceRequired(err)
+		})
+	})
+
+	Context("when Pivotal Network fails for another reason", func() {
+		BeforeEach(func() {
+			statusCode = http.StatusInternalServerError
+		})
+
+		It("returns the error as is", func() {
+			_, err := client.ProductFileDownloadURL("some-product", 1234, 1337)
+			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(BeAssignableToTypeOf(gp.ErrEULAAcceptanceRequired{}))
+		})
+	})
+})
@@ -1,6 +1,8 @@
 package gp
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,11 +14,21 @@ import (
 
 type Client struct {
 	client pivnet.Client
+	cache  *lookupCache
+	host   string
 }
 
-func NewClient(config pivnet.ClientConfig, logger logger.Logger) *Client {
+func NewClient(config pivnet.ClientConfig, transport http.RoundTripper, logger logger.Logger) *Client {
+	client := pivnet.NewClient(config, logger)
+
+	if transport != nil {
+		client.HTTP.Transport = transport
+	}
+
 	return &Client{
-		client: pivnet.NewClient(config, logger),
+		client: client,
+		cache:  newLookupCache(),
+		host:   config.Host,
 	}
 }
 
@@ -25,11 +37,11 @@ func (c Client) GetFederationToken(productSlug string) (pivnet.FederationToken,
 }
 
 func (c Client) ReleaseTypes() ([]pivnet.ReleaseType, error) {
-	return c.client.ReleaseTypes.Get()
+	return c.cache.releaseTypesOrFetch(c.client.ReleaseTypes.Get)
 }
 
 func (c Client) S3PrefixForProductSlug(productSlug string) (string, error) {
-	product, err := c.client.Products.Get(productSlug)
+	product, err := c.FindProductForSlug(productSlug)
 	if err != nil {
 		return "", err
 	}
@@ -38,11 +50,20 @@ func (c Client) S3PrefixForProductSlug(productSlug string) (string, error) {
 }
 
 func (c Client) ReleasesForProductSlug(productSlug string) ([]pivnet.Release, error) {
-	return c.client.Releases.List(productSlug)
+	return c.cache.releaseListOrFetch(productSlug, func() ([]pivnet.Release, error) {
+		return c.listReleases(productSlug, 0)
+	})
+}
+
+// RecentReleasesForProductSlug returns at most limit releases of the product,
+// the most recent first, listing only as many pages as are needed for them.
+// As they are not all of the releases of the product they are not cached.
+func (c Client) RecentReleasesForProductSlug(productSlug string, limit int) ([]pivnet.Release, error) {
+	return c.listReleases(productSlug, limit)
 }
 
 func (c Client) GetRelease(productSlug string, version string) (pivnet.Release, error) {
-	releases, err := c.client.Releases.List(productSlug)
+	releases, err := c.ReleasesForProductSlug(productSlug)
 	if err != nil {
 		return pivnet.Release{}, err
 	}
@@ -59,43 +80,72 @@ func (c Client) GetRelease(productSlug string, version string) (pivnet.Release,
 		return pivnet.Release{}, fmt.Errorf("release not found")
 	}
 
-	release, err := c.client.Releases.Get(productSlug, foundRelease.ID)
+	release, err := c.cache.releaseOrFetch(productSlug, foundRelease.ID, func() (pivnet.Release, error) {
+		return c.client.Releases.Get(productSlug, foundRelease.ID)
+	})
 	if err != nil {
 		return pivnet.Release{}, err
 	}
 	return release, nil
 }
 
+// GetReleaseByID returns the release with the ID, without listing the
+// releases of the product.
+func (c Client) GetReleaseByID(productSlug string, releaseID int) (pivnet.Release, error) {
+	return c.cache.releaseOrFetch(productSlug, releaseID, func() (pivnet.Release, error) {
+		return c.client.Releases.Get(productSlug, releaseID)
+	})
+}
+
 func (c Client) UpdateRelease(productSlug string, release pivnet.Release) (pivnet.Release, error) {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.Releases.Update(productSlug, release)
 }
 
 func (c Client) CreateRelease(config pivnet.CreateReleaseConfig) (pivnet.Release, error) {
+	defer c.cache.forgetReleases(config.ProductSlug)
 	return c.client.Releases.Create(config)
 }
 
 func (c Client) DeleteRelease(productSlug string, release pivnet.Release) error {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.Releases.Delete(productSlug, release)
 }
 
 func (c Client) AddUserGroup(productSlug string, releaseID int, userGroupID int) error {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.UserGroups.AddToRelease(productSlug, releaseID, userGroupID)
 }
 
+func (c Client) AllUserGroups() ([]pivnet.UserGroup, error) {
+	return c.client.UserGroups.List()
+}
+
 func (c Client) UserGroups(productSlug string, releaseID int) ([]pivnet.UserGroup, error) {
 	return c.client.UserGroups.ListForRelease(productSlug, releaseID)
 }
 
 func (c Client) AcceptEULA(productSlug string, releaseID int) error {
-	return c.client.EULA.Accept(productSlug, releaseID)
+	err := c.client.EULA.Accept(productSlug, releaseID)
+	if isEULAAcceptanceRequired(err) {
+		return c.eulaAcceptanceRequired(productSlug, releaseID, err)
+	}
+
+	return err
 }
 
 func (c Client) EULAs() ([]pivnet.EULA, error) {
 	return c.client.EULA.List()
 }
 
+func (c Client) EULA(eulaSlug string) (pivnet.EULA, error) {
+	return c.client.EULA.Get(eulaSlug)
+}
+
 func (c Client) FindProductForSlug(slug string) (pivnet.Product, error) {
-	return c.client.Products.Get(slug)
+	return c.cache.productOrFetch(slug, func() (pivnet.Product, error) {
+		return c.client.Products.Get(slug)
+	})
 }
 
 func (c Client) ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error) {
@@ -115,27 +165,173 @@ func (c Client) ProductFileForRelease(productSlug string, releaseID int, product
 }
 
 func (c Client) DeleteProductFile(productSlug string, releaseID int) (pivnet.ProductFile, error) {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.ProductFiles.Delete(productSlug, releaseID)
 }
 
 func (c Client) CreateProductFile(config pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+	defer c.cache.forgetReleases(config.ProductSlug)
 	return c.client.ProductFiles.Create(config)
 }
 
 func (c Client) AddProductFile(productSlug string, releaseID int, productFileID int) error {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.ProductFiles.AddToRelease(productSlug, releaseID, productFileID)
 }
 
+// ProductFileAttributes are additional product file attributes used by
+// marketplace listings and export review. They are not supported by
+// pivnet.CreateProductFileConfig so are set with a separate request.
+type ProductFileAttributes struct {
+	PlatformArchitecture  string   `json:"platform_architecture,omitempty"`
+	IncludedOSSComponents []string `json:"included_oss_components,omitempty"`
+	ECCN                  string   `json:"eccn,omitempty"`
+	LicenseException      string   `json:"license_exception,omitempty"`
+}
+
+func (a ProductFileAttributes) IsEmpty() bool {
+	return a.PlatformArchitecture == "" &&
+		len(a.IncludedOSSComponents) == 0 &&
+		a.ECCN == "" &&
+		a.LicenseException == ""
+}
+
+func (c Client) UpdateProductFileAttributes(productSlug string, productFileID int, attributes ProductFileAttributes) error {
+	defer c.cache.forgetReleases(productSlug)
+
+	url := fmt.Sprintf("/products/%s/product_files/%d", productSlug, productFileID)
+
+	body := struct {
+		ProductFile ProductFileAttributes `json:"product_file"`
+	}{
+		ProductFile: attributes,
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		// Untested as it is too hard to force json.Marshal to return an error
+		return err
+	}
+
+	resp, err := c.client.MakeRequest("PATCH", url, http.StatusOK, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// ReleaseAttributes are release attributes shown on the download page of the
+// product. They are not supported by pivnet.CreateReleaseConfig so are set
+// with a separate request.
+type ReleaseAttributes struct {
+	// Series, e.g. '2.7', is the series under which the release is nested.
+	Series string `json:"series,omitempty"`
+
+	RequiresLicenseKey bool   `json:"requires_license_key,omitempty"`
+	LicenseTermsURL    string `json:"license_terms_url,omitempty"`
+}
+
+func (a ReleaseAttributes) IsEmpty() bool {
+	return a.Series == "" &&
+		!a.RequiresLicenseKey &&
+		a.LicenseTermsURL == ""
+}
+
+func (c Client) UpdateReleaseAttributes(productSlug string, releaseID int, attributes ReleaseAttributes) error {
+	defer c.cache.forgetReleases(productSlug)
+
+	url := fmt.Sprintf("/products/%s/releases/%d", productSlug, releaseID)
+
+	body := struct {
+		Release ReleaseAttributes `json:"release"`
+	}{
+		Release: attributes,
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		// Untested as it is too hard to force json.Marshal to return an error
+		return err
+	}
+
+	resp, err := c.client.MakeRequest("PATCH", url, http.StatusOK, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
 func (c Client) CreateFileGroup(config pivnet.CreateFileGroupConfig) (pivnet.FileGroup, error) {
+	defer c.cache.forgetReleases(config.ProductSlug)
 	return c.client.FileGroups.Create(config)
 }
 
 func (c Client) AddFileGroup(productSlug string, releaseID int, fileGroupID int) error {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.FileGroups.AddToRelease(productSlug, releaseID, fileGroupID)
 }
 
 func (c Client) DownloadProductFile(writer *os.File, productSlug string, releaseID int, productFileID int, progressWriter io.Writer) error {
-	return c.client.ProductFiles.DownloadForRelease(writer, productSlug, releaseID, productFileID, progressWriter)
+	err := c.client.ProductFiles.DownloadForRelease(writer, productSlug, releaseID, productFileID, progressWriter)
+	if isEULAAcceptanceRequired(err) {
+		return c.eulaAcceptanceRequired(productSlug, releaseID, err)
+	}
+
+	return err
+}
+
+// ProductFileDownloadURL returns the signed URL from which the product file
+// is downloaded, without downloading it, so that it can be read by range.
+func (c Client) ProductFileDownloadURL(productSlug string, releaseID int, productFileID int) (string, error) {
+	url := fmt.Sprintf(
+		"/products/%s/releases/%d/product_files/%d/download",
+		productSlug,
+		releaseID,
+		productFileID,
+	)
+
+	req, err := c.client.CreateRequest("POST", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	httpClient := *c.client.HTTP
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnavailableForLegalReasons {
+		return "", c.eulaAcceptanceRequired(productSlug, releaseID, fmt.Errorf(
+			"failed to get download URL for product file %d: unexpected status code %d",
+			productFileID,
+			resp.StatusCode,
+		))
+	}
+
+	if resp.StatusCode != http.StatusFound {
+		return "", fmt.Errorf(
+			"failed to get download URL for product file %d: unexpected status code %d",
+			productFileID,
+			resp.StatusCode,
+		)
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("failed to get download URL for product file %d: no location", productFileID)
+	}
+
+	return location, nil
 }
 
 func (c Client) FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error) {
@@ -147,6 +343,7 @@ func (c Client) ReleaseDependencies(productSlug string, releaseID int) ([]pivnet
 }
 
 func (c Client) AddReleaseDependency(productSlug string, releaseID int, dependentReleaseID int) error {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.ReleaseDependencies.Add(productSlug, releaseID, dependentReleaseID)
 }
 
@@ -155,6 +352,7 @@ func (c Client) DependencySpecifiers(productSlug string, releaseID int) ([]pivne
 }
 
 func (c Client) CreateDependencySpecifier(productSlug string, releaseID int, dependentProductSlug string, specifier string) (pivnet.DependencySpecifier, error) {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.DependencySpecifiers.Create(productSlug, releaseID, dependentProductSlug, specifier)
 }
 
@@ -167,10 +365,12 @@ func (c Client) UpgradePathSpecifiers(productSlug string, releaseID int) ([]pivn
 }
 
 func (c Client) CreateUpgradePathSpecifier(productSlug string, releaseID int, specifier string) (pivnet.UpgradePathSpecifier, error) {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.UpgradePathSpecifiers.Create(productSlug, releaseID, specifier)
 }
 
 func (c Client) AddReleaseUpgradePath(productSlug string, releaseID int, previousReleaseID int) error {
+	defer c.cache.forgetReleases(productSlug)
 	return c.client.ReleaseUpgradePaths.Add(productSlug, releaseID, previousReleaseID)
 }
 
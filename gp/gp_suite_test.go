@@ -0,0 +1,13 @@
+package gp_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GP Suite")
+}
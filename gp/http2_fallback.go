@@ -0,0 +1,144 @@
+package gp
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+// HTTP2FallbackTransport makes requests with a transport which may negotiate
+// HTTP/2 until one fails with an HTTP/2 error, e.g. because an intercepting
+// proxy resets long-lived streams, after which it makes every request over
+// HTTP/1.1 instead.
+//
+// A request which fails before its response is received is retried over
+// HTTP/1.1 immediately if its body can be replayed. A response body which
+// fails while being read cannot be retried transparently, so the error is
+// returned and the retry of its caller, e.g. of a download, is made over
+// HTTP/1.1.
+type HTTP2FallbackTransport struct {
+	transport http.RoundTripper
+	fallback  http.RoundTripper
+	logger    logger.Logger
+
+	downgraded *int32
+}
+
+func NewHTTP2FallbackTransport(config TransportConfig, logger logger.Logger) *HTTP2FallbackTransport {
+	fallback := NewTransport(config)
+	fallback.TLSClientConfig.NextProtos = []string{"http/1.1"}
+
+	// A non-nil, empty TLSNextProto prevents net/http from using HTTP/2.
+	fallback.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+
+	// A transport with a custom dialer or TLS config only negotiates HTTP/2
+	// if it is forced to.
+	transport := NewTransport(config)
+	transport.ForceAttemptHTTP2 = true
+
+	return &HTTP2FallbackTransport{
+		transport:  transport,
+		fallback:   fallback,
+		logger:     logger,
+		downgraded: new(int32),
+	}
+}
+
+func (t *HTTP2FallbackTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.LoadInt32(t.downgraded) == 1 {
+		return t.fallback.RoundTrip(req)
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		if !isHTTP2Error(err) {
+			return nil, err
+		}
+
+		t.downgrade(req, err)
+
+		retry, ok := replayable(req)
+		if !ok {
+			return nil, err
+		}
+
+		return t.fallback.RoundTrip(retry)
+	}
+
+	resp.Body = &http2FallbackBody{
+		ReadCloser: resp.Body,
+		transport:  t,
+		req:        req,
+	}
+
+	return resp, nil
+}
+
+// downgrade makes every later request over HTTP/1.1, logging the first
+// error which caused it.
+func (t *HTTP2FallbackTransport) downgrade(req *http.Request, err error) {
+	if !atomic.CompareAndSwapInt32(t.downgraded, 0, 1) {
+		return
+	}
+
+	t.logger.Info(fmt.Sprintf(
+		"HTTP/2 request to '%s' failed, possibly due to an intercepting proxy - using HTTP/1.1 from now on: %s",
+		req.URL.Host,
+		err.Error(),
+	))
+}
+
+// replayable returns a copy of req with a fresh body, or false if its body
+// has already been consumed and cannot be recreated.
+func replayable(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+
+	retry := new(http.Request)
+	*retry = *req
+	retry.Body = body
+
+	return retry, true
+}
+
+// http2FallbackBody downgrades its transport if reading it fails with an
+// HTTP/2 error.
+type http2FallbackBody struct {
+	io.ReadCloser
+	transport *HTTP2FallbackTransport
+	req       *http.Request
+}
+
+func (b *http2FallbackBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && isHTTP2Error(err) {
+		b.transport.downgrade(b.req, err)
+	}
+
+	return n, err
+}
+
+// isHTTP2Error returns whether err is one of the stream or connection errors
+// of the net/http HTTP/2 implementation, which does not export their types.
+func isHTTP2Error(err error) bool {
+	message := err.Error()
+
+	return strings.Contains(message, "http2:") ||
+		strings.Contains(message, "stream error:") ||
+		strings.Contains(message, "HTTP_1_1_REQUIRED")
+}
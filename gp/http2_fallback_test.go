@@ -0,0 +1,116 @@
+package gp_test
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTP2FallbackTransport", func() {
+	var (
+		fakeLogger logger.Logger
+
+		server *httptest.Server
+
+		mu         sync.Mutex
+		protocols  []string
+		breakHTTP2 bool
+
+		client *http.Client
+	)
+
+	BeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		protocols = nil
+		breakHTTP2 = false
+
+		server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			protocols = append(protocols, r.Proto)
+			mu.Unlock()
+
+			// Resetting the stream mimics an intercepting proxy which does
+			// not support HTTP/2.
+			if r.ProtoMajor == 2 && breakHTTP2 {
+				panic(http.ErrAbortHandler)
+			}
+
+			ioutil.ReadAll(r.Body)
+			w.Write([]byte("some-response"))
+		}))
+		server.EnableHTTP2 = true
+		server.StartTLS()
+
+		transport := gp.NewHTTP2FallbackTransport(gp.TransportConfig{SkipSSLValidation: true}, fakeLogger)
+		client = &http.Client{Transport: transport}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	requestProtocols := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return protocols
+	}
+
+	get := func() string {
+		resp, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		return string(body)
+	}
+
+	It("makes requests over HTTP/2", func() {
+		Expect(get()).To(Equal("some-response"))
+
+		Expect(requestProtocols()).To(Equal([]string{"HTTP/2.0"}))
+	})
+
+	Context("when HTTP/2 requests fail", func() {
+		BeforeEach(func() {
+			breakHTTP2 = true
+		})
+
+		It("retries the request over HTTP/1.1", func() {
+			Expect(get()).To(Equal("some-response"))
+
+			Expect(requestProtocols()).To(Equal([]string{"HTTP/2.0", "HTTP/1.1"}))
+		})
+
+		It("makes every later request over HTTP/1.1", func() {
+			get()
+			Expect(get()).To(Equal("some-response"))
+
+			Expect(requestProtocols()).To(Equal([]string{"HTTP/2.0", "HTTP/1.1", "HTTP/1.1"}))
+		})
+
+		Context("when the request body can be replayed", func() {
+			It("retries the request with its body", func() {
+				resp, err := client.Post(server.URL, "text/plain", strings.NewReader("some-body"))
+				Expect(err).NotTo(HaveOccurred())
+				resp.Body.Close()
+
+				Expect(requestProtocols()).To(Equal([]string{"HTTP/2.0", "HTTP/1.1"}))
+			})
+		})
+	})
+})
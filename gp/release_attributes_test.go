@@ -0,0 +1,81 @@
+package gp_test
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpdateReleaseAttributes", func() {
+	var (
+		server *httptest.Server
+
+		methods []string
+		paths   []string
+		bodies  []string
+
+		client *gp.Client
+	)
+
+	BeforeEach(func() {
+		methods = nil
+		paths = nil
+		bodies = nil
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+
+			methods = append(methods, r.Method)
+			paths = append(paths, r.URL.Path)
+			bodies = append(bodies, string(body))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		}))
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		client = gp.NewClient(
+			pivnet.ClientConfig{Host: server.URL, Token: "some-token"},
+			nil,
+			logshim.NewLogShim(logger, logger, true),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sets the series and the license of the release in a single request", func() {
+		err := client.UpdateReleaseAttributes("some-product", 1234, gp.ReleaseAttributes{
+			Series:             "2.7",
+			RequiresLicenseKey: true,
+			LicenseTermsURL:    "https://example.com/license-terms.pdf",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(methods).To(Equal([]string{"PATCH"}))
+		Expect(paths[0]).To(HaveSuffix("/products/some-product/releases/1234"))
+		Expect(bodies[0]).To(MatchJSON(`{
+			"release": {
+				"series": "2.7",
+				"requires_license_key": true,
+				"license_terms_url": "https://example.com/license-terms.pdf"
+			}
+		}`))
+	})
+
+	It("leaves out attributes which are not set", func() {
+		err := client.UpdateReleaseAttributes("some-product", 1234, gp.ReleaseAttributes{Series: "2.7"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(bodies[0]).To(MatchJSON(`{"release": {"series": "2.7"}}`))
+	})
+})
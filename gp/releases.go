@@ -0,0 +1,114 @@
+package gp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+const (
+	// releasesPerPage is the number of releases requested per page when
+	// listing the releases of a product.
+	releasesPerPage = 100
+
+	// maxReleasePages bounds the pages listed for a product, in case
+	// Pivotal Network keeps returning new releases.
+	maxReleasePages = 1000
+)
+
+type releasesPage struct {
+	Releases []pivnet.Release `json:"releases"`
+	Links    struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"_links"`
+}
+
+// listReleases returns the releases of the product from every page of the
+// listing, as products with many releases are not listed in full on the
+// first page. If limit is positive, only the first limit releases are
+// listed, fetching no more pages than are needed for them.
+func (c Client) listReleases(productSlug string, limit int) ([]pivnet.Release, error) {
+	return allReleases(func(page int) (releasesPage, error) {
+		return c.releasesPage(productSlug, page)
+	}, limit)
+}
+
+func (c Client) releasesPage(productSlug string, page int) (releasesPage, error) {
+	req, err := c.client.CreateRequest("GET", fmt.Sprintf("/products/%s/releases", productSlug), nil)
+	if err != nil {
+		return releasesPage{}, err
+	}
+
+	query := req.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(releasesPerPage))
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := c.client.HTTP.Do(req)
+	if err != nil {
+		return releasesPage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return releasesPage{}, fmt.Errorf(
+			"failed to list releases of product '%s': unexpected status code %d",
+			productSlug,
+			resp.StatusCode,
+		)
+	}
+
+	var p releasesPage
+	err = json.NewDecoder(resp.Body).Decode(&p)
+	if err != nil {
+		return releasesPage{}, fmt.Errorf("failed to decode releases of product '%s': %s", productSlug, err.Error())
+	}
+
+	return p, nil
+}
+
+// allReleases fetches pages, starting from the first, until a page has no
+// next link and is not full, or adds no releases which were not already
+// listed, so that an endpoint which ignores the page returns its releases
+// once. If limit is positive, it also stops once limit releases are listed.
+func allReleases(fetch func(page int) (releasesPage, error), limit int) ([]pivnet.Release, error) {
+	releases := []pivnet.Release{}
+	seen := map[int]bool{}
+
+	for page := 1; page <= maxReleasePages; page++ {
+		p, err := fetch(page)
+		if err != nil {
+			return nil, err
+		}
+
+		var added int
+		for _, r := range p.Releases {
+			if seen[r.ID] {
+				continue
+			}
+
+			seen[r.ID] = true
+			releases = append(releases, r)
+			added++
+		}
+
+		if limit > 0 && len(releases) >= limit {
+			return releases[:limit], nil
+		}
+
+		if added == 0 {
+			break
+		}
+
+		if p.Links.Next == nil && len(p.Releases) < releasesPerPage {
+			break
+		}
+	}
+
+	return releases, nil
+}
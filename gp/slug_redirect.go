@@ -0,0 +1,200 @@
+package gp
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+// maxSlugRedirects bounds the redirects followed for a single request, in
+// case products are redirected to each other.
+const maxSlugRedirects = 10
+
+// SlugRedirectTransport follows the redirects of the Pivotal Network API for
+// products which have moved to a new slug.
+//
+// net/http follows some redirects itself, but turns a redirected POST or
+// PATCH into a GET, so requests which modify a moved product fail while
+// requests which read it succeed. Instead, a redirect to the same path under
+// another product slug is followed here with the same method and body, and
+// later requests for the previous slug go directly to the canonical one.
+//
+// If strict is set, a redirect fails the request instead, so that a pipeline
+// is updated to the canonical slug rather than relying on the redirect.
+type SlugRedirectTransport struct {
+	transport http.RoundTripper
+	strict    bool
+	logger    logger.Logger
+
+	mu    *sync.Mutex
+	moved map[string]string
+}
+
+func NewSlugRedirectTransport(transport http.RoundTripper, strict bool, logger logger.Logger) *SlugRedirectTransport {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &SlugRedirectTransport{
+		transport: transport,
+		strict:    strict,
+		logger:    logger,
+		mu:        &sync.Mutex{},
+		moved:     map[string]string{},
+	}
+}
+
+func (t *SlugRedirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if slug, ok := productSlugFromPath(req.URL.Path); ok {
+		if canonical, moved := t.canonicalSlug(slug); moved {
+			u := *req.URL
+			u.Path = strings.Replace(u.Path, "/products/"+slug, "/products/"+canonical, 1)
+			u.RawPath = ""
+
+			redirected, ok := withURL(req, &u)
+			if ok {
+				req = redirected
+			}
+		}
+	}
+
+	for i := 0; ; i++ {
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil || !isRedirect(resp.StatusCode) {
+			return resp, err
+		}
+
+		slug, canonical, location, ok := movedProduct(req, resp)
+		if !ok {
+			return resp, nil
+		}
+
+		if i == maxSlugRedirects {
+			resp.Body.Close()
+			return nil, fmt.Errorf("stopped after %d redirects of product '%s'", maxSlugRedirects, slug)
+		}
+
+		if t.strict {
+			resp.Body.Close()
+			return nil, fmt.Errorf(
+				"product_slug '%s' has moved to '%s' and strict_slug is set - update product_slug to '%s'",
+				slug,
+				canonical,
+				canonical,
+			)
+		}
+
+		redirected, ok := withURL(req, location)
+		if !ok {
+			// The body cannot be sent again, so leave the redirect to the
+			// caller.
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		t.recordMove(slug, canonical)
+		req = redirected
+	}
+}
+
+// canonicalSlug returns the slug which a previous request found the product
+// to have moved to.
+func (t *SlugRedirectTransport) canonicalSlug(slug string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	canonical, ok := t.moved[slug]
+	return canonical, ok
+}
+
+// recordMove logs the first redirect of each slug, so that it is clear which
+// slug the pipeline should use.
+func (t *SlugRedirectTransport) recordMove(slug string, canonical string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.moved[slug]; ok {
+		return
+	}
+	t.moved[slug] = canonical
+
+	t.logger.Info(fmt.Sprintf(
+		"Product '%s' has moved to '%s' - following the redirect, but product_slug should be updated to '%s'",
+		slug,
+		canonical,
+		canonical,
+	))
+}
+
+// movedProduct returns the previous and canonical slugs of the product, and
+// the location to follow, if the response redirects the request to the same
+// host under a different product slug.
+func movedProduct(req *http.Request, resp *http.Response) (string, string, *url.URL, bool) {
+	header := resp.Header.Get("Location")
+	if header == "" {
+		return "", "", nil, false
+	}
+
+	location, err := req.URL.Parse(header)
+	if err != nil || location.Host != req.URL.Host {
+		return "", "", nil, false
+	}
+
+	slug, ok := productSlugFromPath(req.URL.Path)
+	if !ok {
+		return "", "", nil, false
+	}
+
+	canonical, ok := productSlugFromPath(location.Path)
+	if !ok || canonical == slug {
+		return "", "", nil, false
+	}
+
+	return slug, canonical, location, true
+}
+
+// productSlugFromPath returns the slug following /products/ in the path of an
+// API request, e.g. 'some-product' of /api/v2/products/some-product/releases.
+func productSlugFromPath(p string) (string, bool) {
+	segments := strings.Split(p, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if segments[i] == "products" && segments[i+1] != "" {
+			return segments[i+1], true
+		}
+	}
+
+	return "", false
+}
+
+// withURL returns a copy of req to the URL with a fresh body, or false if its
+// body cannot be sent again. The request of the caller is not modified.
+func withURL(req *http.Request, u *url.URL) (*http.Request, bool) {
+	replayed, ok := replayable(req)
+	if !ok {
+		return nil, false
+	}
+
+	redirected := new(http.Request)
+	*redirected = *replayed
+	redirected.URL = u
+	redirected.Host = ""
+
+	return redirected, true
+}
+
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently,
+		http.StatusFound,
+		http.StatusSeeOther,
+		http.StatusTemporaryRedirect,
+		http.StatusPermanentRedirect:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package gp
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultMaxIdleConns        = 100
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultIdleConnTimeout     = 90 * time.Second
+)
+
+// fipsCipherSuites are the TLS 1.2 cipher suites which use only
+// FIPS-approved algorithms. The cipher suites of TLS 1.3 are not
+// configurable, and all use FIPS-approved algorithms.
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+type TransportConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	TLSHandshakeTimeout time.Duration
+	SkipSSLValidation   bool
+
+	// FIPS restricts TLS to version 1.2 or later with FIPS-approved cipher
+	// suites and curves.
+	FIPS bool
+}
+
+// NewTransport returns an http.Transport that is intended to be shared
+// between every client created during a single run of the resource.
+// Zero values in the config fall back to the net/http defaults.
+func NewTransport(config TransportConfig) *http.Transport {
+	maxIdleConns := config.MaxIdleConns
+	if maxIdleConns == 0 {
+		maxIdleConns = defaultMaxIdleConns
+	}
+
+	tlsHandshakeTimeout := config.TLSHandshakeTimeout
+	if tlsHandshakeTimeout == 0 {
+		tlsHandshakeTimeout = defaultTLSHandshakeTimeout
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.SkipSSLValidation,
+	}
+
+	if config.FIPS {
+		tlsConfig.MinVersion = tls.VersionTLS12
+		tlsConfig.CipherSuites = fipsCipherSuites
+		tlsConfig.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+	}
+
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       defaultIdleConnTimeout,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
@@ -0,0 +1,91 @@
+package gpgsign
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SignatureSuffix is appended to the name of a file to form the name of its
+// detached signature.
+const SignatureSuffix = ".asc"
+
+type Signer struct {
+	privateKey string
+	passphrase string
+}
+
+// NewSigner returns a Signer which signs files with the ASCII-armored GPG
+// private key, unlocked with passphrase if it is protected. An empty private
+// key disables signing.
+func NewSigner(privateKey string, passphrase string) Signer {
+	return Signer{
+		privateKey: privateKey,
+		passphrase: passphrase,
+	}
+}
+
+// WriteSignature writes a detached, ASCII-armored signature of exactGlob
+// alongside it in sourcesDir and returns the glob of the signature. If no
+// private key is configured no signature is written and the returned glob is
+// empty.
+func (s Signer) WriteSignature(sourcesDir string, exactGlob string) (string, error) {
+	if s.privateKey == "" {
+		return "", nil
+	}
+
+	// The key is imported into a keyring of its own so that signing neither
+	// depends on nor modifies the keyring of the user.
+	homeDir, err := ioutil.TempDir("", "pivnet-resource-gpg")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		// Stop the agent which gpg starts for the keyring before removing it.
+		exec.Command("gpgconf", "--homedir", homeDir, "--kill", "gpg-agent").Run()
+		os.RemoveAll(homeDir)
+	}()
+
+	err = s.gpg(homeDir, s.privateKey, "--import")
+	if err != nil {
+		return "", fmt.Errorf("failed to import gpg_private_key: %s", err)
+	}
+
+	signatureGlob := exactGlob + SignatureSuffix
+
+	err = s.gpg(
+		homeDir,
+		s.passphrase,
+		"--pinentry-mode", "loopback",
+		"--passphrase-fd", "0",
+		"--yes",
+		"--armor",
+		"--detach-sign",
+		"--output", filepath.Join(sourcesDir, signatureGlob),
+		filepath.Join(sourcesDir, exactGlob),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign '%s': %s", exactGlob, err)
+	}
+
+	return signatureGlob, nil
+}
+
+func (s Signer) gpg(homeDir string, stdin string, args ...string) error {
+	cmd := exec.Command("gpg", append([]string{"--batch", "--homedir", homeDir}, args...)...)
+	cmd.Stdin = strings.NewReader(stdin)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package gpgsign_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGpgsign(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gpgsign Suite")
+}
@@ -0,0 +1,113 @@
+package gpgsign_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/gpgsign"
+)
+
+var _ = Describe("Signer", func() {
+	var (
+		sourcesDir string
+		keyringDir string
+
+		privateKey string
+		passphrase string
+	)
+
+	gpg := func(stdin string, args ...string) []byte {
+		cmd := exec.Command("gpg", append([]string{"--batch", "--homedir", keyringDir}, args...)...)
+		cmd.Stdin = strings.NewReader(stdin)
+		cmd.Stderr = GinkgoWriter
+
+		out, err := cmd.Output()
+		Expect(err).NotTo(HaveOccurred())
+		return out
+	}
+
+	BeforeEach(func() {
+		var err error
+		sourcesDir, err = ioutil.TempDir("", "gpgsign")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.MkdirAll(filepath.Join(sourcesDir, "some"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(sourcesDir, "some", "file"), []byte("some contents"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		passphrase = "some-passphrase"
+		privateKey = ""
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(sourcesDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("when no private key is configured", func() {
+		It("does not write a signature", func() {
+			signatureGlob, err := gpgsign.NewSigner("", "").WriteSignature(sourcesDir, "some/file")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(signatureGlob).To(BeEmpty())
+			Expect(filepath.Join(sourcesDir, "some", "file.asc")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("when a private key is configured", func() {
+		BeforeEach(func() {
+			if _, err := exec.LookPath("gpg"); err != nil {
+				Skip("gpg is not installed")
+			}
+
+			var err error
+			keyringDir, err = ioutil.TempDir("", "gpgsign-keyring")
+			Expect(err).NotTo(HaveOccurred())
+
+			gpg("", "--pinentry-mode", "loopback", "--passphrase", passphrase,
+				"--quick-generate-key", "Some Signer <signer@example.com>", "ed25519", "sign", "never")
+			privateKey = string(gpg("", "--pinentry-mode", "loopback", "--passphrase", passphrase,
+				"--armor", "--export-secret-keys"))
+		})
+
+		AfterEach(func() {
+			exec.Command("gpgconf", "--homedir", keyringDir, "--kill", "gpg-agent").Run()
+			err := os.RemoveAll(keyringDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("writes a detached signature of the file alongside it", func() {
+			signatureGlob, err := gpgsign.NewSigner(privateKey, passphrase).WriteSignature(sourcesDir, "some/file")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(signatureGlob).To(Equal("some/file.asc"))
+
+			signature, err := ioutil.ReadFile(filepath.Join(sourcesDir, signatureGlob))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(signature)).To(HavePrefix("-----BEGIN PGP SIGNATURE-----"))
+
+			gpg("", "--verify", filepath.Join(sourcesDir, signatureGlob), filepath.Join(sourcesDir, "some", "file"))
+		})
+
+		Context("when the passphrase is wrong", func() {
+			It("returns an error", func() {
+				_, err := gpgsign.NewSigner(privateKey, "wrong-passphrase").WriteSignature(sourcesDir, "some/file")
+				Expect(err).To(MatchError(ContainSubstring("failed to sign 'some/file'")))
+			})
+		})
+
+		Context("when the private key is invalid", func() {
+			It("returns an error", func() {
+				_, err := gpgsign.NewSigner("not a key", passphrase).WriteSignature(sourcesDir, "some/file")
+				Expect(err).To(MatchError(ContainSubstring("failed to import gpg_private_key")))
+			})
+		})
+	})
+})
@@ -1,12 +1,14 @@
 package in
 
 import (
+	"archive/tar"
+	"archive/zip"
 	"bufio"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -20,6 +22,10 @@ var archiveMimetypes = []string{
 	"application/zip",
 }
 
+// maxLinknameLength bounds the target read from a symlink entry of a zip
+// archive.
+const maxLinknameLength = 4096
+
 type Archive struct{}
 
 func (a *Archive) Mimetype(filename string) string {
@@ -43,6 +49,13 @@ func (a *Archive) Mimetype(filename string) string {
 	return ""
 }
 
+// Extract unpacks the archive into its directory and removes it. Entries are
+// streamed to disk one at a time, so archives much larger than the available
+// memory can be unpacked, including zip archives in the zip64 format.
+//
+// A gzipped tar archive is unpacked directly, without writing the tar archive
+// to disk first. Any other gzipped file is decompressed alongside it, as with
+// gunzip.
 func (a *Archive) Extract(mime, filename string) error {
 	destDir := filepath.Dir(filename)
 
@@ -51,49 +64,302 @@ func (a *Archive) Extract(mime, filename string) error {
 		return fmt.Errorf("failed to extract archive: %s with mimetype %s", err.Error(), mime)
 	}
 
-	if mime == "application/gzip" || mime == "application/x-gzip" {
-		fileInfos, err := ioutil.ReadDir(destDir)
+	return os.Remove(filename)
+}
+
+func inflate(mime, path, destination string) error {
+	switch mime {
+	case "application/zip":
+		return unzip(path, destination)
+
+	case "application/x-tar":
+		f, err := os.Open(path)
 		if err != nil {
-			return fmt.Errorf("failed to read dir: %s", err)
+			return err
 		}
+		defer f.Close()
+
+		return untar(f, destination)
 
-		if len(fileInfos) != 1 {
-			return fmt.Errorf("%d files found after gunzip; expected 1", len(fileInfos))
+	case "application/gzip", "application/x-gzip":
+		return gunzip(path, destination)
+
+	default:
+		return fmt.Errorf("don't know how to extract %s", mime)
+	}
+}
+
+func unzip(path, destination string) error {
+	// archive/zip reads the entries through the central directory, which
+	// locates them in the file without reading it into memory, and handles
+	// the zip64 extensions of archives larger than 4GB.
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		target, err := entryPath(destination, f.Name)
+		if err != nil {
+			return err
 		}
 
-		filename = filepath.Join(destDir, fileInfos[0].Name())
-		mime = a.Mimetype(filename)
-		if mime == "application/x-tar" {
-			err = inflate(mime, filename, destDir)
-			if err != nil {
-				return fmt.Errorf("failed to extract archive x-tar: %s", err.Error())
-			}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = os.MkdirAll(target, dirMode(mode))
+
+		case mode&os.ModeSymlink != 0:
+			err = extractZipSymlink(f, destination, target)
+
+		default:
+			err = extractZipFile(f, target)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func inflate(mime, path, destination string) error {
-	var cmd *exec.Cmd
+func extractZipFile(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
 
-	switch mime {
-	case "application/zip":
-		cmd = exec.Command("unzip", "-d", destination, path)
-		defer os.Remove(path)
+	return writeFile(target, rc, f.Mode())
+}
 
-	case "application/x-tar":
-		cmd = exec.Command("tar", "xf", path, "-C", destination)
-		defer os.Remove(path)
+func extractZipSymlink(f *zip.File, destination, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
 
-	case "application/gzip", "application/x-gzip":
-		cmd = exec.Command("gunzip", path)
+	// The content of a symlink entry is its target, which is short.
+	linkname, err := ioutil.ReadAll(io.LimitReader(rc, maxLinknameLength))
+	if err != nil {
+		return err
+	}
+
+	err = checkLinkname(destination, f.Name, target, string(linkname))
+	if err != nil {
+		return err
+	}
+
+	return symlink(string(linkname), target)
+}
 
+func untar(r io.Reader, destination string) error {
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeXGlobalHeader, tar.TypeXHeader:
+			continue
+		}
+
+		target, err := entryPath(destination, header.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := header.FileInfo().Mode()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, dirMode(mode))
+
+		case tar.TypeSymlink:
+			err = checkLinkname(destination, header.Name, target, header.Linkname)
+			if err == nil {
+				err = symlink(header.Linkname, target)
+			}
+
+		case tar.TypeLink:
+			var source string
+			source, err = entryPath(destination, header.Linkname)
+			if err == nil {
+				err = link(source, target)
+			}
+
+		case tar.TypeReg, tar.TypeRegA:
+			err = writeFile(target, tr, mode)
+
+		default:
+			// Devices and FIFOs are not expected in product files, and
+			// cannot be created without privileges.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func gunzip(path, destination string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	br := bufio.NewReader(gr)
+
+	mime, err := mimetype(br)
+	if err != nil {
+		return err
+	}
+
+	if mime == "application/x-tar" {
+		return untar(br, destination)
+	}
+
+	name, err := gunzippedName(filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(destination, name), br, 0644)
+}
+
+// gunzippedName returns the name of a decompressed gzipped file, following
+// the suffixes recognised by gunzip.
+func gunzippedName(name string) (string, error) {
+	switch {
+	case strings.HasSuffix(name, ".tgz"):
+		return strings.TrimSuffix(name, ".tgz") + ".tar", nil
+	case strings.HasSuffix(name, ".gz") && name != ".gz":
+		return strings.TrimSuffix(name, ".gz"), nil
 	default:
-		return fmt.Errorf("don't know how to extract %s", mime)
+		return "", fmt.Errorf("%s: unknown suffix", name)
 	}
+}
+
+// entryPath returns the path at which an entry of an archive is extracted,
+// refusing entries which would be written outside of the destination, either
+// directly or through a symlink extracted by an earlier entry.
+func entryPath(destination, name string) (string, error) {
+	target := filepath.Join(destination, name)
+
+	if !isWithin(destination, target) {
+		return "", fmt.Errorf("entry '%s' is outside of the destination", name)
+	}
+
+	destination = filepath.Clean(destination)
+	for dir := filepath.Dir(target); dir != destination && isWithin(destination, dir); dir = filepath.Dir(dir) {
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("entry '%s' is inside of a symlink", name)
+		}
+	}
+
+	return target, nil
+}
+
+// checkLinkname refuses symlinks whose targets are outside of the
+// destination, through which later entries could be written anywhere.
+func checkLinkname(destination, name, target, linkname string) error {
+	if filepath.IsAbs(linkname) {
+		return fmt.Errorf("symlink '%s' has an absolute target: '%s'", name, linkname)
+	}
+
+	if !isWithin(destination, filepath.Join(filepath.Dir(target), linkname)) {
+		return fmt.Errorf("symlink '%s' has a target outside of the destination: '%s'", name, linkname)
+	}
+
+	return nil
+}
+
+// isWithin returns whether path is the destination or inside of it.
+func isWithin(destination, path string) bool {
+	destination = filepath.Clean(destination)
+
+	return path == destination ||
+		strings.HasPrefix(path, destination+string(filepath.Separator))
+}
+
+// writeFile streams r to the file at path, creating its parent directories,
+// as the entries of an archive need not be preceded by their directories.
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	// An entry replaces a symlink extracted by an earlier entry, rather than
+	// being written through it.
+	info, err := os.Lstat(path)
+	if err == nil && info.Mode()&os.ModeSymlink != 0 {
+		err = os.Remove(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func symlink(linkname, path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	os.Remove(path)
+	return os.Symlink(linkname, path)
+}
+
+func link(source, path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	os.Remove(path)
+	return os.Link(source, path)
+}
 
-	return cmd.Run()
+// dirMode ensures extracted directories can be written to, so that their
+// entries can be extracted.
+func dirMode(mode os.FileMode) os.FileMode {
+	return mode.Perm() | 0700
 }
 
 func mimetype(r *bufio.Reader) (string, error) {
@@ -0,0 +1,307 @@
+package in_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/in"
+)
+
+func writeTarFiles(w io.Writer, files map[string]string) {
+	tarWriter := tar.NewWriter(w)
+
+	for name, content := range files {
+		Expect(tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})).To(Succeed())
+
+		_, err := tarWriter.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Expect(tarWriter.Close()).To(Succeed())
+}
+
+// archiveEntry is a regular file, or a symlink if linkname is set.
+type archiveEntry struct {
+	name     string
+	content  string
+	linkname string
+}
+
+func writeTarEntries(w io.Writer, entries []archiveEntry) {
+	tarWriter := tar.NewWriter(w)
+
+	for _, e := range entries {
+		header := &tar.Header{
+			Name:     e.name,
+			Mode:     0644,
+			Size:     int64(len(e.content)),
+			Typeflag: tar.TypeReg,
+		}
+		if e.linkname != "" {
+			header.Size = 0
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = e.linkname
+		}
+		Expect(tarWriter.WriteHeader(header)).To(Succeed())
+
+		_, err := tarWriter.Write([]byte(e.content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Expect(tarWriter.Close()).To(Succeed())
+}
+
+func writeZipEntries(w io.Writer, entries []archiveEntry) {
+	zipWriter := zip.NewWriter(w)
+
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name}
+		header.SetMode(0644)
+
+		content := e.content
+		if e.linkname != "" {
+			header.SetMode(os.ModeSymlink | 0777)
+			content = e.linkname
+		}
+
+		w, err := zipWriter.CreateHeader(header)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	Expect(zipWriter.Close()).To(Succeed())
+}
+
+var _ = Describe("Archive", func() {
+	var (
+		dir     string
+		archive *in.Archive
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "pivnet-resource-archive")
+		Expect(err).NotTo(HaveOccurred())
+
+		archive = &in.Archive{}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	readFile := func(name string) string {
+		contents, err := ioutil.ReadFile(filepath.Join(dir, name))
+		Expect(err).NotTo(HaveOccurred())
+		return string(contents)
+	}
+
+	Describe("Extract", func() {
+		It("extracts a zip archive and removes it", func() {
+			path := filepath.Join(dir, "some.zip")
+			f, err := os.Create(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			zipWriter := zip.NewWriter(f)
+			w, err := zipWriter.Create("some-dir/some-file")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte("some-content"))
+			Expect(err).NotTo(HaveOccurred())
+
+			header := &zip.FileHeader{Name: "some-script"}
+			header.SetMode(0755)
+			w, err = zipWriter.CreateHeader(header)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte("#!/bin/sh"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(zipWriter.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+
+			err = archive.Extract(archive.Mimetype(path), path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(readFile("some-dir/some-file")).To(Equal("some-content"))
+
+			info, err := os.Stat(filepath.Join(dir, "some-script"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm() & 0100).NotTo(BeZero())
+
+			Expect(path).NotTo(BeAnExistingFile())
+		})
+
+		It("extracts a gzipped tar archive without leaving the tar archive", func() {
+			path := filepath.Join(dir, "some.tgz")
+			f, err := os.Create(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			gzipWriter := gzip.NewWriter(f)
+			writeTarFiles(gzipWriter, map[string]string{
+				"some-dir/some-file": "some-content",
+				"other-file":         "other-content",
+			})
+			Expect(gzipWriter.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+
+			err = archive.Extract(archive.Mimetype(path), path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(readFile("some-dir/some-file")).To(Equal("some-content"))
+			Expect(readFile("other-file")).To(Equal("other-content"))
+
+			Expect(path).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(dir, "some.tar")).NotTo(BeAnExistingFile())
+		})
+
+		It("decompresses a gzipped file which is not a tar archive", func() {
+			path := filepath.Join(dir, "some-file.txt.gz")
+			f, err := os.Create(path)
+			Expect(err).NotTo(HaveOccurred())
+
+			gzipWriter := gzip.NewWriter(f)
+			_, err = gzipWriter.Write([]byte("some-content"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gzipWriter.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+
+			err = archive.Extract(archive.Mimetype(path), path)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(readFile("some-file.txt")).To(Equal("some-content"))
+			Expect(path).NotTo(BeAnExistingFile())
+		})
+
+		Context("when an entry is outside of the destination", func() {
+			It("returns an error", func() {
+				path := filepath.Join(dir, "some.tar")
+				f, err := os.Create(path)
+				Expect(err).NotTo(HaveOccurred())
+
+				writeTarFiles(f, map[string]string{
+					"../some-file": "some-content",
+				})
+				Expect(f.Close()).To(Succeed())
+
+				err = archive.Extract("application/x-tar", path)
+				Expect(err).To(MatchError(ContainSubstring("entry '../some-file' is outside of the destination")))
+
+				Expect(filepath.Join(filepath.Dir(dir), "some-file")).NotTo(BeAnExistingFile())
+			})
+		})
+
+		for _, format := range []string{"tar", "zip"} {
+			format := format
+
+			Context("when extracting symlinks from a "+format+" archive", func() {
+				var (
+					destination string
+					outside     string
+				)
+
+				BeforeEach(func() {
+					destination = filepath.Join(dir, "destination")
+					Expect(os.Mkdir(destination, 0755)).To(Succeed())
+
+					outside = filepath.Join(dir, "outside")
+					Expect(os.Mkdir(outside, 0755)).To(Succeed())
+				})
+
+				extract := func(entries []archiveEntry) error {
+					path := filepath.Join(destination, "some."+format)
+					f, err := os.Create(path)
+					Expect(err).NotTo(HaveOccurred())
+
+					mime := "application/x-tar"
+					if format == "zip" {
+						mime = "application/zip"
+						writeZipEntries(f, entries)
+					} else {
+						writeTarEntries(f, entries)
+					}
+					Expect(f.Close()).To(Succeed())
+
+					return archive.Extract(mime, path)
+				}
+
+				It("extracts symlinks to entries inside of the destination", func() {
+					err := extract([]archiveEntry{
+						{name: "some-dir/some-file", content: "some-content"},
+						{name: "some-link", linkname: "some-dir/some-file"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					linkname, err := os.Readlink(filepath.Join(destination, "some-link"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(linkname).To(Equal("some-dir/some-file"))
+				})
+
+				Context("when a symlink has an absolute target", func() {
+					It("returns an error", func() {
+						err := extract([]archiveEntry{
+							{name: "some-link", linkname: outside},
+							{name: "some-link/some-file", content: "some-content"},
+						})
+						Expect(err).To(MatchError(ContainSubstring("symlink 'some-link' has an absolute target")))
+
+						Expect(filepath.Join(outside, "some-file")).NotTo(BeAnExistingFile())
+					})
+				})
+
+				Context("when a symlink has a target outside of the destination", func() {
+					It("returns an error", func() {
+						err := extract([]archiveEntry{
+							{name: "some-dir/some-link", linkname: "../../outside"},
+							{name: "some-dir/some-link/some-file", content: "some-content"},
+						})
+						Expect(err).To(MatchError(ContainSubstring("symlink 'some-dir/some-link' has a target outside of the destination: '../../outside'")))
+
+						Expect(filepath.Join(outside, "some-file")).NotTo(BeAnExistingFile())
+					})
+				})
+
+				Context("when an entry is inside of a symlink", func() {
+					It("returns an error", func() {
+						err := extract([]archiveEntry{
+							{name: "some-dir/some-file", content: "some-content"},
+							{name: "some-link", linkname: "some-dir"},
+							{name: "some-link/some-file", content: "other-content"},
+						})
+						Expect(err).To(MatchError(ContainSubstring("entry 'some-link/some-file' is inside of a symlink")))
+
+						contents, err := ioutil.ReadFile(filepath.Join(destination, "some-dir", "some-file"))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(contents)).To(Equal("some-content"))
+					})
+				})
+
+				Context("when an entry replaces a symlink", func() {
+					It("replaces the symlink rather than writing through it", func() {
+						err := extract([]archiveEntry{
+							{name: "some-file", content: "some-content"},
+							{name: "some-link", linkname: "some-file"},
+							{name: "some-link", content: "other-content"},
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						contents, err := ioutil.ReadFile(filepath.Join(destination, "some-file"))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(contents)).To(Equal("some-content"))
+					})
+				})
+			})
+		}
+	})
+})
@@ -0,0 +1,108 @@
+package in
+
+import (
+	"fmt"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/capabilities"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
+	"github.com/pivotal-cf/pivnet-resource/versions"
+)
+
+// dryRun lists the product files of the release which the get would
+// download, along with their sizes, so that the cost of a get can be
+// estimated before running it. Nothing is downloaded or written and the EULA
+// is not accepted.
+func (c InCommand) dryRun(
+	input concourse.InRequest,
+	release pivnet.Release,
+	productSlug string,
+) (concourse.InResponse, error) {
+	c.logger.Info("Dry run - listing the files which would be downloaded, without downloading them")
+
+	releaseProductFiles, err := c.pivnetClient.ProductFilesForRelease(productSlug, release.ID)
+	if err != nil {
+		return concourse.InResponse{}, err
+	}
+
+	var unsupportedFeatures []string
+
+	fileGroups, err := c.pivnetClient.FileGroupsForRelease(productSlug, release.ID)
+	err = c.skipUnsupported(capabilities.FileGroups, err, &unsupportedFeatures)
+	if err != nil {
+		return concourse.InResponse{}, err
+	}
+
+	allProductFiles := releaseProductFiles
+	for _, fg := range fileGroups {
+		allProductFiles = append(allProductFiles, fg.ProductFiles...)
+	}
+
+	selected, err := c.selectProductFiles(input.Params.Globs, input.Params.PlatformFilter, allProductFiles)
+	if err != nil {
+		return concourse.InResponse{}, err
+	}
+
+	concourseMetadata := c.addReleaseMetadata([]concourse.Metadata{}, release)
+
+	var totalSize int64
+	for _, pf := range selected {
+		size := int64(pf.Size)
+		totalSize += size
+
+		c.logger.Info(fmt.Sprintf("Would download: '%s' (%s)", pf.Name, formatSize(size)))
+
+		concourseMetadata = append(concourseMetadata, concourse.Metadata{
+			Name:  "dry_run_file",
+			Value: fmt.Sprintf("%s (%s)", pf.Name, formatSize(size)),
+		})
+	}
+
+	c.logger.Info(fmt.Sprintf("Would download %d files totalling %s", len(selected), formatSize(totalSize)))
+
+	concourseMetadata = append(concourseMetadata,
+		concourse.Metadata{Name: "dry_run_file_count", Value: fmt.Sprintf("%d", len(selected))},
+		concourse.Metadata{Name: "dry_run_total_size", Value: formatSize(totalSize)},
+	)
+
+	version, fingerprint := resolution.SplitVersion(input.Version.ProductVersion)
+
+	versionWithFingerprint, err := versions.CombineVersionAndFingerprint(version, fingerprint)
+	if err != nil {
+		return concourse.InResponse{}, err
+	}
+
+	out := concourse.InResponse{
+		Version:  concourse.ReleaseVersion(versionWithFingerprint, release, input.Source),
+		Metadata: concourseMetadata,
+	}
+
+	if input.Source.ProductFilesFingerprint {
+		out.Version.ProductFilesFingerprint = resolution.ProductFilesFingerprint(releaseProductFiles)
+	}
+
+	return out, nil
+}
+
+// formatSize returns the size in bytes in the largest binary unit in which it
+// is at least 1, e.g. '1.50 GiB'.
+func formatSize(size int64) string {
+	units := []string{"KiB", "MiB", "GiB", "TiB"}
+
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	value := float64(size) / 1024
+	unit := units[0]
+	for _, u := range units[1:] {
+		if value < 1024 {
+			break
+		}
+		value /= 1024
+		unit = u
+	}
+
+	return fmt.Sprintf("%.2f %s", value, unit)
+}
@@ -101,4 +101,34 @@ var _ = Describe("FileWriter", func() {
 			Expect(unmarshalledMetadata).To(Equal(inputMetadata))
 		})
 	})
+
+	Describe("WriteEnvFile", func() {
+		It("writes the version, release ID and path of each downloaded file as shell variables", func() {
+			inputMetadata := metadata.Metadata{
+				Release: &metadata.Release{
+					ID:      1234,
+					Version: "1.2.3",
+				},
+				ProductFiles: []metadata.ProductFile{
+					{ID: 1, LocalFile: "some-product-1.2.3.pivotal"},
+					{ID: 2, LocalFile: "stemcells/some_product-1.2.3.pivotal"},
+					{ID: 3, LocalFile: ""},
+					{ID: 4, LocalFile: "it's-a-file.txt"},
+				},
+			}
+
+			err := fileWriter.WriteEnvFile(inputMetadata)
+			Expect(err).NotTo(HaveOccurred())
+
+			b, err := ioutil.ReadFile(filepath.Join(downloadDir, filesystem.EnvFileName))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(b)).To(Equal(`VERSION='1.2.3'
+RELEASE_ID='1234'
+FILE_SOME_PRODUCT_1_2_3_PIVOTAL_PATH='some-product-1.2.3.pivotal'
+FILE_SOME_PRODUCT_1_2_3_PIVOTAL_2_PATH='stemcells/some_product-1.2.3.pivotal'
+FILE_IT_S_A_FILE_TXT_PATH='it'\''s-a-file.txt'
+`))
+		})
+	})
 })
@@ -2,9 +2,12 @@ package filesystem
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
@@ -74,3 +77,76 @@ func (w FileWriter) WriteVersionFile(version string) error {
 
 	return nil
 }
+
+func (w FileWriter) WriteEULAFile(content string) error {
+	eulaFilepath := filepath.Join(w.downloadDir, "EULA.txt")
+
+	w.logger.Debug("Writing EULA to file")
+
+	err := ioutil.WriteFile(eulaFilepath, []byte(content), os.ModePerm)
+	if err != nil {
+		// Untested as it is too hard to force io.WriteFile to return an error
+		return err
+	}
+
+	return nil
+}
+
+// EnvFileName is the name of the file of shell variables written alongside
+// the metadata files so that tasks can source it rather than parse metadata.
+const EnvFileName = "pivnet.env"
+
+var invalidEnvNameChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// WriteEnvFile writes the version and ID of the release, and the path of each
+// downloaded file relative to the download directory, as shell variables:
+//
+//	VERSION='1.2.3'
+//	RELEASE_ID='1234'
+//	FILE_SOME_PRODUCT_1_2_3_PIVOTAL_PATH='some-product-1.2.3.pivotal'
+func (w FileWriter) WriteEnvFile(mdata metadata.Metadata) error {
+	envFilepath := filepath.Join(w.downloadDir, EnvFileName)
+
+	w.logger.Debug("Writing environment file")
+
+	var lines []string
+	if mdata.Release != nil {
+		lines = append(lines,
+			envLine("VERSION", mdata.Release.Version),
+			envLine("RELEASE_ID", fmt.Sprintf("%d", mdata.Release.ID)),
+		)
+	}
+
+	names := map[string]bool{}
+	for _, pf := range mdata.ProductFiles {
+		if pf.LocalFile == "" {
+			continue
+		}
+
+		name := fileEnvName(filepath.Base(pf.LocalFile))
+		if names[name] {
+			name = fileEnvName(fmt.Sprintf("%s_%d", filepath.Base(pf.LocalFile), pf.ID))
+		}
+		names[name] = true
+
+		lines = append(lines, envLine(name, filepath.ToSlash(pf.LocalFile)))
+	}
+
+	err := ioutil.WriteFile(envFilepath, []byte(strings.Join(lines, "\n")+"\n"), os.ModePerm)
+	if err != nil {
+		// Untested as it is too hard to force io.WriteFile to return an error
+		return err
+	}
+
+	return nil
+}
+
+func fileEnvName(fileName string) string {
+	name := invalidEnvNameChars.ReplaceAllString(strings.ToUpper(fileName), "_")
+	return fmt.Sprintf("FILE_%s_PATH", strings.Trim(name, "_"))
+}
+
+// envLine single-quotes the value so that it is not expanded by the shell.
+func envLine(name string, value string) string {
+	return fmt.Sprintf("%s='%s'", name, strings.Replace(value, "'", `'\''`, -1))
+}
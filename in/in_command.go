@@ -2,13 +2,24 @@ package in
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/boshrelease"
+	"github.com/pivotal-cf/pivnet-resource/capabilities"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/events"
+	"github.com/pivotal-cf/pivnet-resource/filenames"
+	"github.com/pivotal-cf/pivnet-resource/gp"
+	"github.com/pivotal-cf/pivnet-resource/logging"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/platform"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
 	"github.com/pivotal-cf/pivnet-resource/versions"
 )
 
@@ -18,11 +29,15 @@ type filterer interface {
 		productFiles []pivnet.ProductFile,
 		globs []string,
 	) ([]pivnet.ProductFile, error)
+	ProductFilesByPlatform(
+		productFiles []pivnet.ProductFile,
+		p platform.Platform,
+	) ([]pivnet.ProductFile, error)
 }
 
 //go:generate counterfeiter --fake-name FakeDownloader . downloader
 type downloader interface {
-	Download(productFiles []pivnet.ProductFile, productSlug string, releaseID int) ([]string, error)
+	Download(productFiles []pivnet.ProductFile, productSlug string, releaseID int) ([]string, map[int]error, error)
 }
 
 //go:generate counterfeiter --fake-name FakeFileSummer . fileSummer
@@ -35,12 +50,16 @@ type fileWriter interface {
 	WriteMetadataJSONFile(mdata metadata.Metadata) error
 	WriteMetadataYAMLFile(mdata metadata.Metadata) error
 	WriteVersionFile(versionWithFingerprint string) error
+	WriteEULAFile(content string) error
+	WriteEnvFile(mdata metadata.Metadata) error
 }
 
 //go:generate counterfeiter --fake-name FakePivnetClient . pivnetClient
 type pivnetClient interface {
 	GetRelease(productSlug string, version string) (pivnet.Release, error)
+	GetReleaseByID(productSlug string, releaseID int) (pivnet.Release, error)
 	AcceptEULA(productSlug string, releaseID int) error
+	EULA(eulaSlug string) (pivnet.EULA, error)
 	FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error)
 	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
 	ProductFileForRelease(productSlug string, releaseID int, productFileID int) (pivnet.ProductFile, error)
@@ -56,16 +75,59 @@ type archive interface {
 	Extract(mime, filename string) error
 }
 
+//go:generate counterfeiter --fake-name FakeEventEmitter . eventEmitter
+type eventEmitter interface {
+	Emit(event events.Event)
+}
+
+//go:generate counterfeiter --fake-name FakeBundleWriter . bundleWriter
+type bundleWriter interface {
+	Write(productFileNames []string) error
+}
+
+//go:generate counterfeiter --fake-name FakeChecksumSummer . checksumSummer
+type checksumSummer interface {
+	SumFile(filepath string) (map[string]string, error)
+}
+
+//go:generate counterfeiter --fake-name FakeBOSHReleaseReader . boshReleaseReader
+type boshReleaseReader interface {
+	Read(fileName string) (*boshrelease.Manifest, error)
+}
+
+//go:generate counterfeiter --fake-name FakeFileCache . fileCache
+type fileCache interface {
+	Add(sha256 string, filepath string) error
+}
+
+//go:generate counterfeiter --fake-name FakeLatestResolver . latestResolver
+type latestResolver interface {
+	Latest(source concourse.Source) (concourse.Version, error)
+}
+
+//go:generate counterfeiter --fake-name FakeCompressor . compressor
+type compressor interface {
+	Compress(format string, path string) (string, error)
+}
+
 type InCommand struct {
-	logger           logger.Logger
-	downloadDir      string
-	pivnetClient     pivnetClient
-	filter           filterer
-	downloader       downloader
-	sha256FileSummer fileSummer
-	md5FileSummer    fileSummer
-	fileWriter       fileWriter
-	archive          archive
+	logger            logger.Logger
+	downloadDir       string
+	pivnetClient      pivnetClient
+	filter            filterer
+	downloader        downloader
+	sha256FileSummer  fileSummer
+	md5FileSummer     fileSummer
+	fileWriter        fileWriter
+	archive           archive
+	eventEmitter      eventEmitter
+	bundleWriter      bundleWriter
+	boshReleaseReader boshReleaseReader
+	checksumSummer    checksumSummer
+	fileCache         fileCache
+	latestResolver    latestResolver
+	releaseResolver   *resolution.Resolver
+	compressor        compressor
 }
 
 func NewInCommand(
@@ -77,49 +139,87 @@ func NewInCommand(
 	md5FileSummer fileSummer,
 	fileWriter fileWriter,
 	archive archive,
+	eventEmitter eventEmitter,
+	bundleWriter bundleWriter,
+	boshReleaseReader boshReleaseReader,
+	checksumSummer checksumSummer,
+	fileCache fileCache,
+	latestResolver latestResolver,
+	compressor compressor,
 ) *InCommand {
 	return &InCommand{
-		logger:           logger,
-		pivnetClient:     pivnetClient,
-		filter:           filter,
-		downloader:       downloader,
-		sha256FileSummer: sha256FileSummer,
-		md5FileSummer:    md5FileSummer,
-		fileWriter:       fileWriter,
-		archive:          archive,
+		logger:            logger,
+		pivnetClient:      pivnetClient,
+		filter:            filter,
+		downloader:        downloader,
+		sha256FileSummer:  sha256FileSummer,
+		md5FileSummer:     md5FileSummer,
+		fileWriter:        fileWriter,
+		archive:           archive,
+		eventEmitter:      eventEmitter,
+		bundleWriter:      bundleWriter,
+		boshReleaseReader: boshReleaseReader,
+		checksumSummer:    checksumSummer,
+		fileCache:         fileCache,
+		latestResolver:    latestResolver,
+		releaseResolver:   resolution.NewResolver(logger, pivnetClient),
+		compressor:        compressor,
 	}
 }
 
 func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error) {
 	productSlug := input.Source.ProductSlug
 
-	version, fingerprint, err := versions.SplitIntoVersionAndFingerprint(input.Version.ProductVersion)
-	if err != nil {
-		c.logger.Info("Parsing of fingerprint failed; continuing without it")
-		version = input.Version.ProductVersion
-		fingerprint = ""
+	if input.Version.ProductVersion == "" {
+		c.logger.Info("No version provided - resolving latest release matching source")
+
+		latest, err := c.latestResolver.Latest(input.Source)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+
+		input.Version = latest
 	}
 
-	c.logger.Info(fmt.Sprintf(
-		"Getting release for product slug: '%s' and product version: '%s'",
-		productSlug,
-		version,
-	))
+	// The release is resolved as check resolves the versions it emits, so
+	// that every version emitted by check can be downloaded. A release ID in
+	// the version is preferred, as it identifies the release even if its
+	// version has since been edited or reused.
+	var release pivnet.Release
+	var err error
+	if input.Version.ReleaseID != "" {
+		releaseID, convErr := strconv.Atoi(input.Version.ReleaseID)
+		if convErr != nil {
+			return concourse.InResponse{}, fmt.Errorf("release_id '%s' is not a valid release ID", input.Version.ReleaseID)
+		}
 
-	release, err := c.pivnetClient.GetRelease(productSlug, version)
+		release, productSlug, err = c.releaseResolver.ReleaseByID(
+			productSlug,
+			input.Source.PreviousSlugs,
+			releaseID,
+			input.Version.ProductVersion,
+		)
+	} else {
+		release, productSlug, err = c.releaseResolver.Release(
+			productSlug,
+			input.Source.PreviousSlugs,
+			input.Version.ProductVersion,
+		)
+	}
 	if err != nil {
 		return concourse.InResponse{}, err
 	}
 
-	if fingerprint != "" {
-		actualFingerprint := release.SoftwareFilesUpdatedAt
-		if actualFingerprint != fingerprint {
-			return concourse.InResponse{}, fmt.Errorf(
-				"provided fingerprint: '%s' does not match actual fingerprint (from pivnet): '%s' - %s",
-				fingerprint,
-				actualFingerprint,
-				"pivnet does not support downloading old versions of a release",
-			)
+	if input.Params.DryRun {
+		return c.dryRun(input, release, productSlug)
+	}
+
+	version, fingerprint := resolution.SplitVersion(input.Version.ProductVersion)
+
+	if input.Params.EULAAction == concourse.EULAActionPreview {
+		err = c.previewEULA(release, input.Params.Accept)
+		if err != nil {
+			return concourse.InResponse{}, err
 		}
 	}
 
@@ -137,9 +237,12 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 		return concourse.InResponse{}, err
 	}
 
+	var unsupportedFeatures []string
+
 	c.logger.Info("Getting file groups")
 
 	fileGroups, err := c.pivnetClient.FileGroupsForRelease(productSlug, release.ID)
+	err = c.skipUnsupported(capabilities.FileGroups, err, &unsupportedFeatures)
 	if err != nil {
 		return concourse.InResponse{}, err
 	}
@@ -152,6 +255,7 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 	c.logger.Info("Getting release dependencies")
 
 	releaseDependencies, err := c.pivnetClient.ReleaseDependencies(productSlug, release.ID)
+	err = c.skipUnsupported(capabilities.ReleaseDependencies, err, &unsupportedFeatures)
 	if err != nil {
 		return concourse.InResponse{}, err
 	}
@@ -159,6 +263,7 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 	c.logger.Info("Getting dependency specifiers")
 
 	dependencySpecifiers, err := c.pivnetClient.DependencySpecifiers(productSlug, release.ID)
+	err = c.skipUnsupported(capabilities.DependencySpecifiers, err, &unsupportedFeatures)
 	if err != nil {
 		return concourse.InResponse{}, err
 	}
@@ -166,6 +271,7 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 	c.logger.Info("Getting release upgrade paths")
 
 	releaseUpgradePaths, err := c.pivnetClient.ReleaseUpgradePaths(productSlug, release.ID)
+	err = c.skipUnsupported(capabilities.ReleaseUpgradePaths, err, &unsupportedFeatures)
 	if err != nil {
 		return concourse.InResponse{}, err
 	}
@@ -173,28 +279,81 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 	c.logger.Info("Getting upgrade path specifiers")
 
 	upgradePathSpecifiers, err := c.pivnetClient.UpgradePathSpecifiers(productSlug, release.ID)
+	err = c.skipUnsupported(capabilities.UpgradePathSpecifiers, err, &unsupportedFeatures)
 	if err != nil {
 		return concourse.InResponse{}, err
 	}
 
 	c.logger.Info("Downloading files")
 
-	err = c.downloadFiles(input.Params.Globs, allProductFiles, productSlug, release.ID, input.Params.Unpack)
+	localFileNames, files, downloadErrors, err := c.downloadFiles(
+		input.Params.Globs,
+		input.Params.GlobSubdirs,
+		input.Params.PlatformFilter,
+		input.Params.AllowPartial,
+		input.Params.SparseDownload(),
+		input.Source.FIPS,
+		allProductFiles,
+		productSlug,
+		release.ID,
+	)
 	if err != nil {
 		return concourse.InResponse{}, err
 	}
 
+	var stemcell *metadata.Stemcell
+	if input.Params.Stemcell != nil {
+		stemcell, err = c.downloadStemcell(*input.Params.Stemcell, input.Source.FIPS, releaseDependencies)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+	}
+
+	var fileChecksums map[int]map[string]string
+	if len(input.Source.ChecksumAlgorithms) > 0 && !input.Params.SparseDownload() {
+		fileChecksums, err = c.sumFiles(files, localFileNames)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+	}
+
+	// BOSH releases are read before unpacking as unpacking removes the
+	// downloaded tarballs.
+	var boshReleases map[int]*metadata.BOSHRelease
+	if input.Params.BOSHReleaseMetadata {
+		boshReleases, err = c.readBOSHReleases(localFileNames)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+	}
+
+	if input.Params.Unpack {
+		err = c.unpackFiles(files)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+	}
+
+	if input.Params.Compress != "" {
+		err = c.compressFiles(input.Params.Compress, files, localFileNames)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+	}
+
 	c.logger.Info("Creating metadata")
 
 	versionWithFingerprint, err := versions.CombineVersionAndFingerprint(version, fingerprint)
 
+	description, customMetadata := metadata.DecodeCustomMetadata(release.Description)
+
 	mdata := metadata.Metadata{
 		Release: &metadata.Release{
 			ID:                    release.ID,
 			Version:               release.Version,
 			ReleaseType:           string(release.ReleaseType),
 			ReleaseDate:           release.ReleaseDate,
-			Description:           release.Description,
+			Description:           description,
 			ReleaseNotesURL:       release.ReleaseNotesURL,
 			Availability:          release.Availability,
 			Controlled:            release.Controlled,
@@ -203,7 +362,10 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 			EndOfSupportDate:      release.EndOfSupportDate,
 			EndOfGuidanceDate:     release.EndOfGuidanceDate,
 			EndOfAvailabilityDate: release.EndOfAvailabilityDate,
+			CustomMetadata:        customMetadata,
 		},
+		Stemcell:            stemcell,
+		UnsupportedFeatures: unsupportedFeatures,
 	}
 
 	if release.EULA != nil {
@@ -230,6 +392,10 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 			SystemRequirements: pf.SystemRequirements,
 			Platforms:          pf.Platforms,
 			IncludedFiles:      pf.IncludedFiles,
+			LocalFile:          localFileNames[pf.ID],
+			BOSHRelease:        boshReleases[pf.ID],
+			Checksums:          fileChecksums[pf.ID],
+			DownloadError:      downloadErrors[pf.ID],
 		})
 	}
 
@@ -301,56 +467,212 @@ func (c *InCommand) Run(input concourse.InRequest) (concourse.InResponse, error)
 		return concourse.InResponse{}, err
 	}
 
+	err = c.fileWriter.WriteEnvFile(mdata)
+	if err != nil {
+		return concourse.InResponse{}, err
+	}
+
+	if input.Params.Bundle {
+		err = c.writeBundle(release, localFileNames)
+		if err != nil {
+			return concourse.InResponse{}, err
+		}
+	}
+
 	concourseMetadata := c.addReleaseMetadata([]concourse.Metadata{}, release)
 
+	c.eventEmitter.Emit(events.Event{
+		Type:    events.Completed,
+		Version: versionWithFingerprint,
+	})
+
 	out := concourse.InResponse{
-		Version: concourse.Version{
-			ProductVersion: versionWithFingerprint,
-		},
+		Version:  concourse.ReleaseVersion(versionWithFingerprint, release, input.Source),
 		Metadata: concourseMetadata,
 	}
 
+	// The version must match the one emitted by check, which fingerprints
+	// the same product files of the release.
+	if input.Source.ProductFilesFingerprint {
+		out.Version.ProductFilesFingerprint = resolution.ProductFilesFingerprint(releaseProductFiles)
+	}
+
 	return out, nil
 }
 
+// skipUnsupported returns nil, recording the feature as unsupported, if err
+// shows that the feature is not available to the product or token, so that
+// the get continues without it. Otherwise it returns err.
+func (c InCommand) skipUnsupported(feature string, err error, unsupportedFeatures *[]string) error {
+	if err == nil || !capabilities.IsUnsupported(feature, err) {
+		return err
+	}
+
+	logging.Warn(c.logger, fmt.Sprintf(
+		"%s are not available to this token and will be missing from the metadata: %s",
+		feature,
+		err.Error(),
+	))
+	*unsupportedFeatures = append(*unsupportedFeatures, feature)
+
+	return nil
+}
+
+// previewEULA writes the EULA text of the release to disk so that it can be
+// reviewed, and returns an error with instructions unless accept is set.
+func (c InCommand) previewEULA(release pivnet.Release, accept bool) error {
+	if release.EULA == nil {
+		return nil
+	}
+
+	c.logger.Info(fmt.Sprintf("Getting EULA: '%s'", release.EULA.Slug))
+
+	eula, err := c.pivnetClient.EULA(release.EULA.Slug)
+	if err != nil {
+		return err
+	}
+
+	err = c.fileWriter.WriteEULAFile(eula.Content)
+	if err != nil {
+		return err
+	}
+
+	if !accept {
+		// A failed get step has no output, so the EULA is logged for review.
+		c.logger.Info(fmt.Sprintf("EULA '%s':\n%s", release.EULA.Slug, eula.Content))
+
+		return fmt.Errorf(
+			"release '%s' requires EULA '%s' to be accepted - review it in the output above and set 'accept: true' in the get params to accept it",
+			release.Version,
+			release.EULA.Slug,
+		)
+	}
+
+	return nil
+}
+
+// writeBundle writes the EULA text alongside the metadata files and bundles
+// them with the downloaded files for transfer to an air-gapped environment.
+func (c InCommand) writeBundle(release pivnet.Release, localFileNames map[int]string) error {
+	if release.EULA != nil {
+		c.logger.Info(fmt.Sprintf("Getting EULA: '%s'", release.EULA.Slug))
+
+		eula, err := c.pivnetClient.EULA(release.EULA.Slug)
+		if err != nil {
+			return err
+		}
+
+		err = c.fileWriter.WriteEULAFile(eula.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	var fileNames []string
+	for _, fileName := range localFileNames {
+		fileNames = append(fileNames, fileName)
+	}
+
+	c.logger.Info("Writing bundle")
+
+	return c.bundleWriter.Write(fileNames)
+}
+
+// downloadFiles downloads the product files matching the globs and verifies
+// their checksums. Files which fail to download are an error unless
+// allowPartial is set, in which case the reason each failed is returned keyed
+// by product file ID and the files are omitted from the local file names.
+// If platformFilter is set, only the files matching the globs which can be
+// used on that platform are downloaded.
+// Files matching a glob of globSubdirs are moved into its subdirectory.
+// Files only partially downloaded by a sparse download are neither verified
+// nor cached.
+// If fips is set, MD5 is never computed, so files which could only be
+// verified by MD5 are an error before any are downloaded.
 func (c InCommand) downloadFiles(
 	globs []string,
+	globSubdirs map[string]string,
+	platformFilter string,
+	allowPartial bool,
+	sparse bool,
+	fips bool,
 	productFiles []pivnet.ProductFile,
 	productSlug string,
 	releaseID int,
-	unpack bool,
-) error {
-	c.logger.Info("Filtering download links by glob")
+) (map[int]string, []string, map[int]string, error) {
+	filtered, err := c.selectProductFiles(globs, platformFilter, productFiles)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	filtered := productFiles
+	if fips && !sparse {
+		var md5Only []string
+		for _, p := range filtered {
+			if p.FileType == pivnet.FileTypeSoftware && p.SHA256 == "" {
+				md5Only = append(md5Only, fmt.Sprintf("'%s'", p.Name))
+			}
+		}
 
-	// If globs were not provided, download everything without filtering.
-	if globs != nil {
-		var err error
-		filtered, err = c.filter.ProductFileKeysByGlobs(productFiles, globs)
-		if err != nil {
-			return err
+		if len(md5Only) > 0 {
+			return nil, nil, nil, fmt.Errorf(
+				"fips is set, but product files have no SHA256 and could only be verified by MD5:\n  - %s",
+				strings.Join(md5Only, "\n  - "),
+			)
 		}
 	}
 
 	c.logger.Info("Downloading filtered files")
 
-	files, err := c.downloader.Download(filtered, productSlug, releaseID)
+	files, failures, err := c.downloader.Download(filtered, productSlug, releaseID)
 	if err != nil {
-		return err
+		return nil, nil, nil, err
+	}
+
+	var problems []string
+	downloadErrors := map[int]string{}
+	for _, p := range filtered {
+		failure, ok := failures[p.ID]
+		if !ok {
+			continue
+		}
+
+		// No file of the release can be downloaded until its EULA has been
+		// accepted, so this is reported on its own even if allow_partial is
+		// set.
+		if eulaErr, ok := failure.(gp.ErrEULAAcceptanceRequired); ok {
+			return nil, nil, nil, eulaErr
+		}
+
+		problems = append(problems, fmt.Sprintf("'%s': %s", p.Name, failure.Error()))
+		downloadErrors[p.ID] = failure.Error()
 	}
 
+	if len(problems) > 0 {
+		if !allowPartial {
+			return nil, nil, nil, fmt.Errorf(
+				"failed to download product files:\n  - %s",
+				strings.Join(problems, "\n  - "),
+			)
+		}
+
+		c.logger.Info(fmt.Sprintf(
+			"Continuing without product files which failed to download:\n  - %s",
+			strings.Join(problems, "\n  - "),
+		))
+	}
+
+	// The downloader suffixes colliding file names with the product file ID,
+	// so expected checksums are keyed by the same local file names.
+	localFileNames := filenames.ForProductFiles(filtered)
+
 	fileSHA256s := map[string]string{}
 	fileMD5s := map[string]string{}
 	for _, p := range productFiles {
-		parts := strings.Split(p.AWSObjectKey, "/")
-
-		if len(parts) < 1 {
-			panic("not enough components to form filename")
+		fileName, ok := localFileNames[p.ID]
+		if !ok {
+			fileName = filenames.Base(p)
 		}
 
-		fileName := parts[len(parts)-1]
-
 		if fileName == "" {
 			panic("empty file name")
 		}
@@ -361,39 +683,289 @@ func (c InCommand) downloadFiles(
 		}
 	}
 
-	err = c.compareSHA256sOrMD5s(files, fileSHA256s, fileMD5s)
-	if err != nil {
-		return err
+	if sparse {
+		c.logger.Info("Skipping checksum verification of partially downloaded files")
+	} else {
+		err = c.compareSHA256sOrMD5s(files, fileSHA256s, fileMD5s, fips)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		c.cacheFiles(files, fileSHA256s)
+	}
+
+	for id := range downloadErrors {
+		delete(localFileNames, id)
+	}
+
+	if len(globSubdirs) > 0 {
+		files, err = c.moveToSubdirs(globSubdirs, filtered, files, localFileNames)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return localFileNames, files, downloadErrors, nil
+}
+
+// selectProductFiles returns the product files matching the globs, or all of
+// them if no globs are provided, which can be used on the platform of
+// platformFilter if it is set.
+func (c InCommand) selectProductFiles(
+	globs []string,
+	platformFilter string,
+	productFiles []pivnet.ProductFile,
+) ([]pivnet.ProductFile, error) {
+	c.logger.Info("Filtering download links by glob")
+
+	filtered := productFiles
+
+	// If globs were not provided, download everything without filtering.
+	if globs != nil {
+		var err error
+		filtered, err = c.filter.ProductFileKeysByGlobs(productFiles, globs)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if unpack {
-		for _, destinationPath := range files {
-			mime := c.archive.Mimetype(destinationPath)
+	if platformFilter != "" {
+		p, err := platform.Parse(platformFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		c.logger.Info(fmt.Sprintf("Filtering download links by platform: %s", p))
 
-			if mime == "" {
-				c.logger.Info(fmt.Sprintf("not an archive: %s", destinationPath))
-				continue
+		byPlatform, err := c.filter.ProductFilesByPlatform(filtered, p)
+		if err != nil {
+			return nil, err
+		}
+
+		matched := map[int]bool{}
+		for _, productFile := range byPlatform {
+			matched[productFile.ID] = true
+		}
+
+		for _, productFile := range filtered {
+			if !matched[productFile.ID] {
+				c.logger.Info(fmt.Sprintf("Skipping product file for another platform: '%s'", productFile.Name))
 			}
+		}
 
-			err = c.archive.Extract(mime, destinationPath)
-			if err != nil {
-				return err
+		filtered = byPlatform
+	}
+
+	return filtered, nil
+}
+
+// moveToSubdirs moves each downloaded file into the subdirectory of the
+// first glob, in order, which it matches. The local file names are updated to
+// include the subdirectory and the paths of the moved files are returned.
+func (c InCommand) moveToSubdirs(
+	globSubdirs map[string]string,
+	productFiles []pivnet.ProductFile,
+	files []string,
+	localFileNames map[int]string,
+) ([]string, error) {
+	globs := make([]string, 0, len(globSubdirs))
+	for glob := range globSubdirs {
+		globs = append(globs, glob)
+	}
+	sort.Strings(globs)
+
+	subdirs := map[int]string{}
+	for _, glob := range globs {
+		matched, err := c.filter.ProductFileKeysByGlobs(productFiles, []string{glob})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range matched {
+			if _, ok := subdirs[p.ID]; !ok {
+				subdirs[p.ID] = globSubdirs[glob]
 			}
 		}
 	}
 
+	paths := map[string]string{}
+	for id, fileName := range localFileNames {
+		subdir, ok := subdirs[id]
+		if !ok || subdir == "" {
+			continue
+		}
+
+		localFileNames[id] = filepath.Join(subdir, fileName)
+		paths[fileName] = localFileNames[id]
+	}
+
+	moved := make([]string, 0, len(files))
+	for _, downloadPath := range files {
+		localFileName, ok := paths[filepath.Base(downloadPath)]
+		if !ok {
+			moved = append(moved, downloadPath)
+			continue
+		}
+
+		destinationPath := filepath.Join(filepath.Dir(downloadPath), localFileName)
+
+		c.logger.Info(fmt.Sprintf("Moving '%s' to '%s'", downloadPath, destinationPath))
+
+		err := os.MkdirAll(filepath.Dir(destinationPath), os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.Rename(downloadPath, destinationPath)
+		if err != nil {
+			return nil, err
+		}
+
+		moved = append(moved, destinationPath)
+	}
+
+	return moved, nil
+}
+
+// cacheFiles adds each file verified against its SHA256 to the download
+// cache. Failing to cache a file does not fail the get.
+func (c InCommand) cacheFiles(files []string, fileSHA256s map[string]string) {
+	for _, downloadPath := range files {
+		sha256 := fileSHA256s[filepath.Base(downloadPath)]
+		if sha256 == "" {
+			continue
+		}
+
+		err := c.fileCache.Add(sha256, downloadPath)
+		if err != nil {
+			logging.Warn(c.logger, fmt.Sprintf("Could not add '%s' to the download cache: %s", downloadPath, err.Error()))
+		}
+	}
+}
+
+func (c InCommand) unpackFiles(files []string) error {
+	for _, destinationPath := range files {
+		mime := c.archive.Mimetype(destinationPath)
+
+		if mime == "" {
+			c.logger.Info(fmt.Sprintf("not an archive: %s", destinationPath))
+			continue
+		}
+
+		err := c.archive.Extract(mime, destinationPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compressFiles compresses each downloaded file to reduce the size of the
+// volume, updating the local file names to those of the compressed files.
+// Checksums are of the files as downloaded, so are computed beforehand.
+func (c InCommand) compressFiles(format string, files []string, localFileNames map[int]string) error {
+	c.logger.Info(fmt.Sprintf("Compressing downloaded files with %s", format))
+
+	compressedNames := map[string]string{}
+	for _, downloadPath := range files {
+		compressedPath, err := c.compressor.Compress(format, downloadPath)
+		if err != nil {
+			return err
+		}
+
+		compressedNames[filepath.Base(downloadPath)] = filepath.Base(compressedPath)
+	}
+
+	for id, fileName := range localFileNames {
+		compressedName, ok := compressedNames[filepath.Base(fileName)]
+		if !ok {
+			continue
+		}
+
+		localFileNames[id] = filepath.Join(filepath.Dir(fileName), compressedName)
+	}
+
 	return nil
 }
 
+// sumFiles computes the configured additional checksums of each downloaded
+// file, keyed by product file ID.
+func (c InCommand) sumFiles(files []string, localFileNames map[int]string) (map[int]map[string]string, error) {
+	c.logger.Info("Calculating additional checksums for downloaded files")
+
+	pathsByName := map[string]string{}
+	for _, f := range files {
+		pathsByName[filepath.Base(f)] = f
+	}
+
+	fileChecksums := map[int]map[string]string{}
+	for id, fileName := range localFileNames {
+		downloadPath, ok := pathsByName[filepath.Base(fileName)]
+		if !ok {
+			continue
+		}
+
+		sums, err := c.checksumSummer.SumFile(downloadPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for algorithm, sum := range sums {
+			c.logger.Info(fmt.Sprintf("%s %s is: %s", downloadPath, strings.ToUpper(algorithm), sum))
+		}
+
+		fileChecksums[id] = sums
+	}
+
+	return fileChecksums, nil
+}
+
+// readBOSHReleases returns the BOSH release manifest of each downloaded
+// gzipped tarball, keyed by product file ID. Tarballs which are not BOSH
+// releases are skipped.
+func (c InCommand) readBOSHReleases(localFileNames map[int]string) (map[int]*metadata.BOSHRelease, error) {
+	boshReleases := map[int]*metadata.BOSHRelease{}
+
+	for id, fileName := range localFileNames {
+		if !strings.HasSuffix(fileName, ".tgz") && !strings.HasSuffix(fileName, ".tar.gz") {
+			continue
+		}
+
+		c.logger.Info(fmt.Sprintf("Reading BOSH release manifest from: '%s'", fileName))
+
+		manifest, err := c.boshReleaseReader.Read(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		if manifest == nil {
+			c.logger.Info(fmt.Sprintf("not a BOSH release: %s", fileName))
+			continue
+		}
+
+		boshReleases[id] = &metadata.BOSHRelease{
+			Name:               manifest.Name,
+			Version:            manifest.Version,
+			CommitHash:         manifest.CommitHash,
+			UncommittedChanges: manifest.UncommittedChanges,
+		}
+	}
+
+	return boshReleases, nil
+}
+
 func (c InCommand) addReleaseMetadata(
 	concourseMetadata []concourse.Metadata,
 	release pivnet.Release,
 ) []concourse.Metadata {
+	description, customMetadata := metadata.DecodeCustomMetadata(release.Description)
+
 	cmdata := append(concourseMetadata,
 		concourse.Metadata{Name: "version", Value: release.Version},
 		concourse.Metadata{Name: "release_type", Value: string(release.ReleaseType)},
 		concourse.Metadata{Name: "release_date", Value: release.ReleaseDate},
-		concourse.Metadata{Name: "description", Value: release.Description},
+		concourse.Metadata{Name: "description", Value: description},
 		concourse.Metadata{Name: "release_notes_url", Value: release.ReleaseNotesURL},
 		concourse.Metadata{Name: "availability", Value: release.Availability},
 		concourse.Metadata{Name: "controlled", Value: fmt.Sprintf("%t", release.Controlled)},
@@ -410,10 +982,15 @@ func (c InCommand) addReleaseMetadata(
 		)
 	}
 
+	cmdata = append(cmdata, concourse.CustomMetadata(customMetadata)...)
+
 	return cmdata
 }
 
-func (c InCommand) compareSHA256sOrMD5s(filepaths []string, expectedSHA256s map[string]string, expectedMD5s map[string]string) error {
+// compareSHA256sOrMD5s verifies each file against its expected SHA256, or its
+// expected MD5 if it has no SHA256. If fips is set, MD5 is never computed, as
+// software files without a SHA256 have already been rejected.
+func (c InCommand) compareSHA256sOrMD5s(filepaths []string, expectedSHA256s map[string]string, expectedMD5s map[string]string, fips bool) error {
 	c.logger.Info("Calculating SHA256 or MD5 for downloaded files")
 
 	for _, downloadPath := range filepaths {
@@ -427,14 +1004,22 @@ func (c InCommand) compareSHA256sOrMD5s(filepaths []string, expectedSHA256s map[
 			}
 
 			if expectedSHA256 != actualSHA256 {
-				return fmt.Errorf(
+				return c.quarantine(downloadPath, fmt.Errorf(
 					"SHA256 comparison failed for downloaded file: '%s'. Expected (from pivnet): '%s' - actual (from file): '%s'",
 					downloadPath,
 					expectedSHA256,
 					actualSHA256,
-				)
+				))
 			}
 			c.logger.Info(fmt.Sprintf("%s SHA256 is: %s", downloadPath, actualSHA256))
+
+			c.eventEmitter.Emit(events.Event{
+				Type:     events.Verified,
+				File:     downloadPath,
+				Checksum: "sha256:" + actualSHA256,
+			})
+		} else if fips {
+			c.logger.Info(fmt.Sprintf("Skipping MD5 of '%s' as fips is set", downloadPath))
 		} else {
 			expectedMD5 := expectedMD5s[f]
 
@@ -444,14 +1029,20 @@ func (c InCommand) compareSHA256sOrMD5s(filepaths []string, expectedSHA256s map[
 			}
 
 			if expectedMD5 != "" && expectedMD5 != actualMD5 {
-				return fmt.Errorf(
+				return c.quarantine(downloadPath, fmt.Errorf(
 					"MD5 comparison failed for downloaded file: '%s'. Expected (from pivnet): '%s' - actual (from file): '%s'",
 					downloadPath,
 					expectedMD5,
 					actualMD5,
-				)
+				))
 			}
 			c.logger.Info(fmt.Sprintf("%s MD5 is: %s", downloadPath, actualMD5))
+
+			c.eventEmitter.Emit(events.Event{
+				Type:     events.Verified,
+				File:     downloadPath,
+				Checksum: "md5:" + actualMD5,
+			})
 		}
 	}
 
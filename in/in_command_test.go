@@ -2,7 +2,11 @@ package in_test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 
 	. "github.com/onsi/ginkgo"
@@ -10,10 +14,17 @@ import (
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/boshrelease"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/config"
+	"github.com/pivotal-cf/pivnet-resource/events"
+	"github.com/pivotal-cf/pivnet-resource/gp"
 	"github.com/pivotal-cf/pivnet-resource/in"
 	"github.com/pivotal-cf/pivnet-resource/in/infakes"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/platform"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
+	"github.com/pivotal-cf/pivnet-resource/resolution/resolutionfakes"
 	"github.com/pivotal-cf/pivnet-resource/versions"
 )
 
@@ -25,13 +36,20 @@ var _ = Describe("In", func() {
 	var (
 		fakeLogger logger.Logger
 
-		fakeFilter           *infakes.FakeFilter
-		fakeDownloader       *infakes.FakeDownloader
-		fakePivnetClient     *infakes.FakePivnetClient
-		fakeSHA256FileSummer *infakes.FakeFileSummer
-		fakeMD5FileSummer    *infakes.FakeFileSummer
-		fakeFileWriter       *infakes.FakeFileWriter
-		fakeArchive          *infakes.FakeArchive
+		fakeFilter            *infakes.FakeFilter
+		fakeDownloader        *infakes.FakeDownloader
+		fakePivnetClient      *infakes.FakePivnetClient
+		fakeSHA256FileSummer  *infakes.FakeFileSummer
+		fakeMD5FileSummer     *infakes.FakeFileSummer
+		fakeFileWriter        *infakes.FakeFileWriter
+		fakeArchive           *infakes.FakeArchive
+		fakeEventEmitter      *infakes.FakeEventEmitter
+		fakeBundleWriter      *infakes.FakeBundleWriter
+		fakeBOSHReleaseReader *infakes.FakeBOSHReleaseReader
+		fakeChecksumSummer    *infakes.FakeChecksumSummer
+		fakeFileCache         *infakes.FakeFileCache
+		fakeLatestResolver    *infakes.FakeLatestResolver
+		fakeCompressor        *infakes.FakeCompressor
 
 		fileGroups []pivnet.FileGroup
 
@@ -56,6 +74,7 @@ var _ = Describe("In", func() {
 
 		release             pivnet.Release
 		downloadFilepaths   []string
+		downloadFailures    map[int]error
 		fileContentsSHA256s []string
 		fileContentsMD5s    []string
 
@@ -81,12 +100,20 @@ var _ = Describe("In", func() {
 		fakeMD5FileSummer = &infakes.FakeFileSummer{}
 		fakeFileWriter = &infakes.FakeFileWriter{}
 		fakeArchive = &infakes.FakeArchive{}
+		fakeEventEmitter = &infakes.FakeEventEmitter{}
+		fakeBundleWriter = &infakes.FakeBundleWriter{}
+		fakeBOSHReleaseReader = &infakes.FakeBOSHReleaseReader{}
+		fakeChecksumSummer = &infakes.FakeChecksumSummer{}
+		fakeFileCache = &infakes.FakeFileCache{}
+		fakeLatestResolver = &infakes.FakeLatestResolver{}
+		fakeCompressor = &infakes.FakeCompressor{}
 
 		getReleaseErr = nil
 		acceptEULAErr = nil
 		productFilesErr = nil
 		filterErr = nil
 		downloadErr = nil
+		downloadFailures = nil
 		sha256sumErr = nil
 		md5sumErr = nil
 		releaseDependenciesErr = nil
@@ -296,7 +323,7 @@ var _ = Describe("In", func() {
 		fakePivnetClient.FileGroupsForReleaseReturns(fileGroups, fileGroupsErr)
 
 		fakeFilter.ProductFileKeysByGlobsReturns(filteredProductFiles, filterErr)
-		fakeDownloader.DownloadReturns(downloadFilepaths, downloadErr)
+		fakeDownloader.DownloadReturns(downloadFilepaths, downloadFailures, downloadErr)
 		fakeSHA256FileSummer.SumFileStub = func(path string) (string, error) {
 			if sha256sumErr != nil {
 				return "", sha256sumErr
@@ -338,6 +365,13 @@ var _ = Describe("In", func() {
 			fakeMD5FileSummer,
 			fakeFileWriter,
 			fakeArchive,
+			fakeEventEmitter,
+			fakeBundleWriter,
+			fakeBOSHReleaseReader,
+			fakeChecksumSummer,
+			fakeFileCache,
+			fakeLatestResolver,
+			fakeCompressor,
 		)
 	})
 
@@ -349,6 +383,49 @@ var _ = Describe("In", func() {
 		Expect(fakeFileWriter.WriteVersionFileArgsForCall(0)).To(Equal(versionWithFingerprint))
 	})
 
+	It("returns the version without metadata", func() {
+		response, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(response.Version).To(Equal(concourse.Version{
+			ProductVersion: versionWithFingerprint,
+		}))
+	})
+
+	Context("when version metadata is requested", func() {
+		BeforeEach(func() {
+			inRequest.Source.VersionMetadata = true
+			release.ReleaseType = "Security Release"
+		})
+
+		It("includes the release type and EULA slug in the version", func() {
+			response, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.Version).To(Equal(concourse.Version{
+				ProductVersion: versionWithFingerprint,
+				ReleaseType:    "Security Release",
+				EULASlug:       eulaSlug,
+			}))
+		})
+	})
+
+	Context("when a product files fingerprint is requested", func() {
+		BeforeEach(func() {
+			inRequest.Source.ProductFilesFingerprint = true
+		})
+
+		It("includes the fingerprint of the product files of the release in the version", func() {
+			response, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.Version).To(Equal(concourse.Version{
+				ProductVersion:          versionWithFingerprint,
+				ProductFilesFingerprint: resolution.ProductFilesFingerprint(releaseProductFiles),
+			}))
+		})
+	})
+
 	It("invokes the json metadata file writer with correct metadata", func() {
 		_, err := inCommand.Run(inRequest)
 		Expect(err).NotTo(HaveOccurred())
@@ -391,6 +468,454 @@ var _ = Describe("In", func() {
 		validateUpgradePathSpecifiersMetadata(invokedMetadata, upgradePathSpecifiers)
 	})
 
+	Context("when the release description contains custom metadata", func() {
+		BeforeEach(func() {
+			release.Description = metadata.EncodeCustomMetadata(
+				"some description",
+				map[string]string{"build_id": "1234"},
+			)
+		})
+
+		It("writes the description and custom metadata separately", func() {
+			response, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			Expect(invokedMetadata.Release.Description).To(Equal("some description"))
+			Expect(invokedMetadata.Release.CustomMetadata).To(Equal(map[string]string{"build_id": "1234"}))
+
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "description", Value: "some description"}))
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "custom_metadata.build_id", Value: "1234"}))
+		})
+	})
+
+	It("writes the environment file with the same metadata", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeFileWriter.WriteEnvFileCallCount()).To(Equal(1))
+		Expect(fakeFileWriter.WriteEnvFileArgsForCall(0)).To(Equal(fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)))
+	})
+
+	Context("when writing the environment file returns an error", func() {
+		BeforeEach(func() {
+			fakeFileWriter.WriteEnvFileReturns(fmt.Errorf("some env file error"))
+		})
+
+		It("returns the error", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).To(MatchError("some env file error"))
+		})
+	})
+
+	It("records the local file name of each downloaded product file in the metadata", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+		for i, pf := range invokedMetadata.ProductFiles {
+			Expect(pf.LocalFile).To(Equal(downloadFilepaths[i]))
+		}
+	})
+
+	It("does not compress the downloaded files", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeCompressor.CompressCallCount()).To(Equal(0))
+	})
+
+	Context("when compress is set", func() {
+		BeforeEach(func() {
+			inRequest.Params.Compress = "zstd"
+			inRequest.Source.ChecksumAlgorithms = []string{"sha512"}
+
+			fakeCompressor.CompressStub = func(format string, path string) (string, error) {
+				return path + ".zst", nil
+			}
+		})
+
+		It("compresses each downloaded file with the format", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeCompressor.CompressCallCount()).To(Equal(len(downloadFilepaths)))
+			for i, path := range downloadFilepaths {
+				format, compressedPath := fakeCompressor.CompressArgsForCall(i)
+				Expect(format).To(Equal("zstd"))
+				Expect(compressedPath).To(Equal(path))
+			}
+		})
+
+		It("records the local file names of the compressed files in the metadata", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			for i, pf := range invokedMetadata.ProductFiles {
+				Expect(pf.LocalFile).To(Equal(downloadFilepaths[i] + ".zst"))
+			}
+		})
+
+		It("calculates the checksums of the files before compressing them", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			var summedPaths []string
+			for i := 0; i < fakeChecksumSummer.SumFileCallCount(); i++ {
+				summedPaths = append(summedPaths, fakeChecksumSummer.SumFileArgsForCall(i))
+			}
+			Expect(summedPaths).To(ConsistOf(downloadFilepaths))
+		})
+
+		Context("when compressing a file returns an error", func() {
+			BeforeEach(func() {
+				fakeCompressor.CompressStub = nil
+				fakeCompressor.CompressReturns("", fmt.Errorf("some compress error"))
+			})
+
+			It("returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("some compress error"))
+			})
+		})
+	})
+
+	It("adds the downloaded files to the download cache with their SHA256", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeFileCache.AddCallCount()).To(Equal(len(downloadFilepaths)))
+
+		sha256, path := fakeFileCache.AddArgsForCall(0)
+		Expect(sha256).To(Equal(fileContentsSHA256s[0]))
+		Expect(path).To(Equal(downloadFilepaths[0]))
+	})
+
+	Context("when adding a file to the download cache returns an error", func() {
+		BeforeEach(func() {
+			fakeFileCache.AddReturns(fmt.Errorf("some cache error"))
+		})
+
+		It("does not return an error", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when dry_run is set", func() {
+		BeforeEach(func() {
+			inRequest.Params.DryRun = true
+
+			releaseProductFiles[0].Size = 1024
+			releaseProductFiles[1].Size = 2 * 1024 * 1024
+		})
+
+		It("lists the files which would be downloaded with their sizes", func() {
+			response, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.Version.ProductVersion).To(Equal(versionWithFingerprint))
+
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "dry_run_file", Value: "product file 1234 (1.00 KiB)"}))
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "dry_run_file", Value: "product file 3456 (2.00 MiB)"}))
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "dry_run_file_count", Value: "4"}))
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "dry_run_total_size", Value: "2.00 MiB"}))
+		})
+
+		It("does not accept the EULA, download or write anything", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.AcceptEULACallCount()).To(Equal(0))
+			Expect(fakeDownloader.DownloadCallCount()).To(Equal(0))
+			Expect(fakeFileWriter.WriteVersionFileCallCount()).To(Equal(0))
+			Expect(fakeFileWriter.WriteMetadataYAMLFileCallCount()).To(Equal(0))
+		})
+
+		Context("when globs are provided", func() {
+			BeforeEach(func() {
+				inRequest.Params.Globs = []string{"some-glob"}
+				filteredProductFiles = []pivnet.ProductFile{releaseProductFiles[1]}
+			})
+
+			It("lists only the files matching them", func() {
+				response, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response.Metadata).NotTo(ContainElement(concourse.Metadata{Name: "dry_run_file", Value: "product file 1234 (1.00 KiB)"}))
+				Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "dry_run_file_count", Value: "1"}))
+				Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "dry_run_total_size", Value: "2.00 MiB"}))
+			})
+		})
+	})
+
+	Context("when a product file fails to download", func() {
+		BeforeEach(func() {
+			downloadFailures = map[int]error{
+				3456: fmt.Errorf("some download error"),
+			}
+		})
+
+		JustBeforeEach(func() {
+			fakeDownloader.DownloadReturns(
+				[]string{downloadFilepaths[0], downloadFilepaths[2], downloadFilepaths[3]},
+				downloadFailures,
+				nil,
+			)
+		})
+
+		It("returns an error naming the file", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).To(MatchError("failed to download product files:\n  - 'product file 3456': some download error"))
+
+			Expect(fakeFileWriter.WriteMetadataYAMLFileCallCount()).To(Equal(0))
+		})
+
+		Context("when allow_partial is set", func() {
+			BeforeEach(func() {
+				inRequest.Params.AllowPartial = true
+			})
+
+			It("records the failure in the metadata and succeeds", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+
+				Expect(invokedMetadata.ProductFiles[1].ID).To(Equal(3456))
+				Expect(invokedMetadata.ProductFiles[1].DownloadError).To(Equal("some download error"))
+				Expect(invokedMetadata.ProductFiles[1].LocalFile).To(BeEmpty())
+
+				for _, i := range []int{0, 2, 3} {
+					Expect(invokedMetadata.ProductFiles[i].DownloadError).To(BeEmpty())
+					Expect(invokedMetadata.ProductFiles[i].LocalFile).To(Equal(downloadFilepaths[i]))
+				}
+			})
+		})
+	})
+
+	It("does not write a bundle", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeBundleWriter.WriteCallCount()).To(Equal(0))
+		Expect(fakeFileWriter.WriteEULAFileCallCount()).To(Equal(0))
+	})
+
+	Context("when a bundle is requested", func() {
+		BeforeEach(func() {
+			inRequest.Params.Bundle = true
+
+			fakePivnetClient.EULAReturns(pivnet.EULA{Slug: eulaSlug, Content: "some eula text"}, nil)
+		})
+
+		It("writes the EULA text and bundles the downloaded files", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.EULAArgsForCall(0)).To(Equal(eulaSlug))
+			Expect(fakeFileWriter.WriteEULAFileArgsForCall(0)).To(Equal("some eula text"))
+
+			Expect(fakeBundleWriter.WriteCallCount()).To(Equal(1))
+			Expect(fakeBundleWriter.WriteArgsForCall(0)).To(ConsistOf(downloadFilepaths))
+		})
+
+		Context("when getting the EULA returns an error", func() {
+			BeforeEach(func() {
+				fakePivnetClient.EULAReturns(pivnet.EULA{}, fmt.Errorf("some eula error"))
+			})
+
+			It("returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("some eula error"))
+			})
+		})
+
+		Context("when writing the bundle returns an error", func() {
+			BeforeEach(func() {
+				fakeBundleWriter.WriteReturns(fmt.Errorf("some bundle error"))
+			})
+
+			It("returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("some bundle error"))
+			})
+		})
+	})
+
+	It("does not calculate additional checksums", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeChecksumSummer.SumFileCallCount()).To(Equal(0))
+	})
+
+	Context("when additional checksum algorithms are configured", func() {
+		BeforeEach(func() {
+			inRequest.Source.ChecksumAlgorithms = []string{"sha512"}
+
+			fakeChecksumSummer.SumFileStub = func(path string) (map[string]string, error) {
+				return map[string]string{"sha512": "sha512-of-" + path}, nil
+			}
+		})
+
+		It("records the checksums of each downloaded file in the metadata", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeChecksumSummer.SumFileCallCount()).To(Equal(len(downloadFilepaths)))
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			for _, pf := range invokedMetadata.ProductFiles {
+				Expect(pf.Checksums).To(Equal(map[string]string{
+					"sha512": "sha512-of-" + pf.LocalFile,
+				}))
+			}
+		})
+
+		Context("when calculating the checksums returns an error", func() {
+			BeforeEach(func() {
+				fakeChecksumSummer.SumFileReturns(nil, fmt.Errorf("some checksum error"))
+			})
+
+			It("returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("some checksum error"))
+			})
+		})
+	})
+
+	Context("when a sparse download is requested", func() {
+		BeforeEach(func() {
+			inRequest.Params.ZipMembers = []string{"metadata/*.yml"}
+			inRequest.Source.ChecksumAlgorithms = []string{"sha512"}
+		})
+
+		It("does not verify, cache or checksum the partially downloaded files", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSHA256FileSummer.SumFileCallCount()).To(Equal(0))
+			Expect(fakeMD5FileSummer.SumFileCallCount()).To(Equal(0))
+			Expect(fakeFileCache.AddCallCount()).To(Equal(0))
+			Expect(fakeChecksumSummer.SumFileCallCount()).To(Equal(0))
+		})
+
+		It("records the local file names in the metadata", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			Expect(invokedMetadata.ProductFiles[0].LocalFile).To(Equal(downloadFilepaths[0]))
+		})
+	})
+
+	It("does not read BOSH release manifests", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeBOSHReleaseReader.ReadCallCount()).To(Equal(0))
+	})
+
+	Context("when BOSH release metadata is requested", func() {
+		BeforeEach(func() {
+			inRequest.Params.BOSHReleaseMetadata = true
+
+			releaseProductFiles[0].AWSObjectKey = "some-release.tgz"
+			downloadFilepaths[0] = "some-release.tgz"
+
+			fakeBOSHReleaseReader.ReadReturns(&boshrelease.Manifest{
+				Name:       "some-release",
+				Version:    "1.2.3",
+				CommitHash: "abc123",
+			}, nil)
+		})
+
+		It("reads the manifest of each downloaded tarball into the metadata", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeBOSHReleaseReader.ReadCallCount()).To(Equal(1))
+			Expect(fakeBOSHReleaseReader.ReadArgsForCall(0)).To(Equal("some-release.tgz"))
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			for _, pf := range invokedMetadata.ProductFiles {
+				if pf.ID == releaseProductFiles[0].ID {
+					Expect(pf.BOSHRelease).To(Equal(&metadata.BOSHRelease{
+						Name:       "some-release",
+						Version:    "1.2.3",
+						CommitHash: "abc123",
+					}))
+				} else {
+					Expect(pf.BOSHRelease).To(BeNil())
+				}
+			}
+		})
+
+		Context("when the tarball is not a BOSH release", func() {
+			BeforeEach(func() {
+				fakeBOSHReleaseReader.ReadReturns(nil, nil)
+			})
+
+			It("does not add BOSH release metadata", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+				for _, pf := range invokedMetadata.ProductFiles {
+					Expect(pf.BOSHRelease).To(BeNil())
+				}
+			})
+		})
+
+		Context("when reading the manifest returns an error", func() {
+			BeforeEach(func() {
+				fakeBOSHReleaseReader.ReadReturns(nil, fmt.Errorf("some manifest error"))
+			})
+
+			It("returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("some manifest error"))
+			})
+		})
+	})
+
+	Context("when product files share a name", func() {
+		BeforeEach(func() {
+			releaseProductFiles[1].AWSObjectKey = downloadFilepaths[0]
+			downloadFilepaths[0] = "file-1234-1234"
+			downloadFilepaths[1] = "file-1234-3456"
+		})
+
+		It("verifies and records them under names suffixed with the product file ID", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			Expect(invokedMetadata.ProductFiles[0].LocalFile).To(Equal("file-1234-1234"))
+			Expect(invokedMetadata.ProductFiles[1].LocalFile).To(Equal("file-1234-3456"))
+		})
+	})
+
+	It("emits a verified event for each downloaded file and a completed event", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeEventEmitter.EmitCallCount()).To(Equal(len(downloadFilepaths) + 1))
+
+		for i := range downloadFilepaths {
+			event := fakeEventEmitter.EmitArgsForCall(i)
+			Expect(event.Type).To(Equal(events.Verified))
+			Expect(event.File).To(Equal(downloadFilepaths[i]))
+			Expect(event.Checksum).To(Equal("sha256:" + fileContentsSHA256s[i]))
+		}
+
+		event := fakeEventEmitter.EmitArgsForCall(len(downloadFilepaths))
+		Expect(event.Type).To(Equal(events.Completed))
+		Expect(event.Version).To(Equal(versionWithFingerprint))
+	})
+
 	It("downloads all files (nil globs acts like *)", func() {
 		_, err := inCommand.Run(inRequest)
 		Expect(err).NotTo(HaveOccurred())
@@ -411,29 +936,213 @@ var _ = Describe("In", func() {
 		Expect(fakeSHA256FileSummer.SumFileCallCount() + fakeMD5FileSummer.SumFileCallCount()).To(Equal(len(downloadFilepaths)))
 	})
 
-	Context("when version is provided without fingerprint", func() {
-		BeforeEach(func() {
-			inRequest.Version = concourse.Version{
-				ProductVersion: version,
-			}
+	Context("when version is provided without fingerprint", func() {
+		BeforeEach(func() {
+			inRequest.Version = concourse.Version{
+				ProductVersion: version,
+			}
+		})
+
+		It("returns without error (does not compare against actual fingerprint)", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when getting release returns error", func() {
+		BeforeEach(func() {
+			getReleaseErr = fmt.Errorf("some release error")
+		})
+
+		It("returns error", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).To(HaveOccurred())
+
+			Expect(err).To(Equal(getReleaseErr))
+		})
+	})
+
+	Context("when previous product slugs are provided", func() {
+		BeforeEach(func() {
+			inRequest.Source.PreviousSlugs = []string{"previous-slug"}
+		})
+
+		Context("when the release is not found for the product slug", func() {
+			JustBeforeEach(func() {
+				fakePivnetClient.GetReleaseStub = func(slug string, version string) (pivnet.Release, error) {
+					if slug == "previous-slug" {
+						return release, nil
+					}
+					return pivnet.Release{}, fmt.Errorf("release not found")
+				}
+			})
+
+			It("uses the previous slug for all subsequent requests", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePivnetClient.GetReleaseCallCount()).To(Equal(2))
+
+				invokedProductSlug, _ := fakePivnetClient.AcceptEULAArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal("previous-slug"))
+
+				invokedProductSlug, _ = fakePivnetClient.ProductFilesForReleaseArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal("previous-slug"))
+			})
+		})
+
+		Context("when the release is not found for any slug", func() {
+			BeforeEach(func() {
+				getReleaseErr = fmt.Errorf("some release error")
+			})
+
+			It("returns the error for the product slug", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(Equal(getReleaseErr))
+
+				Expect(fakePivnetClient.GetReleaseCallCount()).To(Equal(2))
+			})
+		})
+	})
+
+	Context("when no version is provided", func() {
+		BeforeEach(func() {
+			inRequest.Version = concourse.Version{}
+			inRequest.Source.ProductVersion = "1.2.*"
+
+			fakeLatestResolver.LatestReturns(concourse.Version{
+				ProductVersion: versionWithFingerprint,
+			}, nil)
+		})
+
+		It("fetches the latest release matching the source", func() {
+			response, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeLatestResolver.LatestCallCount()).To(Equal(1))
+			Expect(fakeLatestResolver.LatestArgsForCall(0)).To(Equal(inRequest.Source))
+
+			Expect(fakePivnetClient.GetReleaseCallCount()).To(Equal(1))
+			_, invokedVersion := fakePivnetClient.GetReleaseArgsForCall(0)
+			Expect(invokedVersion).To(Equal(version))
+
+			Expect(response.Version.ProductVersion).To(Equal(versionWithFingerprint))
+		})
+
+		Context("when a channel is provided", func() {
+			BeforeEach(func() {
+				inRequest.Source.ProductVersion = ""
+				inRequest.Source.Channel = "stable"
+
+				err := config.ApplyChannel(&inRequest.Source)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeReleaseLister := &resolutionfakes.FakeReleaseLister{}
+				fakeReleaseLister.ReleaseTypesReturns([]pivnet.ReleaseType{
+					"Alpha Release",
+					"Major Release",
+					"Minor Release",
+					"Maintenance Release",
+					"Security Release",
+				}, nil)
+				fakeReleaseLister.ReleasesForProductSlugReturns([]pivnet.Release{
+					{ID: 2, Version: "D", ReleaseType: "Alpha Release", Availability: "All Users"},
+					{ID: 1, Version: version, ReleaseType: "Minor Release", Availability: "All Users", SoftwareFilesUpdatedAt: fingerprint},
+				}, nil)
+
+				logger := log.New(GinkgoWriter, "", log.LstdFlags)
+				matcher := resolution.NewMatcher(
+					logshim.NewLogShim(logger, logger, true),
+					&resolutionfakes.FakeFilter{},
+					fakeReleaseLister,
+					&resolutionfakes.FakeSorter{},
+				)
+				fakeLatestResolver.LatestStub = matcher.Latest
+			})
+
+			It("fetches the latest release of the channel", func() {
+				response, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakePivnetClient.GetReleaseCallCount()).To(Equal(1))
+				_, invokedVersion := fakePivnetClient.GetReleaseArgsForCall(0)
+				Expect(invokedVersion).To(Equal(version))
+
+				Expect(response.Version.ProductVersion).To(Equal(versionWithFingerprint))
+			})
+		})
+
+		Context("when resolving the latest release returns an error", func() {
+			var expectedErr error
+
+			BeforeEach(func() {
+				expectedErr = fmt.Errorf("cannot find specified release")
+				fakeLatestResolver.LatestReturns(concourse.Version{}, expectedErr)
+			})
+
+			It("returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(Equal(expectedErr))
+
+				Expect(fakePivnetClient.GetReleaseCallCount()).To(Equal(0))
+			})
 		})
+	})
 
-		It("returns without error (does not compare against actual fingerprint)", func() {
+	Context("when a version is provided", func() {
+		It("does not resolve the latest release", func() {
 			_, err := inCommand.Run(inRequest)
 			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeLatestResolver.LatestCallCount()).To(Equal(0))
 		})
 	})
 
-	Context("when getting release returns error", func() {
+	Context("when the version has a release ID", func() {
 		BeforeEach(func() {
-			getReleaseErr = fmt.Errorf("some release error")
+			inRequest.Version.ReleaseID = "1234"
 		})
 
-		It("returns error", func() {
+		JustBeforeEach(func() {
+			fakePivnetClient.GetReleaseByIDReturns(release, getReleaseErr)
+		})
+
+		It("gets the release by its ID rather than its version", func() {
 			_, err := inCommand.Run(inRequest)
-			Expect(err).To(HaveOccurred())
+			Expect(err).NotTo(HaveOccurred())
 
-			Expect(err).To(Equal(getReleaseErr))
+			Expect(fakePivnetClient.GetReleaseCallCount()).To(Equal(0))
+			Expect(fakePivnetClient.GetReleaseByIDCallCount()).To(Equal(1))
+
+			invokedProductSlug, invokedReleaseID := fakePivnetClient.GetReleaseByIDArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal(productSlug))
+			Expect(invokedReleaseID).To(Equal(1234))
+		})
+
+		Context("when version_release_id is set", func() {
+			BeforeEach(func() {
+				inRequest.Source.VersionReleaseID = true
+			})
+
+			It("includes the release ID in the returned version", func() {
+				response, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response.Version.ReleaseID).To(Equal("1234"))
+			})
+		})
+
+		Context("when the release ID is not a number", func() {
+			BeforeEach(func() {
+				inRequest.Version.ReleaseID = "not-an-id"
+			})
+
+			It("returns an error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("release_id 'not-an-id' is not a valid release ID"))
+
+				Expect(fakePivnetClient.GetReleaseByIDCallCount()).To(Equal(0))
+			})
 		})
 	})
 
@@ -454,6 +1163,64 @@ var _ = Describe("In", func() {
 		})
 	})
 
+	Context("when eula_action is preview", func() {
+		BeforeEach(func() {
+			inRequest.Params.EULAAction = concourse.EULAActionPreview
+
+			fakePivnetClient.EULAReturns(pivnet.EULA{Slug: eulaSlug, Content: "some eula text"}, nil)
+		})
+
+		It("writes the EULA text and returns an error with instructions", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("set 'accept: true'"))
+
+			Expect(fakePivnetClient.EULAArgsForCall(0)).To(Equal(eulaSlug))
+			Expect(fakeFileWriter.WriteEULAFileArgsForCall(0)).To(Equal("some eula text"))
+
+			Expect(fakePivnetClient.AcceptEULACallCount()).To(Equal(0))
+			Expect(fakeDownloader.DownloadCallCount()).To(Equal(0))
+		})
+
+		Context("when accept is set", func() {
+			BeforeEach(func() {
+				inRequest.Params.Accept = true
+			})
+
+			It("writes the EULA text and accepts the EULA", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeFileWriter.WriteEULAFileArgsForCall(0)).To(Equal("some eula text"))
+				Expect(fakePivnetClient.AcceptEULACallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the release has no EULA", func() {
+			BeforeEach(func() {
+				release.EULA = nil
+			})
+
+			It("does not require the EULA to be accepted", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeFileWriter.WriteEULAFileCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when getting the EULA returns an error", func() {
+			BeforeEach(func() {
+				fakePivnetClient.EULAReturns(pivnet.EULA{}, fmt.Errorf("some eula error"))
+			})
+
+			It("returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("some eula error"))
+			})
+		})
+	})
+
 	Context("when accepting EULA returns error", func() {
 		BeforeEach(func() {
 			acceptEULAErr = fmt.Errorf("some eula error")
@@ -467,6 +1234,103 @@ var _ = Describe("In", func() {
 		})
 	})
 
+	Context("when the EULA must be clicked through on the website", func() {
+		var eulaErr gp.ErrEULAAcceptanceRequired
+
+		BeforeEach(func() {
+			eulaErr = gp.ErrEULAAcceptanceRequired{
+				ReleaseID: release.ID,
+				URL:       "https://network.example.com/products/some-product/releases/1234",
+				Err:       fmt.Errorf("some eula error"),
+			}
+
+			downloadFailures = map[int]error{
+				3456: eulaErr,
+			}
+		})
+
+		JustBeforeEach(func() {
+			fakeDownloader.DownloadReturns(
+				[]string{downloadFilepaths[0], downloadFilepaths[2], downloadFilepaths[3]},
+				downloadFailures,
+				nil,
+			)
+		})
+
+		It("returns an error with where to accept it", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).To(Equal(eulaErr))
+			Expect(err.Error()).To(ContainSubstring("https://network.example.com/products/some-product/releases/1234"))
+
+			Expect(fakeFileWriter.WriteMetadataYAMLFileCallCount()).To(Equal(0))
+		})
+
+		Context("when allow_partial is set", func() {
+			BeforeEach(func() {
+				inRequest.Params.AllowPartial = true
+			})
+
+			It("still returns the error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(Equal(eulaErr))
+			})
+		})
+	})
+
+	Context("when file groups are not available to the token", func() {
+		BeforeEach(func() {
+			fileGroups = nil
+			fileGroupsErr = pivnet.ErrPivnetOther{ResponseCode: http.StatusForbidden, Message: "forbidden"}
+		})
+
+		It("continues without file groups and records them as unsupported in the metadata", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			Expect(invokedMetadata.FileGroups).To(BeEmpty())
+			Expect(invokedMetadata.UnsupportedFeatures).To(Equal([]string{"file_groups"}))
+		})
+	})
+
+	Context("when file groups are not found", func() {
+		BeforeEach(func() {
+			fileGroupsErr = pivnet.ErrNotFound{ErrPivnetOther: pivnet.ErrPivnetOther{ResponseCode: http.StatusNotFound}}
+		})
+
+		It("returns the error rather than continuing without file groups", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).To(Equal(fileGroupsErr))
+		})
+	})
+
+	Context("when upgrade paths are not available to the token", func() {
+		BeforeEach(func() {
+			releaseUpgradePaths = nil
+			upgradePathSpecifiers = nil
+			releaseUpgradePathsErr = pivnet.ErrNotFound{ErrPivnetOther: pivnet.ErrPivnetOther{ResponseCode: http.StatusNotFound}}
+			upgradePathSpecifiersErr = pivnet.ErrNotFound{ErrPivnetOther: pivnet.ErrPivnetOther{ResponseCode: http.StatusNotFound}}
+		})
+
+		It("continues without upgrade paths and records them as unsupported in the metadata", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			Expect(invokedMetadata.UpgradePaths).To(BeEmpty())
+			Expect(invokedMetadata.UpgradePathSpecifiers).To(BeEmpty())
+			Expect(invokedMetadata.UnsupportedFeatures).To(Equal([]string{"release_upgrade_paths", "upgrade_path_specifiers"}))
+		})
+	})
+
+	It("does not record any unsupported features", func() {
+		_, err := inCommand.Run(inRequest)
+		Expect(err).NotTo(HaveOccurred())
+
+		invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+		Expect(invokedMetadata.UnsupportedFeatures).To(BeEmpty())
+	})
+
 	Context("when getting file groups returns error", func() {
 		BeforeEach(func() {
 			fileGroupsErr = fmt.Errorf("some file group error")
@@ -493,6 +1357,44 @@ var _ = Describe("In", func() {
 		})
 	})
 
+	Describe("when platform_filter is provided", func() {
+		BeforeEach(func() {
+			inRequest.Params.PlatformFilter = "linux/amd64"
+		})
+
+		JustBeforeEach(func() {
+			fakeFilter.ProductFilesByPlatformStub = func(productFiles []pivnet.ProductFile, p platform.Platform) ([]pivnet.ProductFile, error) {
+				return productFiles[:1], nil
+			}
+		})
+
+		It("downloads only the files for the platform", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFilter.ProductFilesByPlatformCallCount()).To(Equal(1))
+			invokedProductFiles, invokedPlatform := fakeFilter.ProductFilesByPlatformArgsForCall(0)
+			Expect(invokedProductFiles).NotTo(BeEmpty())
+			Expect(invokedPlatform).To(Equal(platform.Platform{OS: platform.Linux, Arch: platform.AMD64}))
+
+			downloadedProductFiles, _, _ := fakeDownloader.DownloadArgsForCall(0)
+			Expect(downloadedProductFiles).To(Equal(invokedProductFiles[:1]))
+		})
+
+		Context("when no files are for the platform", func() {
+			JustBeforeEach(func() {
+				fakeFilter.ProductFilesByPlatformReturns(nil, fmt.Errorf("some platform error"))
+			})
+
+			It("returns the error without downloading", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("some platform error"))
+
+				Expect(fakeDownloader.DownloadCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Describe("when globs are provided", func() {
 		BeforeEach(func() {
 			inRequest.Params.Globs = []string{"some*glob", "other*glob"}
@@ -582,6 +1484,44 @@ var _ = Describe("In", func() {
 					_, err := inCommand.Run(inRequest)
 					Expect(err).To(HaveOccurred())
 				})
+
+				Context("when the file exists", func() {
+					var (
+						downloadDir string
+					)
+
+					BeforeEach(func() {
+						var err error
+						downloadDir, err = ioutil.TempDir("", "pivnet-resource")
+						Expect(err).NotTo(HaveOccurred())
+
+						for i, f := range downloadFilepaths {
+							downloadFilepaths[i] = filepath.Join(downloadDir, f)
+							err = ioutil.WriteFile(downloadFilepaths[i], []byte(f), os.ModePerm)
+							Expect(err).NotTo(HaveOccurred())
+						}
+					})
+
+					AfterEach(func() {
+						err := os.RemoveAll(downloadDir)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("moves it into the quarantine directory with the reason", func() {
+						_, err := inCommand.Run(inRequest)
+						Expect(err).To(HaveOccurred())
+
+						quarantinePath := filepath.Join(downloadDir, "quarantine", filepath.Base(downloadFilepaths[0]))
+
+						Expect(downloadFilepaths[0]).NotTo(BeAnExistingFile())
+						Expect(quarantinePath).To(BeAnExistingFile())
+
+						reason, err := ioutil.ReadFile(quarantinePath + ".reason")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(reason)).To(ContainSubstring("SHA256 comparison failed"))
+						Expect(string(reason)).To(ContainSubstring("incorrect sha256"))
+					})
+				})
 			})
 		})
 
@@ -619,6 +1559,263 @@ var _ = Describe("In", func() {
 					Expect(err).To(HaveOccurred())
 				})
 			})
+
+			Context("when fips is set", func() {
+				BeforeEach(func() {
+					filteredProductFiles[0].SHA256 = ""
+					inRequest.Source.FIPS = true
+				})
+
+				It("returns an error without downloading any files", func() {
+					_, err := inCommand.Run(inRequest)
+					Expect(err).To(MatchError(ContainSubstring("could only be verified by MD5")))
+					Expect(err).To(MatchError(ContainSubstring("'product file 1234'")))
+
+					Expect(fakeDownloader.DownloadCallCount()).To(Equal(0))
+				})
+
+				Context("when the file type is not 'Software'", func() {
+					BeforeEach(func() {
+						filteredProductFiles[0].FileType = "not software"
+					})
+
+					It("does not compute its MD5", func() {
+						_, err := inCommand.Run(inRequest)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(fakeMD5FileSummer.SumFileCallCount()).To(Equal(0))
+					})
+				})
+			})
+		})
+	})
+
+	Describe("when globs are given with subdirectories", func() {
+		var (
+			downloadDir string
+		)
+
+		BeforeEach(func() {
+			var err error
+			downloadDir, err = ioutil.TempDir("", "pivnet-resource")
+			Expect(err).NotTo(HaveOccurred())
+
+			for i, f := range downloadFilepaths {
+				downloadFilepaths[i] = filepath.Join(downloadDir, f)
+				err = ioutil.WriteFile(downloadFilepaths[i], []byte(f), os.ModePerm)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			inRequest.Params.Globs = []string{"file-1*", "file-3*"}
+			inRequest.Params.GlobSubdirs = map[string]string{
+				"file-1*": "some-dir",
+				"file-3*": "other-dir/nested",
+			}
+		})
+
+		JustBeforeEach(func() {
+			fakeFilter.ProductFileKeysByGlobsStub = func(productFiles []pivnet.ProductFile, globs []string) ([]pivnet.ProductFile, error) {
+				switch strings.Join(globs, ",") {
+				case "file-1*":
+					return []pivnet.ProductFile{releaseProductFiles[0]}, nil
+				case "file-3*":
+					return []pivnet.ProductFile{releaseProductFiles[1]}, nil
+				default:
+					return filteredProductFiles, nil
+				}
+			}
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(downloadDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("moves the files matching each glob into its subdirectory", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(downloadDir, "some-dir", "file-1234")).To(BeARegularFile())
+			Expect(filepath.Join(downloadDir, "other-dir", "nested", "file-3456")).To(BeARegularFile())
+			Expect(downloadFilepaths[0]).NotTo(BeAnExistingFile())
+			Expect(downloadFilepaths[1]).NotTo(BeAnExistingFile())
+			Expect(downloadFilepaths[2]).To(BeARegularFile())
+		})
+
+		It("records the local file names including the subdirectory in the metadata", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			Expect(invokedMetadata.ProductFiles[0].LocalFile).To(Equal(filepath.Join("some-dir", "file-1234")))
+			Expect(invokedMetadata.ProductFiles[1].LocalFile).To(Equal(filepath.Join("other-dir", "nested", "file-3456")))
+			Expect(invokedMetadata.ProductFiles[2].LocalFile).To(Equal("file-4567"))
+		})
+
+		Context("when unpack is set", func() {
+			BeforeEach(func() {
+				inRequest.Params.Unpack = true
+			})
+
+			It("unpacks the files in their subdirectories", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeArchive.MimetypeArgsForCall(0)).To(Equal(filepath.Join(downloadDir, "some-dir", "file-1234")))
+			})
+		})
+	})
+
+	Describe("when a stemcell is requested", func() {
+		var (
+			downloadDir string
+
+			stemcellRelease      pivnet.Release
+			stemcellProductFiles []pivnet.ProductFile
+			stemcellFilepath     string
+		)
+
+		BeforeEach(func() {
+			var err error
+			downloadDir, err = ioutil.TempDir("", "pivnet-resource")
+			Expect(err).NotTo(HaveOccurred())
+
+			stemcellFilepath = filepath.Join(downloadDir, "bosh-stemcell-1.250-vsphere.tgz")
+			err = ioutil.WriteFile(stemcellFilepath, []byte("some-stemcell"), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+
+			stemcellRelease = pivnet.Release{
+				ID:      802,
+				Version: "1.250",
+			}
+
+			stemcellProductFiles = []pivnet.ProductFile{
+				{
+					ID:           9001,
+					Name:         "vSphere stemcell",
+					AWSObjectKey: "product-files/stemcells-ubuntu-jammy/bosh-stemcell-1.250-vsphere.tgz",
+					FileType:     pivnet.FileTypeSoftware,
+					SHA256:       "some-stemcell-sha256",
+				},
+			}
+
+			releaseDependencies = append(releaseDependencies,
+				pivnet.ReleaseDependency{Release: pivnet.DependentRelease{
+					ID:      801,
+					Version: "1.100",
+					Product: pivnet.Product{Slug: "stemcells-ubuntu-jammy"},
+				}},
+				pivnet.ReleaseDependency{Release: pivnet.DependentRelease{
+					ID:      802,
+					Version: "1.250",
+					Product: pivnet.Product{Slug: "stemcells-ubuntu-jammy"},
+				}},
+				pivnet.ReleaseDependency{Release: pivnet.DependentRelease{
+					ID:      803,
+					Version: "621.900",
+					Product: pivnet.Product{Slug: "stemcells-ubuntu-xenial"},
+				}},
+			)
+
+			inRequest.Params.Stemcell = &concourse.StemcellParams{
+				OS:         "ubuntu-jammy",
+				MinVersion: "1.200",
+			}
+		})
+
+		JustBeforeEach(func() {
+			fakePivnetClient.GetReleaseByIDReturns(stemcellRelease, nil)
+			fakePivnetClient.ProductFilesForReleaseStub = func(productSlug string, releaseID int) ([]pivnet.ProductFile, error) {
+				if productSlug == "stemcells-ubuntu-jammy" {
+					return stemcellProductFiles, nil
+				}
+				return releaseProductFiles, productFilesErr
+			}
+
+			fakeDownloader.DownloadStub = func(productFiles []pivnet.ProductFile, productSlug string, releaseID int) ([]string, map[int]error, error) {
+				if productSlug == "stemcells-ubuntu-jammy" {
+					return []string{stemcellFilepath}, nil, nil
+				}
+				return downloadFilepaths, downloadFailures, downloadErr
+			}
+
+			fakeFilter.ProductFileKeysByGlobsStub = func(productFiles []pivnet.ProductFile, globs []string) ([]pivnet.ProductFile, error) {
+				if strings.Join(globs, ",") == "*" {
+					return productFiles, nil
+				}
+				return filteredProductFiles, filterErr
+			}
+
+			releaseSHA256Stub := fakeSHA256FileSummer.SumFileStub
+			fakeSHA256FileSummer.SumFileStub = func(path string) (string, error) {
+				if path == stemcellFilepath {
+					return "some-stemcell-sha256", nil
+				}
+				return releaseSHA256Stub(path)
+			}
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(downloadDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("gets the latest stemcell of the os no older than the minimum version", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnetClient.GetReleaseByIDCallCount()).To(Equal(1))
+			invokedProductSlug, invokedReleaseID := fakePivnetClient.GetReleaseByIDArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal("stemcells-ubuntu-jammy"))
+			Expect(invokedReleaseID).To(Equal(802))
+
+			Expect(fakePivnetClient.AcceptEULACallCount()).To(Equal(2))
+			invokedProductSlug, invokedReleaseID = fakePivnetClient.AcceptEULAArgsForCall(1)
+			Expect(invokedProductSlug).To(Equal("stemcells-ubuntu-jammy"))
+			Expect(invokedReleaseID).To(Equal(802))
+		})
+
+		It("moves the stemcell files into the stemcell subdirectory", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(downloadDir, "stemcell", "bosh-stemcell-1.250-vsphere.tgz")).To(BeARegularFile())
+			Expect(stemcellFilepath).NotTo(BeAnExistingFile())
+		})
+
+		It("records the stemcell in the metadata", func() {
+			_, err := inCommand.Run(inRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedMetadata := fakeFileWriter.WriteMetadataYAMLFileArgsForCall(0)
+			Expect(invokedMetadata.Stemcell).To(Equal(&metadata.Stemcell{
+				ProductSlug: "stemcells-ubuntu-jammy",
+				ReleaseID:   802,
+				Version:     "1.250",
+				ProductFiles: []metadata.ProductFile{
+					{
+						ID:           9001,
+						File:         "vSphere stemcell",
+						AWSObjectKey: "product-files/stemcells-ubuntu-jammy/bosh-stemcell-1.250-vsphere.tgz",
+						FileType:     pivnet.FileTypeSoftware,
+						SHA256:       "some-stemcell-sha256",
+						LocalFile:    filepath.Join("stemcell", "bosh-stemcell-1.250-vsphere.tgz"),
+					},
+				},
+			}))
+		})
+
+		Context("when no stemcell of the os is at least the minimum version", func() {
+			BeforeEach(func() {
+				inRequest.Params.Stemcell.MinVersion = "2"
+			})
+
+			It("returns an error", func() {
+				_, err := inCommand.Run(inRequest)
+				Expect(err).To(MatchError("release does not depend on a stemcell of 'stemcells-ubuntu-jammy' with version at least '2'"))
+
+				Expect(fakePivnetClient.GetReleaseByIDCallCount()).To(Equal(0))
+			})
 		})
 	})
 
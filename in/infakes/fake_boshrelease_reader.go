@@ -0,0 +1,76 @@
+// This file was generated by counterfeiter
+package infakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/pivnet-resource/boshrelease"
+)
+
+type FakeBOSHReleaseReader struct {
+	ReadStub        func(fileName string) (*boshrelease.Manifest, error)
+	readMutex       sync.RWMutex
+	readArgsForCall []struct {
+		fileName string
+	}
+	readReturns struct {
+		result1 *boshrelease.Manifest
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBOSHReleaseReader) Read(fileName string) (*boshrelease.Manifest, error) {
+	fake.readMutex.Lock()
+	fake.readArgsForCall = append(fake.readArgsForCall, struct {
+		fileName string
+	}{fileName})
+	fake.recordInvocation("Read", []interface{}{fileName})
+	fake.readMutex.Unlock()
+	if fake.ReadStub != nil {
+		return fake.ReadStub(fileName)
+	} else {
+		return fake.readReturns.result1, fake.readReturns.result2
+	}
+}
+
+func (fake *FakeBOSHReleaseReader) ReadCallCount() int {
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	return len(fake.readArgsForCall)
+}
+
+func (fake *FakeBOSHReleaseReader) ReadArgsForCall(i int) string {
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	return fake.readArgsForCall[i].fileName
+}
+
+func (fake *FakeBOSHReleaseReader) ReadReturns(result1 *boshrelease.Manifest, result2 error) {
+	fake.ReadStub = nil
+	fake.readReturns = struct {
+		result1 *boshrelease.Manifest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBOSHReleaseReader) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBOSHReleaseReader) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
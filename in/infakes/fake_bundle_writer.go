@@ -0,0 +1,77 @@
+// This file was generated by counterfeiter
+package infakes
+
+import (
+	"sync"
+)
+
+type FakeBundleWriter struct {
+	WriteStub        func(productFileNames []string) error
+	writeMutex       sync.RWMutex
+	writeArgsForCall []struct {
+		productFileNames []string
+	}
+	writeReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBundleWriter) Write(productFileNames []string) error {
+	var productFileNamesCopy []string
+	if productFileNames != nil {
+		productFileNamesCopy = make([]string, len(productFileNames))
+		copy(productFileNamesCopy, productFileNames)
+	}
+	fake.writeMutex.Lock()
+	fake.writeArgsForCall = append(fake.writeArgsForCall, struct {
+		productFileNames []string
+	}{productFileNamesCopy})
+	fake.recordInvocation("Write", []interface{}{productFileNamesCopy})
+	fake.writeMutex.Unlock()
+	if fake.WriteStub != nil {
+		return fake.WriteStub(productFileNames)
+	} else {
+		return fake.writeReturns.result1
+	}
+}
+
+func (fake *FakeBundleWriter) WriteCallCount() int {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return len(fake.writeArgsForCall)
+}
+
+func (fake *FakeBundleWriter) WriteArgsForCall(i int) []string {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return fake.writeArgsForCall[i].productFileNames
+}
+
+func (fake *FakeBundleWriter) WriteReturns(result1 error) {
+	fake.WriteStub = nil
+	fake.writeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBundleWriter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBundleWriter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
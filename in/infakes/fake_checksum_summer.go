@@ -0,0 +1,74 @@
+// This file was generated by counterfeiter
+package infakes
+
+import (
+	"sync"
+)
+
+type FakeChecksumSummer struct {
+	SumFileStub        func(filepath string) (map[string]string, error)
+	sumFileMutex       sync.RWMutex
+	sumFileArgsForCall []struct {
+		filepath string
+	}
+	sumFileReturns struct {
+		result1 map[string]string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeChecksumSummer) SumFile(filepath string) (map[string]string, error) {
+	fake.sumFileMutex.Lock()
+	fake.sumFileArgsForCall = append(fake.sumFileArgsForCall, struct {
+		filepath string
+	}{filepath})
+	fake.recordInvocation("SumFile", []interface{}{filepath})
+	fake.sumFileMutex.Unlock()
+	if fake.SumFileStub != nil {
+		return fake.SumFileStub(filepath)
+	} else {
+		return fake.sumFileReturns.result1, fake.sumFileReturns.result2
+	}
+}
+
+func (fake *FakeChecksumSummer) SumFileCallCount() int {
+	fake.sumFileMutex.RLock()
+	defer fake.sumFileMutex.RUnlock()
+	return len(fake.sumFileArgsForCall)
+}
+
+func (fake *FakeChecksumSummer) SumFileArgsForCall(i int) string {
+	fake.sumFileMutex.RLock()
+	defer fake.sumFileMutex.RUnlock()
+	return fake.sumFileArgsForCall[i].filepath
+}
+
+func (fake *FakeChecksumSummer) SumFileReturns(result1 map[string]string, result2 error) {
+	fake.SumFileStub = nil
+	fake.sumFileReturns = struct {
+		result1 map[string]string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeChecksumSummer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.sumFileMutex.RLock()
+	defer fake.sumFileMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeChecksumSummer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
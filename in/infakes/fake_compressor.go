@@ -0,0 +1,76 @@
+// This file was generated by counterfeiter
+package infakes
+
+import (
+	"sync"
+)
+
+type FakeCompressor struct {
+	CompressStub        func(format string, path string) (string, error)
+	compressMutex       sync.RWMutex
+	compressArgsForCall []struct {
+		format string
+		path   string
+	}
+	compressReturns struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCompressor) Compress(format string, path string) (string, error) {
+	fake.compressMutex.Lock()
+	fake.compressArgsForCall = append(fake.compressArgsForCall, struct {
+		format string
+		path   string
+	}{format, path})
+	fake.recordInvocation("Compress", []interface{}{format, path})
+	fake.compressMutex.Unlock()
+	if fake.CompressStub != nil {
+		return fake.CompressStub(format, path)
+	} else {
+		return fake.compressReturns.result1, fake.compressReturns.result2
+	}
+}
+
+func (fake *FakeCompressor) CompressCallCount() int {
+	fake.compressMutex.RLock()
+	defer fake.compressMutex.RUnlock()
+	return len(fake.compressArgsForCall)
+}
+
+func (fake *FakeCompressor) CompressArgsForCall(i int) (string, string) {
+	fake.compressMutex.RLock()
+	defer fake.compressMutex.RUnlock()
+	return fake.compressArgsForCall[i].format, fake.compressArgsForCall[i].path
+}
+
+func (fake *FakeCompressor) CompressReturns(result1 string, result2 error) {
+	fake.CompressStub = nil
+	fake.compressReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCompressor) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.compressMutex.RLock()
+	defer fake.compressMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCompressor) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
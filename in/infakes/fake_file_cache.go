@@ -0,0 +1,74 @@
+// This file was generated by counterfeiter
+package infakes
+
+import (
+	"sync"
+)
+
+type FakeFileCache struct {
+	AddStub        func(sha256 string, filepath string) error
+	addMutex       sync.RWMutex
+	addArgsForCall []struct {
+		sha256   string
+		filepath string
+	}
+	addReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeFileCache) Add(sha256 string, filepath string) error {
+	fake.addMutex.Lock()
+	fake.addArgsForCall = append(fake.addArgsForCall, struct {
+		sha256   string
+		filepath string
+	}{sha256, filepath})
+	fake.recordInvocation("Add", []interface{}{sha256, filepath})
+	fake.addMutex.Unlock()
+	if fake.AddStub != nil {
+		return fake.AddStub(sha256, filepath)
+	} else {
+		return fake.addReturns.result1
+	}
+}
+
+func (fake *FakeFileCache) AddCallCount() int {
+	fake.addMutex.RLock()
+	defer fake.addMutex.RUnlock()
+	return len(fake.addArgsForCall)
+}
+
+func (fake *FakeFileCache) AddArgsForCall(i int) (string, string) {
+	fake.addMutex.RLock()
+	defer fake.addMutex.RUnlock()
+	return fake.addArgsForCall[i].sha256, fake.addArgsForCall[i].filepath
+}
+
+func (fake *FakeFileCache) AddReturns(result1 error) {
+	fake.AddStub = nil
+	fake.addReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFileCache) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addMutex.RLock()
+	defer fake.addMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeFileCache) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
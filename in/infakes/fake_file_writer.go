@@ -32,6 +32,22 @@ type FakeFileWriter struct {
 	writeVersionFileReturns struct {
 		result1 error
 	}
+	WriteEULAFileStub        func(content string) error
+	writeEULAFileMutex       sync.RWMutex
+	writeEULAFileArgsForCall []struct {
+		content string
+	}
+	writeEULAFileReturns struct {
+		result1 error
+	}
+	WriteEnvFileStub        func(mdata metadata.Metadata) error
+	writeEnvFileMutex       sync.RWMutex
+	writeEnvFileArgsForCall []struct {
+		mdata metadata.Metadata
+	}
+	writeEnvFileReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -135,6 +151,72 @@ func (fake *FakeFileWriter) WriteVersionFileReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeFileWriter) WriteEULAFile(content string) error {
+	fake.writeEULAFileMutex.Lock()
+	fake.writeEULAFileArgsForCall = append(fake.writeEULAFileArgsForCall, struct {
+		content string
+	}{content})
+	fake.recordInvocation("WriteEULAFile", []interface{}{content})
+	fake.writeEULAFileMutex.Unlock()
+	if fake.WriteEULAFileStub != nil {
+		return fake.WriteEULAFileStub(content)
+	} else {
+		return fake.writeEULAFileReturns.result1
+	}
+}
+
+func (fake *FakeFileWriter) WriteEULAFileCallCount() int {
+	fake.writeEULAFileMutex.RLock()
+	defer fake.writeEULAFileMutex.RUnlock()
+	return len(fake.writeEULAFileArgsForCall)
+}
+
+func (fake *FakeFileWriter) WriteEULAFileArgsForCall(i int) string {
+	fake.writeEULAFileMutex.RLock()
+	defer fake.writeEULAFileMutex.RUnlock()
+	return fake.writeEULAFileArgsForCall[i].content
+}
+
+func (fake *FakeFileWriter) WriteEULAFileReturns(result1 error) {
+	fake.WriteEULAFileStub = nil
+	fake.writeEULAFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFileWriter) WriteEnvFile(mdata metadata.Metadata) error {
+	fake.writeEnvFileMutex.Lock()
+	fake.writeEnvFileArgsForCall = append(fake.writeEnvFileArgsForCall, struct {
+		mdata metadata.Metadata
+	}{mdata})
+	fake.recordInvocation("WriteEnvFile", []interface{}{mdata})
+	fake.writeEnvFileMutex.Unlock()
+	if fake.WriteEnvFileStub != nil {
+		return fake.WriteEnvFileStub(mdata)
+	} else {
+		return fake.writeEnvFileReturns.result1
+	}
+}
+
+func (fake *FakeFileWriter) WriteEnvFileCallCount() int {
+	fake.writeEnvFileMutex.RLock()
+	defer fake.writeEnvFileMutex.RUnlock()
+	return len(fake.writeEnvFileArgsForCall)
+}
+
+func (fake *FakeFileWriter) WriteEnvFileArgsForCall(i int) metadata.Metadata {
+	fake.writeEnvFileMutex.RLock()
+	defer fake.writeEnvFileMutex.RUnlock()
+	return fake.writeEnvFileArgsForCall[i].mdata
+}
+
+func (fake *FakeFileWriter) WriteEnvFileReturns(result1 error) {
+	fake.WriteEnvFileStub = nil
+	fake.writeEnvFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFileWriter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -144,6 +226,10 @@ func (fake *FakeFileWriter) Invocations() map[string][][]interface{} {
 	defer fake.writeMetadataYAMLFileMutex.RUnlock()
 	fake.writeVersionFileMutex.RLock()
 	defer fake.writeVersionFileMutex.RUnlock()
+	fake.writeEULAFileMutex.RLock()
+	defer fake.writeEULAFileMutex.RUnlock()
+	fake.writeEnvFileMutex.RLock()
+	defer fake.writeEnvFileMutex.RUnlock()
 	return fake.invocations
 }
 
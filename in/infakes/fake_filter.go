@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/platform"
 )
 
 type FakeFilter struct {
@@ -18,6 +19,16 @@ type FakeFilter struct {
 		result1 []go_pivnet.ProductFile
 		result2 error
 	}
+	ProductFilesByPlatformStub        func(productFiles []go_pivnet.ProductFile, p platform.Platform) ([]go_pivnet.ProductFile, error)
+	productFilesByPlatformMutex       sync.RWMutex
+	productFilesByPlatformArgsForCall []struct {
+		productFiles []go_pivnet.ProductFile
+		p            platform.Platform
+	}
+	productFilesByPlatformReturns struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -67,11 +78,53 @@ func (fake *FakeFilter) ProductFileKeysByGlobsReturns(result1 []go_pivnet.Produc
 	}{result1, result2}
 }
 
+func (fake *FakeFilter) ProductFilesByPlatform(productFiles []go_pivnet.ProductFile, p platform.Platform) ([]go_pivnet.ProductFile, error) {
+	var productFilesCopy []go_pivnet.ProductFile
+	if productFiles != nil {
+		productFilesCopy = make([]go_pivnet.ProductFile, len(productFiles))
+		copy(productFilesCopy, productFiles)
+	}
+	fake.productFilesByPlatformMutex.Lock()
+	fake.productFilesByPlatformArgsForCall = append(fake.productFilesByPlatformArgsForCall, struct {
+		productFiles []go_pivnet.ProductFile
+		p            platform.Platform
+	}{productFilesCopy, p})
+	fake.recordInvocation("ProductFilesByPlatform", []interface{}{productFilesCopy, p})
+	fake.productFilesByPlatformMutex.Unlock()
+	if fake.ProductFilesByPlatformStub != nil {
+		return fake.ProductFilesByPlatformStub(productFiles, p)
+	} else {
+		return fake.productFilesByPlatformReturns.result1, fake.productFilesByPlatformReturns.result2
+	}
+}
+
+func (fake *FakeFilter) ProductFilesByPlatformCallCount() int {
+	fake.productFilesByPlatformMutex.RLock()
+	defer fake.productFilesByPlatformMutex.RUnlock()
+	return len(fake.productFilesByPlatformArgsForCall)
+}
+
+func (fake *FakeFilter) ProductFilesByPlatformArgsForCall(i int) ([]go_pivnet.ProductFile, platform.Platform) {
+	fake.productFilesByPlatformMutex.RLock()
+	defer fake.productFilesByPlatformMutex.RUnlock()
+	return fake.productFilesByPlatformArgsForCall[i].productFiles, fake.productFilesByPlatformArgsForCall[i].p
+}
+
+func (fake *FakeFilter) ProductFilesByPlatformReturns(result1 []go_pivnet.ProductFile, result2 error) {
+	fake.ProductFilesByPlatformStub = nil
+	fake.productFilesByPlatformReturns = struct {
+		result1 []go_pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeFilter) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.productFileKeysByGlobsMutex.RLock()
 	defer fake.productFileKeysByGlobsMutex.RUnlock()
+	fake.productFilesByPlatformMutex.RLock()
+	defer fake.productFilesByPlatformMutex.RUnlock()
 	return fake.invocations
 }
 
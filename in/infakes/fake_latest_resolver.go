@@ -0,0 +1,76 @@
+// This file was generated by counterfeiter
+package infakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+type FakeLatestResolver struct {
+	LatestStub        func(source concourse.Source) (concourse.Version, error)
+	latestMutex       sync.RWMutex
+	latestArgsForCall []struct {
+		source concourse.Source
+	}
+	latestReturns struct {
+		result1 concourse.Version
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeLatestResolver) Latest(source concourse.Source) (concourse.Version, error) {
+	fake.latestMutex.Lock()
+	fake.latestArgsForCall = append(fake.latestArgsForCall, struct {
+		source concourse.Source
+	}{source})
+	fake.recordInvocation("Latest", []interface{}{source})
+	fake.latestMutex.Unlock()
+	if fake.LatestStub != nil {
+		return fake.LatestStub(source)
+	} else {
+		return fake.latestReturns.result1, fake.latestReturns.result2
+	}
+}
+
+func (fake *FakeLatestResolver) LatestCallCount() int {
+	fake.latestMutex.RLock()
+	defer fake.latestMutex.RUnlock()
+	return len(fake.latestArgsForCall)
+}
+
+func (fake *FakeLatestResolver) LatestArgsForCall(i int) concourse.Source {
+	fake.latestMutex.RLock()
+	defer fake.latestMutex.RUnlock()
+	return fake.latestArgsForCall[i].source
+}
+
+func (fake *FakeLatestResolver) LatestReturns(result1 concourse.Version, result2 error) {
+	fake.LatestStub = nil
+	fake.latestReturns = struct {
+		result1 concourse.Version
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLatestResolver) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.latestMutex.RLock()
+	defer fake.latestMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeLatestResolver) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
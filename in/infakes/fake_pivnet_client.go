@@ -98,6 +98,25 @@ type FakePivnetClient struct {
 		result1 []go_pivnet.UpgradePathSpecifier
 		result2 error
 	}
+	EULAStub        func(eulaSlug string) (go_pivnet.EULA, error)
+	eULAMutex       sync.RWMutex
+	eULAArgsForCall []struct {
+		eulaSlug string
+	}
+	eULAReturns struct {
+		result1 go_pivnet.EULA
+		result2 error
+	}
+	GetReleaseByIDStub        func(productSlug string, releaseID int) (go_pivnet.Release, error)
+	getReleaseByIDMutex       sync.RWMutex
+	getReleaseByIDArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	getReleaseByIDReturns struct {
+		result1 go_pivnet.Release
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -417,6 +436,75 @@ func (fake *FakePivnetClient) UpgradePathSpecifiersReturns(result1 []go_pivnet.U
 	}{result1, result2}
 }
 
+func (fake *FakePivnetClient) EULA(eulaSlug string) (go_pivnet.EULA, error) {
+	fake.eULAMutex.Lock()
+	fake.eULAArgsForCall = append(fake.eULAArgsForCall, struct {
+		eulaSlug string
+	}{eulaSlug})
+	fake.recordInvocation("EULA", []interface{}{eulaSlug})
+	fake.eULAMutex.Unlock()
+	if fake.EULAStub != nil {
+		return fake.EULAStub(eulaSlug)
+	} else {
+		return fake.eULAReturns.result1, fake.eULAReturns.result2
+	}
+}
+
+func (fake *FakePivnetClient) EULACallCount() int {
+	fake.eULAMutex.RLock()
+	defer fake.eULAMutex.RUnlock()
+	return len(fake.eULAArgsForCall)
+}
+
+func (fake *FakePivnetClient) EULAArgsForCall(i int) string {
+	fake.eULAMutex.RLock()
+	defer fake.eULAMutex.RUnlock()
+	return fake.eULAArgsForCall[i].eulaSlug
+}
+
+func (fake *FakePivnetClient) EULAReturns(result1 go_pivnet.EULA, result2 error) {
+	fake.EULAStub = nil
+	fake.eULAReturns = struct {
+		result1 go_pivnet.EULA
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetReleaseByID(productSlug string, releaseID int) (go_pivnet.Release, error) {
+	fake.getReleaseByIDMutex.Lock()
+	fake.getReleaseByIDArgsForCall = append(fake.getReleaseByIDArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("GetReleaseByID", []interface{}{productSlug, releaseID})
+	fake.getReleaseByIDMutex.Unlock()
+	if fake.GetReleaseByIDStub != nil {
+		return fake.GetReleaseByIDStub(productSlug, releaseID)
+	} else {
+		return fake.getReleaseByIDReturns.result1, fake.getReleaseByIDReturns.result2
+	}
+}
+
+func (fake *FakePivnetClient) GetReleaseByIDCallCount() int {
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
+	return len(fake.getReleaseByIDArgsForCall)
+}
+
+func (fake *FakePivnetClient) GetReleaseByIDArgsForCall(i int) (string, int) {
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
+	return fake.getReleaseByIDArgsForCall[i].productSlug, fake.getReleaseByIDArgsForCall[i].releaseID
+}
+
+func (fake *FakePivnetClient) GetReleaseByIDReturns(result1 go_pivnet.Release, result2 error) {
+	fake.GetReleaseByIDStub = nil
+	fake.getReleaseByIDReturns = struct {
+		result1 go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -438,6 +526,10 @@ func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	defer fake.releaseUpgradePathsMutex.RUnlock()
 	fake.upgradePathSpecifiersMutex.RLock()
 	defer fake.upgradePathSpecifiersMutex.RUnlock()
+	fake.eULAMutex.RLock()
+	defer fake.eULAMutex.RUnlock()
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,41 @@
+package in
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/pivnet-resource/logging"
+)
+
+// quarantineDir is the subdirectory of the download directory into which
+// files which fail verification are moved.
+const quarantineDir = "quarantine"
+
+// quarantine moves a downloaded file which failed verification into the
+// quarantine subdirectory, alongside a file named after it with a .reason
+// suffix which records why, so that possibly tampered files can be inspected
+// rather than being deleted. It returns the reason, as the get fails
+// regardless; failing to quarantine the file is only logged.
+func (c InCommand) quarantine(downloadPath string, reason error) error {
+	dir := filepath.Join(filepath.Dir(downloadPath), quarantineDir)
+	quarantinePath := filepath.Join(dir, filepath.Base(downloadPath))
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err == nil {
+		err = os.Rename(downloadPath, quarantinePath)
+	}
+	if err == nil {
+		err = ioutil.WriteFile(quarantinePath+".reason", []byte(reason.Error()+"\n"), 0644)
+	}
+
+	if err != nil {
+		logging.Warn(c.logger, fmt.Sprintf("Could not quarantine '%s': %s", downloadPath, err.Error()))
+		return reason
+	}
+
+	c.logger.Info(fmt.Sprintf("Quarantined '%s' to '%s'", downloadPath, quarantinePath))
+
+	return reason
+}
@@ -0,0 +1,164 @@
+package in
+
+import (
+	"fmt"
+
+	bsemver "github.com/blang/semver"
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/semver"
+)
+
+const (
+	// stemcellProductPrefix is the prefix of the slugs of the stemcell
+	// products on Pivotal Network, e.g. stemcells-ubuntu-jammy.
+	stemcellProductPrefix = "stemcells-"
+
+	// stemcellDir is the subdirectory into which the stemcell is downloaded.
+	stemcellDir = "stemcell"
+)
+
+// downloadStemcell downloads the files of the latest stemcell release of the
+// OS which the release depends on, no older than the minimum version, into
+// the stemcell subdirectory. This keeps a tile and its stemcell consistent
+// without a separate stemcell resource.
+func (c InCommand) downloadStemcell(
+	params concourse.StemcellParams,
+	fips bool,
+	releaseDependencies []pivnet.ReleaseDependency,
+) (*metadata.Stemcell, error) {
+	productSlug := stemcellProductPrefix + params.OS
+
+	dependency, err := c.latestStemcellDependency(productSlug, params.MinVersion, releaseDependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info(fmt.Sprintf(
+		"Getting stemcell release for product slug: '%s' and version: '%s'",
+		productSlug,
+		dependency.Version,
+	))
+
+	release, err := c.pivnetClient.GetReleaseByID(productSlug, dependency.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Info(fmt.Sprintf("Accepting EULA for stemcell release with ID: %d", release.ID))
+
+	err = c.pivnetClient.AcceptEULA(productSlug, release.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	productFiles, err := c.pivnetClient.ProductFilesForRelease(productSlug, release.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(productFiles) == 0 {
+		return nil, fmt.Errorf("stemcell release '%s' of '%s' has no product files", release.Version, productSlug)
+	}
+
+	c.logger.Info("Downloading stemcell files")
+
+	localFileNames, _, _, err := c.downloadFiles(
+		params.Globs,
+		map[string]string{"*": stemcellDir},
+		"",
+		false,
+		false,
+		fips,
+		productFiles,
+		productSlug,
+		release.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	stemcell := &metadata.Stemcell{
+		ProductSlug: productSlug,
+		ReleaseID:   release.ID,
+		Version:     release.Version,
+	}
+
+	for _, pf := range productFiles {
+		localFileName, ok := localFileNames[pf.ID]
+		if !ok {
+			continue
+		}
+
+		stemcell.ProductFiles = append(stemcell.ProductFiles, metadata.ProductFile{
+			ID:           pf.ID,
+			File:         pf.Name,
+			AWSObjectKey: pf.AWSObjectKey,
+			FileType:     pf.FileType,
+			FileVersion:  pf.FileVersion,
+			SHA256:       pf.SHA256,
+			MD5:          pf.MD5,
+			LocalFile:    localFileName,
+		})
+	}
+
+	return stemcell, nil
+}
+
+// latestStemcellDependency returns the dependent release of the stemcell
+// product with the greatest version no lower than minVersion, if set.
+// Dependent releases whose versions are not semver are skipped.
+func (c InCommand) latestStemcellDependency(
+	productSlug string,
+	minVersion string,
+	releaseDependencies []pivnet.ReleaseDependency,
+) (pivnet.DependentRelease, error) {
+	var floor semver.Constraint
+	if minVersion != "" {
+		var err error
+		floor, err = semver.ParseConstraint(">= " + minVersion)
+		if err != nil {
+			return pivnet.DependentRelease{}, err
+		}
+	}
+
+	converter := semver.NewSemverConverter(c.logger)
+
+	var latest *pivnet.DependentRelease
+	var latestVersion bsemver.Version
+	for i, d := range releaseDependencies {
+		if d.Release.Product.Slug != productSlug {
+			continue
+		}
+
+		v, err := converter.ToValidSemver(d.Release.Version)
+		if err != nil {
+			c.logger.Info(fmt.Sprintf("Skipping stemcell with version which is not semver: '%s'", d.Release.Version))
+			continue
+		}
+
+		if minVersion != "" && !floor.Check(v) {
+			continue
+		}
+
+		if latest == nil || v.GT(latestVersion) {
+			latest = &releaseDependencies[i].Release
+			latestVersion = v
+		}
+	}
+
+	if latest == nil {
+		if minVersion != "" {
+			return pivnet.DependentRelease{}, fmt.Errorf(
+				"release does not depend on a stemcell of '%s' with version at least '%s'",
+				productSlug,
+				minVersion,
+			)
+		}
+
+		return pivnet.DependentRelease{}, fmt.Errorf("release does not depend on a stemcell of '%s'", productSlug)
+	}
+
+	return *latest, nil
+}
@@ -0,0 +1,178 @@
+package includedfiles
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// tarMagicOffset is the offset of the "ustar" magic in the first header of a
+// tar archive.
+const tarMagicOffset = 257
+
+var (
+	zipMagic      = []byte("PK\x03\x04")
+	emptyZipMagic = []byte("PK\x05\x06")
+	gzipMagic     = []byte{0x1f, 0x8b}
+	tarMagic      = []byte("ustar")
+)
+
+type Lister struct {
+	enabled bool
+}
+
+// NewLister returns a Lister which lists the top-level contents of zip, tar
+// and gzipped tar archives. If enabled is false nothing is listed.
+func NewLister(enabled bool) Lister {
+	return Lister{
+		enabled: enabled,
+	}
+}
+
+// List returns the sorted top-level contents of the archive exactGlob in
+// sourcesDir, with directories suffixed by a slash. Nothing is returned for
+// a file which is not an archive, or if the lister is disabled.
+func (l Lister) List(sourcesDir string, exactGlob string) ([]string, error) {
+	if !l.enabled {
+		return nil, nil
+	}
+
+	path := filepath.Join(sourcesDir, exactGlob)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	header, err := r.Peek(tarMagicOffset + len(tarMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	var names []string
+	switch {
+	case bytes.HasPrefix(header, zipMagic), bytes.HasPrefix(header, emptyZipMagic):
+		names, err = zipNames(path)
+	case bytes.HasPrefix(header, gzipMagic):
+		names, err = gzipTarNames(r)
+	case isTar(header):
+		names, err = tarNames(r)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contents of '%s': %s", exactGlob, err.Error())
+	}
+
+	return topLevel(names), nil
+}
+
+func isTar(header []byte) bool {
+	return len(header) >= tarMagicOffset+len(tarMagic) &&
+		bytes.Equal(header[tarMagicOffset:tarMagicOffset+len(tarMagic)], tarMagic)
+}
+
+func zipNames(path string) ([]string, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var names []string
+	for _, f := range zr.File {
+		name := f.Name
+		if f.FileInfo().IsDir() && !strings.HasSuffix(name, "/") {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// gzipTarNames lists a gzipped tar archive. A gzipped file which is not a
+// tar archive has no contents to list.
+func gzipTarNames(r io.Reader) ([]string, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	br := bufio.NewReader(gzipReader)
+
+	header, err := br.Peek(tarMagicOffset + len(tarMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if !isTar(header) {
+		return nil, nil
+	}
+
+	return tarNames(br)
+}
+
+func tarNames(r io.Reader) ([]string, error) {
+	tarReader := tar.NewReader(r)
+
+	var names []string
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeXGlobalHeader, tar.TypeXHeader:
+			continue
+		case tar.TypeDir:
+			names = append(names, strings.TrimSuffix(header.Name, "/")+"/")
+		default:
+			names = append(names, header.Name)
+		}
+	}
+}
+
+// topLevel returns the unique first path element of each name, suffixed by
+// a slash if it is a directory, i.e. if it is followed by further elements.
+func topLevel(names []string) []string {
+	seen := map[string]bool{}
+	var entries []string
+
+	for _, name := range names {
+		name = strings.TrimLeft(strings.TrimPrefix(name, "./"), "/")
+		if name == "" || name == "." || name == "./" {
+			continue
+		}
+
+		entry := name
+		if i := strings.Index(name, "/"); i != -1 {
+			entry = name[:i+1]
+		}
+
+		if !seen[entry] {
+			seen[entry] = true
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Strings(entries)
+
+	return entries
+}
@@ -0,0 +1,13 @@
+package includedfiles_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestIncludedFiles(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IncludedFiles Suite")
+}
@@ -0,0 +1,152 @@
+package includedfiles_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/includedfiles"
+)
+
+func writeTar(w io.Writer, names []string) {
+	tarWriter := tar.NewWriter(w)
+
+	for _, name := range names {
+		header := &tar.Header{
+			Name: name,
+			Mode: 0644,
+		}
+		if name[len(name)-1] == '/' {
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		}
+
+		Expect(tarWriter.WriteHeader(header)).To(Succeed())
+	}
+
+	Expect(tarWriter.Close()).To(Succeed())
+}
+
+var _ = Describe("Lister", func() {
+	var (
+		dir    string
+		lister includedfiles.Lister
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "includedfiles")
+		Expect(err).NotTo(HaveOccurred())
+
+		lister = includedfiles.NewLister(true)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("lists the top-level contents of a zip archive", func() {
+		f, err := os.Create(filepath.Join(dir, "some-tile.pivotal"))
+		Expect(err).NotTo(HaveOccurred())
+
+		zipWriter := zip.NewWriter(f)
+		for _, name := range []string{"metadata/some-tile.yml", "releases/a.tgz", "releases/b.tgz", "README.md"} {
+			_, err = zipWriter.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zipWriter.Close()).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+
+		includedFiles, err := lister.List(dir, "some-tile.pivotal")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(includedFiles).To(Equal([]string{"README.md", "metadata/", "releases/"}))
+	})
+
+	It("lists the top-level contents of a gzipped tar archive", func() {
+		f, err := os.Create(filepath.Join(dir, "some-release.tgz"))
+		Expect(err).NotTo(HaveOccurred())
+
+		gzipWriter := gzip.NewWriter(f)
+		writeTar(gzipWriter, []string{"./", "./release.MF", "./jobs/", "./jobs/some-job.tgz", "./packages/"})
+		Expect(gzipWriter.Close()).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+
+		includedFiles, err := lister.List(dir, "some-release.tgz")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(includedFiles).To(Equal([]string{"jobs/", "packages/", "release.MF"}))
+	})
+
+	It("lists the top-level contents of a tar archive", func() {
+		f, err := os.Create(filepath.Join(dir, "some-archive.tar"))
+		Expect(err).NotTo(HaveOccurred())
+
+		writeTar(f, []string{"bin/some-cli", "LICENSE"})
+		Expect(f.Close()).To(Succeed())
+
+		includedFiles, err := lister.List(dir, "some-archive.tar")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(includedFiles).To(Equal([]string{"LICENSE", "bin/"}))
+	})
+
+	It("lists nothing for a file which is not an archive", func() {
+		err := ioutil.WriteFile(filepath.Join(dir, "some-file.txt"), []byte("some contents"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		includedFiles, err := lister.List(dir, "some-file.txt")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(includedFiles).To(BeNil())
+	})
+
+	It("lists nothing for a gzipped file which is not a tar archive", func() {
+		f, err := os.Create(filepath.Join(dir, "some-file.gz"))
+		Expect(err).NotTo(HaveOccurred())
+
+		gzipWriter := gzip.NewWriter(f)
+		_, err = gzipWriter.Write([]byte("some contents"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gzipWriter.Close()).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+
+		includedFiles, err := lister.List(dir, "some-file.gz")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(includedFiles).To(BeNil())
+	})
+
+	Context("when the archive is corrupt", func() {
+		BeforeEach(func() {
+			err := ioutil.WriteFile(filepath.Join(dir, "corrupt.zip"), []byte("PK\x03\x04 not really a zip"), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error", func() {
+			_, err := lister.List(dir, "corrupt.zip")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to list contents of 'corrupt.zip'"))
+		})
+	})
+
+	Context("when the file does not exist", func() {
+		It("returns an error", func() {
+			_, err := lister.List(dir, "missing.zip")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when the lister is disabled", func() {
+		BeforeEach(func() {
+			lister = includedfiles.NewLister(false)
+		})
+
+		It("lists nothing", func() {
+			includedFiles, err := lister.List(dir, "missing.zip")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(includedFiles).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,482 @@
+package local
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/bundle"
+	"github.com/pivotal-cf/pivnet-resource/filenames"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/versions"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	metadataFileName = "metadata.yaml"
+	versionFileName  = "version"
+)
+
+// Client serves releases and product files from a local directory instead of
+// Pivotal Network, for use in offline or air-gapped environments.
+//
+// The directory is laid out as <dir>/<product_slug>/<version>/, where each
+// version directory holds the output of a previous get: the metadata.yaml and
+// version files along with the downloaded product files.
+type Client struct {
+	dir    string
+	logger logger.Logger
+}
+
+type localRelease struct {
+	dir         string
+	fingerprint string
+	metadata    metadata.Metadata
+}
+
+func NewClient(dir string, logger logger.Logger) *Client {
+	return &Client{
+		dir:    dir,
+		logger: logger,
+	}
+}
+
+func (c Client) ReleaseTypes() ([]pivnet.ReleaseType, error) {
+	productDirs, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[pivnet.ReleaseType]bool{}
+	var releaseTypes []pivnet.ReleaseType
+	for _, productDir := range productDirs {
+		if !productDir.IsDir() {
+			continue
+		}
+
+		releases, err := c.ReleasesForProductSlug(productDir.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range releases {
+			if !seen[r.ReleaseType] {
+				seen[r.ReleaseType] = true
+				releaseTypes = append(releaseTypes, r.ReleaseType)
+			}
+		}
+	}
+
+	return releaseTypes, nil
+}
+
+// ReleasesForProductSlug returns the releases for the product, newest first.
+func (c Client) ReleasesForProductSlug(productSlug string) ([]pivnet.Release, error) {
+	localReleases, err := c.localReleases(productSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []pivnet.Release
+	for _, lr := range localReleases {
+		releases = append(releases, lr.release())
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		if releases[i].ReleaseDate != releases[j].ReleaseDate {
+			return releases[i].ReleaseDate > releases[j].ReleaseDate
+		}
+		return releases[i].ID > releases[j].ID
+	})
+
+	return releases, nil
+}
+
+// RecentReleasesForProductSlug returns at most limit releases of the product,
+// the most recent first.
+func (c Client) RecentReleasesForProductSlug(productSlug string, limit int) ([]pivnet.Release, error) {
+	releases, err := c.ReleasesForProductSlug(productSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(releases) > limit {
+		releases = releases[:limit]
+	}
+
+	return releases, nil
+}
+
+func (c Client) GetRelease(productSlug string, version string) (pivnet.Release, error) {
+	localReleases, err := c.localReleases(productSlug)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	for _, lr := range localReleases {
+		if lr.metadata.Release.Version == version {
+			return lr.release(), nil
+		}
+	}
+
+	return pivnet.Release{}, fmt.Errorf("release not found")
+}
+
+func (c Client) GetReleaseByID(productSlug string, releaseID int) (pivnet.Release, error) {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	return lr.release(), nil
+}
+
+// EULA returns the EULA with the text found alongside any local release which
+// requires it.
+func (c Client) EULA(eulaSlug string) (pivnet.EULA, error) {
+	productDirs, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return pivnet.EULA{}, err
+	}
+
+	for _, productDir := range productDirs {
+		if !productDir.IsDir() {
+			continue
+		}
+
+		localReleases, err := c.localReleases(productDir.Name())
+		if err != nil {
+			return pivnet.EULA{}, err
+		}
+
+		for _, lr := range localReleases {
+			if lr.metadata.Release.EULASlug != eulaSlug {
+				continue
+			}
+
+			content, err := ioutil.ReadFile(filepath.Join(lr.dir, bundle.EULAFileName))
+			if err != nil {
+				continue
+			}
+
+			return pivnet.EULA{
+				Slug:    eulaSlug,
+				Content: string(content),
+			}, nil
+		}
+	}
+
+	return pivnet.EULA{}, fmt.Errorf("EULA: '%s' not found", eulaSlug)
+}
+
+// AcceptEULA is a no-op as EULAs must have been accepted when the releases
+// were originally downloaded.
+func (c Client) AcceptEULA(productSlug string, releaseID int) error {
+	return nil
+}
+
+func (c Client) ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error) {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var productFiles []pivnet.ProductFile
+	for _, rpf := range lr.metadata.Release.ProductFiles {
+		pf, err := lr.productFile(rpf.ID)
+		if err != nil {
+			return nil, err
+		}
+		productFiles = append(productFiles, pf)
+	}
+
+	return productFiles, nil
+}
+
+func (c Client) ProductFileForRelease(productSlug string, releaseID int, productFileID int) (pivnet.ProductFile, error) {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+
+	return lr.productFile(productFileID)
+}
+
+func (c Client) FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error) {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileGroups []pivnet.FileGroup
+	for _, fg := range lr.metadata.FileGroups {
+		fileGroup := pivnet.FileGroup{
+			ID:   fg.ID,
+			Name: fg.Name,
+		}
+
+		for _, fgpf := range fg.ProductFiles {
+			pf, err := lr.productFile(fgpf.ID)
+			if err != nil {
+				return nil, err
+			}
+			fileGroup.ProductFiles = append(fileGroup.ProductFiles, pf)
+		}
+
+		fileGroups = append(fileGroups, fileGroup)
+	}
+
+	return fileGroups, nil
+}
+
+func (c Client) ReleaseDependencies(productSlug string, releaseID int) ([]pivnet.ReleaseDependency, error) {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var dependencies []pivnet.ReleaseDependency
+	for _, d := range lr.metadata.Dependencies {
+		dependencies = append(dependencies, pivnet.ReleaseDependency{
+			Release: pivnet.DependentRelease{
+				ID:      d.Release.ID,
+				Version: d.Release.Version,
+				Product: pivnet.Product{
+					ID:   d.Release.Product.ID,
+					Slug: d.Release.Product.Slug,
+					Name: d.Release.Product.Name,
+				},
+			},
+		})
+	}
+
+	return dependencies, nil
+}
+
+func (c Client) DependencySpecifiers(productSlug string, releaseID int) ([]pivnet.DependencySpecifier, error) {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var specifiers []pivnet.DependencySpecifier
+	for _, d := range lr.metadata.DependencySpecifiers {
+		specifiers = append(specifiers, pivnet.DependencySpecifier{
+			ID:        d.ID,
+			Specifier: d.Specifier,
+			Product: pivnet.Product{
+				Slug: d.ProductSlug,
+			},
+		})
+	}
+
+	return specifiers, nil
+}
+
+func (c Client) ReleaseUpgradePaths(productSlug string, releaseID int) ([]pivnet.ReleaseUpgradePath, error) {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var upgradePaths []pivnet.ReleaseUpgradePath
+	for _, u := range lr.metadata.UpgradePaths {
+		upgradePaths = append(upgradePaths, pivnet.ReleaseUpgradePath{
+			Release: pivnet.UpgradePathRelease{
+				ID:      u.ID,
+				Version: u.Version,
+			},
+		})
+	}
+
+	return upgradePaths, nil
+}
+
+func (c Client) UpgradePathSpecifiers(productSlug string, releaseID int) ([]pivnet.UpgradePathSpecifier, error) {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var specifiers []pivnet.UpgradePathSpecifier
+	for _, u := range lr.metadata.UpgradePathSpecifiers {
+		specifiers = append(specifiers, pivnet.UpgradePathSpecifier{
+			ID:        u.ID,
+			Specifier: u.Specifier,
+		})
+	}
+
+	return specifiers, nil
+}
+
+// DownloadProductFile copies the product file from the local directory.
+func (c Client) DownloadProductFile(writer *os.File, productSlug string, releaseID int, productFileID int, progressWriter io.Writer) error {
+	lr, err := c.localReleaseByID(productSlug, releaseID)
+	if err != nil {
+		return err
+	}
+
+	pf, err := lr.productFile(productFileID)
+	if err != nil {
+		return err
+	}
+
+	localFile := filenames.Base(pf)
+	for _, mpf := range lr.metadata.ProductFiles {
+		if mpf.ID == productFileID && mpf.LocalFile != "" {
+			localFile = mpf.LocalFile
+		}
+	}
+
+	sourcePath := filepath.Join(lr.dir, localFile)
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		// Releases imported from a bundle keep their files in a subdirectory.
+		sourcePath = filepath.Join(lr.dir, bundle.FilesDir, localFile)
+	}
+	c.logger.Debug(fmt.Sprintf("Copying local file: '%s'", sourcePath))
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	_, err = io.Copy(writer, source)
+	return err
+}
+
+func (c Client) localReleases(productSlug string) ([]localRelease, error) {
+	productDir := filepath.Join(c.dir, productSlug)
+
+	versionDirs, err := ioutil.ReadDir(productDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var localReleases []localRelease
+	for _, versionDir := range versionDirs {
+		if !versionDir.IsDir() {
+			continue
+		}
+
+		lr, err := readLocalRelease(filepath.Join(productDir, versionDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		localReleases = append(localReleases, lr)
+	}
+
+	return localReleases, nil
+}
+
+func (c Client) localReleaseByID(productSlug string, releaseID int) (localRelease, error) {
+	localReleases, err := c.localReleases(productSlug)
+	if err != nil {
+		return localRelease{}, err
+	}
+
+	for _, lr := range localReleases {
+		if lr.metadata.Release.ID == releaseID {
+			return lr, nil
+		}
+	}
+
+	return localRelease{}, fmt.Errorf("release with ID: %d not found", releaseID)
+}
+
+func readLocalRelease(dir string) (localRelease, error) {
+	metadataFilepath := filepath.Join(dir, metadataFileName)
+
+	b, err := ioutil.ReadFile(metadataFilepath)
+	if err != nil {
+		return localRelease{}, err
+	}
+
+	var mdata metadata.Metadata
+	err = yaml.Unmarshal(b, &mdata)
+	if err != nil {
+		return localRelease{}, fmt.Errorf("failed to parse '%s': %s", metadataFilepath, err)
+	}
+
+	if mdata.Release == nil {
+		return localRelease{}, fmt.Errorf("missing release in '%s'", metadataFilepath)
+	}
+
+	var fingerprint string
+	versionBytes, err := ioutil.ReadFile(filepath.Join(dir, versionFileName))
+	if err == nil {
+		_, fingerprint, err = versions.SplitIntoVersionAndFingerprint(strings.TrimSpace(string(versionBytes)))
+		if err != nil {
+			fingerprint = ""
+		}
+	}
+
+	return localRelease{
+		dir:         dir,
+		fingerprint: fingerprint,
+		metadata:    mdata,
+	}, nil
+}
+
+func (lr localRelease) release() pivnet.Release {
+	r := lr.metadata.Release
+
+	release := pivnet.Release{
+		ID:                     r.ID,
+		Version:                r.Version,
+		ReleaseType:            pivnet.ReleaseType(r.ReleaseType),
+		ReleaseDate:            r.ReleaseDate,
+		Description:            metadata.EncodeCustomMetadata(r.Description, r.CustomMetadata),
+		ReleaseNotesURL:        r.ReleaseNotesURL,
+		Availability:           r.Availability,
+		Controlled:             r.Controlled,
+		ECCN:                   r.ECCN,
+		LicenseException:       r.LicenseException,
+		EndOfSupportDate:       r.EndOfSupportDate,
+		EndOfGuidanceDate:      r.EndOfGuidanceDate,
+		EndOfAvailabilityDate:  r.EndOfAvailabilityDate,
+		SoftwareFilesUpdatedAt: lr.fingerprint,
+	}
+
+	if r.EULASlug != "" {
+		release.EULA = &pivnet.EULA{
+			Slug: r.EULASlug,
+		}
+	}
+
+	return release
+}
+
+func (lr localRelease) productFile(productFileID int) (pivnet.ProductFile, error) {
+	for _, pf := range lr.metadata.ProductFiles {
+		if pf.ID == productFileID {
+			return pivnet.ProductFile{
+				ID:                 pf.ID,
+				Name:               pf.File,
+				Description:        pf.Description,
+				AWSObjectKey:       pf.AWSObjectKey,
+				FileType:           pf.FileType,
+				FileVersion:        pf.FileVersion,
+				SHA256:             pf.SHA256,
+				MD5:                pf.MD5,
+				DocsURL:            pf.DocsURL,
+				SystemRequirements: pf.SystemRequirements,
+				Platforms:          pf.Platforms,
+				IncludedFiles:      pf.IncludedFiles,
+			}, nil
+		}
+	}
+
+	return pivnet.ProductFile{}, fmt.Errorf(
+		"product file with ID: %d not found in '%s'",
+		productFileID,
+		filepath.Join(lr.dir, metadataFileName),
+	)
+}
@@ -0,0 +1,198 @@
+package local_test
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/local"
+)
+
+const releaseMetadata = `---
+release:
+  id: 1234
+  version: 1.2.3
+  release_type: Minor Release
+  eula_slug: some-eula
+  release_date: "2017-10-17"
+  product_files:
+  - id: 10
+product_files:
+- id: 10
+  file: Some File
+  aws_object_key: product/some-file.zip
+  file_type: Software
+  sha256: some-sha256
+- id: 20
+  file: Grouped File
+  aws_object_key: product/grouped-file.zip
+  local_file: grouped-file-20.zip
+file_groups:
+- id: 30
+  name: some file group
+  product_files:
+  - id: 20
+dependency_specifiers:
+- id: 40
+  specifier: 1.2.*
+  product_slug: other-product
+`
+
+var _ = Describe("Client", func() {
+	var (
+		dir    string
+		client *local.Client
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "local")
+		Expect(err).NotTo(HaveOccurred())
+
+		releaseDir := filepath.Join(dir, "some-product", "1.2.3")
+		err = os.MkdirAll(releaseDir, os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(releaseDir, "metadata.yaml"), []byte(releaseMetadata), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(releaseDir, "version"), []byte("1.2.3#some-fingerprint"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(releaseDir, "some-file.zip"), []byte("some contents"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(releaseDir, "grouped-file-20.zip"), []byte("grouped contents"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		client = local.NewClient(dir, logshim.NewLogShim(logger, logger, true))
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(dir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("ReleasesForProductSlug", func() {
+		It("returns the releases in the product directory", func() {
+			releases, err := client.ReleasesForProductSlug("some-product")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(releases).To(HaveLen(1))
+			Expect(releases[0].ID).To(Equal(1234))
+			Expect(releases[0].Version).To(Equal("1.2.3"))
+			Expect(releases[0].ReleaseType).To(Equal(pivnet.ReleaseType("Minor Release")))
+			Expect(releases[0].SoftwareFilesUpdatedAt).To(Equal("some-fingerprint"))
+			Expect(releases[0].EULA.Slug).To(Equal("some-eula"))
+		})
+
+		Context("when the product directory does not exist", func() {
+			It("returns an error", func() {
+				_, err := client.ReleasesForProductSlug("some-other-product")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("ReleaseTypes", func() {
+		It("returns the release types of all local releases", func() {
+			releaseTypes, err := client.ReleaseTypes()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(releaseTypes).To(Equal([]pivnet.ReleaseType{"Minor Release"}))
+		})
+	})
+
+	Describe("GetRelease", func() {
+		It("returns the release with the version", func() {
+			release, err := client.GetRelease("some-product", "1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(release.ID).To(Equal(1234))
+		})
+
+		Context("when the version does not exist", func() {
+			It("returns an error", func() {
+				_, err := client.GetRelease("some-product", "9.9.9")
+				Expect(err).To(MatchError("release not found"))
+			})
+		})
+	})
+
+	Describe("ProductFilesForRelease and FileGroupsForRelease", func() {
+		It("returns the release product files and file groups", func() {
+			productFiles, err := client.ProductFilesForRelease("some-product", 1234)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(productFiles).To(HaveLen(1))
+			Expect(productFiles[0].Name).To(Equal("Some File"))
+			Expect(productFiles[0].SHA256).To(Equal("some-sha256"))
+
+			fileGroups, err := client.FileGroupsForRelease("some-product", 1234)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fileGroups).To(HaveLen(1))
+			Expect(fileGroups[0].ProductFiles).To(HaveLen(1))
+			Expect(fileGroups[0].ProductFiles[0].ID).To(Equal(20))
+		})
+	})
+
+	Describe("DependencySpecifiers", func() {
+		It("returns the dependency specifiers", func() {
+			specifiers, err := client.DependencySpecifiers("some-product", 1234)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(specifiers).To(HaveLen(1))
+			Expect(specifiers[0].Specifier).To(Equal("1.2.*"))
+			Expect(specifiers[0].Product.Slug).To(Equal("other-product"))
+		})
+	})
+
+	Describe("DownloadProductFile", func() {
+		var (
+			file *os.File
+		)
+
+		BeforeEach(func() {
+			var err error
+			file, err = ioutil.TempFile("", "local-download")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			file.Close()
+			os.Remove(file.Name())
+		})
+
+		It("copies the product file", func() {
+			err := client.DownloadProductFile(file, "some-product", 1234, 10, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			b, err := ioutil.ReadFile(file.Name())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal("some contents"))
+		})
+
+		It("uses the local file name when recorded", func() {
+			err := client.DownloadProductFile(file, "some-product", 1234, 20, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			b, err := ioutil.ReadFile(file.Name())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal("grouped contents"))
+		})
+
+		Context("when the release does not exist", func() {
+			It("returns an error", func() {
+				err := client.DownloadProductFile(file, "some-product", 9999, 10, GinkgoWriter)
+				Expect(err).To(MatchError("release with ID: 9999 not found"))
+			})
+		})
+	})
+})
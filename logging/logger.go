@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelPrefixes = map[Level]string{
+	LevelWarn:  "WARN: ",
+	LevelError: "ERROR: ",
+}
+
+// Logger is a leveled logger which satisfies logger.Logger. Each message is
+// written in a single write, so it can be shared by concurrent goroutines
+// and, through a SyncWriter, with other output.
+//
+// Warnings and errors are always logged, and debug messages only if verbose.
+type Logger struct {
+	logger *log.Logger
+	level  Level
+}
+
+// NewLogger returns a Logger which writes messages of at least info level
+// with l, or also debug messages if verbose is set.
+func NewLogger(l *log.Logger, verbose bool) *Logger {
+	level := LevelInfo
+	if verbose {
+		level = LevelDebug
+	}
+
+	return &Logger{
+		logger: l,
+		level:  level,
+	}
+}
+
+func (l *Logger) Debug(action string, data ...logger.Data) {
+	l.log(LevelDebug, action, data)
+}
+
+func (l *Logger) Info(action string, data ...logger.Data) {
+	l.log(LevelInfo, action, data)
+}
+
+func (l *Logger) Warn(action string, data ...logger.Data) {
+	l.log(LevelWarn, action, data)
+}
+
+func (l *Logger) Error(action string, data ...logger.Data) {
+	l.log(LevelError, action, data)
+}
+
+func (l *Logger) log(level Level, action string, data []logger.Data) {
+	if level < l.level {
+		return
+	}
+
+	message := levelPrefixes[level] + action
+	for _, d := range data {
+		message += " " + formatData(d)
+	}
+
+	// log.Logger writes the message in one write, while holding its lock.
+	l.logger.Output(3, message)
+}
+
+// formatData formats the data sorted by key, so that messages with the same
+// data are logged identically.
+func formatData(data logger.Data) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, data[k])
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+type warner interface {
+	Warn(action string, data ...logger.Data)
+}
+
+// Warn logs a warning with the logger if it is leveled, or as information
+// otherwise, e.g. with the logshim loggers of tests.
+func Warn(l logger.Logger, action string, data ...logger.Data) {
+	if w, ok := l.(warner); ok {
+		w.Warn(action, data...)
+		return
+	}
+
+	l.Info(levelPrefixes[LevelWarn]+action, data...)
+}
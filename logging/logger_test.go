@@ -0,0 +1,82 @@
+package logging_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/logging"
+)
+
+var _ = Describe("Logger", func() {
+	var (
+		output  *bytes.Buffer
+		verbose bool
+
+		l *logging.Logger
+	)
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		verbose = false
+	})
+
+	JustBeforeEach(func() {
+		l = logging.NewLogger(log.New(output, "", log.LstdFlags), verbose)
+	})
+
+	It("logs info, warnings and errors with their level", func() {
+		l.Info("some info")
+		l.Warn("some warning")
+		l.Error("some error")
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[0]).To(HaveSuffix(" some info"))
+		Expect(lines[1]).To(HaveSuffix(" WARN: some warning"))
+		Expect(lines[2]).To(HaveSuffix(" ERROR: some error"))
+	})
+
+	It("does not log debug messages", func() {
+		l.Debug("some debug")
+
+		Expect(output.String()).To(BeEmpty())
+	})
+
+	It("logs data sorted by key", func() {
+		l.Info("some info", logger.Data{"b": 2, "a": "one"})
+
+		Expect(output.String()).To(HaveSuffix(" some info a=one b=2\n"))
+	})
+
+	Context("when verbose", func() {
+		BeforeEach(func() {
+			verbose = true
+		})
+
+		It("logs debug messages", func() {
+			l.Debug("some debug")
+
+			Expect(output.String()).To(HaveSuffix(" some debug\n"))
+		})
+	})
+
+	Describe("Warn", func() {
+		It("logs a warning with a leveled logger", func() {
+			logging.Warn(l, "some warning")
+
+			Expect(output.String()).To(HaveSuffix(" WARN: some warning\n"))
+		})
+
+		It("logs the warning as information with other loggers", func() {
+			shimLogger := log.New(output, "", 0)
+			logging.Warn(logshim.NewLogShim(shimLogger, shimLogger, false), "some warning")
+
+			Expect(output.String()).To(ContainSubstring("WARN: some warning"))
+		})
+	})
+})
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// SyncWriter serializes the writes of concurrent writers, e.g. the logger and
+// the progress output of S3 transfers, so that their lines are not torn.
+type SyncWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func NewSyncWriter(w io.Writer) *SyncWriter {
+	return &SyncWriter{
+		mu: &sync.Mutex{},
+		w:  w,
+	}
+}
+
+// Write writes p in one piece. Callers must write whole lines, as
+// log.Logger does, or use a LineWriter.
+func (s *SyncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.w.Write(p)
+}
+
+// LineWriter returns a writer for a single source of output which may write
+// partial lines. Its output is buffered until the end of each line, which is
+// a newline or a carriage return as written by progress bars, and then
+// written in one piece.
+func (s *SyncWriter) LineWriter() *LineWriter {
+	return &LineWriter{
+		mu:     &sync.Mutex{},
+		writer: s,
+	}
+}
+
+type LineWriter struct {
+	mu     *sync.Mutex
+	writer *SyncWriter
+	buf    bytes.Buffer
+}
+
+func (l *LineWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf.Write(p)
+
+	end := bytes.LastIndexAny(l.buf.Bytes(), "\n\r")
+	if end == -1 {
+		return len(p), nil
+	}
+
+	lines := make([]byte, end+1)
+	copy(lines, l.buf.Next(end+1))
+
+	_, err := l.writer.Write(lines)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush writes any partial line which has been buffered.
+func (l *LineWriter) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := l.writer.Write(l.buf.Next(l.buf.Len()))
+	return err
+}
@@ -0,0 +1,90 @@
+package logging_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/logging"
+)
+
+// chunkedWriter records each write separately, so that torn lines can be
+// detected.
+type chunkedWriter struct {
+	writes []string
+}
+
+func (c *chunkedWriter) Write(p []byte) (int, error) {
+	c.writes = append(c.writes, string(p))
+	return len(p), nil
+}
+
+var _ = Describe("SyncWriter", func() {
+	It("does not interleave lines written concurrently in pieces", func() {
+		output := &bytes.Buffer{}
+		syncWriter := logging.NewSyncWriter(output)
+		l := logging.NewLogger(log.New(syncWriter, "", log.LstdFlags), false)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+
+			go func(i int) {
+				defer wg.Done()
+
+				lineWriter := syncWriter.LineWriter()
+				for j := 0; j < 50; j++ {
+					fmt.Fprintf(lineWriter, "progress %d-", i)
+					fmt.Fprintf(lineWriter, "%d\n", j)
+				}
+			}(i)
+
+			go func(i int) {
+				defer wg.Done()
+
+				for j := 0; j < 50; j++ {
+					l.Info(fmt.Sprintf("message %d-%d", i, j))
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		Expect(lines).To(HaveLen(1000))
+
+		for _, line := range lines {
+			Expect(line).To(MatchRegexp(`^(progress \d+-\d+|\S+ \S+ message \d+-\d+)$`))
+		}
+	})
+
+	Describe("LineWriter", func() {
+		var (
+			output     *chunkedWriter
+			lineWriter *logging.LineWriter
+		)
+
+		BeforeEach(func() {
+			output = &chunkedWriter{}
+			lineWriter = logging.NewSyncWriter(output).LineWriter()
+		})
+
+		It("writes whole lines, ending in a newline or carriage return", func() {
+			fmt.Fprint(lineWriter, "some ")
+			Expect(output.writes).To(BeEmpty())
+
+			fmt.Fprint(lineWriter, "line\nsome progress 10%\rsome partial")
+			Expect(output.writes).To(Equal([]string{"some line\nsome progress 10%\r"}))
+		})
+
+		It("writes the partial line when flushed", func() {
+			fmt.Fprint(lineWriter, "some partial")
+
+			Expect(lineWriter.Flush()).To(Succeed())
+			Expect(output.writes).To(Equal([]string{"some partial"}))
+		})
+	})
+})
@@ -0,0 +1,97 @@
+package metadata
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Pivotal Network has no custom fields for releases, so custom metadata is
+// stored as a delimited section at the end of the release description.
+const (
+	customMetadataStart = "[pivnet-resource custom_metadata]"
+	customMetadataEnd   = "[/pivnet-resource custom_metadata]"
+)
+
+var customMetadataKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// EncodeCustomMetadata returns the description with the custom metadata
+// appended to it, one "key: value" line per entry ordered by key.
+func EncodeCustomMetadata(description string, custom map[string]string) string {
+	if len(custom) == 0 {
+		return description
+	}
+
+	keys := make([]string, 0, len(custom))
+	for k := range custom {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := []string{customMetadataStart}
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %s", k, custom[k]))
+	}
+	lines = append(lines, customMetadataEnd)
+
+	section := strings.Join(lines, "\n")
+	if description == "" {
+		return section
+	}
+
+	return strings.TrimRight(description, "\n") + "\n\n" + section
+}
+
+// DecodeCustomMetadata splits a description written by EncodeCustomMetadata
+// into the original description and the custom metadata. A description
+// without a custom metadata section is returned unchanged with nil metadata.
+func DecodeCustomMetadata(description string) (string, map[string]string) {
+	start := strings.LastIndex(description, customMetadataStart)
+	if start < 0 {
+		return description, nil
+	}
+
+	end := strings.Index(description[start:], customMetadataEnd)
+	if end < 0 {
+		return description, nil
+	}
+
+	section := description[start+len(customMetadataStart) : start+end]
+
+	custom := map[string]string{}
+	for _, line := range strings.Split(section, "\n") {
+		kv := strings.SplitN(line, ": ", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		custom[kv[0]] = kv[1]
+	}
+
+	remaining := description[:start] + description[start+end+len(customMetadataEnd):]
+
+	return strings.TrimRight(remaining, "\n"), custom
+}
+
+func validateCustomMetadata(custom map[string]string) error {
+	keys := make([]string, 0, len(custom))
+	for k := range custom {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !customMetadataKeyRegexp.MatchString(k) {
+			return fmt.Errorf(
+				"custom_metadata key '%s' must only contain letters, digits, '_', '.' and '-'",
+				k,
+			)
+		}
+
+		if strings.ContainsAny(custom[k], "\r\n") {
+			return fmt.Errorf("custom_metadata value for '%s' must not contain newlines", k)
+		}
+	}
+
+	return nil
+}
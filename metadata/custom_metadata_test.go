@@ -0,0 +1,65 @@
+package metadata_test
+
+import (
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CustomMetadata", func() {
+	var (
+		custom map[string]string
+	)
+
+	BeforeEach(func() {
+		custom = map[string]string{
+			"build_id": "1234",
+			"commit":   "abc: def",
+		}
+	})
+
+	Describe("EncodeCustomMetadata", func() {
+		It("appends the custom metadata to the description ordered by key", func() {
+			encoded := metadata.EncodeCustomMetadata("some description\n", custom)
+
+			Expect(encoded).To(Equal(`some description
+
+[pivnet-resource custom_metadata]
+build_id: 1234
+commit: abc: def
+[/pivnet-resource custom_metadata]`))
+		})
+
+		It("returns the description unchanged when there is no custom metadata", func() {
+			Expect(metadata.EncodeCustomMetadata("some description", nil)).To(Equal("some description"))
+		})
+	})
+
+	Describe("DecodeCustomMetadata", func() {
+		It("returns the original description and custom metadata", func() {
+			description, decoded := metadata.DecodeCustomMetadata(
+				metadata.EncodeCustomMetadata("some description", custom),
+			)
+
+			Expect(description).To(Equal("some description"))
+			Expect(decoded).To(Equal(custom))
+		})
+
+		It("decodes custom metadata without a description", func() {
+			description, decoded := metadata.DecodeCustomMetadata(
+				metadata.EncodeCustomMetadata("", custom),
+			)
+
+			Expect(description).To(BeEmpty())
+			Expect(decoded).To(Equal(custom))
+		})
+
+		It("returns descriptions without custom metadata unchanged", func() {
+			description, decoded := metadata.DecodeCustomMetadata("some description")
+
+			Expect(description).To(Equal("some description"))
+			Expect(decoded).To(BeNil())
+		})
+	})
+})
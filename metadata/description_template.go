@@ -0,0 +1,59 @@
+package metadata
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+// DescriptionData is the data with which a description template is
+// rendered.
+type DescriptionData struct {
+	Version      string
+	ReleaseType  string
+	ReleaseDate  string
+	Description  string
+	Dependencies []DependencySpecifier
+
+	// Files are the contents of the named input files, e.g. changelogs.
+	Files map[string]string
+}
+
+// RenderDescription renders the Go text/template tmpl with the release of the
+// metadata and the contents of the named files, so that a description can be
+// assembled from several inputs. Referencing a file which was not provided is
+// an error rather than rendering as empty. The template may use the trim
+// function, which strips surrounding whitespace, e.g. {{ trim .Files.changelog }}.
+func RenderDescription(tmpl string, m Metadata, files map[string]string) (string, error) {
+	t, err := template.New("description").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"trim": strings.TrimSpace}).
+		Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	data := DescriptionData{
+		Dependencies: m.DependencySpecifiers,
+		Files:        files,
+	}
+
+	if m.Release != nil {
+		data.Version = m.Release.Version
+		data.ReleaseType = m.Release.ReleaseType
+		data.ReleaseDate = m.Release.ReleaseDate
+		data.Description = m.Release.Description
+	}
+
+	if data.Files == nil {
+		data.Files = map[string]string{}
+	}
+
+	var b bytes.Buffer
+	err = t.Execute(&b, data)
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,68 @@
+package metadata_test
+
+import (
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RenderDescription", func() {
+	var (
+		m     metadata.Metadata
+		files map[string]string
+	)
+
+	BeforeEach(func() {
+		m = metadata.Metadata{
+			Release: &metadata.Release{
+				Version:     "1.2.3",
+				ReleaseType: "Minor Release",
+				ReleaseDate: "2017-10-17",
+				Description: "Some release",
+			},
+			DependencySpecifiers: []metadata.DependencySpecifier{
+				{ProductSlug: "some-product", Specifier: "1.2.*"},
+				{ProductSlug: "other-product", Specifier: "2.*"},
+			},
+		}
+
+		files = map[string]string{
+			"core":    "- fixed a bug\n",
+			"plugins": "\n- added a plugin\n\n",
+		}
+	})
+
+	It("renders the release and the contents of the files", func() {
+		tmpl := `{{ .Description }} {{ .Version }} ({{ .ReleaseType }}, {{ .ReleaseDate }})
+Requires:{{ range .Dependencies }} {{ .ProductSlug }} {{ .Specifier }};{{ end }}
+Core:
+{{ .Files.core }}Plugins:
+{{ trim .Files.plugins }}`
+
+		description, err := metadata.RenderDescription(tmpl, m, files)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(description).To(Equal(`Some release 1.2.3 (Minor Release, 2017-10-17)
+Requires: some-product 1.2.*; other-product 2.*;
+Core:
+- fixed a bug
+Plugins:
+- added a plugin`))
+	})
+
+	Context("when the template references a file which was not provided", func() {
+		It("returns an error", func() {
+			_, err := metadata.RenderDescription("{{ .Files.missing }}", m, files)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("missing"))
+		})
+	})
+
+	Context("when the template is invalid", func() {
+		It("returns an error", func() {
+			_, err := metadata.RenderDescription("{{ .Version", m, files)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
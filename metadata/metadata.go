@@ -1,6 +1,9 @@
 package metadata
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Metadata struct {
 	Release               *Release               `yaml:"release,omitempty"`
@@ -9,6 +12,15 @@ type Metadata struct {
 	UpgradePathSpecifiers []UpgradePathSpecifier `yaml:"upgrade_path_specifiers,omitempty"`
 	FileGroups            []FileGroup            `yaml:"file_groups,omitempty"`
 
+	// Stemcell is the stemcell downloaded alongside the release, if the
+	// stemcell params of get are set.
+	Stemcell *Stemcell `yaml:"stemcell,omitempty"`
+
+	// UnsupportedFeatures are the features of the release, e.g. file_groups,
+	// which were not available to the token on get, so are missing rather
+	// than empty.
+	UnsupportedFeatures []string `yaml:"unsupported_features,omitempty"`
+
 	// Deprecated
 	Dependencies []Dependency  `yaml:"dependencies,omitempty"`
 	UpgradePaths []UpgradePath `yaml:"upgrade_paths,omitempty"`
@@ -24,6 +36,10 @@ type Release struct {
 	ReleaseNotesURL       string               `yaml:"release_notes_url"`
 	Availability          string               `yaml:"availability"`
 	UserGroupIDs          []string             `yaml:"user_group_ids,omitempty"`
+	UserGroups            []string             `yaml:"user_groups,omitempty"`
+	Series                string               `yaml:"series,omitempty"`
+	RequiresLicenseKey    bool                 `yaml:"requires_license_key,omitempty"`
+	LicenseTermsURL       string               `yaml:"license_terms_url,omitempty"`
 	Controlled            bool                 `yaml:"controlled"`
 	ECCN                  string               `yaml:"eccn"`
 	LicenseException      string               `yaml:"license_exception"`
@@ -31,6 +47,14 @@ type Release struct {
 	EndOfGuidanceDate     string               `yaml:"end_of_guidance_date"`
 	EndOfAvailabilityDate string               `yaml:"end_of_availability_date"`
 	ProductFiles          []ReleaseProductFile `yaml:"product_files,omitempty"`
+	CustomMetadata        map[string]string    `yaml:"custom_metadata,omitempty"`
+}
+
+type Stemcell struct {
+	ProductSlug  string        `yaml:"product_slug"`
+	ReleaseID    int           `yaml:"release_id"`
+	Version      string        `yaml:"version"`
+	ProductFiles []ProductFile `yaml:"product_files,omitempty"`
 }
 
 type ReleaseProductFile struct {
@@ -52,12 +76,32 @@ type ProductFile struct {
 	SystemRequirements []string `yaml:"system_requirements,omitempty"`
 	Platforms          []string `yaml:"platforms,omitempty"`
 	IncludedFiles      []string `yaml:"included_files,omitempty"`
+	LocalFile          string   `yaml:"local_file,omitempty"`
+	FileGroup          string   `yaml:"file_group,omitempty"`
+	SBOM               string   `yaml:"sbom,omitempty"`
+
+	BOSHRelease   *BOSHRelease      `yaml:"bosh_release,omitempty"`
+	Checksums     map[string]string `yaml:"checksums,omitempty"`
+	DownloadError string            `yaml:"download_error,omitempty"`
+
+	PlatformArchitecture  string   `yaml:"platform_architecture,omitempty"`
+	IncludedOSSComponents []string `yaml:"included_oss_components,omitempty"`
+	ECCN                  string   `yaml:"eccn,omitempty"`
+	LicenseException      string   `yaml:"license_exception,omitempty"`
+}
+
+type BOSHRelease struct {
+	Name               string `yaml:"name,omitempty"`
+	Version            string `yaml:"version,omitempty"`
+	CommitHash         string `yaml:"commit_hash,omitempty"`
+	UncommittedChanges bool   `yaml:"uncommitted_changes,omitempty"`
 }
 
 type FileGroup struct {
 	ID           int                    `yaml:"id,omitempty"`
 	Name         string                 `yaml:"name,omitempty"`
 	ProductFiles []FileGroupProductFile `yaml:"product_files,omitempty"`
+	S3Target     string                 `yaml:"s3_target,omitempty"`
 }
 
 type FileGroupProductFile struct {
@@ -119,6 +163,44 @@ func (m Metadata) Validate() ([]string, error) {
 		return nil, fmt.Errorf("missing required value %q", "eula_slug")
 	}
 
+	err := validateCustomMetadata(m.Release.CustomMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// The series is set once the release has been created, so it is
+	// validated beforehand rather than leaving a release without its series.
+	series := m.Release.Series
+	if series != "" && !isTrimmed(series) {
+		return nil, fmt.Errorf("series '%s' must not be blank or have leading or trailing whitespace", series)
+	}
+
+	licenseTermsURL := m.Release.LicenseTermsURL
+	if licenseTermsURL != "" &&
+		!strings.HasPrefix(licenseTermsURL, "http://") &&
+		!strings.HasPrefix(licenseTermsURL, "https://") {
+		return nil, fmt.Errorf("license_terms_url '%s' must be an http or https URL", licenseTermsURL)
+	}
+
+	for _, productFile := range m.ProductFiles {
+		if productFile.SBOM != "" && productFile.SBOM == productFile.File {
+			return nil, fmt.Errorf("sbom of product file '%s' must be a different file", productFile.File)
+		}
+
+		if productFile.FileGroup != "" && !m.hasFileGroup(productFile.FileGroup) {
+			return nil, fmt.Errorf(
+				"file_group '%s' of product file '%s' is not one of the file_groups",
+				productFile.FileGroup,
+				productFile.File,
+			)
+		}
+
+		err := productFile.validateAttributes()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for i, d := range m.DependencySpecifiers {
 		if d.ProductSlug == "" {
 			return nil, fmt.Errorf(
@@ -158,3 +240,86 @@ func (m Metadata) Validate() ([]string, error) {
 	var deprecations []string
 	return deprecations, nil
 }
+
+// S3TargetForFile returns the S3 target of the file group which the product
+// file belongs to, or an empty string if the file is uploaded to Pivotal
+// Network.
+func (m Metadata) S3TargetForFile(file string) string {
+	for _, productFile := range m.ProductFiles {
+		if productFile.File != file || productFile.FileGroup == "" {
+			continue
+		}
+
+		for _, fileGroup := range m.FileGroups {
+			if fileGroup.Name == productFile.FileGroup {
+				return fileGroup.S3Target
+			}
+		}
+	}
+
+	return ""
+}
+
+// ProductFileForSBOM returns the product file whose SBOM is the file, or
+// false if the file is not the SBOM of any product file.
+func (m Metadata) ProductFileForSBOM(file string) (ProductFile, bool) {
+	for _, productFile := range m.ProductFiles {
+		if productFile.SBOM != "" && productFile.SBOM == file {
+			return productFile, true
+		}
+	}
+
+	return ProductFile{}, false
+}
+
+func (m Metadata) hasFileGroup(name string) bool {
+	for _, fileGroup := range m.FileGroups {
+		if fileGroup.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateAttributes validates the attributes which are set once the product
+// file has been created, rather than leaving a product file without them.
+func (f ProductFile) validateAttributes() error {
+	attributes := []struct {
+		name  string
+		value string
+	}{
+		{"platform_architecture", f.PlatformArchitecture},
+		{"eccn", f.ECCN},
+		{"license_exception", f.LicenseException},
+	}
+	for _, a := range attributes {
+		if a.value != "" && !isTrimmed(a.value) {
+			return fmt.Errorf(
+				"%s '%s' of product file '%s' must not be blank or have leading or trailing whitespace",
+				a.name,
+				a.value,
+				f.File,
+			)
+		}
+	}
+
+	for _, component := range f.IncludedOSSComponents {
+		if !isTrimmed(component) {
+			return fmt.Errorf(
+				"included_oss_components '%s' of product file '%s' must not be blank or have leading or trailing whitespace",
+				component,
+				f.File,
+			)
+		}
+	}
+
+	return nil
+}
+
+// isTrimmed returns whether the value has no leading or trailing whitespace
+// and is not only whitespace.
+func isTrimmed(value string) bool {
+	trimmed := strings.TrimSpace(value)
+	return trimmed != "" && trimmed == value
+}
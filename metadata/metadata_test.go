@@ -83,6 +83,138 @@ var _ = Describe("Metadata", func() {
 			})
 		})
 
+		Context("when a product file names a file group", func() {
+			BeforeEach(func() {
+				data.ProductFiles[0].FileGroup = "docs"
+			})
+
+			It("returns an error if the file group is missing", func() {
+				_, err := data.Validate()
+				Expect(err).To(MatchError("file_group 'docs' of product file 'hello.txt' is not one of the file_groups"))
+			})
+
+			It("returns without error if the file group exists", func() {
+				data.FileGroups = []metadata.FileGroup{{Name: "docs"}}
+
+				_, err := data.Validate()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when a product file is its own sbom", func() {
+			BeforeEach(func() {
+				data.ProductFiles[0].SBOM = "hello.txt"
+			})
+
+			It("returns an error", func() {
+				_, err := data.Validate()
+				Expect(err).To(MatchError("sbom of product file 'hello.txt' must be a different file"))
+			})
+		})
+
+		Context("when a series is provided", func() {
+			BeforeEach(func() {
+				data.Release.Series = "2.7"
+			})
+
+			It("returns without error", func() {
+				_, err := data.Validate()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when it has trailing whitespace", func() {
+				BeforeEach(func() {
+					data.Release.Series = "2.7 "
+				})
+
+				It("returns an error", func() {
+					_, err := data.Validate()
+					Expect(err).To(MatchError("series '2.7 ' must not be blank or have leading or trailing whitespace"))
+				})
+			})
+		})
+
+		Context("when extra attributes of a product file are provided", func() {
+			BeforeEach(func() {
+				data.ProductFiles[0].PlatformArchitecture = "x86_64"
+				data.ProductFiles[0].IncludedOSSComponents = []string{"openssl"}
+			})
+
+			It("returns without error", func() {
+				_, err := data.Validate()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when one has leading whitespace", func() {
+				BeforeEach(func() {
+					data.ProductFiles[0].PlatformArchitecture = " x86_64"
+				})
+
+				It("returns an error", func() {
+					_, err := data.Validate()
+					Expect(err).To(MatchError("platform_architecture ' x86_64' of product file 'hello.txt' must not be blank or have leading or trailing whitespace"))
+				})
+			})
+
+			Context("when an included OSS component is blank", func() {
+				BeforeEach(func() {
+					data.ProductFiles[0].IncludedOSSComponents = []string{"openssl", ""}
+				})
+
+				It("returns an error", func() {
+					_, err := data.Validate()
+					Expect(err).To(MatchError("included_oss_components '' of product file 'hello.txt' must not be blank or have leading or trailing whitespace"))
+				})
+			})
+		})
+
+		Context("when a license terms url is provided", func() {
+			BeforeEach(func() {
+				data.Release.LicenseTermsURL = "https://example.com/license-terms.pdf"
+			})
+
+			It("returns without error", func() {
+				_, err := data.Validate()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Context("when it is not an http or https URL", func() {
+				BeforeEach(func() {
+					data.Release.LicenseTermsURL = "license-terms.pdf"
+				})
+
+				It("returns an error", func() {
+					_, err := data.Validate()
+					Expect(err).To(MatchError("license_terms_url 'license-terms.pdf' must be an http or https URL"))
+				})
+			})
+		})
+
+		Context("when custom metadata is provided", func() {
+			BeforeEach(func() {
+				data.Release.CustomMetadata = map[string]string{"build_id": "1234"}
+			})
+
+			It("returns without error", func() {
+				_, err := data.Validate()
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns an error if a key contains invalid characters", func() {
+				data.Release.CustomMetadata["build id"] = "1234"
+
+				_, err := data.Validate()
+				Expect(err).To(MatchError("custom_metadata key 'build id' must only contain letters, digits, '_', '.' and '-'"))
+			})
+
+			It("returns an error if a value contains a newline", func() {
+				data.Release.CustomMetadata["build_id"] = "12\n34"
+
+				_, err := data.Validate()
+				Expect(err).To(MatchError("custom_metadata value for 'build_id' must not contain newlines"))
+			})
+		})
+
 		Context("when dependencies are provided", func() {
 			BeforeEach(func() {
 				data.Dependencies = []metadata.Dependency{
@@ -192,4 +324,63 @@ var _ = Describe("Metadata", func() {
 			})
 		})
 	})
+
+	Describe("S3TargetForFile", func() {
+		var (
+			data metadata.Metadata
+		)
+
+		BeforeEach(func() {
+			data = metadata.Metadata{
+				ProductFiles: []metadata.ProductFile{
+					{File: "docs.pdf", FileGroup: "docs"},
+					{File: "binary.tgz", FileGroup: "binaries"},
+					{File: "other.tgz"},
+				},
+				FileGroups: []metadata.FileGroup{
+					{Name: "docs", S3Target: "docs-bucket"},
+					{Name: "binaries"},
+				},
+			}
+		})
+
+		It("returns the S3 target of the file group of the file", func() {
+			Expect(data.S3TargetForFile("docs.pdf")).To(Equal("docs-bucket"))
+		})
+
+		It("returns an empty string for files without an S3 target", func() {
+			Expect(data.S3TargetForFile("binary.tgz")).To(BeEmpty())
+			Expect(data.S3TargetForFile("other.tgz")).To(BeEmpty())
+			Expect(data.S3TargetForFile("missing.tgz")).To(BeEmpty())
+		})
+	})
+
+	Describe("ProductFileForSBOM", func() {
+		var (
+			data metadata.Metadata
+		)
+
+		BeforeEach(func() {
+			data = metadata.Metadata{
+				ProductFiles: []metadata.ProductFile{
+					{File: "binary.tgz", SBOM: "binary.spdx.json"},
+					{File: "other.tgz"},
+				},
+			}
+		})
+
+		It("returns the product file whose sbom is the file", func() {
+			productFile, ok := data.ProductFileForSBOM("binary.spdx.json")
+			Expect(ok).To(BeTrue())
+			Expect(productFile.File).To(Equal("binary.tgz"))
+		})
+
+		It("returns false for files which are not an sbom", func() {
+			_, ok := data.ProductFileForSBOM("other.tgz")
+			Expect(ok).To(BeFalse())
+
+			_, ok = data.ProductFileForSBOM("")
+			Expect(ok).To(BeFalse())
+		})
+	})
 })
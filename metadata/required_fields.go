@@ -0,0 +1,86 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DocFile is the required field which is present when at least one product
+// file is documentation.
+const DocFile = "doc_file"
+
+// documentationFileType is the Pivotal Network file type of documentation.
+const documentationFileType = "Documentation"
+
+var requiredFieldChecks = map[string]func(m Metadata) bool{
+	"description":              func(m Metadata) bool { return m.Release.Description != "" },
+	"release_notes_url":        func(m Metadata) bool { return m.Release.ReleaseNotesURL != "" },
+	"release_date":             func(m Metadata) bool { return m.Release.ReleaseDate != "" },
+	"eula_slug":                func(m Metadata) bool { return m.Release.EULASlug != "" },
+	"eccn":                     func(m Metadata) bool { return m.Release.ECCN != "" },
+	"license_exception":        func(m Metadata) bool { return m.Release.LicenseException != "" },
+	"end_of_support_date":      func(m Metadata) bool { return m.Release.EndOfSupportDate != "" },
+	"end_of_guidance_date":     func(m Metadata) bool { return m.Release.EndOfGuidanceDate != "" },
+	"end_of_availability_date": func(m Metadata) bool { return m.Release.EndOfAvailabilityDate != "" },
+	"user_groups": func(m Metadata) bool {
+		return len(m.Release.UserGroupIDs) > 0 || len(m.Release.UserGroups) > 0
+	},
+	"dependency_specifiers":   func(m Metadata) bool { return len(m.DependencySpecifiers) > 0 },
+	"upgrade_path_specifiers": func(m Metadata) bool { return len(m.UpgradePathSpecifiers) > 0 },
+	DocFile: func(m Metadata) bool {
+		for _, f := range m.ProductFiles {
+			if f.FileType == documentationFileType {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// RequiredFields are the fields which can be required via required_fields.
+var RequiredFields = []string{
+	"description",
+	"release_notes_url",
+	"release_date",
+	"eula_slug",
+	"eccn",
+	"license_exception",
+	"end_of_support_date",
+	"end_of_guidance_date",
+	"end_of_availability_date",
+	"user_groups",
+	"dependency_specifiers",
+	"upgrade_path_specifiers",
+	DocFile,
+}
+
+// ValidateRequiredFields returns an error if any of the fields cannot be
+// required.
+func ValidateRequiredFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := requiredFieldChecks[field]; !ok {
+			return fmt.Errorf(
+				"required field '%s' must be one of: ['%s']",
+				field,
+				strings.Join(RequiredFields, "', '"),
+			)
+		}
+	}
+
+	return nil
+}
+
+// MissingFields returns the required fields which are not provided, in the
+// order they are required, so that every missing item of a release checklist
+// is reported at once. The fields must already have been validated.
+func (m Metadata) MissingFields(required []string) []string {
+	var missing []string
+	for _, field := range required {
+		check, ok := requiredFieldChecks[field]
+		if !ok || m.Release == nil || !check(m) {
+			missing = append(missing, field)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,75 @@
+package metadata_test
+
+import (
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Required fields", func() {
+	Describe("ValidateRequiredFields", func() {
+		It("accepts the fields which can be required", func() {
+			Expect(metadata.ValidateRequiredFields(metadata.RequiredFields)).To(Succeed())
+		})
+
+		It("rejects other fields", func() {
+			err := metadata.ValidateRequiredFields([]string{"release_notes_url", "release_notes"})
+			Expect(err).To(MatchError(ContainSubstring("required field 'release_notes' must be one of: ['description', ")))
+		})
+	})
+
+	Describe("MissingFields", func() {
+		var (
+			data metadata.Metadata
+		)
+
+		BeforeEach(func() {
+			data = metadata.Metadata{
+				Release: &metadata.Release{
+					Version:          "1.0.0",
+					EULASlug:         "some-eula",
+					ReleaseNotesURL:  "https://example.com/release-notes",
+					EndOfSupportDate: "2030-01-01",
+					UserGroups:       []string{"some-user-group"},
+				},
+				ProductFiles: []metadata.ProductFile{
+					{File: "some-file", FileType: "Software"},
+				},
+			}
+		})
+
+		It("returns nothing when every field is provided", func() {
+			missing := data.MissingFields([]string{"eula_slug", "release_notes_url", "end_of_support_date", "user_groups"})
+			Expect(missing).To(BeEmpty())
+		})
+
+		It("returns every missing field in the order they are required", func() {
+			missing := data.MissingFields([]string{"eccn", "eula_slug", "upgrade_path_specifiers", "doc_file"})
+			Expect(missing).To(Equal([]string{"eccn", "upgrade_path_specifiers", "doc_file"}))
+		})
+
+		Context("when a product file is documentation", func() {
+			BeforeEach(func() {
+				data.ProductFiles = append(data.ProductFiles, metadata.ProductFile{
+					File:     "some-docs.pdf",
+					FileType: "Documentation",
+				})
+			})
+
+			It("provides the doc file", func() {
+				Expect(data.MissingFields([]string{"doc_file"})).To(BeEmpty())
+			})
+		})
+
+		Context("when release is missing", func() {
+			BeforeEach(func() {
+				data.Release = nil
+			})
+
+			It("returns every field", func() {
+				Expect(data.MissingFields([]string{"eula_slug", "doc_file"})).To(Equal([]string{"eula_slug", "doc_file"}))
+			})
+		})
+	})
+})
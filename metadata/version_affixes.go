@@ -0,0 +1,22 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AffixVersion returns the version with the provided prefix and suffix, e.g.
+// '1.2.3-LTS' for '1.2.3' with the suffix '-LTS'. A version which already
+// has the prefix or suffix, e.g. from a version file templated with it,
+// returns an error rather than being published without it being applied.
+func AffixVersion(version string, prefix string, suffix string) (string, error) {
+	if prefix != "" && strings.HasPrefix(version, prefix) {
+		return "", fmt.Errorf("version '%s' already starts with version_prefix: '%s'", version, prefix)
+	}
+
+	if suffix != "" && strings.HasSuffix(version, suffix) {
+		return "", fmt.Errorf("version '%s' already ends with version_suffix: '%s'", version, suffix)
+	}
+
+	return prefix + version + suffix, nil
+}
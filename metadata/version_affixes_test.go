@@ -0,0 +1,40 @@
+package metadata_test
+
+import (
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AffixVersion", func() {
+	It("returns the version unchanged without a prefix or suffix", func() {
+		Expect(metadata.AffixVersion("1.2.3", "", "")).To(Equal("1.2.3"))
+	})
+
+	It("applies the prefix", func() {
+		Expect(metadata.AffixVersion("1.2.3", "lts-", "")).To(Equal("lts-1.2.3"))
+	})
+
+	It("applies the suffix", func() {
+		Expect(metadata.AffixVersion("1.2.3", "", "-LTS")).To(Equal("1.2.3-LTS"))
+	})
+
+	It("applies the prefix and suffix", func() {
+		Expect(metadata.AffixVersion("1.2.3", "lts-", "-LTS")).To(Equal("lts-1.2.3-LTS"))
+	})
+
+	Context("when the version already has the prefix", func() {
+		It("returns an error", func() {
+			_, err := metadata.AffixVersion("lts-1.2.3", "lts-", "-LTS")
+			Expect(err).To(MatchError("version 'lts-1.2.3' already starts with version_prefix: 'lts-'"))
+		})
+	})
+
+	Context("when the version already has the suffix", func() {
+		It("returns an error", func() {
+			_, err := metadata.AffixVersion("1.2.3-LTS", "", "-LTS")
+			Expect(err).To(MatchError("version '1.2.3-LTS' already ends with version_suffix: '-LTS'"))
+		})
+	})
+})
@@ -2,10 +2,13 @@ package out
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/logging"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 )
 
@@ -23,7 +26,13 @@ type OutCommand struct {
 	releaseUpgradePathsAdder     releaseUpgradePathsAdder
 	upgradePathSpecifiersCreator upgradePathSpecifiersCreator
 	finalizer                    finalizer
+	publishVerifier              publishVerifier
+	snapshotter                  snapshotter
+	publishLock                  publishLock
+	uploadState                  uploadState
+	scanner                      scanner
 	uploader                     uploader
+	transferStats                transferStats
 	m                            metadata.Metadata
 	skipUpload                   bool
 }
@@ -42,7 +51,13 @@ type OutCommandConfig struct {
 	ReleaseUpgradePathsAdder     releaseUpgradePathsAdder
 	UpgradePathSpecifiersCreator upgradePathSpecifiersCreator
 	Finalizer                    finalizer
+	PublishVerifier              publishVerifier
+	Snapshotter                  snapshotter
+	PublishLock                  publishLock
+	UploadState                  uploadState
+	Scanner                      scanner
 	Uploader                     uploader
+	TransferStats                transferStats
 	M                            metadata.Metadata
 	SkipUpload                   bool
 }
@@ -62,7 +77,13 @@ func NewOutCommand(config OutCommandConfig) OutCommand {
 		releaseUpgradePathsAdder:     config.ReleaseUpgradePathsAdder,
 		upgradePathSpecifiersCreator: config.UpgradePathSpecifiersCreator,
 		finalizer:                    config.Finalizer,
+		publishVerifier:              config.PublishVerifier,
+		snapshotter:                  config.Snapshotter,
+		publishLock:                  config.PublishLock,
+		uploadState:                  config.UploadState,
+		scanner:                      config.Scanner,
 		uploader:                     config.Uploader,
+		transferStats:                config.TransferStats,
 		m:                            config.M,
 		skipUpload:                   config.SkipUpload,
 	}
@@ -70,7 +91,10 @@ func NewOutCommand(config OutCommandConfig) OutCommand {
 
 //go:generate counterfeiter --fake-name Creator . creator
 type creator interface {
+	ValidateVersion() error
 	Create() (pivnet.Release, error)
+	Resume(releaseID int) (pivnet.Release, bool, error)
+	SetAttributes(release pivnet.Release) error
 }
 
 //go:generate counterfeiter --fake-name Uploader . uploader
@@ -113,6 +137,39 @@ type finalizer interface {
 	Finalize(productSlug string, releaseVersion string) (concourse.OutResponse, error)
 }
 
+//go:generate counterfeiter --fake-name PublishVerifier . publishVerifier
+type publishVerifier interface {
+	Verify(release pivnet.Release, exactGlobs []string) error
+}
+
+//go:generate counterfeiter --fake-name Snapshotter . snapshotter
+type snapshotter interface {
+	Snapshot(release pivnet.Release) error
+}
+
+//go:generate counterfeiter --fake-name UploadState . uploadState
+type uploadState interface {
+	Resume(version string) (int, bool, error)
+	Start(release pivnet.Release) error
+	Finish() error
+}
+
+//go:generate counterfeiter --fake-name Scanner . scanner
+type scanner interface {
+	Scan(exactGlob string) (string, error)
+}
+
+//go:generate counterfeiter --fake-name PublishLock . publishLock
+type publishLock interface {
+	Acquire() error
+	Release() error
+}
+
+//go:generate counterfeiter --fake-name TransferStats . transferStats
+type transferStats interface {
+	Metadata(wallTime time.Duration) []concourse.Metadata
+}
+
 //go:generate counterfeiter --fake-name Validation . validation
 type validation interface {
 	Validate() error
@@ -124,6 +181,8 @@ type globber interface {
 }
 
 func (c OutCommand) Run(input concourse.OutRequest) (concourse.OutResponse, error) {
+	start := time.Now()
+
 	if c.outDir == "" {
 		return concourse.OutResponse{}, fmt.Errorf("out dir must be provided")
 	}
@@ -138,6 +197,16 @@ func (c OutCommand) Run(input concourse.OutRequest) (concourse.OutResponse, erro
 		return concourse.OutResponse{}, err
 	}
 
+	expectedFileCount := input.Params.ExpectedFileCount
+	if expectedFileCount > 0 && len(exactGlobs) != expectedFileCount {
+		return concourse.OutResponse{}, fmt.Errorf(
+			"expected %d files to match file_glob but found %d: %v",
+			expectedFileCount,
+			len(exactGlobs),
+			exactGlobs,
+		)
+	}
+
 	var missingFiles []string
 	for _, f := range c.m.ProductFiles {
 		var foundFile bool
@@ -162,7 +231,50 @@ func (c OutCommand) Run(input concourse.OutRequest) (concourse.OutResponse, erro
 			)
 	}
 
-	pivnetRelease, err := c.creator.Create()
+	missingFields := c.m.MissingFields(input.Params.RequiredFields)
+	if len(missingFields) > 0 {
+		return concourse.OutResponse{}, fmt.Errorf(
+			"metadata is missing required fields: %s",
+			strings.Join(missingFields, ", "),
+		)
+	}
+
+	// An affixed version is derived by the put rather than read as is from
+	// the version file, so a collision with an existing release is caught
+	// before any file is scanned or uploaded. A resumed put may already have
+	// created the release.
+	if !input.Params.Resume && (input.Params.VersionPrefix != "" || input.Params.VersionSuffix != "") {
+		err = c.creator.ValidateVersion()
+		if err != nil {
+			return concourse.OutResponse{}, err
+		}
+	}
+
+	var scanResults []concourse.Metadata
+	if !c.skipUpload {
+		scanResults, err = c.scanFiles(exactGlobs)
+		if err != nil {
+			return concourse.OutResponse{}, err
+		}
+	}
+
+	if input.Params.PublishLock {
+		err = c.publishLock.Acquire()
+		if err != nil {
+			return concourse.OutResponse{}, err
+		}
+
+		// The lock expires if it cannot be released, so failing to release
+		// it does not fail the put.
+		defer func() {
+			releaseErr := c.publishLock.Release()
+			if releaseErr != nil {
+				logging.Warn(c.logger, fmt.Sprintf("Could not release publish lock: %s", releaseErr.Error()))
+			}
+		}()
+	}
+
+	pivnetRelease, err := c.createRelease(input.Params.Resume)
 	if err != nil {
 		return concourse.OutResponse{}, err
 	}
@@ -207,12 +319,144 @@ func (c OutCommand) Run(input concourse.OutRequest) (concourse.OutResponse, erro
 		return concourse.OutResponse{}, err
 	}
 
+	if input.Params.VerifyPublish {
+		var uploadedGlobs []string
+		if !c.skipUpload {
+			uploadedGlobs = exactGlobs
+		}
+
+		err = c.publishVerifier.Verify(pivnetRelease, uploadedGlobs)
+		if err != nil {
+			return concourse.OutResponse{}, err
+		}
+	}
+
 	out, err := c.finalizer.Finalize(input.Source.ProductSlug, pivnetRelease.Version)
 	if err != nil {
 		return concourse.OutResponse{}, err
 	}
 
+	// The snapshot is taken once the release is finalized, so that it is of
+	// the release as published.
+	if input.Params.SnapshotFile != "" {
+		err = c.snapshotter.Snapshot(pivnetRelease)
+		if err != nil {
+			return concourse.OutResponse{}, err
+		}
+	}
+
+	if input.Params.Resume {
+		// The state only saves uploads of a later put, so failing to delete
+		// it does not fail the put.
+		finishErr := c.uploadState.Finish()
+		if finishErr != nil {
+			logging.Warn(c.logger, fmt.Sprintf("Could not delete upload state: %s", finishErr.Error()))
+		}
+	}
+
+	out.Metadata = append(out.Metadata, scanResults...)
+
+	stats := c.transferStats.Metadata(time.Since(start))
+	for _, s := range stats {
+		c.logger.Info(fmt.Sprintf("Publish stat: %s: %s", s.Name, s.Value))
+	}
+	out.Metadata = append(out.Metadata, stats...)
+
 	c.logger.Info("Put complete")
 
 	return out, nil
 }
+
+// scanFiles scans each file before the release is created, so that nothing
+// is published if any file fails its scan, and returns the result of each
+// scan as metadata of the put.
+func (c OutCommand) scanFiles(exactGlobs []string) ([]concourse.Metadata, error) {
+	var results []concourse.Metadata
+	for _, exactGlob := range exactGlobs {
+		result, err := c.scanner.Scan(exactGlob)
+		if err != nil {
+			return nil, err
+		}
+
+		if result == "" {
+			continue
+		}
+
+		c.logger.Info(fmt.Sprintf("Scanned file: '%s' - result: %s", exactGlob, result))
+		results = append(results, concourse.Metadata{
+			Name:  "scan_result",
+			Value: fmt.Sprintf("%s: %s", exactGlob, result),
+		})
+	}
+
+	return results, nil
+}
+
+// createRelease creates the release, or resumes it, and sets its
+// attributes. They are set once the release has been recorded, so that a
+// put which fails to set them can be resumed rather than failing because the
+// release already exists.
+func (c OutCommand) createRelease(resume bool) (pivnet.Release, error) {
+	release, err := c.createOrResumeRelease(resume)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	err = c.creator.SetAttributes(release)
+	if err != nil {
+		if resume {
+			logging.Warn(c.logger, fmt.Sprintf(
+				"Release '%s' was created with ID: %d but is incomplete - put it again to resume it",
+				release.Version,
+				release.ID,
+			))
+		} else {
+			logging.Warn(c.logger, fmt.Sprintf(
+				"Release '%s' was created with ID: %d but is incomplete - delete it, or put it again with override set to true",
+				release.Version,
+				release.ID,
+			))
+		}
+
+		return pivnet.Release{}, err
+	}
+
+	return release, nil
+}
+
+// createOrResumeRelease creates the release. If resume is set and a previous
+// put of the same version recorded its progress before being interrupted,
+// that put's release is resumed instead.
+func (c OutCommand) createOrResumeRelease(resume bool) (pivnet.Release, error) {
+	if !resume {
+		return c.creator.Create()
+	}
+
+	releaseID, found, err := c.uploadState.Resume(c.m.Release.Version)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	if found {
+		release, exists, err := c.creator.Resume(releaseID)
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+
+		if exists {
+			return release, nil
+		}
+	}
+
+	release, err := c.creator.Create()
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	err = c.uploadState.Start(release)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	return release, nil
+}
@@ -3,6 +3,7 @@ package out_test
 import (
 	"errors"
 	"log"
+	"time"
 
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
@@ -22,6 +23,12 @@ var _ = Describe("Out", func() {
 			fakeLogger logger.Logger
 
 			finalizer                    *outfakes.Finalizer
+			publishVerifier              *outfakes.PublishVerifier
+			snapshotter                  *outfakes.Snapshotter
+			publishLock                  *outfakes.PublishLock
+			uploadState                  *outfakes.UploadState
+			scanner                      *outfakes.Scanner
+			transferStats                *outfakes.TransferStats
 			userGroupsUpdater            *outfakes.UserGroupsUpdater
 			releaseFileGroupsAdder       *outfakes.ReleaseFileGroupsAdder
 			releaseDependenciesAdder     *outfakes.ReleaseDependenciesAdder
@@ -34,8 +41,14 @@ var _ = Describe("Out", func() {
 			globber                      *outfakes.Globber
 			cmd                          out.OutCommand
 
-			skipUpload bool
-			request    concourse.OutRequest
+			skipUpload        bool
+			verifyPublish     bool
+			snapshotFile      string
+			expectedFileCount int
+			usePublishLock    bool
+			resume            bool
+			requiredFields    []string
+			request           concourse.OutRequest
 
 			productSlug string
 
@@ -59,6 +72,12 @@ var _ = Describe("Out", func() {
 			fakeLogger = logshim.NewLogShim(logger, logger, true)
 
 			finalizer = &outfakes.Finalizer{}
+			publishVerifier = &outfakes.PublishVerifier{}
+			snapshotter = &outfakes.Snapshotter{}
+			publishLock = &outfakes.PublishLock{}
+			uploadState = &outfakes.UploadState{}
+			scanner = &outfakes.Scanner{}
+			transferStats = &outfakes.TransferStats{}
 			userGroupsUpdater = &outfakes.UserGroupsUpdater{}
 			releaseFileGroupsAdder = &outfakes.ReleaseFileGroupsAdder{}
 			releaseDependenciesAdder = &outfakes.ReleaseDependenciesAdder{}
@@ -71,6 +90,12 @@ var _ = Describe("Out", func() {
 			globber = &outfakes.Globber{}
 
 			skipUpload = false
+			verifyPublish = false
+			snapshotFile = ""
+			expectedFileCount = 0
+			usePublishLock = false
+			resume = false
+			requiredFields = nil
 
 			productSlug = "some-product-slug"
 
@@ -112,6 +137,12 @@ var _ = Describe("Out", func() {
 				Validation:                   validator,
 				Creator:                      creator,
 				Finalizer:                    finalizer,
+				PublishVerifier:              publishVerifier,
+				Snapshotter:                  snapshotter,
+				PublishLock:                  publishLock,
+				UploadState:                  uploadState,
+				Scanner:                      scanner,
+				TransferStats:                transferStats,
 				UserGroupsUpdater:            userGroupsUpdater,
 				ReleaseFileGroupsAdder:       releaseFileGroupsAdder,
 				ReleaseDependenciesAdder:     releaseDependenciesAdder,
@@ -149,6 +180,14 @@ var _ = Describe("Out", func() {
 				Source: concourse.Source{
 					ProductSlug: productSlug,
 				},
+				Params: concourse.OutParams{
+					VerifyPublish:     verifyPublish,
+					SnapshotFile:      snapshotFile,
+					ExpectedFileCount: expectedFileCount,
+					PublishLock:       usePublishLock,
+					Resume:            resume,
+					RequiredFields:    requiredFields,
+				},
 			}
 		})
 
@@ -187,6 +226,87 @@ var _ = Describe("Out", func() {
 			Expect(invokedReleaseVersion).To(Equal("some-version"))
 		})
 
+		It("does not verify the published release", func() {
+			_, err := cmd.Run(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(publishVerifier.VerifyCallCount()).To(Equal(0))
+		})
+
+		Context("when verify_publish is true", func() {
+			BeforeEach(func() {
+				verifyPublish = true
+			})
+
+			It("verifies the published release before finalizing", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(publishVerifier.VerifyCallCount()).To(Equal(1))
+				invokedPivnetRelease, invokedExactGlobs := publishVerifier.VerifyArgsForCall(0)
+				Expect(invokedPivnetRelease).To(Equal(pivnet.Release{ID: 1337, Availability: "none", Version: "some-version"}))
+				Expect(invokedExactGlobs).To(Equal([]string{"some-glob-1", "some-glob-2"}))
+			})
+
+			Context("when verification fails", func() {
+				BeforeEach(func() {
+					publishVerifier.VerifyReturns(errors.New("verify failed"))
+				})
+
+				It("returns the error without finalizing", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("verify failed"))
+
+					Expect(finalizer.FinalizeCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		It("does not snapshot the published release", func() {
+			_, err := cmd.Run(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(snapshotter.SnapshotCallCount()).To(Equal(0))
+		})
+
+		Context("when snapshot_file is provided", func() {
+			BeforeEach(func() {
+				snapshotFile = "snapshot/release.yml"
+			})
+
+			It("snapshots the published release", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(snapshotter.SnapshotCallCount()).To(Equal(1))
+				Expect(snapshotter.SnapshotArgsForCall(0)).To(Equal(pivnet.Release{ID: 1337, Availability: "none", Version: "some-version"}))
+			})
+
+			Context("when finalizing fails", func() {
+				BeforeEach(func() {
+					finalizeErr = errors.New("finalize failed")
+				})
+
+				It("does not snapshot the release", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("finalize failed"))
+
+					Expect(snapshotter.SnapshotCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the snapshot fails", func() {
+				BeforeEach(func() {
+					snapshotter.SnapshotReturns(errors.New("snapshot failed"))
+				})
+
+				It("returns the error", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("snapshot failed"))
+				})
+			})
+		})
+
 		Context("when skipUpload is true", func() {
 			BeforeEach(func() {
 				skipUpload = true
@@ -198,6 +318,13 @@ var _ = Describe("Out", func() {
 
 				Expect(uploader.UploadCallCount()).To(Equal(0))
 			})
+
+			It("does not scan any files", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(scanner.ScanCallCount()).To(Equal(0))
+			})
 		})
 
 		Context("when outdir is not provided", func() {
@@ -243,6 +370,356 @@ var _ = Describe("Out", func() {
 			})
 		})
 
+		Context("when expected_file_count matches the number of files", func() {
+			BeforeEach(func() {
+				expectedFileCount = 2
+			})
+
+			It("creates the release", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(creator.CreateCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when expected_file_count does not match the number of files", func() {
+			BeforeEach(func() {
+				expectedFileCount = 3
+			})
+
+			It("returns an error without creating the release", func() {
+				_, err := cmd.Run(request)
+				Expect(err).To(MatchError("expected 3 files to match file_glob but found 2: [some-glob-1 some-glob-2]"))
+
+				Expect(creator.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when required fields are missing from the metadata", func() {
+			BeforeEach(func() {
+				requiredFields = []string{"release_notes_url", "eula_slug", "doc_file"}
+			})
+
+			It("returns an error listing them without creating the release", func() {
+				_, err := cmd.Run(request)
+				Expect(err).To(MatchError("metadata is missing required fields: release_notes_url, eula_slug, doc_file"))
+
+				Expect(creator.CreateCallCount()).To(Equal(0))
+				Expect(uploader.UploadCallCount()).To(Equal(0))
+			})
+		})
+
+		It("does not validate the version before creating the release", func() {
+			_, err := cmd.Run(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(creator.ValidateVersionCallCount()).To(Equal(0))
+		})
+
+		Context("when the version is affixed", func() {
+			JustBeforeEach(func() {
+				request.Params.VersionSuffix = "-LTS"
+			})
+
+			It("validates the version", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(creator.ValidateVersionCallCount()).To(Equal(1))
+			})
+
+			Context("when a release already has the version", func() {
+				BeforeEach(func() {
+					creator.ValidateVersionReturns(errors.New("some collision error"))
+				})
+
+				It("returns an error without scanning or uploading any file", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("some collision error"))
+
+					Expect(scanner.ScanCallCount()).To(Equal(0))
+					Expect(creator.CreateCallCount()).To(Equal(0))
+					Expect(uploader.UploadCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when resume is true", func() {
+				BeforeEach(func() {
+					resume = true
+				})
+
+				It("does not validate the version, as the release may have been created", func() {
+					_, err := cmd.Run(request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(creator.ValidateVersionCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		It("does not acquire a publish lock", func() {
+			_, err := cmd.Run(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(publishLock.AcquireCallCount()).To(Equal(0))
+			Expect(publishLock.ReleaseCallCount()).To(Equal(0))
+		})
+
+		Context("when publish_lock is true", func() {
+			BeforeEach(func() {
+				usePublishLock = true
+			})
+
+			It("holds the lock while creating and finalizing the release", func() {
+				creator.CreateStub = func() (pivnet.Release, error) {
+					Expect(publishLock.AcquireCallCount()).To(Equal(1))
+					return pivnet.Release{ID: 1337, Version: "some-version"}, nil
+				}
+				finalizer.FinalizeStub = func(string, string) (concourse.OutResponse, error) {
+					Expect(publishLock.ReleaseCallCount()).To(Equal(0))
+					return concourse.OutResponse{}, nil
+				}
+
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(publishLock.AcquireCallCount()).To(Equal(1))
+				Expect(publishLock.ReleaseCallCount()).To(Equal(1))
+			})
+
+			Context("when the lock cannot be acquired", func() {
+				BeforeEach(func() {
+					publishLock.AcquireReturns(errors.New("lock held"))
+				})
+
+				It("returns the error without creating the release", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("lock held"))
+
+					Expect(creator.CreateCallCount()).To(Equal(0))
+					Expect(publishLock.ReleaseCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the put fails", func() {
+				BeforeEach(func() {
+					createErr = errors.New("some create error")
+				})
+
+				It("releases the lock", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(Equal(createErr))
+
+					Expect(publishLock.ReleaseCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the lock cannot be released", func() {
+				BeforeEach(func() {
+					publishLock.ReleaseReturns(errors.New("release error"))
+				})
+
+				It("does not fail the put", func() {
+					_, err := cmd.Run(request)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+
+		It("scans each file and records the results in the metadata", func() {
+			scanner.ScanStub = func(exactGlob string) (string, error) {
+				Expect(creator.CreateCallCount()).To(Equal(0))
+				return "OK", nil
+			}
+
+			response, err := cmd.Run(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(scanner.ScanCallCount()).To(Equal(2))
+			Expect(scanner.ScanArgsForCall(0)).To(Equal("some-glob-1"))
+			Expect(scanner.ScanArgsForCall(1)).To(Equal("some-glob-2"))
+
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "scan_result", Value: "some-glob-1: OK"}))
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "scan_result", Value: "some-glob-2: OK"}))
+		})
+
+		It("records the transfer stats in the metadata once the files are uploaded", func() {
+			transferStats.MetadataStub = func(wallTime time.Duration) []concourse.Metadata {
+				Expect(uploader.UploadCallCount()).To(Equal(1))
+				return []concourse.Metadata{{Name: "publish_bytes", Value: "1234"}}
+			}
+
+			response, err := cmd.Run(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transferStats.MetadataCallCount()).To(Equal(1))
+			Expect(transferStats.MetadataArgsForCall(0)).To(BeNumerically(">", 0))
+
+			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "publish_bytes", Value: "1234"}))
+		})
+
+		Context("when scanning is disabled", func() {
+			It("does not record scan results", func() {
+				response, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, m := range response.Metadata {
+					Expect(m.Name).NotTo(Equal("scan_result"))
+				}
+			})
+		})
+
+		Context("when a file fails its scan", func() {
+			BeforeEach(func() {
+				scanner.ScanReturns("", errors.New("some scan error"))
+			})
+
+			It("returns the error without creating the release", func() {
+				_, err := cmd.Run(request)
+				Expect(err).To(MatchError("some scan error"))
+
+				Expect(creator.CreateCallCount()).To(Equal(0))
+				Expect(uploader.UploadCallCount()).To(Equal(0))
+			})
+		})
+
+		It("does not use the upload state", func() {
+			_, err := cmd.Run(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uploadState.ResumeCallCount()).To(Equal(0))
+			Expect(uploadState.StartCallCount()).To(Equal(0))
+			Expect(uploadState.FinishCallCount()).To(Equal(0))
+		})
+
+		Context("when resume is true", func() {
+			BeforeEach(func() {
+				resume = true
+			})
+
+			It("creates the release, records it and deletes the state once finalized", func() {
+				_, err := cmd.Run(request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploadState.ResumeCallCount()).To(Equal(1))
+				Expect(uploadState.ResumeArgsForCall(0)).To(Equal("release-version"))
+
+				Expect(creator.ResumeCallCount()).To(Equal(0))
+				Expect(creator.CreateCallCount()).To(Equal(1))
+
+				Expect(uploadState.StartCallCount()).To(Equal(1))
+				Expect(uploadState.StartArgsForCall(0).ID).To(Equal(1337))
+
+				Expect(uploadState.FinishCallCount()).To(Equal(1))
+			})
+
+			Context("when the attributes of the release cannot be set", func() {
+				BeforeEach(func() {
+					creator.SetAttributesReturns(errors.New("some attributes error"))
+				})
+
+				It("returns the error once the release is recorded, so that it can be resumed", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("some attributes error"))
+
+					Expect(uploadState.StartCallCount()).To(Equal(1))
+					Expect(uploadState.StartArgsForCall(0).ID).To(Equal(1337))
+
+					Expect(uploader.UploadCallCount()).To(Equal(0))
+					Expect(uploadState.FinishCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when a previous put recorded its release", func() {
+				BeforeEach(func() {
+					uploadState.ResumeReturns(42, true, nil)
+					creator.ResumeReturns(pivnet.Release{ID: 42, Version: "release-version"}, true, nil)
+				})
+
+				It("resumes that release rather than creating one", func() {
+					_, err := cmd.Run(request)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(creator.ResumeCallCount()).To(Equal(1))
+					Expect(creator.ResumeArgsForCall(0)).To(Equal(42))
+					Expect(creator.CreateCallCount()).To(Equal(0))
+					Expect(uploadState.StartCallCount()).To(Equal(0))
+
+					Expect(creator.SetAttributesCallCount()).To(Equal(1))
+					Expect(creator.SetAttributesArgsForCall(0).ID).To(Equal(42))
+
+					Expect(uploader.UploadCallCount()).To(Equal(1))
+					release, _ := uploader.UploadArgsForCall(0)
+					Expect(release.ID).To(Equal(42))
+				})
+
+				Context("when the release no longer exists", func() {
+					BeforeEach(func() {
+						creator.ResumeReturns(pivnet.Release{}, false, nil)
+					})
+
+					It("creates the release and records it", func() {
+						_, err := cmd.Run(request)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(creator.CreateCallCount()).To(Equal(1))
+						Expect(uploadState.StartCallCount()).To(Equal(1))
+					})
+				})
+
+				Context("when resuming the release fails", func() {
+					BeforeEach(func() {
+						creator.ResumeReturns(pivnet.Release{}, false, errors.New("some resume error"))
+					})
+
+					It("returns the error", func() {
+						_, err := cmd.Run(request)
+						Expect(err).To(MatchError("some resume error"))
+
+						Expect(creator.CreateCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when the upload state cannot be read", func() {
+				BeforeEach(func() {
+					uploadState.ResumeReturns(0, false, errors.New("some read error"))
+				})
+
+				It("returns the error", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("some read error"))
+
+					Expect(creator.CreateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the release cannot be recorded", func() {
+				BeforeEach(func() {
+					uploadState.StartReturns(errors.New("some start error"))
+				})
+
+				It("returns the error", func() {
+					_, err := cmd.Run(request)
+					Expect(err).To(MatchError("some start error"))
+
+					Expect(uploader.UploadCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the upload state cannot be deleted", func() {
+				BeforeEach(func() {
+					uploadState.FinishReturns(errors.New("some finish error"))
+				})
+
+				It("does not fail the put", func() {
+					_, err := cmd.Run(request)
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+
 		Context("when a release cannot be created", func() {
 			BeforeEach(func() {
 				createErr = errors.New("some create error")
@@ -254,6 +731,20 @@ var _ = Describe("Out", func() {
 			})
 		})
 
+		Context("when the attributes of a release cannot be set", func() {
+			BeforeEach(func() {
+				creator.SetAttributesReturns(errors.New("some attributes error"))
+			})
+
+			It("returns an error without uploading", func() {
+				_, err := cmd.Run(request)
+				Expect(err).To(MatchError("some attributes error"))
+
+				Expect(creator.SetAttributesArgsForCall(0).ID).To(Equal(1337))
+				Expect(uploader.UploadCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when a release cannot be uploaded", func() {
 			BeforeEach(func() {
 				uploadErr = errors.New("upload error")
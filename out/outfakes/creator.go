@@ -8,6 +8,12 @@ import (
 )
 
 type Creator struct {
+	ValidateVersionStub        func() error
+	validateVersionMutex       sync.RWMutex
+	validateVersionArgsForCall []struct{}
+	validateVersionReturns     struct {
+		result1 error
+	}
 	CreateStub        func() (go_pivnet.Release, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct{}
@@ -15,10 +21,53 @@ type Creator struct {
 		result1 go_pivnet.Release
 		result2 error
 	}
+	ResumeStub        func(releaseID int) (go_pivnet.Release, bool, error)
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct {
+		releaseID int
+	}
+	resumeReturns struct {
+		result1 go_pivnet.Release
+		result2 bool
+		result3 error
+	}
+	SetAttributesStub        func(release go_pivnet.Release) error
+	setAttributesMutex       sync.RWMutex
+	setAttributesArgsForCall []struct {
+		release go_pivnet.Release
+	}
+	setAttributesReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *Creator) ValidateVersion() error {
+	fake.validateVersionMutex.Lock()
+	fake.validateVersionArgsForCall = append(fake.validateVersionArgsForCall, struct{}{})
+	fake.recordInvocation("ValidateVersion", []interface{}{})
+	fake.validateVersionMutex.Unlock()
+	if fake.ValidateVersionStub != nil {
+		return fake.ValidateVersionStub()
+	} else {
+		return fake.validateVersionReturns.result1
+	}
+}
+
+func (fake *Creator) ValidateVersionCallCount() int {
+	fake.validateVersionMutex.RLock()
+	defer fake.validateVersionMutex.RUnlock()
+	return len(fake.validateVersionArgsForCall)
+}
+
+func (fake *Creator) ValidateVersionReturns(result1 error) {
+	fake.ValidateVersionStub = nil
+	fake.validateVersionReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Creator) Create() (go_pivnet.Release, error) {
 	fake.createMutex.Lock()
 	fake.createArgsForCall = append(fake.createArgsForCall, struct{}{})
@@ -45,11 +94,85 @@ func (fake *Creator) CreateReturns(result1 go_pivnet.Release, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *Creator) Resume(releaseID int) (go_pivnet.Release, bool, error) {
+	fake.resumeMutex.Lock()
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct {
+		releaseID int
+	}{releaseID})
+	fake.recordInvocation("Resume", []interface{}{releaseID})
+	fake.resumeMutex.Unlock()
+	if fake.ResumeStub != nil {
+		return fake.ResumeStub(releaseID)
+	} else {
+		return fake.resumeReturns.result1, fake.resumeReturns.result2, fake.resumeReturns.result3
+	}
+}
+
+func (fake *Creator) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *Creator) ResumeArgsForCall(i int) int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return fake.resumeArgsForCall[i].releaseID
+}
+
+func (fake *Creator) ResumeReturns(result1 go_pivnet.Release, result2 bool, result3 error) {
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 go_pivnet.Release
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *Creator) SetAttributes(release go_pivnet.Release) error {
+	fake.setAttributesMutex.Lock()
+	fake.setAttributesArgsForCall = append(fake.setAttributesArgsForCall, struct {
+		release go_pivnet.Release
+	}{release})
+	fake.recordInvocation("SetAttributes", []interface{}{release})
+	fake.setAttributesMutex.Unlock()
+	if fake.SetAttributesStub != nil {
+		return fake.SetAttributesStub(release)
+	} else {
+		return fake.setAttributesReturns.result1
+	}
+}
+
+func (fake *Creator) SetAttributesCallCount() int {
+	fake.setAttributesMutex.RLock()
+	defer fake.setAttributesMutex.RUnlock()
+	return len(fake.setAttributesArgsForCall)
+}
+
+func (fake *Creator) SetAttributesArgsForCall(i int) go_pivnet.Release {
+	fake.setAttributesMutex.RLock()
+	defer fake.setAttributesMutex.RUnlock()
+	return fake.setAttributesArgsForCall[i].release
+}
+
+func (fake *Creator) SetAttributesReturns(result1 error) {
+	fake.SetAttributesStub = nil
+	fake.setAttributesReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Creator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.validateVersionMutex.RLock()
+	defer fake.validateVersionMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	fake.setAttributesMutex.RLock()
+	defer fake.setAttributesMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,95 @@
+// This file was generated by counterfeiter
+package outfakes
+
+import (
+	"sync"
+)
+
+type PublishLock struct {
+	AcquireStub        func() error
+	acquireMutex       sync.RWMutex
+	acquireArgsForCall []struct{}
+	acquireReturns     struct {
+		result1 error
+	}
+	ReleaseStub        func() error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct{}
+	releaseReturns     struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PublishLock) Acquire() error {
+	fake.acquireMutex.Lock()
+	fake.acquireArgsForCall = append(fake.acquireArgsForCall, struct{}{})
+	fake.recordInvocation("Acquire", []interface{}{})
+	fake.acquireMutex.Unlock()
+	if fake.AcquireStub != nil {
+		return fake.AcquireStub()
+	} else {
+		return fake.acquireReturns.result1
+	}
+}
+
+func (fake *PublishLock) AcquireCallCount() int {
+	fake.acquireMutex.RLock()
+	defer fake.acquireMutex.RUnlock()
+	return len(fake.acquireArgsForCall)
+}
+
+func (fake *PublishLock) AcquireReturns(result1 error) {
+	fake.AcquireStub = nil
+	fake.acquireReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PublishLock) Release() error {
+	fake.releaseMutex.Lock()
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct{}{})
+	fake.recordInvocation("Release", []interface{}{})
+	fake.releaseMutex.Unlock()
+	if fake.ReleaseStub != nil {
+		return fake.ReleaseStub()
+	} else {
+		return fake.releaseReturns.result1
+	}
+}
+
+func (fake *PublishLock) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *PublishLock) ReleaseReturns(result1 error) {
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PublishLock) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.acquireMutex.RLock()
+	defer fake.acquireMutex.RUnlock()
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *PublishLock) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,81 @@
+// This file was generated by counterfeiter
+package outfakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/go-pivnet"
+)
+
+type PublishVerifier struct {
+	VerifyStub        func(release pivnet.Release, exactGlobs []string) error
+	verifyMutex       sync.RWMutex
+	verifyArgsForCall []struct {
+		release    pivnet.Release
+		exactGlobs []string
+	}
+	verifyReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PublishVerifier) Verify(release pivnet.Release, exactGlobs []string) error {
+	var exactGlobsCopy []string
+	if exactGlobs != nil {
+		exactGlobsCopy = make([]string, len(exactGlobs))
+		copy(exactGlobsCopy, exactGlobs)
+	}
+	fake.verifyMutex.Lock()
+	fake.verifyArgsForCall = append(fake.verifyArgsForCall, struct {
+		release    pivnet.Release
+		exactGlobs []string
+	}{release, exactGlobsCopy})
+	fake.recordInvocation("Verify", []interface{}{release, exactGlobsCopy})
+	fake.verifyMutex.Unlock()
+	if fake.VerifyStub != nil {
+		return fake.VerifyStub(release, exactGlobs)
+	} else {
+		return fake.verifyReturns.result1
+	}
+}
+
+func (fake *PublishVerifier) VerifyCallCount() int {
+	fake.verifyMutex.RLock()
+	defer fake.verifyMutex.RUnlock()
+	return len(fake.verifyArgsForCall)
+}
+
+func (fake *PublishVerifier) VerifyArgsForCall(i int) (pivnet.Release, []string) {
+	fake.verifyMutex.RLock()
+	defer fake.verifyMutex.RUnlock()
+	return fake.verifyArgsForCall[i].release, fake.verifyArgsForCall[i].exactGlobs
+}
+
+func (fake *PublishVerifier) VerifyReturns(result1 error) {
+	fake.VerifyStub = nil
+	fake.verifyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PublishVerifier) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.verifyMutex.RLock()
+	defer fake.verifyMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *PublishVerifier) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
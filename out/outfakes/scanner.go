@@ -0,0 +1,74 @@
+// This file was generated by counterfeiter
+package outfakes
+
+import (
+	"sync"
+)
+
+type Scanner struct {
+	ScanStub        func(exactGlob string) (string, error)
+	scanMutex       sync.RWMutex
+	scanArgsForCall []struct {
+		exactGlob string
+	}
+	scanReturns struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Scanner) Scan(exactGlob string) (string, error) {
+	fake.scanMutex.Lock()
+	fake.scanArgsForCall = append(fake.scanArgsForCall, struct {
+		exactGlob string
+	}{exactGlob})
+	fake.recordInvocation("Scan", []interface{}{exactGlob})
+	fake.scanMutex.Unlock()
+	if fake.ScanStub != nil {
+		return fake.ScanStub(exactGlob)
+	} else {
+		return fake.scanReturns.result1, fake.scanReturns.result2
+	}
+}
+
+func (fake *Scanner) ScanCallCount() int {
+	fake.scanMutex.RLock()
+	defer fake.scanMutex.RUnlock()
+	return len(fake.scanArgsForCall)
+}
+
+func (fake *Scanner) ScanArgsForCall(i int) string {
+	fake.scanMutex.RLock()
+	defer fake.scanMutex.RUnlock()
+	return fake.scanArgsForCall[i].exactGlob
+}
+
+func (fake *Scanner) ScanReturns(result1 string, result2 error) {
+	fake.ScanStub = nil
+	fake.scanReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Scanner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.scanMutex.RLock()
+	defer fake.scanMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *Scanner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,74 @@
+// This file was generated by counterfeiter
+package outfakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/go-pivnet"
+)
+
+type Snapshotter struct {
+	SnapshotStub        func(release pivnet.Release) error
+	snapshotMutex       sync.RWMutex
+	snapshotArgsForCall []struct {
+		release pivnet.Release
+	}
+	snapshotReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Snapshotter) Snapshot(release pivnet.Release) error {
+	fake.snapshotMutex.Lock()
+	fake.snapshotArgsForCall = append(fake.snapshotArgsForCall, struct {
+		release pivnet.Release
+	}{release})
+	fake.recordInvocation("Snapshot", []interface{}{release})
+	fake.snapshotMutex.Unlock()
+	if fake.SnapshotStub != nil {
+		return fake.SnapshotStub(release)
+	} else {
+		return fake.snapshotReturns.result1
+	}
+}
+
+func (fake *Snapshotter) SnapshotCallCount() int {
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
+	return len(fake.snapshotArgsForCall)
+}
+
+func (fake *Snapshotter) SnapshotArgsForCall(i int) pivnet.Release {
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
+	return fake.snapshotArgsForCall[i].release
+}
+
+func (fake *Snapshotter) SnapshotReturns(result1 error) {
+	fake.SnapshotStub = nil
+	fake.snapshotReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Snapshotter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.snapshotMutex.RLock()
+	defer fake.snapshotMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *Snapshotter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
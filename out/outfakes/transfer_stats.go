@@ -0,0 +1,75 @@
+// This file was generated by counterfeiter
+package outfakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+type TransferStats struct {
+	MetadataStub        func(wallTime time.Duration) []concourse.Metadata
+	metadataMutex       sync.RWMutex
+	metadataArgsForCall []struct {
+		wallTime time.Duration
+	}
+	metadataReturns struct {
+		result1 []concourse.Metadata
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *TransferStats) Metadata(wallTime time.Duration) []concourse.Metadata {
+	fake.metadataMutex.Lock()
+	fake.metadataArgsForCall = append(fake.metadataArgsForCall, struct {
+		wallTime time.Duration
+	}{wallTime})
+	fake.recordInvocation("Metadata", []interface{}{wallTime})
+	fake.metadataMutex.Unlock()
+	if fake.MetadataStub != nil {
+		return fake.MetadataStub(wallTime)
+	} else {
+		return fake.metadataReturns.result1
+	}
+}
+
+func (fake *TransferStats) MetadataCallCount() int {
+	fake.metadataMutex.RLock()
+	defer fake.metadataMutex.RUnlock()
+	return len(fake.metadataArgsForCall)
+}
+
+func (fake *TransferStats) MetadataArgsForCall(i int) time.Duration {
+	fake.metadataMutex.RLock()
+	defer fake.metadataMutex.RUnlock()
+	return fake.metadataArgsForCall[i].wallTime
+}
+
+func (fake *TransferStats) MetadataReturns(result1 []concourse.Metadata) {
+	fake.MetadataStub = nil
+	fake.metadataReturns = struct {
+		result1 []concourse.Metadata
+	}{result1}
+}
+
+func (fake *TransferStats) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.metadataMutex.RLock()
+	defer fake.metadataMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *TransferStats) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,154 @@
+// This file was generated by counterfeiter
+package outfakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+type UploadState struct {
+	ResumeStub        func(version string) (int, bool, error)
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct {
+		version string
+	}
+	resumeReturns struct {
+		result1 int
+		result2 bool
+		result3 error
+	}
+	StartStub        func(release go_pivnet.Release) error
+	startMutex       sync.RWMutex
+	startArgsForCall []struct {
+		release go_pivnet.Release
+	}
+	startReturns struct {
+		result1 error
+	}
+	FinishStub        func() error
+	finishMutex       sync.RWMutex
+	finishArgsForCall []struct{}
+	finishReturns     struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *UploadState) Resume(version string) (int, bool, error) {
+	fake.resumeMutex.Lock()
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct {
+		version string
+	}{version})
+	fake.recordInvocation("Resume", []interface{}{version})
+	fake.resumeMutex.Unlock()
+	if fake.ResumeStub != nil {
+		return fake.ResumeStub(version)
+	} else {
+		return fake.resumeReturns.result1, fake.resumeReturns.result2, fake.resumeReturns.result3
+	}
+}
+
+func (fake *UploadState) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *UploadState) ResumeArgsForCall(i int) string {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return fake.resumeArgsForCall[i].version
+}
+
+func (fake *UploadState) ResumeReturns(result1 int, result2 bool, result3 error) {
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 int
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *UploadState) Start(release go_pivnet.Release) error {
+	fake.startMutex.Lock()
+	fake.startArgsForCall = append(fake.startArgsForCall, struct {
+		release go_pivnet.Release
+	}{release})
+	fake.recordInvocation("Start", []interface{}{release})
+	fake.startMutex.Unlock()
+	if fake.StartStub != nil {
+		return fake.StartStub(release)
+	} else {
+		return fake.startReturns.result1
+	}
+}
+
+func (fake *UploadState) StartCallCount() int {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	return len(fake.startArgsForCall)
+}
+
+func (fake *UploadState) StartArgsForCall(i int) go_pivnet.Release {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	return fake.startArgsForCall[i].release
+}
+
+func (fake *UploadState) StartReturns(result1 error) {
+	fake.StartStub = nil
+	fake.startReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UploadState) Finish() error {
+	fake.finishMutex.Lock()
+	fake.finishArgsForCall = append(fake.finishArgsForCall, struct{}{})
+	fake.recordInvocation("Finish", []interface{}{})
+	fake.finishMutex.Unlock()
+	if fake.FinishStub != nil {
+		return fake.FinishStub()
+	} else {
+		return fake.finishReturns.result1
+	}
+}
+
+func (fake *UploadState) FinishCallCount() int {
+	fake.finishMutex.RLock()
+	defer fake.finishMutex.RUnlock()
+	return len(fake.finishArgsForCall)
+}
+
+func (fake *UploadState) FinishReturns(result1 error) {
+	fake.FinishStub = nil
+	fake.finishReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UploadState) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	fake.finishMutex.RLock()
+	defer fake.finishMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *UploadState) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
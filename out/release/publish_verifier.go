@@ -0,0 +1,197 @@
+package release
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+)
+
+type PublishVerifier struct {
+	logger       logger.Logger
+	pivnet       publishVerifierClient
+	sha256Summer sha256Summer
+	metadata     metadata.Metadata
+	sourcesDir   string
+	productSlug  string
+}
+
+func NewPublishVerifier(
+	logger logger.Logger,
+	pivnetClient publishVerifierClient,
+	sha256Summer sha256Summer,
+	metadata metadata.Metadata,
+	sourcesDir,
+	productSlug string,
+) PublishVerifier {
+	return PublishVerifier{
+		logger:       logger,
+		pivnet:       pivnetClient,
+		sha256Summer: sha256Summer,
+		metadata:     metadata,
+		sourcesDir:   sourcesDir,
+		productSlug:  productSlug,
+	}
+}
+
+//go:generate counterfeiter --fake-name PublishVerifierClient . publishVerifierClient
+type publishVerifierClient interface {
+	GetRelease(productSlug string, releaseVersion string) (pivnet.Release, error)
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
+	DependencySpecifiers(productSlug string, releaseID int) ([]pivnet.DependencySpecifier, error)
+	UpgradePathSpecifiers(productSlug string, releaseID int) ([]pivnet.UpgradePathSpecifier, error)
+}
+
+// Verify re-fetches the release and checks that it matches what was
+// requested, returning an error describing every mismatch found.
+func (v PublishVerifier) Verify(release pivnet.Release, exactGlobs []string) error {
+	v.logger.Info(fmt.Sprintf(
+		"Verifying published release: '%s' with ID: %d",
+		release.Version,
+		release.ID,
+	))
+
+	published, err := v.pivnet.GetRelease(v.productSlug, release.Version)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+
+	if published.Version != v.metadata.Release.Version {
+		problems = append(problems, fmt.Sprintf(
+			"version is '%s', expected '%s'",
+			published.Version,
+			v.metadata.Release.Version,
+		))
+	}
+
+	availability := v.metadata.Release.Availability
+	if availability != "" && published.Availability != availability {
+		problems = append(problems, fmt.Sprintf(
+			"availability is '%s', expected '%s'",
+			published.Availability,
+			availability,
+		))
+	}
+
+	fileProblems, err := v.verifyProductFiles(published, exactGlobs)
+	if err != nil {
+		return err
+	}
+	problems = append(problems, fileProblems...)
+
+	specifierProblems, err := v.verifySpecifiers(published)
+	if err != nil {
+		return err
+	}
+	problems = append(problems, specifierProblems...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf(
+			"publish verification failed:\n  - %s",
+			strings.Join(problems, "\n  - "),
+		)
+	}
+
+	v.logger.Info("Published release verified")
+
+	return nil
+}
+
+func (v PublishVerifier) verifyProductFiles(release pivnet.Release, exactGlobs []string) ([]string, error) {
+	productFiles, err := v.pivnet.ProductFilesForRelease(v.productSlug, release.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+
+	if len(productFiles) < len(exactGlobs) {
+		problems = append(problems, fmt.Sprintf(
+			"release has %d product files, expected at least %d",
+			len(productFiles),
+			len(exactGlobs),
+		))
+	}
+
+	publishedSHA256s := map[string]bool{}
+	for _, pf := range productFiles {
+		publishedSHA256s[pf.SHA256] = true
+	}
+
+	for _, exactGlob := range exactGlobs {
+		sha256, err := v.sha256Summer.SumFile(filepath.Join(v.sourcesDir, exactGlob))
+		if err != nil {
+			return nil, err
+		}
+
+		if !publishedSHA256s[sha256] {
+			problems = append(problems, fmt.Sprintf(
+				"no product file on the release has the SHA256 of '%s': '%s'",
+				exactGlob,
+				sha256,
+			))
+		}
+	}
+
+	return problems, nil
+}
+
+func (v PublishVerifier) verifySpecifiers(release pivnet.Release) ([]string, error) {
+	var problems []string
+
+	if len(v.metadata.DependencySpecifiers) > 0 {
+		dependencySpecifiers, err := v.pivnet.DependencySpecifiers(v.productSlug, release.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, expected := range v.metadata.DependencySpecifiers {
+			var found bool
+			for _, d := range dependencySpecifiers {
+				if d.Product.Slug == expected.ProductSlug && d.Specifier == expected.Specifier {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				problems = append(problems, fmt.Sprintf(
+					"dependency specifier '%s' for product '%s' is missing",
+					expected.Specifier,
+					expected.ProductSlug,
+				))
+			}
+		}
+	}
+
+	if len(v.metadata.UpgradePathSpecifiers) > 0 {
+		upgradePathSpecifiers, err := v.pivnet.UpgradePathSpecifiers(v.productSlug, release.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, expected := range v.metadata.UpgradePathSpecifiers {
+			var found bool
+			for _, u := range upgradePathSpecifiers {
+				if u.Specifier == expected.Specifier {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				problems = append(problems, fmt.Sprintf(
+					"upgrade path specifier '%s' is missing",
+					expected.Specifier,
+				))
+			}
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,185 @@
+package release_test
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/out/release"
+	"github.com/pivotal-cf/pivnet-resource/out/release/releasefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PublishVerifier", func() {
+	Describe("Verify", func() {
+		var (
+			fakeLogger       logger.Logger
+			fakePivnet       *releasefakes.PublishVerifierClient
+			fakeSha256Summer *releasefakes.Sha256Summer
+
+			mdata         metadata.Metadata
+			pivnetRelease pivnet.Release
+			productFiles  []pivnet.ProductFile
+			exactGlobs    []string
+			releaseErr    error
+
+			verifier release.PublishVerifier
+		)
+
+		BeforeEach(func() {
+			logger := log.New(GinkgoWriter, "", log.LstdFlags)
+			fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+			fakePivnet = &releasefakes.PublishVerifierClient{}
+			fakeSha256Summer = &releasefakes.Sha256Summer{}
+
+			mdata = metadata.Metadata{
+				Release: &metadata.Release{
+					Version:      "some-version",
+					Availability: "All Users",
+				},
+				DependencySpecifiers: []metadata.DependencySpecifier{
+					{ProductSlug: "some-dependent-product", Specifier: "1.2.*"},
+				},
+				UpgradePathSpecifiers: []metadata.UpgradePathSpecifier{
+					{Specifier: "1.1.*"},
+				},
+			}
+
+			pivnetRelease = pivnet.Release{
+				ID:           1337,
+				Version:      "some-version",
+				Availability: "All Users",
+			}
+
+			productFiles = []pivnet.ProductFile{
+				{ID: 1, SHA256: "sha256-1"},
+				{ID: 2, SHA256: "sha256-2"},
+			}
+
+			exactGlobs = []string{"file-1", "file-2"}
+			releaseErr = nil
+
+			fakeSha256Summer.SumFileStub = func(path string) (string, error) {
+				switch filepath.Base(path) {
+				case "file-1":
+					return "sha256-1", nil
+				case "file-2":
+					return "sha256-2", nil
+				}
+				return "some-other-sha256", nil
+			}
+
+			fakePivnet.DependencySpecifiersReturns([]pivnet.DependencySpecifier{
+				{Specifier: "1.2.*", Product: pivnet.Product{Slug: "some-dependent-product"}},
+			}, nil)
+
+			fakePivnet.UpgradePathSpecifiersReturns([]pivnet.UpgradePathSpecifier{
+				{Specifier: "1.1.*"},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			fakePivnet.GetReleaseReturns(pivnetRelease, releaseErr)
+			fakePivnet.ProductFilesForReleaseReturns(productFiles, nil)
+
+			verifier = release.NewPublishVerifier(
+				fakeLogger,
+				fakePivnet,
+				fakeSha256Summer,
+				mdata,
+				"/some/sources/dir",
+				"some-product-slug",
+			)
+		})
+
+		It("re-fetches the release and succeeds when it matches", func() {
+			err := verifier.Verify(pivnetRelease, exactGlobs)
+			Expect(err).NotTo(HaveOccurred())
+
+			invokedProductSlug, invokedVersion := fakePivnet.GetReleaseArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal("some-product-slug"))
+			Expect(invokedVersion).To(Equal("some-version"))
+
+			Expect(fakeSha256Summer.SumFileArgsForCall(0)).To(Equal("/some/sources/dir/file-1"))
+		})
+
+		Context("when a file is missing from the release", func() {
+			BeforeEach(func() {
+				productFiles = productFiles[:1]
+			})
+
+			It("returns an error naming the file", func() {
+				err := verifier.Verify(pivnetRelease, exactGlobs)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("has 1 product files, expected at least 2"))
+				Expect(err.Error()).To(ContainSubstring("'file-2'"))
+			})
+		})
+
+		Context("when a file on the release has a different checksum", func() {
+			BeforeEach(func() {
+				productFiles[1].SHA256 = "some-other-sha256"
+			})
+
+			It("returns an error", func() {
+				err := verifier.Verify(pivnetRelease, exactGlobs)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("SHA256 of 'file-2'"))
+			})
+		})
+
+		Context("when the availability does not match", func() {
+			BeforeEach(func() {
+				pivnetRelease.Availability = "Admins Only"
+			})
+
+			It("returns an error", func() {
+				err := verifier.Verify(pivnetRelease, exactGlobs)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("availability is 'Admins Only', expected 'All Users'"))
+			})
+		})
+
+		Context("when a dependency specifier is missing", func() {
+			BeforeEach(func() {
+				fakePivnet.DependencySpecifiersReturns(nil, nil)
+			})
+
+			It("returns an error", func() {
+				err := verifier.Verify(pivnetRelease, exactGlobs)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("dependency specifier '1.2.*' for product 'some-dependent-product' is missing"))
+			})
+		})
+
+		Context("when an upgrade path specifier is missing", func() {
+			BeforeEach(func() {
+				fakePivnet.UpgradePathSpecifiersReturns(nil, nil)
+			})
+
+			It("returns an error", func() {
+				err := verifier.Verify(pivnetRelease, exactGlobs)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("upgrade path specifier '1.1.*' is missing"))
+			})
+		})
+
+		Context("when getting the release returns an error", func() {
+			BeforeEach(func() {
+				releaseErr = errors.New("some release error")
+			})
+
+			It("returns the error", func() {
+				err := verifier.Verify(pivnetRelease, exactGlobs)
+				Expect(err).To(MatchError("some release error"))
+			})
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/gp"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 )
 
@@ -30,6 +31,7 @@ type releaseClient interface {
 	ReleasesForProductSlug(string) ([]pivnet.Release, error)
 	CreateRelease(pivnet.CreateReleaseConfig) (pivnet.Release, error)
 	DeleteRelease(productSlug string, release pivnet.Release) error
+	UpdateReleaseAttributes(productSlug string, releaseID int, attributes gp.ReleaseAttributes) error
 }
 
 //go:generate counterfeiter --fake-name FakeSemverConverter . semverConverter
@@ -59,6 +61,34 @@ func NewReleaseCreator(
 	}
 }
 
+// ValidateVersion ensures that no release of the product already has the
+// version, e.g. one published earlier with the same version_suffix, so that
+// the put fails before any file is scanned or uploaded rather than once
+// Create is reached. It does not fail if override is set, as Create then
+// replaces the existing release.
+func (rc ReleaseCreator) ValidateVersion() error {
+	if rc.params.Override {
+		return nil
+	}
+
+	version := rc.metadata.Release.Version
+
+	rc.logger.Info(fmt.Sprintf("Validating that no release has version: '%s'", version))
+
+	releases, err := rc.pivnet.ReleasesForProductSlug(rc.productSlug)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range releases {
+		if r.Version == version {
+			return rc.existingReleaseError(version)
+		}
+	}
+
+	return nil
+}
+
 func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 	version := rc.metadata.Release.Version
 
@@ -170,6 +200,13 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 		return pivnet.Release{}, err
 	}
 
+	if rc.params.RequireVersionGreaterThanLatest {
+		err = rc.validateVersionGreaterThanLatest(version, releaseType, releases)
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+	}
+
 	for _, r := range releases {
 		if r.Version == version {
 			if rc.params.Override {
@@ -184,11 +221,7 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 					return pivnet.Release{}, err
 				}
 			} else {
-				return pivnet.Release{}, fmt.Errorf(
-					"Release '%s' with version '%s' already exists.",
-					rc.productSlug,
-					version,
-				)
+				return pivnet.Release{}, rc.existingReleaseError(version)
 			}
 		}
 	}
@@ -198,7 +231,7 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 		ReleaseType:           string(releaseType),
 		EULASlug:              eulaSlug,
 		Version:               version,
-		Description:           rc.metadata.Release.Description,
+		Description:           metadata.EncodeCustomMetadata(rc.metadata.Release.Description, rc.metadata.Release.CustomMetadata),
 		ReleaseNotesURL:       rc.metadata.Release.ReleaseNotesURL,
 		ReleaseDate:           rc.metadata.Release.ReleaseDate,
 		Controlled:            rc.metadata.Release.Controlled,
@@ -217,5 +250,142 @@ func (rc ReleaseCreator) Create() (pivnet.Release, error) {
 	}
 
 	rc.logger.Info(fmt.Sprintf("Created new release with ID: %d", release.ID))
+
 	return release, nil
 }
+
+// SetAttributes sets the series and license of the release in a single
+// update, as they are not supported by pivnet.CreateReleaseConfig so are set
+// once it is created. It is safe to call again for a resumed release.
+func (rc ReleaseCreator) SetAttributes(release pivnet.Release) error {
+	attributes := gp.ReleaseAttributes{
+		Series:             rc.metadata.Release.Series,
+		RequiresLicenseKey: rc.metadata.Release.RequiresLicenseKey,
+		LicenseTermsURL:    rc.metadata.Release.LicenseTermsURL,
+	}
+
+	if attributes.IsEmpty() {
+		return nil
+	}
+
+	rc.logger.Info(fmt.Sprintf(
+		"Setting attributes of release - series: '%s', requires license key: %t, license terms: '%s'",
+		attributes.Series,
+		attributes.RequiresLicenseKey,
+		attributes.LicenseTermsURL,
+	))
+
+	err := rc.pivnet.UpdateReleaseAttributes(rc.productSlug, release.ID, attributes)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to set attributes of release '%s' (ID: %d): %s",
+			rc.metadata.Release.Version,
+			release.ID,
+			err.Error(),
+		)
+	}
+
+	return nil
+}
+
+// Resume returns the release with the provided ID, created by a previous put
+// of the same version which was interrupted. It returns false if that release
+// no longer exists, e.g. because it was deleted, in which case a new release
+// should be created instead.
+func (rc ReleaseCreator) Resume(releaseID int) (pivnet.Release, bool, error) {
+	version := rc.metadata.Release.Version
+
+	releases, err := rc.pivnet.ReleasesForProductSlug(rc.productSlug)
+	if err != nil {
+		return pivnet.Release{}, false, err
+	}
+
+	for _, r := range releases {
+		if r.ID == releaseID && r.Version == version {
+			rc.logger.Info(fmt.Sprintf("Resuming existing release: '%s' - id: '%d'", r.Version, r.ID))
+			return r, true, nil
+		}
+	}
+
+	rc.logger.Info(fmt.Sprintf(
+		"Release: '%s' - id: '%d' of the previous put no longer exists",
+		version,
+		releaseID,
+	))
+
+	return pivnet.Release{}, false, nil
+}
+
+// existingReleaseError returns the error for a release which already has the
+// version, naming the affixes if the version was derived with them.
+func (rc ReleaseCreator) existingReleaseError(version string) error {
+	if rc.params.VersionPrefix != "" || rc.params.VersionSuffix != "" {
+		return fmt.Errorf(
+			"Release '%s' with version '%s' already exists (version_prefix: '%s', version_suffix: '%s').",
+			rc.productSlug,
+			version,
+			rc.params.VersionPrefix,
+			rc.params.VersionSuffix,
+		)
+	}
+
+	return fmt.Errorf(
+		"Release '%s' with version '%s' already exists.",
+		rc.productSlug,
+		version,
+	)
+}
+
+// validateVersionGreaterThanLatest ensures that the provided version is
+// greater than every existing release of the same release type.
+// Existing releases whose versions cannot be parsed as semver are ignored,
+// as is an existing release with the same version (see params.override).
+func (rc ReleaseCreator) validateVersionGreaterThanLatest(
+	version string,
+	releaseType pivnet.ReleaseType,
+	releases []pivnet.Release,
+) error {
+	rc.logger.Info(fmt.Sprintf(
+		"Validating version: '%s' is greater than latest release of type: '%s'",
+		version,
+		releaseType,
+	))
+
+	newVersion, err := rc.semverConverter.ToValidSemver(version)
+	if err != nil {
+		return err
+	}
+
+	var latestVersion semver.Version
+	var latestRelease string
+	for _, r := range releases {
+		if r.ReleaseType != releaseType || r.Version == version {
+			continue
+		}
+
+		v, err := rc.semverConverter.ToValidSemver(r.Version)
+		if err != nil {
+			rc.logger.Info(fmt.Sprintf(
+				"Ignoring existing release with non-semver version: '%s'",
+				r.Version,
+			))
+			continue
+		}
+
+		if latestRelease == "" || v.GT(latestVersion) {
+			latestVersion = v
+			latestRelease = r.Version
+		}
+	}
+
+	if latestRelease != "" && !newVersion.GT(latestVersion) {
+		return fmt.Errorf(
+			"provided version: '%s' must be greater than latest '%s' release version: '%s'",
+			version,
+			releaseType,
+			latestRelease,
+		)
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/gp"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 	"github.com/pivotal-cf/pivnet-resource/out/release"
 	"github.com/pivotal-cf/pivnet-resource/out/release/releasefakes"
@@ -37,6 +38,11 @@ var _ = Describe("ReleaseCreator", func() {
 		productSlug       string
 		releaseType       pivnet.ReleaseType
 		params            concourse.OutParams
+		customMetadata    map[string]string
+		series            string
+
+		requiresLicenseKey bool
+		licenseTermsURL    string
 	)
 
 	BeforeEach(func() {
@@ -72,6 +78,10 @@ var _ = Describe("ReleaseCreator", func() {
 	Describe("Create", func() {
 		BeforeEach(func() {
 			params = concourse.OutParams{}
+			customMetadata = nil
+			series = ""
+			requiresLicenseKey = false
+			licenseTermsURL = ""
 		})
 
 		JustBeforeEach(func() {
@@ -84,6 +94,11 @@ var _ = Describe("ReleaseCreator", func() {
 					Description:     "wow, a description",
 					ReleaseNotesURL: "some-url",
 					ReleaseDate:     "1/17/2016",
+					CustomMetadata:  customMetadata,
+					Series:          series,
+
+					RequiresLicenseKey: requiresLicenseKey,
+					LicenseTermsURL:    licenseTermsURL,
 				},
 				ProductFiles: []metadata.ProductFile{
 					{
@@ -134,6 +149,91 @@ var _ = Describe("ReleaseCreator", func() {
 				Controlled:      true,
 				CopyMetadata:    copyMetadata,
 			}))
+
+			Expect(pivnetClient.UpdateReleaseAttributesCallCount()).To(Equal(0))
+		})
+
+		Context("when neither a series nor a license is provided", func() {
+			It("does not update the release when its attributes are set", func() {
+				err := creator.SetAttributes(pivnet.Release{ID: 1337})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.UpdateReleaseAttributesCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a series is provided", func() {
+			BeforeEach(func() {
+				series = "1.8"
+			})
+
+			It("adds the created release to the series once its attributes are set", func() {
+				r, err := creator.Create()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.UpdateReleaseAttributesCallCount()).To(Equal(0))
+
+				err = creator.SetAttributes(r)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.UpdateReleaseAttributesCallCount()).To(Equal(1))
+				invokedProductSlug, releaseID, invokedAttributes := pivnetClient.UpdateReleaseAttributesArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(releaseID).To(Equal(1337))
+				Expect(invokedAttributes).To(Equal(gp.ReleaseAttributes{Series: "1.8"}))
+			})
+
+			Context("when the release requires a license key", func() {
+				BeforeEach(func() {
+					requiresLicenseKey = true
+					licenseTermsURL = "https://example.com/license-terms.pdf"
+				})
+
+				It("sets the series and the license in a single update", func() {
+					err := creator.SetAttributes(pivnet.Release{ID: 1337})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.UpdateReleaseAttributesCallCount()).To(Equal(1))
+					_, _, invokedAttributes := pivnetClient.UpdateReleaseAttributesArgsForCall(0)
+					Expect(invokedAttributes).To(Equal(gp.ReleaseAttributes{
+						Series:             "1.8",
+						RequiresLicenseKey: true,
+						LicenseTermsURL:    "https://example.com/license-terms.pdf",
+					}))
+				})
+			})
+
+			Context("when updating the release returns an error", func() {
+				BeforeEach(func() {
+					pivnetClient.UpdateReleaseAttributesReturns(errors.New("some attributes error"))
+				})
+
+				It("returns an error with the ID of the release", func() {
+					err := creator.SetAttributes(pivnet.Release{ID: 1337})
+					Expect(err).To(MatchError("failed to set attributes of release '1.8.3' (ID: 1337): some attributes error"))
+				})
+			})
+		})
+
+		Context("when the release requires a license key", func() {
+			BeforeEach(func() {
+				requiresLicenseKey = true
+				licenseTermsURL = "https://example.com/license-terms.pdf"
+			})
+
+			It("sets the license of the created release", func() {
+				err := creator.SetAttributes(pivnet.Release{ID: 1337})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(pivnetClient.UpdateReleaseAttributesCallCount()).To(Equal(1))
+				invokedProductSlug, releaseID, invokedAttributes := pivnetClient.UpdateReleaseAttributesArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(releaseID).To(Equal(1337))
+				Expect(invokedAttributes).To(Equal(gp.ReleaseAttributes{
+					RequiresLicenseKey: true,
+					LicenseTermsURL:    "https://example.com/license-terms.pdf",
+				}))
+			})
 		})
 
 		Context("when an error occurs", func() {
@@ -253,6 +353,21 @@ var _ = Describe("ReleaseCreator", func() {
 					_, err := creator.Create()
 					Expect(err).To(MatchError(fmt.Errorf("Release '%s' with version '%s' already exists.", productSlug, releaseVersion)))
 				})
+
+				Context("when a version suffix is provided", func() {
+					BeforeEach(func() {
+						params.VersionSuffix = "-LTS"
+					})
+
+					It("returns an error naming the affixes", func() {
+						_, err := creator.Create()
+						Expect(err).To(MatchError(fmt.Errorf(
+							"Release '%s' with version '%s' already exists (version_prefix: '', version_suffix: '-LTS').",
+							productSlug,
+							releaseVersion,
+						)))
+					})
+				})
 			})
 		})
 
@@ -278,6 +393,85 @@ var _ = Describe("ReleaseCreator", func() {
 			})
 		})
 
+		Context("when requiring the version to be greater than the latest", func() {
+			BeforeEach(func() {
+				params.RequireVersionGreaterThanLatest = true
+
+				existingReleases = []pivnet.Release{
+					{ID: 1, Version: "1.10.2", ReleaseType: releaseType},
+					{ID: 2, Version: "1.8.1", ReleaseType: releaseType},
+					{ID: 3, Version: "2.0.0", ReleaseType: "other-release-type"},
+					{ID: 4, Version: "not-semver", ReleaseType: releaseType},
+				}
+				pivnetClient.ReleasesForProductSlugReturns(existingReleases, nil)
+
+				fakeSemverConverter.ToValidSemverStub = func(input string) (semver.Version, error) {
+					return semver.Parse(input)
+				}
+
+				sourceVersion = ""
+			})
+
+			Context("when the version is greater than the latest of the same release type", func() {
+				BeforeEach(func() {
+					releaseVersion = "1.10.3"
+				})
+
+				It("creates the release", func() {
+					_, err := creator.Create()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.CreateReleaseCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the version is lower than the latest of the same release type", func() {
+				BeforeEach(func() {
+					releaseVersion = "1.9.0"
+				})
+
+				It("returns an error without creating the release", func() {
+					_, err := creator.Create()
+					Expect(err).To(MatchError(
+						"provided version: '1.9.0' must be greater than latest 'some-release-type' release version: '1.10.2'",
+					))
+
+					Expect(pivnetClient.CreateReleaseCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the version is not valid semver", func() {
+				BeforeEach(func() {
+					releaseVersion = "not-semver-either"
+				})
+
+				It("returns an error", func() {
+					_, err := creator.Create()
+					Expect(err).To(HaveOccurred())
+				})
+			})
+		})
+
+		Context("when custom metadata is provided", func() {
+			BeforeEach(func() {
+				customMetadata = map[string]string{"build_id": "1234"}
+			})
+
+			It("appends it to the release description", func() {
+				_, err := creator.Create()
+				Expect(err).NotTo(HaveOccurred())
+
+				config := pivnetClient.CreateReleaseArgsForCall(0)
+				Expect(config.Description).To(Equal(
+					metadata.EncodeCustomMetadata("wow, a description", customMetadata),
+				))
+
+				description, decoded := metadata.DecodeCustomMetadata(config.Description)
+				Expect(description).To(Equal("wow, a description"))
+				Expect(decoded).To(Equal(customMetadata))
+			})
+		})
+
 		Context("When copying metadata", func() {
 			BeforeEach(func() {
 				copyMetadata = true
@@ -344,4 +538,153 @@ var _ = Describe("ReleaseCreator", func() {
 			})
 		})
 	})
+
+	Describe("ValidateVersion", func() {
+		BeforeEach(func() {
+			params = concourse.OutParams{VersionSuffix: "-LTS"}
+			releaseVersion = "1.8.1-LTS"
+		})
+
+		JustBeforeEach(func() {
+			meta := metadata.Metadata{
+				Release: &metadata.Release{
+					Version: releaseVersion,
+				},
+			}
+
+			creator = release.NewReleaseCreator(
+				pivnetClient,
+				fakeSemverConverter,
+				fakeLogger,
+				meta,
+				params,
+				concourse.Source{},
+				"/some/sources/dir",
+				productSlug,
+			)
+		})
+
+		It("does not fail when no release has the version", func() {
+			err := creator.ValidateVersion()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(pivnetClient.ReleasesForProductSlugArgsForCall(0)).To(Equal(productSlug))
+		})
+
+		Context("when a release already has the affixed version", func() {
+			BeforeEach(func() {
+				pivnetClient.ReleasesForProductSlugReturns([]pivnet.Release{
+					{ID: 1234, Version: "1.8.1"},
+					{ID: 1235, Version: "1.8.1-LTS"},
+				}, nil)
+			})
+
+			It("returns an error naming the affixes", func() {
+				err := creator.ValidateVersion()
+				Expect(err).To(MatchError(fmt.Errorf(
+					"Release '%s' with version '1.8.1-LTS' already exists (version_prefix: '', version_suffix: '-LTS').",
+					productSlug,
+				)))
+			})
+
+			Context("when override is set", func() {
+				BeforeEach(func() {
+					params.Override = true
+				})
+
+				It("does not fail, as the release is replaced", func() {
+					err := creator.ValidateVersion()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.ReleasesForProductSlugCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when the releases cannot be listed", func() {
+			BeforeEach(func() {
+				pivnetClient.ReleasesForProductSlugReturns(nil, errors.New("some list error"))
+			})
+
+			It("returns the error", func() {
+				err := creator.ValidateVersion()
+				Expect(err).To(MatchError("some list error"))
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		JustBeforeEach(func() {
+			meta := metadata.Metadata{
+				Release: &metadata.Release{
+					Version: releaseVersion,
+				},
+			}
+
+			creator = release.NewReleaseCreator(
+				pivnetClient,
+				fakeSemverConverter,
+				fakeLogger,
+				meta,
+				concourse.OutParams{},
+				concourse.Source{},
+				"/some/sources/dir",
+				productSlug,
+			)
+		})
+
+		Context("when the release of the previous put exists", func() {
+			BeforeEach(func() {
+				pivnetClient.ReleasesForProductSlugReturns([]pivnet.Release{
+					{ID: 1234, Version: "1.8.1"},
+					{ID: 1337, Version: releaseVersion},
+				}, nil)
+			})
+
+			It("returns it", func() {
+				r, found, err := creator.Resume(1337)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(found).To(BeTrue())
+				Expect(r.ID).To(Equal(1337))
+				Expect(pivnetClient.ReleasesForProductSlugArgsForCall(0)).To(Equal(productSlug))
+				Expect(pivnetClient.CreateReleaseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the release no longer exists", func() {
+			It("returns false", func() {
+				_, found, err := creator.Resume(1337)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when a release with the ID has a different version", func() {
+			BeforeEach(func() {
+				pivnetClient.ReleasesForProductSlugReturns([]pivnet.Release{
+					{ID: 1337, Version: "1.8.2"},
+				}, nil)
+			})
+
+			It("returns false", func() {
+				_, found, err := creator.Resume(1337)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when the releases cannot be listed", func() {
+			BeforeEach(func() {
+				pivnetClient.ReleasesForProductSlugReturns(nil, errors.New("some list error"))
+			})
+
+			It("returns the error", func() {
+				_, _, err := creator.Resume(1337)
+				Expect(err).To(MatchError("some list error"))
+			})
+		})
+	})
 })
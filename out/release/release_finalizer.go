@@ -7,6 +7,7 @@ import (
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
 	"github.com/pivotal-cf/pivnet-resource/versions"
 )
 
@@ -17,6 +18,7 @@ type ReleaseFinalizer struct {
 	params      concourse.OutParams
 	sourcesDir  string
 	productSlug string
+	source      concourse.Source
 }
 
 func NewFinalizer(
@@ -26,6 +28,7 @@ func NewFinalizer(
 	metadata metadata.Metadata,
 	sourcesDir,
 	productSlug string,
+	source concourse.Source,
 ) ReleaseFinalizer {
 	return ReleaseFinalizer{
 		pivnet:      pivnetClient,
@@ -34,12 +37,14 @@ func NewFinalizer(
 		metadata:    metadata,
 		sourcesDir:  sourcesDir,
 		productSlug: productSlug,
+		source:      source,
 	}
 }
 
 //go:generate counterfeiter --fake-name FinalizerClient . finalizerClient
 type finalizerClient interface {
 	GetRelease(productSlug string, releaseVersion string) (pivnet.Release, error)
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
 }
 
 func (rf ReleaseFinalizer) Finalize(productSlug string, releaseVersion string) (concourse.OutResponse, error) {
@@ -53,11 +58,13 @@ func (rf ReleaseFinalizer) Finalize(productSlug string, releaseVersion string) (
 		return concourse.OutResponse{}, err // this will never return an error
 	}
 
+	description, customMetadata := metadata.DecodeCustomMetadata(newRelease.Description)
+
 	metadata := []concourse.Metadata{
 		{Name: "version", Value: newRelease.Version},
 		{Name: "release_type", Value: string(newRelease.ReleaseType)},
 		{Name: "release_date", Value: newRelease.ReleaseDate},
-		{Name: "description", Value: newRelease.Description},
+		{Name: "description", Value: description},
 		{Name: "release_notes_url", Value: newRelease.ReleaseNotesURL},
 		{Name: "availability", Value: newRelease.Availability},
 		{Name: "controlled", Value: fmt.Sprintf("%t", newRelease.Controlled)},
@@ -73,10 +80,23 @@ func (rf ReleaseFinalizer) Finalize(productSlug string, releaseVersion string) (
 			concourse.Metadata{Name: "eula_slug", Value: newRelease.EULA.Slug})
 	}
 
+	metadata = append(metadata, concourse.CustomMetadata(customMetadata)...)
+
+	version := concourse.ReleaseVersion(outputVersion, newRelease, rf.source)
+
+	// The version must match the one check emits for the release, which
+	// fingerprints its product files.
+	if rf.source.ProductFilesFingerprint {
+		productFiles, err := rf.pivnet.ProductFilesForRelease(productSlug, newRelease.ID)
+		if err != nil {
+			return concourse.OutResponse{}, err
+		}
+
+		version.ProductFilesFingerprint = resolution.ProductFilesFingerprint(productFiles)
+	}
+
 	return concourse.OutResponse{
-		Version: concourse.Version{
-			ProductVersion: outputVersion,
-		},
+		Version:  version,
 		Metadata: metadata,
 	}, nil
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 	"github.com/pivotal-cf/pivnet-resource/out/release"
 	"github.com/pivotal-cf/pivnet-resource/out/release/releasefakes"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -31,6 +32,8 @@ var _ = Describe("ReleaseFinalizer", func() {
 
 			releaseErr error
 
+			source concourse.Source
+
 			finalizer release.ReleaseFinalizer
 		)
 
@@ -64,6 +67,7 @@ var _ = Describe("ReleaseFinalizer", func() {
 			}
 
 			releaseErr = nil
+			source = concourse.Source{}
 		})
 
 		JustBeforeEach(func() {
@@ -74,6 +78,7 @@ var _ = Describe("ReleaseFinalizer", func() {
 				mdata,
 				"/some/sources/dir",
 				productSlug,
+				source,
 			)
 
 			fakePivnet.GetReleaseReturns(pivnetRelease, releaseErr)
@@ -92,6 +97,111 @@ var _ = Describe("ReleaseFinalizer", func() {
 			Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "eula_slug", Value: "a_eula_slug"}))
 		})
 
+		Context("when version metadata is requested", func() {
+			BeforeEach(func() {
+				source.VersionMetadata = true
+				pivnetRelease.ReleaseType = "Security Release"
+			})
+
+			It("includes the release type and EULA slug in the version", func() {
+				response, err := finalizer.Finalize(productSlug, pivnetRelease.Version)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response.Version).To(Equal(concourse.Version{
+					ProductVersion: "some-version#some-new-time",
+					ReleaseType:    "Security Release",
+					EULASlug:       "a_eula_slug",
+				}))
+			})
+		})
+
+		Context("when a EULA fingerprint is requested", func() {
+			BeforeEach(func() {
+				source.EULAFingerprint = true
+			})
+
+			It("includes the EULA slug in the version", func() {
+				response, err := finalizer.Finalize(productSlug, pivnetRelease.Version)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response.Version).To(Equal(concourse.Version{
+					ProductVersion: "some-version#some-new-time",
+					EULASlug:       "a_eula_slug",
+				}))
+			})
+		})
+
+		Context("when the release ID is requested", func() {
+			BeforeEach(func() {
+				source.VersionReleaseID = true
+			})
+
+			It("includes the release ID in the version", func() {
+				response, err := finalizer.Finalize(productSlug, pivnetRelease.Version)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response.Version).To(Equal(concourse.Version{
+					ProductVersion: "some-version#some-new-time",
+					ReleaseID:      "1337",
+				}))
+			})
+		})
+
+		Context("when a product files fingerprint is requested", func() {
+			var (
+				productFiles []pivnet.ProductFile
+			)
+
+			BeforeEach(func() {
+				source.ProductFilesFingerprint = true
+
+				productFiles = []pivnet.ProductFile{{ID: 1234, SHA256: "some-sha256"}}
+				fakePivnet.ProductFilesForReleaseReturns(productFiles, nil)
+			})
+
+			It("includes the fingerprint of the product files of the release in the version", func() {
+				response, err := finalizer.Finalize(productSlug, pivnetRelease.Version)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response.Version).To(Equal(concourse.Version{
+					ProductVersion:          "some-version#some-new-time",
+					ProductFilesFingerprint: resolution.ProductFilesFingerprint(productFiles),
+				}))
+
+				invokedProductSlug, invokedReleaseID := fakePivnet.ProductFilesForReleaseArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(invokedReleaseID).To(Equal(1337))
+			})
+
+			Context("when getting the product files returns an error", func() {
+				BeforeEach(func() {
+					fakePivnet.ProductFilesForReleaseReturns(nil, errors.New("product files error"))
+				})
+
+				It("forwards the error", func() {
+					_, err := finalizer.Finalize(productSlug, pivnetRelease.Version)
+					Expect(err).To(MatchError("product files error"))
+				})
+			})
+		})
+
+		Context("when the release description contains custom metadata", func() {
+			BeforeEach(func() {
+				pivnetRelease.Description = metadata.EncodeCustomMetadata(
+					"some description",
+					map[string]string{"build_id": "1234"},
+				)
+			})
+
+			It("returns the description and custom metadata separately", func() {
+				response, err := finalizer.Finalize(productSlug, pivnetRelease.Version)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "description", Value: "some description"}))
+				Expect(response.Metadata).To(ContainElement(concourse.Metadata{Name: "custom_metadata.build_id", Value: "1234"}))
+			})
+		})
+
 		Context("when getting the release returns an error", func() {
 			BeforeEach(func() {
 				releaseErr = errors.New("release error")
@@ -0,0 +1,216 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/capabilities"
+	"github.com/pivotal-cf/pivnet-resource/logging"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"gopkg.in/yaml.v2"
+)
+
+type ReleaseSnapshotter struct {
+	logger       logger.Logger
+	pivnet       releaseSnapshotterClient
+	sourcesDir   string
+	snapshotFile string
+	productSlug  string
+}
+
+func NewReleaseSnapshotter(
+	logger logger.Logger,
+	pivnetClient releaseSnapshotterClient,
+	sourcesDir string,
+	snapshotFile string,
+	productSlug string,
+) ReleaseSnapshotter {
+	return ReleaseSnapshotter{
+		logger:       logger,
+		pivnet:       pivnetClient,
+		sourcesDir:   sourcesDir,
+		snapshotFile: snapshotFile,
+		productSlug:  productSlug,
+	}
+}
+
+//go:generate counterfeiter --fake-name ReleaseSnapshotterClient . releaseSnapshotterClient
+type releaseSnapshotterClient interface {
+	GetReleaseByID(productSlug string, releaseID int) (pivnet.Release, error)
+	ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
+	FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error)
+	DependencySpecifiers(productSlug string, releaseID int) ([]pivnet.DependencySpecifier, error)
+	UpgradePathSpecifiers(productSlug string, releaseID int) ([]pivnet.UpgradePathSpecifier, error)
+}
+
+// Snapshot fetches the release back from Pivotal Network once it has been
+// published and writes it to the snapshot file, in the format of the
+// metadata file, so that later steps and audits see what Pivotal Network
+// recorded rather than what was requested. The snapshot is written as JSON if
+// the snapshot file has a .json extension and as YAML otherwise.
+func (rs ReleaseSnapshotter) Snapshot(release pivnet.Release) error {
+	rs.logger.Info(fmt.Sprintf(
+		"Fetching published release: '%s' with ID: %d for snapshot",
+		release.Version,
+		release.ID,
+	))
+
+	published, err := rs.pivnet.GetReleaseByID(rs.productSlug, release.ID)
+	if err != nil {
+		return err
+	}
+
+	description, customMetadata := metadata.DecodeCustomMetadata(published.Description)
+
+	m := metadata.Metadata{
+		Release: &metadata.Release{
+			ID:                    published.ID,
+			Version:               published.Version,
+			ReleaseType:           string(published.ReleaseType),
+			ReleaseDate:           published.ReleaseDate,
+			Description:           description,
+			ReleaseNotesURL:       published.ReleaseNotesURL,
+			Availability:          published.Availability,
+			Controlled:            published.Controlled,
+			ECCN:                  published.ECCN,
+			LicenseException:      published.LicenseException,
+			EndOfSupportDate:      published.EndOfSupportDate,
+			EndOfGuidanceDate:     published.EndOfGuidanceDate,
+			EndOfAvailabilityDate: published.EndOfAvailabilityDate,
+			CustomMetadata:        customMetadata,
+		},
+	}
+
+	if published.EULA != nil {
+		m.Release.EULASlug = published.EULA.Slug
+	}
+
+	productFiles, err := rs.pivnet.ProductFilesForRelease(rs.productSlug, published.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, pf := range productFiles {
+		m.Release.ProductFiles = append(m.Release.ProductFiles, metadata.ReleaseProductFile{
+			ID: pf.ID,
+		})
+
+		m.ProductFiles = append(m.ProductFiles, snapshotProductFile(pf))
+	}
+
+	fileGroups, err := rs.pivnet.FileGroupsForRelease(rs.productSlug, published.ID)
+	err = rs.skipUnsupported(capabilities.FileGroups, err, &m)
+	if err != nil {
+		return err
+	}
+
+	for _, fg := range fileGroups {
+		mfg := metadata.FileGroup{
+			ID:   fg.ID,
+			Name: fg.Name,
+		}
+
+		for _, pf := range fg.ProductFiles {
+			mfg.ProductFiles = append(mfg.ProductFiles, metadata.FileGroupProductFile{
+				ID: pf.ID,
+			})
+		}
+
+		m.FileGroups = append(m.FileGroups, mfg)
+	}
+
+	dependencySpecifiers, err := rs.pivnet.DependencySpecifiers(rs.productSlug, published.ID)
+	err = rs.skipUnsupported(capabilities.DependencySpecifiers, err, &m)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dependencySpecifiers {
+		m.DependencySpecifiers = append(m.DependencySpecifiers, metadata.DependencySpecifier{
+			ID:          d.ID,
+			Specifier:   d.Specifier,
+			ProductSlug: d.Product.Slug,
+		})
+	}
+
+	upgradePathSpecifiers, err := rs.pivnet.UpgradePathSpecifiers(rs.productSlug, published.ID)
+	err = rs.skipUnsupported(capabilities.UpgradePathSpecifiers, err, &m)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range upgradePathSpecifiers {
+		m.UpgradePathSpecifiers = append(m.UpgradePathSpecifiers, metadata.UpgradePathSpecifier{
+			ID:        u.ID,
+			Specifier: u.Specifier,
+		})
+	}
+
+	return rs.write(m)
+}
+
+// skipUnsupported returns nil, recording the feature as unsupported in the
+// snapshot, if err shows that the feature is not available to the product or
+// token. Otherwise it returns err.
+func (rs ReleaseSnapshotter) skipUnsupported(feature string, err error, m *metadata.Metadata) error {
+	if err == nil || !capabilities.IsUnsupported(feature, err) {
+		return err
+	}
+
+	logging.Warn(rs.logger, fmt.Sprintf(
+		"%s are not available to this token and will be missing from the snapshot: %s",
+		feature,
+		err.Error(),
+	))
+	m.UnsupportedFeatures = append(m.UnsupportedFeatures, feature)
+
+	return nil
+}
+
+func (rs ReleaseSnapshotter) write(m metadata.Metadata) error {
+	var b []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(rs.snapshotFile), ".json") {
+		b, err = json.Marshal(m)
+	} else {
+		b, err = yaml.Marshal(m)
+	}
+	if err != nil {
+		// Untested as it is too hard to force marshalling to return an error
+		return err
+	}
+
+	snapshotPath := filepath.Join(rs.sourcesDir, rs.snapshotFile)
+
+	rs.logger.Info(fmt.Sprintf("Writing snapshot of published release to: '%s'", snapshotPath))
+
+	err = os.MkdirAll(filepath.Dir(snapshotPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(snapshotPath, b, 0644)
+}
+
+func snapshotProductFile(pf pivnet.ProductFile) metadata.ProductFile {
+	return metadata.ProductFile{
+		ID:                 pf.ID,
+		File:               pf.Name,
+		Description:        pf.Description,
+		AWSObjectKey:       pf.AWSObjectKey,
+		FileType:           pf.FileType,
+		FileVersion:        pf.FileVersion,
+		SHA256:             pf.SHA256,
+		MD5:                pf.MD5,
+		DocsURL:            pf.DocsURL,
+		SystemRequirements: pf.SystemRequirements,
+		Platforms:          pf.Platforms,
+		IncludedFiles:      pf.IncludedFiles,
+	}
+}
@@ -0,0 +1,186 @@
+package release_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/out/release"
+	"github.com/pivotal-cf/pivnet-resource/out/release/releasefakes"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReleaseSnapshotter", func() {
+	Describe("Snapshot", func() {
+		var (
+			fakeLogger logger.Logger
+			fakePivnet *releasefakes.ReleaseSnapshotterClient
+
+			sourcesDir   string
+			snapshotFile string
+
+			pivnetRelease pivnet.Release
+			releaseErr    error
+
+			snapshotter release.ReleaseSnapshotter
+		)
+
+		BeforeEach(func() {
+			logger := log.New(GinkgoWriter, "", log.LstdFlags)
+			fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+			fakePivnet = &releasefakes.ReleaseSnapshotterClient{}
+
+			var err error
+			sourcesDir, err = ioutil.TempDir("", "pivnet-resource")
+			Expect(err).NotTo(HaveOccurred())
+
+			snapshotFile = filepath.Join("snapshot", "release.yml")
+
+			pivnetRelease = pivnet.Release{
+				ID:           1337,
+				Version:      "some-version",
+				ReleaseType:  "Minor Release",
+				Availability: "All Users",
+				EULA:         &pivnet.EULA{Slug: "some-eula"},
+			}
+			releaseErr = nil
+
+			fakePivnet.ProductFilesForReleaseReturns([]pivnet.ProductFile{
+				{ID: 1, Name: "some-file", AWSObjectKey: "product-files/some-file", SHA256: "sha256-1"},
+			}, nil)
+			fakePivnet.FileGroupsForReleaseReturns([]pivnet.FileGroup{
+				{ID: 2, Name: "some-file-group", ProductFiles: []pivnet.ProductFile{{ID: 1}}},
+			}, nil)
+			fakePivnet.DependencySpecifiersReturns([]pivnet.DependencySpecifier{
+				{ID: 3, Specifier: "1.2.*", Product: pivnet.Product{Slug: "some-dependent-product"}},
+			}, nil)
+			fakePivnet.UpgradePathSpecifiersReturns([]pivnet.UpgradePathSpecifier{
+				{ID: 4, Specifier: "1.1.*"},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			fakePivnet.GetReleaseByIDReturns(pivnetRelease, releaseErr)
+
+			snapshotter = release.NewReleaseSnapshotter(
+				fakeLogger,
+				fakePivnet,
+				sourcesDir,
+				snapshotFile,
+				"some-product-slug",
+			)
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(sourcesDir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		readSnapshot := func() metadata.Metadata {
+			b, err := ioutil.ReadFile(filepath.Join(sourcesDir, snapshotFile))
+			Expect(err).NotTo(HaveOccurred())
+
+			var m metadata.Metadata
+			err = yaml.Unmarshal(b, &m)
+			Expect(err).NotTo(HaveOccurred())
+
+			return m
+		}
+
+		It("fetches the release by its ID", func() {
+			err := snapshotter.Snapshot(pivnet.Release{ID: 1337, Version: "some-version"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakePivnet.GetReleaseByIDCallCount()).To(Equal(1))
+			invokedProductSlug, invokedReleaseID := fakePivnet.GetReleaseByIDArgsForCall(0)
+			Expect(invokedProductSlug).To(Equal("some-product-slug"))
+			Expect(invokedReleaseID).To(Equal(1337))
+		})
+
+		It("writes the release as recorded by Pivotal Network in the format of the metadata file", func() {
+			err := snapshotter.Snapshot(pivnetRelease)
+			Expect(err).NotTo(HaveOccurred())
+
+			m := readSnapshot()
+			Expect(m.Release.ID).To(Equal(1337))
+			Expect(m.Release.Version).To(Equal("some-version"))
+			Expect(m.Release.ReleaseType).To(Equal("Minor Release"))
+			Expect(m.Release.Availability).To(Equal("All Users"))
+			Expect(m.Release.EULASlug).To(Equal("some-eula"))
+			Expect(m.Release.ProductFiles).To(Equal([]metadata.ReleaseProductFile{{ID: 1}}))
+
+			Expect(m.ProductFiles).To(Equal([]metadata.ProductFile{
+				{ID: 1, File: "some-file", AWSObjectKey: "product-files/some-file", SHA256: "sha256-1"},
+			}))
+			Expect(m.FileGroups).To(Equal([]metadata.FileGroup{
+				{ID: 2, Name: "some-file-group", ProductFiles: []metadata.FileGroupProductFile{{ID: 1}}},
+			}))
+			Expect(m.DependencySpecifiers).To(Equal([]metadata.DependencySpecifier{
+				{ID: 3, Specifier: "1.2.*", ProductSlug: "some-dependent-product"},
+			}))
+			Expect(m.UpgradePathSpecifiers).To(Equal([]metadata.UpgradePathSpecifier{
+				{ID: 4, Specifier: "1.1.*"},
+			}))
+		})
+
+		Context("when the snapshot file has a .json extension", func() {
+			BeforeEach(func() {
+				snapshotFile = "release.json"
+			})
+
+			It("writes the snapshot as JSON", func() {
+				err := snapshotter.Snapshot(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				b, err := ioutil.ReadFile(filepath.Join(sourcesDir, snapshotFile))
+				Expect(err).NotTo(HaveOccurred())
+
+				var m metadata.Metadata
+				err = json.Unmarshal(b, &m)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(m.Release.Version).To(Equal("some-version"))
+			})
+		})
+
+		Context("when file groups are not available to the token", func() {
+			BeforeEach(func() {
+				fakePivnet.FileGroupsForReleaseReturns(nil, pivnet.ErrPivnetOther{ResponseCode: http.StatusForbidden})
+			})
+
+			It("records them as unsupported in the snapshot", func() {
+				err := snapshotter.Snapshot(pivnetRelease)
+				Expect(err).NotTo(HaveOccurred())
+
+				m := readSnapshot()
+				Expect(m.FileGroups).To(BeEmpty())
+				Expect(m.UnsupportedFeatures).To(Equal([]string{"file_groups"}))
+			})
+		})
+
+		Context("when fetching the release returns an error", func() {
+			BeforeEach(func() {
+				releaseErr = errors.New("release error")
+			})
+
+			It("returns the error without writing a snapshot", func() {
+				err := snapshotter.Snapshot(pivnetRelease)
+				Expect(err).To(MatchError("release error"))
+
+				Expect(filepath.Join(sourcesDir, snapshotFile)).NotTo(BeAnExistingFile())
+			})
+		})
+	})
+})
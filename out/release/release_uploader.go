@@ -3,24 +3,48 @@ package release
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/gp"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/sbom"
 )
 
 type ReleaseUploader struct {
-	s3            s3Client
-	pivnet        uploadClient
-	logger        logger.Logger
-	sha256Summer  sha256Summer
-	md5Summer     md5Summer
-	metadata      metadata.Metadata
-	sourcesDir    string
-	productSlug   string
-	asyncTimeout  time.Duration
-	pollFrequency time.Duration
+	s3                  s3Client
+	pivnet              uploadClient
+	logger              logger.Logger
+	sha256Summer        sha256Summer
+	md5Summer           md5Summer
+	chunkManifestWriter chunkManifestWriter
+	signatureWriter     signatureWriter
+	includedFilesLister includedFilesLister
+	sbomDetector        sbomDetector
+	checksumSummer      checksumSummer
+	metadata            metadata.Metadata
+	sourcesDir          string
+	productSlug         string
+	docsURLTemplate     string
+	cleanupStaging      bool
+	asyncTimeout        time.Duration
+	pollFrequency       time.Duration
+	attachConcurrency   int
+	uploadState         uploadState
+}
+
+// pendingAttachment is a product file which has been created, or found with
+// the same content, and is yet to be attached to the release.
+type pendingAttachment struct {
+	exactGlob    string
+	fileSHA256   string
+	awsObjectKey string
+	uploadAs     string
+	productFile  pivnet.ProductFile
+	created      bool
 }
 
 type ProductFileMetadata struct {
@@ -32,6 +56,8 @@ type ProductFileMetadata struct {
 	includedFiles      []string
 	uploadAs           string
 	fileType           string
+	attributes         gp.ProductFileAttributes
+	checksums          map[string]string
 }
 
 //go:generate counterfeiter --fake-name UploadClient . uploadClient
@@ -42,12 +68,14 @@ type uploadClient interface {
 	ProductFiles(productSlug string) ([]pivnet.ProductFile, error)
 	ProductFile(productSlug string, productFileID int) (pivnet.ProductFile, error)
 	DeleteProductFile(productSlug string, releaseID int) (pivnet.ProductFile, error)
+	UpdateProductFileAttributes(productSlug string, productFileID int, attributes gp.ProductFileAttributes) error
 }
 
 //go:generate counterfeiter --fake-name S3Client . s3Client
 type s3Client interface {
 	ComputeAWSObjectKey(string) (string, string, error)
 	UploadFile(string) error
+	DeleteFile(string) error
 }
 
 //go:generate counterfeiter --fake-name Sha256Summer . sha256Summer
@@ -55,38 +83,118 @@ type sha256Summer interface {
 	SumFile(filepath string) (string, error)
 }
 
+// md5Summer may be nil, e.g. when fips is set, in which case files are
+// uploaded without an MD5.
+//
 //go:generate counterfeiter --fake-name Md5Summer . md5Summer
 type md5Summer interface {
 	SumFile(filepath string) (string, error)
 }
 
+//go:generate counterfeiter --fake-name ChunkManifestWriter . chunkManifestWriter
+type chunkManifestWriter interface {
+	WriteManifest(sourcesDir string, exactGlob string) (string, error)
+}
+
+//go:generate counterfeiter --fake-name SignatureWriter . signatureWriter
+type signatureWriter interface {
+	WriteSignature(sourcesDir string, exactGlob string) (string, error)
+}
+
+//go:generate counterfeiter --fake-name IncludedFilesLister . includedFilesLister
+type includedFilesLister interface {
+	List(sourcesDir string, exactGlob string) ([]string, error)
+}
+
+//go:generate counterfeiter --fake-name SBOMDetector . sbomDetector
+type sbomDetector interface {
+	Detect(path string) (string, error)
+}
+
+//go:generate counterfeiter --fake-name UploadState . uploadState
+type uploadState interface {
+	Uploaded(file string, sha256 string) (int, bool)
+	RecordUploaded(file string, sha256 string, productFileID int) error
+}
+
+//go:generate counterfeiter --fake-name ChecksumSummer . checksumSummer
+type checksumSummer interface {
+	SumFile(filepath string) (map[string]string, error)
+}
+
 func NewReleaseUploader(
 	s3 s3Client,
 	pivnet uploadClient,
 	logger logger.Logger,
 	sha256Summer sha256Summer,
 	md5Summer md5Summer,
+	chunkManifestWriter chunkManifestWriter,
+	signatureWriter signatureWriter,
+	includedFilesLister includedFilesLister,
+	sbomDetector sbomDetector,
+	checksumSummer checksumSummer,
 	metadata metadata.Metadata,
 	sourcesDir,
 	productSlug string,
+	docsURLTemplate string,
+	cleanupStaging bool,
 	asyncTimeout time.Duration,
 	pollFrequency time.Duration,
+	attachConcurrency int,
+	uploadState uploadState,
 ) ReleaseUploader {
+	if attachConcurrency < 1 {
+		attachConcurrency = 1
+	}
+
 	return ReleaseUploader{
-		s3:            s3,
-		pivnet:        pivnet,
-		logger:        logger,
-		sha256Summer:  sha256Summer,
-		md5Summer:     md5Summer,
-		metadata:      metadata,
-		sourcesDir:    sourcesDir,
-		productSlug:   productSlug,
-		asyncTimeout:  asyncTimeout,
-		pollFrequency: pollFrequency,
+		s3:                  s3,
+		pivnet:              pivnet,
+		logger:              logger,
+		sha256Summer:        sha256Summer,
+		md5Summer:           md5Summer,
+		chunkManifestWriter: chunkManifestWriter,
+		signatureWriter:     signatureWriter,
+		includedFilesLister: includedFilesLister,
+		sbomDetector:        sbomDetector,
+		checksumSummer:      checksumSummer,
+		metadata:            metadata,
+		sourcesDir:          sourcesDir,
+		productSlug:         productSlug,
+		docsURLTemplate:     docsURLTemplate,
+		cleanupStaging:      cleanupStaging,
+		asyncTimeout:        asyncTimeout,
+		pollFrequency:       pollFrequency,
+		attachConcurrency:   attachConcurrency,
+		uploadState:         uploadState,
 	}
 }
 
+// Upload uploads each file and attaches it to the release. If an upload state
+// is provided, files which it records as uploaded by a previous put are
+// skipped, and each file is recorded once it has been attached.
+//
+// Files are uploaded one at a time, and then attached up to
+// attachConcurrency at a time. Every file is attached even if attaching
+// another fails, and the failures are returned together, in the order of the
+// files.
 func (u ReleaseUploader) Upload(release pivnet.Release, exactGlobs []string) error {
+	exactGlobs, err := u.addChunkManifests(exactGlobs)
+	if err != nil {
+		return err
+	}
+
+	exactGlobs, err = u.addSBOMs(exactGlobs)
+	if err != nil {
+		return err
+	}
+
+	exactGlobs, err = u.addSignatures(exactGlobs)
+	if err != nil {
+		return err
+	}
+
+	var pending []pendingAttachment
 	for _, exactGlob := range exactGlobs {
 
 		awsObjectKey, _, err := u.s3.ComputeAWSObjectKey(exactGlob)
@@ -96,6 +204,29 @@ func (u ReleaseUploader) Upload(release pivnet.Release, exactGlobs []string) err
 
 		fileData := u.getFileData(exactGlob)
 
+		err = u.verifyChecksums(exactGlob, fileData)
+		if err != nil {
+			return err
+		}
+
+		var fileSHA256 string
+		if u.uploadState != nil {
+			fileSHA256, err = u.sha256Summer.SumFile(filepath.Join(u.sourcesDir, exactGlob))
+			if err != nil {
+				return err
+			}
+
+			productFileID, uploaded := u.uploadState.Uploaded(exactGlob, fileSHA256)
+			if uploaded {
+				u.logger.Info(fmt.Sprintf(
+					"File '%s' was uploaded and added as product file with ID: %d by a previous put, skipping",
+					exactGlob,
+					productFileID,
+				))
+				continue
+			}
+		}
+
 		productFiles, err := u.pivnet.ProductFiles(u.productSlug)
 		if err != nil {
 			return err
@@ -135,6 +266,11 @@ func (u ReleaseUploader) Upload(release pivnet.Release, exactGlobs []string) err
 				return err
 			}
 
+			fileData, err = u.addIncludedFiles(exactGlob, fileData)
+			if err != nil {
+				return err
+			}
+
 			productFileConfig, err := u.getProductFileConfig(exactGlob, awsObjectKey, fileData, release)
 			if err != nil {
 				return err
@@ -144,7 +280,6 @@ func (u ReleaseUploader) Upload(release pivnet.Release, exactGlobs []string) err
 			if err != nil {
 				return err
 			}
-
 		} else {
 			u.logger.Info(fmt.Sprintf(
 				"File '%s' already exists, skipping creation",
@@ -152,26 +287,270 @@ func (u ReleaseUploader) Upload(release pivnet.Release, exactGlobs []string) err
 			))
 		}
 
+		// Extra attributes are set on an existing product file too, so that
+		// putting the release again sets them on a product file whose
+		// attributes could not be set by the put which created it.
+		if !fileData.attributes.IsEmpty() {
+			u.logger.Info(fmt.Sprintf(
+				"Setting extra attributes for product file: '%s'",
+				fileData.uploadAs,
+			))
+
+			err = u.pivnet.UpdateProductFileAttributes(u.productSlug, productFile.ID, fileData.attributes)
+			if err != nil {
+				return fmt.Errorf(
+					"failed to set extra attributes of product file '%s' (ID: %d): %s",
+					fileData.uploadAs,
+					productFile.ID,
+					err.Error(),
+				)
+			}
+		}
+
+		pending = append(pending, pendingAttachment{
+			exactGlob:    exactGlob,
+			fileSHA256:   fileSHA256,
+			awsObjectKey: awsObjectKey,
+			uploadAs:     fileData.uploadAs,
+			productFile:  productFile,
+			created:      !foundMatchingFile,
+		})
+	}
+
+	return u.attachAll(release, pending)
+}
+
+// attachAll attaches each pending product file to the release, up to
+// attachConcurrency at a time, as Pivotal Network cannot attach several in a
+// single request. The upload state is not safe for concurrent use, so
+// recording files in it is serialized.
+func (u ReleaseUploader) attachAll(release pivnet.Release, pending []pendingAttachment) error {
+	if len(pending) > 1 && u.attachConcurrency > 1 {
 		u.logger.Info(fmt.Sprintf(
-			"Adding product file: '%s' with ID: %d",
-			fileData.uploadAs,
-			productFile.ID,
+			"Attaching %d product files, up to %d at a time",
+			len(pending),
+			u.attachConcurrency,
 		))
+	}
+
+	errs := make([]error, len(pending))
+	slots := make(chan struct{}, u.attachConcurrency)
+	var recordMu sync.Mutex
+	var wg sync.WaitGroup
 
-		err = u.pivnet.AddProductFile(u.productSlug, release.ID, productFile.ID)
+	for i, p := range pending {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int, p pendingAttachment) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			errs[i] = u.attach(release, p, &recordMu)
+		}(i, p)
+	}
+
+	wg.Wait()
+
+	var problems []string
+	for i, err := range errs {
 		if err != nil {
-			return err
+			problems = append(problems, fmt.Sprintf("'%s': %s", pending[i].uploadAs, err.Error()))
 		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf(
+			"failed to attach %d of %d product files:\n  - %s",
+			len(problems),
+			len(pending),
+			strings.Join(problems, "\n  - "),
+		)
+	}
+
+	return nil
+}
+
+// attach attaches the product file to the release and waits for Pivotal
+// Network to ingest it.
+func (u ReleaseUploader) attach(release pivnet.Release, p pendingAttachment, recordMu *sync.Mutex) error {
+	u.logger.Info(fmt.Sprintf(
+		"Adding product file: '%s' with ID: %d",
+		p.uploadAs,
+		p.productFile.ID,
+	))
+
+	err := u.pivnet.AddProductFile(u.productSlug, release.ID, p.productFile.ID)
+	if err != nil {
+		return err
+	}
+
+	err = u.pollForProductFile(p.productFile)
+	if err != nil {
+		return fmt.Errorf("error while polling: %s", err)
+	}
 
-		err = u.pollForProductFile(productFile)
+	if u.uploadState != nil {
+		recordMu.Lock()
+		err = u.uploadState.RecordUploaded(p.exactGlob, p.fileSHA256, p.productFile.ID)
+		recordMu.Unlock()
 		if err != nil {
-			return fmt.Errorf("error while polling: %s", err)
+			return err
 		}
 	}
 
+	if p.created && u.cleanupStaging {
+		u.deleteStagingObject(p.exactGlob, p.awsObjectKey)
+	}
+
 	return nil
 }
 
+// addChunkManifests writes a chunk manifest for each file large enough to
+// require one, and returns the globs with the manifests appended so that they
+// are uploaded alongside the other product files.
+func (u ReleaseUploader) addChunkManifests(exactGlobs []string) ([]string, error) {
+	globs := append([]string{}, exactGlobs...)
+	for _, exactGlob := range exactGlobs {
+		manifestGlob, err := u.chunkManifestWriter.WriteManifest(u.sourcesDir, exactGlob)
+		if err != nil {
+			return nil, err
+		}
+
+		if manifestGlob != "" {
+			u.logger.Info(fmt.Sprintf(
+				"Wrote chunk manifest: '%s' for file: '%s'",
+				manifestGlob,
+				exactGlob,
+			))
+			globs = append(globs, manifestGlob)
+		}
+	}
+
+	return globs, nil
+}
+
+// addSBOMs returns the globs with the SBOM of each file in the metadata
+// appended, so that they are uploaded alongside the files they describe, and
+// signed like them. SBOMs which are not in a known format are refused.
+func (u ReleaseUploader) addSBOMs(exactGlobs []string) ([]string, error) {
+	globs := append([]string{}, exactGlobs...)
+
+	// An SBOM which is matched by the file glob itself is only uploaded once.
+	seen := map[string]bool{}
+	for _, exactGlob := range exactGlobs {
+		seen[exactGlob] = true
+	}
+
+	for _, exactGlob := range exactGlobs {
+		sbomGlob := ""
+		for _, f := range u.metadata.ProductFiles {
+			if f.File == exactGlob {
+				sbomGlob = f.SBOM
+			}
+		}
+
+		if sbomGlob == "" || seen[sbomGlob] {
+			continue
+		}
+		seen[sbomGlob] = true
+
+		format, err := u.sbomDetector.Detect(filepath.Join(u.sourcesDir, sbomGlob))
+		if err != nil {
+			return nil, fmt.Errorf("sbom of file '%s' is invalid: %s", exactGlob, err.Error())
+		}
+
+		u.logger.Info(fmt.Sprintf(
+			"Attaching %s SBOM: '%s' for file: '%s'",
+			format,
+			sbomGlob,
+			exactGlob,
+		))
+		globs = append(globs, sbomGlob)
+	}
+
+	return globs, nil
+}
+
+// addSignatures writes a detached signature for each file, including chunk
+// manifests, and returns the globs with the signatures appended so that they
+// are uploaded alongside the other product files.
+func (u ReleaseUploader) addSignatures(exactGlobs []string) ([]string, error) {
+	globs := append([]string{}, exactGlobs...)
+	for _, exactGlob := range exactGlobs {
+		signatureGlob, err := u.signatureWriter.WriteSignature(u.sourcesDir, exactGlob)
+		if err != nil {
+			return nil, err
+		}
+
+		if signatureGlob != "" {
+			u.logger.Info(fmt.Sprintf(
+				"Wrote signature: '%s' for file: '%s'",
+				signatureGlob,
+				exactGlob,
+			))
+			globs = append(globs, signatureGlob)
+		}
+	}
+
+	return globs, nil
+}
+
+// addIncludedFiles lists the top-level contents of the file as its included
+// files if it is an archive and none were provided in the metadata.
+func (u ReleaseUploader) addIncludedFiles(exactGlob string, fileData ProductFileMetadata) (ProductFileMetadata, error) {
+	if len(fileData.includedFiles) > 0 {
+		return fileData, nil
+	}
+
+	includedFiles, err := u.includedFilesLister.List(u.sourcesDir, exactGlob)
+	if err != nil {
+		return ProductFileMetadata{}, err
+	}
+
+	if len(includedFiles) > 0 {
+		u.logger.Info(fmt.Sprintf(
+			"Using included files: %s from the contents of file: '%s'",
+			strings.Join(includedFiles, ", "),
+			exactGlob,
+		))
+		fileData.includedFiles = includedFiles
+	}
+
+	return fileData, nil
+}
+
+// deleteStagingObject deletes the uploaded file from the bucket once Pivotal
+// Network has ingested it. The release is complete by this point, so failing
+// to delete the object is logged rather than failing the put.
+func (u ReleaseUploader) deleteStagingObject(exactGlob string, awsObjectKey string) {
+	u.logger.Info(fmt.Sprintf(
+		"Deleting ingested staging object: '%s'",
+		awsObjectKey,
+	))
+
+	err := u.s3.DeleteFile(exactGlob)
+	if err != nil {
+		u.logger.Info(fmt.Sprintf(
+			"Failed to delete staging object: '%s': %s",
+			awsObjectKey,
+			err.Error(),
+		))
+	}
+}
+
+// inProgressTransferStatuses are the file_transfer_status values of a product
+// file which Pivotal Network has not yet finished transferring and scanning.
+var inProgressTransferStatuses = map[string]bool{
+	"in_progress":  true,
+	"pending":      true,
+	"queued":       true,
+	"transferring": true,
+	"scanning":     true,
+}
+
+// pollForProductFile waits until Pivotal Network has ingested the product
+// file, so that the put only succeeds once the file can be downloaded.
 func (u ReleaseUploader) pollForProductFile(productFile pivnet.ProductFile) error {
 	u.logger.Info(fmt.Sprintf(
 		"Polling product file: '%s' for async transfer - will wait up to %v",
@@ -180,38 +559,48 @@ func (u ReleaseUploader) pollForProductFile(productFile pivnet.ProductFile) erro
 	))
 
 	timeoutTimer := time.NewTimer(u.asyncTimeout)
+	defer timeoutTimer.Stop()
+
 	pollTicker := time.NewTicker(u.pollFrequency)
+	defer pollTicker.Stop()
 
+	var status string
 	for {
 		select {
 		case <-timeoutTimer.C:
-			return fmt.Errorf("timed out")
+			return fmt.Errorf(
+				"timed out after %v waiting for product file: '%s' to be ingested (file_transfer_status: %s)",
+				u.asyncTimeout,
+				productFile.Name,
+				status,
+			)
 		case <-pollTicker.C:
 			pf, err := u.pivnet.ProductFile(u.productSlug, productFile.ID)
 			if err != nil {
 				return err
 			}
 
-			if pf.FileTransferStatus != "in_progress" {
+			status = pf.FileTransferStatus
+
+			if inProgressTransferStatuses[status] {
 				u.logger.Info(fmt.Sprintf(
-					"Product file: '%s' async transfer complete",
+					"Product file: '%s' async transfer incomplete (file_transfer_status: %s)",
 					productFile.Name,
+					status,
 				))
+				continue
+			}
 
-				timeoutTimer.Stop()
-				pollTicker.Stop()
-
-				if pf.FileTransferStatus != "complete" {
-					return fmt.Errorf("file_transfer_status: %s", pf.FileTransferStatus)
-				} else {
-					return nil
-				}
+			if status != "complete" {
+				return fmt.Errorf("file_transfer_status: %s", status)
 			}
 
 			u.logger.Info(fmt.Sprintf(
-				"Product file: '%s' async transfer incomplete",
+				"Product file: '%s' async transfer complete",
 				productFile.Name,
 			))
+
+			return nil
 		}
 	}
 }
@@ -242,6 +631,17 @@ func (u ReleaseUploader) getProductFileConfig(exactGlob string, awsObjectKey str
 	if fileData.fileVersion != "" {
 		fileVersion = fileData.fileVersion
 	}
+
+	docsURL := fileData.docsURL
+	if docsURL == "" && u.docsURLTemplate != "" {
+		docsURL = u.renderDocsURL(release.Version, fileData.uploadAs)
+		u.logger.Info(fmt.Sprintf(
+			"Using docs URL: '%s' from template for file: '%s'",
+			docsURL,
+			fileData.uploadAs,
+		))
+	}
+
 	productFileConfig := pivnet.CreateProductFileConfig{
 		ProductSlug:        u.productSlug,
 		Name:               fileData.uploadAs,
@@ -251,7 +651,7 @@ func (u ReleaseUploader) getProductFileConfig(exactGlob string, awsObjectKey str
 		MD5:                fileContentsMD5,
 		Description:        fileData.description,
 		FileType:           fileData.fileType,
-		DocsURL:            fileData.docsURL,
+		DocsURL:            docsURL,
 		SystemRequirements: fileData.systemRequirements,
 		Platforms:          fileData.platforms,
 		IncludedFiles:      fileData.includedFiles,
@@ -259,12 +659,36 @@ func (u ReleaseUploader) getProductFileConfig(exactGlob string, awsObjectKey str
 	return productFileConfig, err
 }
 
+// renderDocsURL substitutes the {product_slug}, {version} and {file_name}
+// placeholders in the docs URL template.
+func (u ReleaseUploader) renderDocsURL(version string, fileName string) string {
+	replacer := strings.NewReplacer(
+		"{product_slug}", u.productSlug,
+		"{version}", version,
+		"{file_name}", fileName,
+	)
+
+	return replacer.Replace(u.docsURLTemplate)
+}
+
 func (u ReleaseUploader) getFileData(exactGlob string) ProductFileMetadata {
 	var fileData ProductFileMetadata
 
 	fileData.uploadAs = filepath.Base(exactGlob)
 	fileData.fileType = "Software"
 
+	// SBOMs are labeled with their own file type and the file they describe,
+	// unless the metadata of the SBOM itself overrides them.
+	if f, ok := u.metadata.ProductFileForSBOM(exactGlob); ok {
+		describedAs := filepath.Base(f.File)
+		if f.UploadAs != "" {
+			describedAs = f.UploadAs
+		}
+
+		fileData.fileType = sbom.FileType
+		fileData.description = fmt.Sprintf("Software bill of materials of %s", describedAs)
+	}
+
 	for _, f := range u.metadata.ProductFiles {
 		if f.File == exactGlob {
 			u.logger.Info(fmt.Sprintf(
@@ -282,7 +706,9 @@ func (u ReleaseUploader) getFileData(exactGlob string) ProductFileMetadata {
 				fileData.uploadAs = f.UploadAs
 			}
 
-			fileData.description = f.Description
+			if f.Description != "" {
+				fileData.description = f.Description
+			}
 
 			if f.FileType != "" {
 				fileData.fileType = f.FileType
@@ -307,6 +733,15 @@ func (u ReleaseUploader) getFileData(exactGlob string) ProductFileMetadata {
 			if len(f.IncludedFiles) > 0 {
 				fileData.includedFiles = f.IncludedFiles
 			}
+
+			fileData.checksums = f.Checksums
+
+			fileData.attributes = gp.ProductFileAttributes{
+				PlatformArchitecture:  f.PlatformArchitecture,
+				IncludedOSSComponents: f.IncludedOSSComponents,
+				ECCN:                  f.ECCN,
+				LicenseException:      f.LicenseException,
+			}
 		} else {
 			u.logger.Info(fmt.Sprintf(
 				"exact glob '%s' does not match metadata file: '%s'",
@@ -318,6 +753,33 @@ func (u ReleaseUploader) getFileData(exactGlob string) ProductFileMetadata {
 	return fileData
 }
 
+// verifyChecksums computes the configured additional checksums of the file
+// and compares them against any expected values in the metadata. Pivotal
+// Network has no field for additional checksums so they are only logged.
+func (u ReleaseUploader) verifyChecksums(exactGlob string, fileData ProductFileMetadata) error {
+	sums, err := u.checksumSummer.SumFile(filepath.Join(u.sourcesDir, exactGlob))
+	if err != nil {
+		return err
+	}
+
+	for algorithm, actual := range sums {
+		u.logger.Info(fmt.Sprintf("%s %s is: %s", exactGlob, strings.ToUpper(algorithm), actual))
+
+		expected := fileData.checksums[algorithm]
+		if expected != "" && expected != actual {
+			return fmt.Errorf(
+				"%s comparison failed for file: '%s'. Expected (from metadata): '%s' - actual (from file): '%s'",
+				strings.ToUpper(algorithm),
+				exactGlob,
+				expected,
+				actual,
+			)
+		}
+	}
+
+	return nil
+}
+
 func (u ReleaseUploader) calculateHashes(fileName string) (string, string, error) {
 	fullFilepath := filepath.Join(u.sourcesDir, fileName)
 	fileContentsSHA256, err := u.sha256Summer.SumFile(fullFilepath)
@@ -325,6 +787,10 @@ func (u ReleaseUploader) calculateHashes(fileName string) (string, string, error
 		return "", "", err
 	}
 
+	if u.md5Summer == nil {
+		return fileContentsSHA256, "", nil
+	}
+
 	fileContentsMD5, err := u.md5Summer.SumFile(fullFilepath)
 	if err != nil {
 		return "", "", err
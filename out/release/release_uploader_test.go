@@ -2,12 +2,15 @@ package release_test
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
 	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/gp"
 	"github.com/pivotal-cf/pivnet-resource/metadata"
 	"github.com/pivotal-cf/pivnet-resource/out/release"
 	"github.com/pivotal-cf/pivnet-resource/out/release/releasefakes"
@@ -20,16 +23,25 @@ var _ = Describe("ReleaseUploader", func() {
 	var (
 		fakeLogger logger.Logger
 
-		s3Client      *releasefakes.S3Client
-		uploadClient  *releasefakes.UploadClient
-		sha256Summer  *releasefakes.Sha256Summer
-		md5Summer     *releasefakes.Md5Summer
-		pivnetRelease pivnet.Release
-		uploader      release.ReleaseUploader
-		asyncTimeout  time.Duration
-		pollFrequency time.Duration
-
-		productSlug string
+		s3Client            *releasefakes.S3Client
+		uploadClient        *releasefakes.UploadClient
+		sha256Summer        *releasefakes.Sha256Summer
+		md5Summer           *releasefakes.Md5Summer
+		chunkManifestWriter *releasefakes.ChunkManifestWriter
+		signatureWriter     *releasefakes.SignatureWriter
+		includedFilesLister *releasefakes.IncludedFilesLister
+		sbomDetector        *releasefakes.SBOMDetector
+		checksumSummer      *releasefakes.ChecksumSummer
+		pivnetRelease       pivnet.Release
+		uploader            release.ReleaseUploader
+		asyncTimeout        time.Duration
+		pollFrequency       time.Duration
+		attachConcurrency   int
+		uploadState         *releasefakes.UploadState
+
+		productSlug     string
+		docsURLTemplate string
+		cleanupStaging  bool
 
 		mdata metadata.Metadata
 
@@ -56,11 +68,20 @@ var _ = Describe("ReleaseUploader", func() {
 		uploadClient = &releasefakes.UploadClient{}
 		sha256Summer = &releasefakes.Sha256Summer{}
 		md5Summer = &releasefakes.Md5Summer{}
+		chunkManifestWriter = &releasefakes.ChunkManifestWriter{}
+		signatureWriter = &releasefakes.SignatureWriter{}
+		includedFilesLister = &releasefakes.IncludedFilesLister{}
+		sbomDetector = &releasefakes.SBOMDetector{}
+		checksumSummer = &releasefakes.ChecksumSummer{}
+		uploadState = nil
 
 		productSlug = "some-product-slug"
+		docsURLTemplate = ""
+		cleanupStaging = false
 
 		asyncTimeout = 450 * time.Millisecond
 		pollFrequency = 15 * time.Millisecond
+		attachConcurrency = 1
 
 		pivnetRelease = pivnet.Release{
 			ID:      1111,
@@ -104,18 +125,51 @@ var _ = Describe("ReleaseUploader", func() {
 	})
 
 	JustBeforeEach(func() {
-		uploader = release.NewReleaseUploader(
-			s3Client,
-			uploadClient,
-			fakeLogger,
-			sha256Summer,
-			md5Summer,
-			mdata,
-			"/some/sources/dir",
-			productSlug,
-			asyncTimeout,
-			pollFrequency,
-		)
+		if uploadState != nil {
+			uploader = release.NewReleaseUploader(
+				s3Client,
+				uploadClient,
+				fakeLogger,
+				sha256Summer,
+				md5Summer,
+				chunkManifestWriter,
+				signatureWriter,
+				includedFilesLister,
+				sbomDetector,
+				checksumSummer,
+				mdata,
+				"/some/sources/dir",
+				productSlug,
+				docsURLTemplate,
+				cleanupStaging,
+				asyncTimeout,
+				pollFrequency,
+				attachConcurrency,
+				uploadState,
+			)
+		} else {
+			uploader = release.NewReleaseUploader(
+				s3Client,
+				uploadClient,
+				fakeLogger,
+				sha256Summer,
+				md5Summer,
+				chunkManifestWriter,
+				signatureWriter,
+				includedFilesLister,
+				sbomDetector,
+				checksumSummer,
+				mdata,
+				"/some/sources/dir",
+				productSlug,
+				docsURLTemplate,
+				cleanupStaging,
+				asyncTimeout,
+				pollFrequency,
+				attachConcurrency,
+				nil,
+			)
+		}
 
 		sha256Summer.SumFileReturns(actualSHA256Sum, sha256SumFileErr)
 		md5Summer.SumFileReturns(actualMD5Sum, md5SumFileErr)
@@ -174,6 +228,408 @@ var _ = Describe("ReleaseUploader", func() {
 			Expect(productFileID).To(Equal(13367))
 		})
 
+		It("does not delete the staging object", func() {
+			err := uploader.Upload(pivnetRelease, []string{"some/file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(s3Client.DeleteFileCallCount()).To(Equal(0))
+		})
+
+		Context("when an upload state is provided", func() {
+			BeforeEach(func() {
+				uploadState = &releasefakes.UploadState{}
+			})
+
+			It("records the file once it has been added to the release", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploadState.UploadedCallCount()).To(Equal(1))
+				file, sha256 := uploadState.UploadedArgsForCall(0)
+				Expect(file).To(Equal("some/file"))
+				Expect(sha256).To(Equal(actualSHA256Sum))
+
+				Expect(uploadState.RecordUploadedCallCount()).To(Equal(1))
+				file, sha256, productFileID := uploadState.RecordUploadedArgsForCall(0)
+				Expect(file).To(Equal("some/file"))
+				Expect(sha256).To(Equal(actualSHA256Sum))
+				Expect(productFileID).To(Equal(13367))
+			})
+
+			Context("when the file was uploaded by a previous put", func() {
+				BeforeEach(func() {
+					uploadState.UploadedReturns(13367, true)
+				})
+
+				It("skips the file", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+					Expect(uploadClient.CreateProductFileCallCount()).To(Equal(0))
+					Expect(uploadClient.AddProductFileCallCount()).To(Equal(0))
+					Expect(uploadState.RecordUploadedCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when recording the file returns an error", func() {
+				BeforeEach(func() {
+					uploadState.RecordUploadedReturns(errors.New("some state error"))
+				})
+
+				It("returns the error", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(MatchError(ContainSubstring("some state error")))
+				})
+			})
+		})
+
+		Context("when cleaning up staging objects", func() {
+			BeforeEach(func() {
+				cleanupStaging = true
+			})
+
+			It("deletes the staging object once the file has been ingested", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(s3Client.DeleteFileCallCount()).To(Equal(1))
+				Expect(s3Client.DeleteFileArgsForCall(0)).To(Equal("some/file"))
+			})
+
+			Context("when deleting the staging object fails", func() {
+				BeforeEach(func() {
+					s3Client.DeleteFileReturns(errors.New("some delete error"))
+				})
+
+				It("does not return an error", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+
+			Context("when ingestion of the file fails", func() {
+				BeforeEach(func() {
+					productFileTransferStatus = "failed_sha256_check"
+				})
+
+				It("does not delete the staging object", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(HaveOccurred())
+
+					Expect(s3Client.DeleteFileCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the file already exists on Pivotal Network", func() {
+				BeforeEach(func() {
+					newAWSObjectKey = existingProductFiles[0].AWSObjectKey
+					existingProductFiles[0].SHA256 = actualSHA256Sum
+				})
+
+				It("does not delete the existing object", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(s3Client.DeleteFileCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		It("checks whether each file requires a chunk manifest", func() {
+			err := uploader.Upload(pivnetRelease, []string{"some/file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(chunkManifestWriter.WriteManifestCallCount()).To(Equal(1))
+			sourcesDir, exactGlob := chunkManifestWriter.WriteManifestArgsForCall(0)
+			Expect(sourcesDir).To(Equal("/some/sources/dir"))
+			Expect(exactGlob).To(Equal("some/file"))
+		})
+
+		It("does not set extra attributes when none are provided", func() {
+			err := uploader.Upload(pivnetRelease, []string{"some/file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uploadClient.UpdateProductFileAttributesCallCount()).To(Equal(0))
+		})
+
+		Context("when extra attributes are provided in the metadata", func() {
+			BeforeEach(func() {
+				mdata.ProductFiles[0].PlatformArchitecture = "x86_64"
+				mdata.ProductFiles[0].IncludedOSSComponents = []string{"openssl"}
+				mdata.ProductFiles[0].ECCN = "5D002"
+				mdata.ProductFiles[0].LicenseException = "ENC Unrestricted"
+			})
+
+			It("sets them on the created product file", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploadClient.UpdateProductFileAttributesCallCount()).To(Equal(1))
+				invokedProductSlug, productFileID, attributes := uploadClient.UpdateProductFileAttributesArgsForCall(0)
+				Expect(invokedProductSlug).To(Equal(productSlug))
+				Expect(productFileID).To(Equal(13367))
+				Expect(attributes).To(Equal(gp.ProductFileAttributes{
+					PlatformArchitecture:  "x86_64",
+					IncludedOSSComponents: []string{"openssl"},
+					ECCN:                  "5D002",
+					LicenseException:      "ENC Unrestricted",
+				}))
+			})
+
+			Context("when setting the attributes returns an error", func() {
+				BeforeEach(func() {
+					uploadClient.UpdateProductFileAttributesReturns(errors.New("some attributes error"))
+				})
+
+				It("returns an error with the ID of the product file", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(MatchError("failed to set extra attributes of product file 'a file' (ID: 13367): some attributes error"))
+
+					Expect(uploadClient.AddProductFileCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when an identical product file already exists", func() {
+				BeforeEach(func() {
+					newAWSObjectKey = existingProductFiles[0].AWSObjectKey
+					existingProductFiles[0].SHA256 = actualSHA256Sum
+					existingProductFiles[0].MD5 = actualMD5Sum
+				})
+
+				It("sets them on the existing product file, in case the put which created it could not", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(uploadClient.CreateProductFileCallCount()).To(Equal(0))
+
+					Expect(uploadClient.UpdateProductFileAttributesCallCount()).To(Equal(1))
+					_, productFileID, _ := uploadClient.UpdateProductFileAttributesArgsForCall(0)
+					Expect(productFileID).To(Equal(1234))
+				})
+			})
+		})
+
+		Context("when additional checksums are computed", func() {
+			BeforeEach(func() {
+				checksumSummer.SumFileReturns(map[string]string{"sha512": "some-sha512"}, nil)
+			})
+
+			It("uploads the file", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(checksumSummer.SumFileArgsForCall(0)).To(Equal("/some/sources/dir/some/file"))
+				Expect(s3Client.UploadFileCallCount()).To(Equal(1))
+			})
+
+			Context("when the metadata has a matching checksum", func() {
+				BeforeEach(func() {
+					mdata.ProductFiles[0].Checksums = map[string]string{"sha512": "some-sha512"}
+				})
+
+				It("uploads the file", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(s3Client.UploadFileCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the metadata has a different checksum", func() {
+				BeforeEach(func() {
+					mdata.ProductFiles[0].Checksums = map[string]string{"sha512": "some-other-sha512"}
+				})
+
+				It("returns an error without uploading", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(MatchError(ContainSubstring("SHA512 comparison failed for file: 'some/file'")))
+
+					Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when computing the checksums returns an error", func() {
+				BeforeEach(func() {
+					checksumSummer.SumFileReturns(nil, errors.New("some checksum error"))
+				})
+
+				It("returns the error", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(MatchError("some checksum error"))
+				})
+			})
+		})
+
+		Context("when a chunk manifest is written for a file", func() {
+			BeforeEach(func() {
+				chunkManifestWriter.WriteManifestReturns("some/file.sha256chunks.json", nil)
+			})
+
+			It("uploads the manifest as a product file", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(s3Client.UploadFileCallCount()).To(Equal(2))
+				Expect(s3Client.UploadFileArgsForCall(1)).To(Equal("some/file.sha256chunks.json"))
+
+				Expect(uploadClient.CreateProductFileCallCount()).To(Equal(2))
+				Expect(uploadClient.CreateProductFileArgsForCall(1).Name).To(Equal("file.sha256chunks.json"))
+				Expect(uploadClient.AddProductFileCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when writing a chunk manifest returns an error", func() {
+			BeforeEach(func() {
+				chunkManifestWriter.WriteManifestReturns("", errors.New("some manifest error"))
+			})
+
+			It("returns the error", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).To(MatchError("some manifest error"))
+
+				Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when a signature is written for each file", func() {
+			BeforeEach(func() {
+				chunkManifestWriter.WriteManifestReturns("some/file.sha256chunks.json", nil)
+				signatureWriter.WriteSignatureStub = func(sourcesDir string, exactGlob string) (string, error) {
+					return exactGlob + ".asc", nil
+				}
+			})
+
+			It("signs each file, including chunk manifests, and uploads the signatures as product files", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(signatureWriter.WriteSignatureCallCount()).To(Equal(2))
+				sourcesDir, exactGlob := signatureWriter.WriteSignatureArgsForCall(0)
+				Expect(sourcesDir).To(Equal("/some/sources/dir"))
+				Expect(exactGlob).To(Equal("some/file"))
+				_, exactGlob = signatureWriter.WriteSignatureArgsForCall(1)
+				Expect(exactGlob).To(Equal("some/file.sha256chunks.json"))
+
+				Expect(s3Client.UploadFileCallCount()).To(Equal(4))
+				Expect(s3Client.UploadFileArgsForCall(2)).To(Equal("some/file.asc"))
+				Expect(s3Client.UploadFileArgsForCall(3)).To(Equal("some/file.sha256chunks.json.asc"))
+
+				Expect(uploadClient.CreateProductFileCallCount()).To(Equal(4))
+				Expect(uploadClient.CreateProductFileArgsForCall(2).Name).To(Equal("file.asc"))
+				Expect(uploadClient.AddProductFileCallCount()).To(Equal(4))
+			})
+		})
+
+		Context("when a file has an sbom in the metadata", func() {
+			BeforeEach(func() {
+				mdata.ProductFiles[0].SBOM = "some/file.spdx.json"
+				sbomDetector.DetectReturns("SPDX", nil)
+				signatureWriter.WriteSignatureStub = func(sourcesDir string, exactGlob string) (string, error) {
+					return exactGlob + ".asc", nil
+				}
+			})
+
+			It("uploads the sbom as a product file of its own type describing the file", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(sbomDetector.DetectCallCount()).To(Equal(1))
+				Expect(sbomDetector.DetectArgsForCall(0)).To(Equal("/some/sources/dir/some/file.spdx.json"))
+
+				Expect(s3Client.UploadFileCallCount()).To(Equal(4))
+				Expect(s3Client.UploadFileArgsForCall(1)).To(Equal("some/file.spdx.json"))
+
+				config := uploadClient.CreateProductFileArgsForCall(1)
+				Expect(config.Name).To(Equal("file.spdx.json"))
+				Expect(config.FileType).To(Equal("Software Bill of Materials"))
+				Expect(config.Description).To(Equal("Software bill of materials of a file"))
+			})
+
+			It("signs the sbom", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(signatureWriter.WriteSignatureCallCount()).To(Equal(2))
+				_, exactGlob := signatureWriter.WriteSignatureArgsForCall(1)
+				Expect(exactGlob).To(Equal("some/file.spdx.json"))
+			})
+
+			Context("when the sbom is also matched by the file glob", func() {
+				It("uploads it once", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file", "some/file.spdx.json"})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(s3Client.UploadFileCallCount()).To(Equal(4))
+				})
+			})
+
+			Context("when the sbom is not in a known format", func() {
+				BeforeEach(func() {
+					sbomDetector.DetectReturns("", errors.New("some format error"))
+				})
+
+				It("returns an error before uploading any files", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(MatchError("sbom of file 'some/file' is invalid: some format error"))
+
+					Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when writing a signature returns an error", func() {
+			BeforeEach(func() {
+				signatureWriter.WriteSignatureReturns("", errors.New("some signature error"))
+			})
+
+			It("returns the error", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).To(MatchError("some signature error"))
+
+				Expect(s3Client.UploadFileCallCount()).To(Equal(0))
+			})
+		})
+
+		It("does not list the contents of files with included files in the metadata", func() {
+			err := uploader.Upload(pivnetRelease, []string{"some/file"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(includedFilesLister.ListCallCount()).To(Equal(0))
+		})
+
+		Context("when the metadata has no included files for the file", func() {
+			BeforeEach(func() {
+				mdata.ProductFiles[0].IncludedFiles = nil
+				includedFilesLister.ListReturns([]string{"metadata/", "releases/"}, nil)
+			})
+
+			It("uses the contents of the file as its included files", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(includedFilesLister.ListCallCount()).To(Equal(1))
+				sourcesDir, exactGlob := includedFilesLister.ListArgsForCall(0)
+				Expect(sourcesDir).To(Equal("/some/sources/dir"))
+				Expect(exactGlob).To(Equal("some/file"))
+
+				Expect(uploadClient.CreateProductFileArgsForCall(0).IncludedFiles).To(Equal([]string{"metadata/", "releases/"}))
+			})
+
+			Context("when listing the contents returns an error", func() {
+				BeforeEach(func() {
+					includedFilesLister.ListReturns(nil, errors.New("some list error"))
+				})
+
+				It("returns the error", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(MatchError("some list error"))
+
+					Expect(uploadClient.CreateProductFileCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		Context("when a product file already exists with AWSObjectKey", func() {
 			BeforeEach(func() {
 				newAWSObjectKey = existingProductFiles[0].AWSObjectKey
@@ -221,6 +677,36 @@ var _ = Describe("ReleaseUploader", func() {
 			})
 		})
 
+		Context("when a docs URL template is provided", func() {
+			BeforeEach(func() {
+				docsURLTemplate = "https://docs.example.com/{product_slug}/{version}/{file_name}.html"
+			})
+
+			It("keeps an explicitly provided docs URL", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).NotTo(HaveOccurred())
+
+				createArgs := uploadClient.CreateProductFileArgsForCall(0)
+				Expect(createArgs.DocsURL).To(Equal("some-docs-url"))
+			})
+
+			Context("when the file does not have a docs URL", func() {
+				BeforeEach(func() {
+					mdata.ProductFiles[0].DocsURL = ""
+				})
+
+				It("uses the rendered template as the docs URL", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).NotTo(HaveOccurred())
+
+					createArgs := uploadClient.CreateProductFileArgsForCall(0)
+					Expect(createArgs.DocsURL).To(Equal(
+						"https://docs.example.com/some-product-slug/some-release-version/a file.html",
+					))
+				})
+			})
+		})
+
 		Context("when the file sha256 cannot be computed", func() {
 			BeforeEach(func() {
 				sha256SumFileErr = errors.New("sha256 error")
@@ -294,7 +780,75 @@ var _ = Describe("ReleaseUploader", func() {
 
 			It("returns an error", func() {
 				err := uploader.Upload(pivnetRelease, []string{""})
-				Expect(err).To(MatchError(errors.New("error adding product")))
+				Expect(err).To(MatchError(ContainSubstring("error adding product")))
+			})
+
+			Context("when only some of the product files cannot be added", func() {
+				BeforeEach(func() {
+					chunkManifestWriter.WriteManifestReturns("some/file.sha256chunks.json", nil)
+					uploadClient.AddProductFileStub = func(productSlug string, releaseID int, productFileID int) error {
+						if uploadClient.AddProductFileCallCount() == 1 {
+							return errors.New("error adding product")
+						}
+						return nil
+					}
+				})
+
+				It("adds every product file and returns the failures", func() {
+					err := uploader.Upload(pivnetRelease, []string{"some/file"})
+					Expect(err).To(MatchError("failed to attach 1 of 2 product files:\n  - 'file': error adding product"))
+
+					Expect(uploadClient.AddProductFileCallCount()).To(Equal(2))
+				})
+			})
+		})
+
+		Context("when an attach concurrency is provided", func() {
+			var (
+				mu       sync.Mutex
+				attached []int
+			)
+
+			BeforeEach(func() {
+				attachConcurrency = 3
+				attached = nil
+
+				chunkManifestWriter.WriteManifestReturns("some/file.sha256chunks.json", nil)
+				signatureWriter.WriteSignatureStub = func(sourcesDir string, exactGlob string) (string, error) {
+					return exactGlob + ".asc", nil
+				}
+
+				uploadClient.AddProductFileStub = func(productSlug string, releaseID int, productFileID int) error {
+					mu.Lock()
+					defer mu.Unlock()
+
+					attached = append(attached, productFileID)
+					if productFileID == 2 || productFileID == 4 {
+						return fmt.Errorf("error adding product file %d", productFileID)
+					}
+					return nil
+				}
+			})
+
+			JustBeforeEach(func() {
+				var nextID int
+				uploadClient.CreateProductFileStub = func(config pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+					nextID++
+					return pivnet.ProductFile{ID: nextID, Name: config.Name}, nil
+				}
+
+				uploadClient.ProductFileStub = func(productSlug string, productFileID int) (pivnet.ProductFile, error) {
+					return pivnet.ProductFile{ID: productFileID, FileTransferStatus: "complete"}, nil
+				}
+			})
+
+			It("adds every product file and returns the failures in the order of the files", func() {
+				err := uploader.Upload(pivnetRelease, []string{"some/file"})
+				Expect(err).To(MatchError("failed to attach 2 of 4 product files:\n" +
+					"  - 'file.sha256chunks.json': error adding product file 2\n" +
+					"  - 'file.sha256chunks.json.asc': error adding product file 4"))
+
+				Expect(attached).To(ConsistOf(1, 2, 3, 4))
 			})
 		})
 
@@ -325,6 +879,27 @@ var _ = Describe("ReleaseUploader", func() {
 			})
 		})
 
+		Context("when the product file is still being transferred or scanned", func() {
+			JustBeforeEach(func() {
+				statuses := []string{"transferring", "scanning", "complete"}
+				uploadClient.ProductFileStub = func(string, int) (pivnet.ProductFile, error) {
+					productFile := existingProductFiles[0]
+					productFile.FileTransferStatus = statuses[0]
+					if len(statuses) > 1 {
+						statuses = statuses[1:]
+					}
+					return productFile, nil
+				}
+			})
+
+			It("keeps polling until the transfer is complete", func() {
+				err := uploader.Upload(pivnetRelease, []string{""})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(uploadClient.ProductFileCallCount()).To(Equal(3))
+			})
+		})
+
 		Context("when polling for the product file times out", func() {
 			BeforeEach(func() {
 				asyncTimeout = pollFrequency / 2
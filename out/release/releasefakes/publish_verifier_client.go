@@ -0,0 +1,219 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/go-pivnet"
+)
+
+type PublishVerifierClient struct {
+	GetReleaseStub        func(productSlug string, releaseVersion string) (pivnet.Release, error)
+	getReleaseMutex       sync.RWMutex
+	getReleaseArgsForCall []struct {
+		productSlug    string
+		releaseVersion string
+	}
+	getReleaseReturns struct {
+		result1 pivnet.Release
+		result2 error
+	}
+	ProductFilesForReleaseStub        func(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
+	productFilesForReleaseMutex       sync.RWMutex
+	productFilesForReleaseArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	productFilesForReleaseReturns struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	DependencySpecifiersStub        func(productSlug string, releaseID int) ([]pivnet.DependencySpecifier, error)
+	dependencySpecifiersMutex       sync.RWMutex
+	dependencySpecifiersArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	dependencySpecifiersReturns struct {
+		result1 []pivnet.DependencySpecifier
+		result2 error
+	}
+	UpgradePathSpecifiersStub        func(productSlug string, releaseID int) ([]pivnet.UpgradePathSpecifier, error)
+	upgradePathSpecifiersMutex       sync.RWMutex
+	upgradePathSpecifiersArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	upgradePathSpecifiersReturns struct {
+		result1 []pivnet.UpgradePathSpecifier
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PublishVerifierClient) GetRelease(productSlug string, releaseVersion string) (pivnet.Release, error) {
+	fake.getReleaseMutex.Lock()
+	fake.getReleaseArgsForCall = append(fake.getReleaseArgsForCall, struct {
+		productSlug    string
+		releaseVersion string
+	}{productSlug, releaseVersion})
+	fake.recordInvocation("GetRelease", []interface{}{productSlug, releaseVersion})
+	fake.getReleaseMutex.Unlock()
+	if fake.GetReleaseStub != nil {
+		return fake.GetReleaseStub(productSlug, releaseVersion)
+	} else {
+		return fake.getReleaseReturns.result1, fake.getReleaseReturns.result2
+	}
+}
+
+func (fake *PublishVerifierClient) GetReleaseCallCount() int {
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	return len(fake.getReleaseArgsForCall)
+}
+
+func (fake *PublishVerifierClient) GetReleaseArgsForCall(i int) (string, string) {
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	return fake.getReleaseArgsForCall[i].productSlug, fake.getReleaseArgsForCall[i].releaseVersion
+}
+
+func (fake *PublishVerifierClient) GetReleaseReturns(result1 pivnet.Release, result2 error) {
+	fake.GetReleaseStub = nil
+	fake.getReleaseReturns = struct {
+		result1 pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PublishVerifierClient) ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error) {
+	fake.productFilesForReleaseMutex.Lock()
+	fake.productFilesForReleaseArgsForCall = append(fake.productFilesForReleaseArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ProductFilesForRelease", []interface{}{productSlug, releaseID})
+	fake.productFilesForReleaseMutex.Unlock()
+	if fake.ProductFilesForReleaseStub != nil {
+		return fake.ProductFilesForReleaseStub(productSlug, releaseID)
+	} else {
+		return fake.productFilesForReleaseReturns.result1, fake.productFilesForReleaseReturns.result2
+	}
+}
+
+func (fake *PublishVerifierClient) ProductFilesForReleaseCallCount() int {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return len(fake.productFilesForReleaseArgsForCall)
+}
+
+func (fake *PublishVerifierClient) ProductFilesForReleaseArgsForCall(i int) (string, int) {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return fake.productFilesForReleaseArgsForCall[i].productSlug, fake.productFilesForReleaseArgsForCall[i].releaseID
+}
+
+func (fake *PublishVerifierClient) ProductFilesForReleaseReturns(result1 []pivnet.ProductFile, result2 error) {
+	fake.ProductFilesForReleaseStub = nil
+	fake.productFilesForReleaseReturns = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PublishVerifierClient) DependencySpecifiers(productSlug string, releaseID int) ([]pivnet.DependencySpecifier, error) {
+	fake.dependencySpecifiersMutex.Lock()
+	fake.dependencySpecifiersArgsForCall = append(fake.dependencySpecifiersArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("DependencySpecifiers", []interface{}{productSlug, releaseID})
+	fake.dependencySpecifiersMutex.Unlock()
+	if fake.DependencySpecifiersStub != nil {
+		return fake.DependencySpecifiersStub(productSlug, releaseID)
+	} else {
+		return fake.dependencySpecifiersReturns.result1, fake.dependencySpecifiersReturns.result2
+	}
+}
+
+func (fake *PublishVerifierClient) DependencySpecifiersCallCount() int {
+	fake.dependencySpecifiersMutex.RLock()
+	defer fake.dependencySpecifiersMutex.RUnlock()
+	return len(fake.dependencySpecifiersArgsForCall)
+}
+
+func (fake *PublishVerifierClient) DependencySpecifiersArgsForCall(i int) (string, int) {
+	fake.dependencySpecifiersMutex.RLock()
+	defer fake.dependencySpecifiersMutex.RUnlock()
+	return fake.dependencySpecifiersArgsForCall[i].productSlug, fake.dependencySpecifiersArgsForCall[i].releaseID
+}
+
+func (fake *PublishVerifierClient) DependencySpecifiersReturns(result1 []pivnet.DependencySpecifier, result2 error) {
+	fake.DependencySpecifiersStub = nil
+	fake.dependencySpecifiersReturns = struct {
+		result1 []pivnet.DependencySpecifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PublishVerifierClient) UpgradePathSpecifiers(productSlug string, releaseID int) ([]pivnet.UpgradePathSpecifier, error) {
+	fake.upgradePathSpecifiersMutex.Lock()
+	fake.upgradePathSpecifiersArgsForCall = append(fake.upgradePathSpecifiersArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("UpgradePathSpecifiers", []interface{}{productSlug, releaseID})
+	fake.upgradePathSpecifiersMutex.Unlock()
+	if fake.UpgradePathSpecifiersStub != nil {
+		return fake.UpgradePathSpecifiersStub(productSlug, releaseID)
+	} else {
+		return fake.upgradePathSpecifiersReturns.result1, fake.upgradePathSpecifiersReturns.result2
+	}
+}
+
+func (fake *PublishVerifierClient) UpgradePathSpecifiersCallCount() int {
+	fake.upgradePathSpecifiersMutex.RLock()
+	defer fake.upgradePathSpecifiersMutex.RUnlock()
+	return len(fake.upgradePathSpecifiersArgsForCall)
+}
+
+func (fake *PublishVerifierClient) UpgradePathSpecifiersArgsForCall(i int) (string, int) {
+	fake.upgradePathSpecifiersMutex.RLock()
+	defer fake.upgradePathSpecifiersMutex.RUnlock()
+	return fake.upgradePathSpecifiersArgsForCall[i].productSlug, fake.upgradePathSpecifiersArgsForCall[i].releaseID
+}
+
+func (fake *PublishVerifierClient) UpgradePathSpecifiersReturns(result1 []pivnet.UpgradePathSpecifier, result2 error) {
+	fake.UpgradePathSpecifiersStub = nil
+	fake.upgradePathSpecifiersReturns = struct {
+		result1 []pivnet.UpgradePathSpecifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PublishVerifierClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	fake.dependencySpecifiersMutex.RLock()
+	defer fake.dependencySpecifiersMutex.RUnlock()
+	fake.upgradePathSpecifiersMutex.RLock()
+	defer fake.upgradePathSpecifiersMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *PublishVerifierClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/gp"
 )
 
 type ReleaseClient struct {
@@ -49,6 +50,16 @@ type ReleaseClient struct {
 	deleteReleaseReturns struct {
 		result1 error
 	}
+	UpdateReleaseAttributesStub        func(productSlug string, releaseID int, attributes gp.ReleaseAttributes) error
+	updateReleaseAttributesMutex       sync.RWMutex
+	updateReleaseAttributesArgsForCall []struct {
+		productSlug string
+		releaseID   int
+		attributes  gp.ReleaseAttributes
+	}
+	updateReleaseAttributesReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -207,6 +218,41 @@ func (fake *ReleaseClient) DeleteReleaseReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *ReleaseClient) UpdateReleaseAttributes(productSlug string, releaseID int, attributes gp.ReleaseAttributes) error {
+	fake.updateReleaseAttributesMutex.Lock()
+	fake.updateReleaseAttributesArgsForCall = append(fake.updateReleaseAttributesArgsForCall, struct {
+		productSlug string
+		releaseID   int
+		attributes  gp.ReleaseAttributes
+	}{productSlug, releaseID, attributes})
+	fake.recordInvocation("UpdateReleaseAttributes", []interface{}{productSlug, releaseID, attributes})
+	fake.updateReleaseAttributesMutex.Unlock()
+	if fake.UpdateReleaseAttributesStub != nil {
+		return fake.UpdateReleaseAttributesStub(productSlug, releaseID, attributes)
+	} else {
+		return fake.updateReleaseAttributesReturns.result1
+	}
+}
+
+func (fake *ReleaseClient) UpdateReleaseAttributesCallCount() int {
+	fake.updateReleaseAttributesMutex.RLock()
+	defer fake.updateReleaseAttributesMutex.RUnlock()
+	return len(fake.updateReleaseAttributesArgsForCall)
+}
+
+func (fake *ReleaseClient) UpdateReleaseAttributesArgsForCall(i int) (string, int, gp.ReleaseAttributes) {
+	fake.updateReleaseAttributesMutex.RLock()
+	defer fake.updateReleaseAttributesMutex.RUnlock()
+	return fake.updateReleaseAttributesArgsForCall[i].productSlug, fake.updateReleaseAttributesArgsForCall[i].releaseID, fake.updateReleaseAttributesArgsForCall[i].attributes
+}
+
+func (fake *ReleaseClient) UpdateReleaseAttributesReturns(result1 error) {
+	fake.UpdateReleaseAttributesStub = nil
+	fake.updateReleaseAttributesReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ReleaseClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -220,6 +266,8 @@ func (fake *ReleaseClient) Invocations() map[string][][]interface{} {
 	defer fake.createReleaseMutex.RUnlock()
 	fake.deleteReleaseMutex.RLock()
 	defer fake.deleteReleaseMutex.RUnlock()
+	fake.updateReleaseAttributesMutex.RLock()
+	defer fake.updateReleaseAttributesMutex.RUnlock()
 	return fake.invocations
 }
 
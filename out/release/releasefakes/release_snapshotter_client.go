@@ -0,0 +1,266 @@
+// This file was generated by counterfeiter
+package releasefakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/go-pivnet"
+)
+
+type ReleaseSnapshotterClient struct {
+	GetReleaseByIDStub        func(productSlug string, releaseID int) (pivnet.Release, error)
+	getReleaseByIDMutex       sync.RWMutex
+	getReleaseByIDArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	getReleaseByIDReturns struct {
+		result1 pivnet.Release
+		result2 error
+	}
+	ProductFilesForReleaseStub        func(productSlug string, releaseID int) ([]pivnet.ProductFile, error)
+	productFilesForReleaseMutex       sync.RWMutex
+	productFilesForReleaseArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	productFilesForReleaseReturns struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	FileGroupsForReleaseStub        func(productSlug string, releaseID int) ([]pivnet.FileGroup, error)
+	fileGroupsForReleaseMutex       sync.RWMutex
+	fileGroupsForReleaseArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	fileGroupsForReleaseReturns struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	DependencySpecifiersStub        func(productSlug string, releaseID int) ([]pivnet.DependencySpecifier, error)
+	dependencySpecifiersMutex       sync.RWMutex
+	dependencySpecifiersArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	dependencySpecifiersReturns struct {
+		result1 []pivnet.DependencySpecifier
+		result2 error
+	}
+	UpgradePathSpecifiersStub        func(productSlug string, releaseID int) ([]pivnet.UpgradePathSpecifier, error)
+	upgradePathSpecifiersMutex       sync.RWMutex
+	upgradePathSpecifiersArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	upgradePathSpecifiersReturns struct {
+		result1 []pivnet.UpgradePathSpecifier
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ReleaseSnapshotterClient) GetReleaseByID(productSlug string, releaseID int) (pivnet.Release, error) {
+	fake.getReleaseByIDMutex.Lock()
+	fake.getReleaseByIDArgsForCall = append(fake.getReleaseByIDArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("GetReleaseByID", []interface{}{productSlug, releaseID})
+	fake.getReleaseByIDMutex.Unlock()
+	if fake.GetReleaseByIDStub != nil {
+		return fake.GetReleaseByIDStub(productSlug, releaseID)
+	} else {
+		return fake.getReleaseByIDReturns.result1, fake.getReleaseByIDReturns.result2
+	}
+}
+
+func (fake *ReleaseSnapshotterClient) GetReleaseByIDCallCount() int {
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
+	return len(fake.getReleaseByIDArgsForCall)
+}
+
+func (fake *ReleaseSnapshotterClient) GetReleaseByIDArgsForCall(i int) (string, int) {
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
+	return fake.getReleaseByIDArgsForCall[i].productSlug, fake.getReleaseByIDArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseSnapshotterClient) GetReleaseByIDReturns(result1 pivnet.Release, result2 error) {
+	fake.GetReleaseByIDStub = nil
+	fake.getReleaseByIDReturns = struct {
+		result1 pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseSnapshotterClient) ProductFilesForRelease(productSlug string, releaseID int) ([]pivnet.ProductFile, error) {
+	fake.productFilesForReleaseMutex.Lock()
+	fake.productFilesForReleaseArgsForCall = append(fake.productFilesForReleaseArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("ProductFilesForRelease", []interface{}{productSlug, releaseID})
+	fake.productFilesForReleaseMutex.Unlock()
+	if fake.ProductFilesForReleaseStub != nil {
+		return fake.ProductFilesForReleaseStub(productSlug, releaseID)
+	} else {
+		return fake.productFilesForReleaseReturns.result1, fake.productFilesForReleaseReturns.result2
+	}
+}
+
+func (fake *ReleaseSnapshotterClient) ProductFilesForReleaseCallCount() int {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return len(fake.productFilesForReleaseArgsForCall)
+}
+
+func (fake *ReleaseSnapshotterClient) ProductFilesForReleaseArgsForCall(i int) (string, int) {
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	return fake.productFilesForReleaseArgsForCall[i].productSlug, fake.productFilesForReleaseArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseSnapshotterClient) ProductFilesForReleaseReturns(result1 []pivnet.ProductFile, result2 error) {
+	fake.ProductFilesForReleaseStub = nil
+	fake.productFilesForReleaseReturns = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseSnapshotterClient) FileGroupsForRelease(productSlug string, releaseID int) ([]pivnet.FileGroup, error) {
+	fake.fileGroupsForReleaseMutex.Lock()
+	fake.fileGroupsForReleaseArgsForCall = append(fake.fileGroupsForReleaseArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("FileGroupsForRelease", []interface{}{productSlug, releaseID})
+	fake.fileGroupsForReleaseMutex.Unlock()
+	if fake.FileGroupsForReleaseStub != nil {
+		return fake.FileGroupsForReleaseStub(productSlug, releaseID)
+	} else {
+		return fake.fileGroupsForReleaseReturns.result1, fake.fileGroupsForReleaseReturns.result2
+	}
+}
+
+func (fake *ReleaseSnapshotterClient) FileGroupsForReleaseCallCount() int {
+	fake.fileGroupsForReleaseMutex.RLock()
+	defer fake.fileGroupsForReleaseMutex.RUnlock()
+	return len(fake.fileGroupsForReleaseArgsForCall)
+}
+
+func (fake *ReleaseSnapshotterClient) FileGroupsForReleaseArgsForCall(i int) (string, int) {
+	fake.fileGroupsForReleaseMutex.RLock()
+	defer fake.fileGroupsForReleaseMutex.RUnlock()
+	return fake.fileGroupsForReleaseArgsForCall[i].productSlug, fake.fileGroupsForReleaseArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseSnapshotterClient) FileGroupsForReleaseReturns(result1 []pivnet.FileGroup, result2 error) {
+	fake.FileGroupsForReleaseStub = nil
+	fake.fileGroupsForReleaseReturns = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseSnapshotterClient) DependencySpecifiers(productSlug string, releaseID int) ([]pivnet.DependencySpecifier, error) {
+	fake.dependencySpecifiersMutex.Lock()
+	fake.dependencySpecifiersArgsForCall = append(fake.dependencySpecifiersArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("DependencySpecifiers", []interface{}{productSlug, releaseID})
+	fake.dependencySpecifiersMutex.Unlock()
+	if fake.DependencySpecifiersStub != nil {
+		return fake.DependencySpecifiersStub(productSlug, releaseID)
+	} else {
+		return fake.dependencySpecifiersReturns.result1, fake.dependencySpecifiersReturns.result2
+	}
+}
+
+func (fake *ReleaseSnapshotterClient) DependencySpecifiersCallCount() int {
+	fake.dependencySpecifiersMutex.RLock()
+	defer fake.dependencySpecifiersMutex.RUnlock()
+	return len(fake.dependencySpecifiersArgsForCall)
+}
+
+func (fake *ReleaseSnapshotterClient) DependencySpecifiersArgsForCall(i int) (string, int) {
+	fake.dependencySpecifiersMutex.RLock()
+	defer fake.dependencySpecifiersMutex.RUnlock()
+	return fake.dependencySpecifiersArgsForCall[i].productSlug, fake.dependencySpecifiersArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseSnapshotterClient) DependencySpecifiersReturns(result1 []pivnet.DependencySpecifier, result2 error) {
+	fake.DependencySpecifiersStub = nil
+	fake.dependencySpecifiersReturns = struct {
+		result1 []pivnet.DependencySpecifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseSnapshotterClient) UpgradePathSpecifiers(productSlug string, releaseID int) ([]pivnet.UpgradePathSpecifier, error) {
+	fake.upgradePathSpecifiersMutex.Lock()
+	fake.upgradePathSpecifiersArgsForCall = append(fake.upgradePathSpecifiersArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("UpgradePathSpecifiers", []interface{}{productSlug, releaseID})
+	fake.upgradePathSpecifiersMutex.Unlock()
+	if fake.UpgradePathSpecifiersStub != nil {
+		return fake.UpgradePathSpecifiersStub(productSlug, releaseID)
+	} else {
+		return fake.upgradePathSpecifiersReturns.result1, fake.upgradePathSpecifiersReturns.result2
+	}
+}
+
+func (fake *ReleaseSnapshotterClient) UpgradePathSpecifiersCallCount() int {
+	fake.upgradePathSpecifiersMutex.RLock()
+	defer fake.upgradePathSpecifiersMutex.RUnlock()
+	return len(fake.upgradePathSpecifiersArgsForCall)
+}
+
+func (fake *ReleaseSnapshotterClient) UpgradePathSpecifiersArgsForCall(i int) (string, int) {
+	fake.upgradePathSpecifiersMutex.RLock()
+	defer fake.upgradePathSpecifiersMutex.RUnlock()
+	return fake.upgradePathSpecifiersArgsForCall[i].productSlug, fake.upgradePathSpecifiersArgsForCall[i].releaseID
+}
+
+func (fake *ReleaseSnapshotterClient) UpgradePathSpecifiersReturns(result1 []pivnet.UpgradePathSpecifier, result2 error) {
+	fake.UpgradePathSpecifiersStub = nil
+	fake.upgradePathSpecifiersReturns = struct {
+		result1 []pivnet.UpgradePathSpecifier
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ReleaseSnapshotterClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
+	fake.productFilesForReleaseMutex.RLock()
+	defer fake.productFilesForReleaseMutex.RUnlock()
+	fake.fileGroupsForReleaseMutex.RLock()
+	defer fake.fileGroupsForReleaseMutex.RUnlock()
+	fake.dependencySpecifiersMutex.RLock()
+	defer fake.dependencySpecifiersMutex.RUnlock()
+	fake.upgradePathSpecifiersMutex.RLock()
+	defer fake.upgradePathSpecifiersMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *ReleaseSnapshotterClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -32,6 +32,17 @@ type S3Client struct {
 	uploadFileReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteFileStub        func(string) error
+	deleteFileMutex       sync.RWMutex
+	deleteFileArgsForCall []struct {
+		arg1 string
+	}
+	deleteFileReturns struct {
+		result1 error
+	}
+	deleteFileReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -138,6 +149,54 @@ func (fake *S3Client) UploadFileReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *S3Client) DeleteFile(arg1 string) error {
+	fake.deleteFileMutex.Lock()
+	ret, specificReturn := fake.deleteFileReturnsOnCall[len(fake.deleteFileArgsForCall)]
+	fake.deleteFileArgsForCall = append(fake.deleteFileArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("DeleteFile", []interface{}{arg1})
+	fake.deleteFileMutex.Unlock()
+	if fake.DeleteFileStub != nil {
+		return fake.DeleteFileStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.deleteFileReturns.result1
+}
+
+func (fake *S3Client) DeleteFileCallCount() int {
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
+	return len(fake.deleteFileArgsForCall)
+}
+
+func (fake *S3Client) DeleteFileArgsForCall(i int) string {
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
+	return fake.deleteFileArgsForCall[i].arg1
+}
+
+func (fake *S3Client) DeleteFileReturns(result1 error) {
+	fake.DeleteFileStub = nil
+	fake.deleteFileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *S3Client) DeleteFileReturnsOnCall(i int, result1 error) {
+	fake.DeleteFileStub = nil
+	if fake.deleteFileReturnsOnCall == nil {
+		fake.deleteFileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteFileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *S3Client) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -145,6 +204,8 @@ func (fake *S3Client) Invocations() map[string][][]interface{} {
 	defer fake.computeAWSObjectKeyMutex.RUnlock()
 	fake.uploadFileMutex.RLock()
 	defer fake.uploadFileMutex.RUnlock()
+	fake.deleteFileMutex.RLock()
+	defer fake.deleteFileMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
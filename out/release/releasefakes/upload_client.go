@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/gp"
 )
 
 type UploadClient struct {
@@ -114,6 +115,19 @@ type UploadClient struct {
 		result1 pivnet.ProductFile
 		result2 error
 	}
+	UpdateProductFileAttributesStub        func(productSlug string, productFileID int, attributes gp.ProductFileAttributes) error
+	updateProductFileAttributesMutex       sync.RWMutex
+	updateProductFileAttributesArgsForCall []struct {
+		productSlug   string
+		productFileID int
+		attributes    gp.ProductFileAttributes
+	}
+	updateProductFileAttributesReturns struct {
+		result1 error
+	}
+	updateProductFileAttributesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -526,6 +540,56 @@ func (fake *UploadClient) DeleteProductFileReturnsOnCall(i int, result1 pivnet.P
 	}{result1, result2}
 }
 
+func (fake *UploadClient) UpdateProductFileAttributes(productSlug string, productFileID int, attributes gp.ProductFileAttributes) error {
+	fake.updateProductFileAttributesMutex.Lock()
+	ret, specificReturn := fake.updateProductFileAttributesReturnsOnCall[len(fake.updateProductFileAttributesArgsForCall)]
+	fake.updateProductFileAttributesArgsForCall = append(fake.updateProductFileAttributesArgsForCall, struct {
+		productSlug   string
+		productFileID int
+		attributes    gp.ProductFileAttributes
+	}{productSlug, productFileID, attributes})
+	fake.recordInvocation("UpdateProductFileAttributes", []interface{}{productSlug, productFileID, attributes})
+	fake.updateProductFileAttributesMutex.Unlock()
+	if fake.UpdateProductFileAttributesStub != nil {
+		return fake.UpdateProductFileAttributesStub(productSlug, productFileID, attributes)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.updateProductFileAttributesReturns.result1
+}
+
+func (fake *UploadClient) UpdateProductFileAttributesCallCount() int {
+	fake.updateProductFileAttributesMutex.RLock()
+	defer fake.updateProductFileAttributesMutex.RUnlock()
+	return len(fake.updateProductFileAttributesArgsForCall)
+}
+
+func (fake *UploadClient) UpdateProductFileAttributesArgsForCall(i int) (string, int, gp.ProductFileAttributes) {
+	fake.updateProductFileAttributesMutex.RLock()
+	defer fake.updateProductFileAttributesMutex.RUnlock()
+	return fake.updateProductFileAttributesArgsForCall[i].productSlug, fake.updateProductFileAttributesArgsForCall[i].productFileID, fake.updateProductFileAttributesArgsForCall[i].attributes
+}
+
+func (fake *UploadClient) UpdateProductFileAttributesReturns(result1 error) {
+	fake.UpdateProductFileAttributesStub = nil
+	fake.updateProductFileAttributesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *UploadClient) UpdateProductFileAttributesReturnsOnCall(i int, result1 error) {
+	fake.UpdateProductFileAttributesStub = nil
+	if fake.updateProductFileAttributesReturnsOnCall == nil {
+		fake.updateProductFileAttributesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateProductFileAttributesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *UploadClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -545,6 +609,8 @@ func (fake *UploadClient) Invocations() map[string][][]interface{} {
 	defer fake.productFileMutex.RUnlock()
 	fake.deleteProductFileMutex.RLock()
 	defer fake.deleteProductFileMutex.RUnlock()
+	fake.updateProductFileAttributesMutex.RLock()
+	defer fake.updateProductFileAttributesMutex.RUnlock()
 	return fake.invocations
 }
 
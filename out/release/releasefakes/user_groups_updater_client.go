@@ -28,6 +28,13 @@ type UserGroupsUpdaterClient struct {
 	addUserGroupReturns struct {
 		result1 error
 	}
+	AllUserGroupsStub        func() ([]go_pivnet.UserGroup, error)
+	allUserGroupsMutex       sync.RWMutex
+	allUserGroupsArgsForCall []struct{}
+	allUserGroupsReturns     struct {
+		result1 []go_pivnet.UserGroup
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -102,6 +109,32 @@ func (fake *UserGroupsUpdaterClient) AddUserGroupReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *UserGroupsUpdaterClient) AllUserGroups() ([]go_pivnet.UserGroup, error) {
+	fake.allUserGroupsMutex.Lock()
+	fake.allUserGroupsArgsForCall = append(fake.allUserGroupsArgsForCall, struct{}{})
+	fake.recordInvocation("AllUserGroups", []interface{}{})
+	fake.allUserGroupsMutex.Unlock()
+	if fake.AllUserGroupsStub != nil {
+		return fake.AllUserGroupsStub()
+	} else {
+		return fake.allUserGroupsReturns.result1, fake.allUserGroupsReturns.result2
+	}
+}
+
+func (fake *UserGroupsUpdaterClient) AllUserGroupsCallCount() int {
+	fake.allUserGroupsMutex.RLock()
+	defer fake.allUserGroupsMutex.RUnlock()
+	return len(fake.allUserGroupsArgsForCall)
+}
+
+func (fake *UserGroupsUpdaterClient) AllUserGroupsReturns(result1 []go_pivnet.UserGroup, result2 error) {
+	fake.AllUserGroupsStub = nil
+	fake.allUserGroupsReturns = struct {
+		result1 []go_pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *UserGroupsUpdaterClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -109,6 +142,8 @@ func (fake *UserGroupsUpdaterClient) Invocations() map[string][][]interface{} {
 	defer fake.updateReleaseMutex.RUnlock()
 	fake.addUserGroupMutex.RLock()
 	defer fake.addUserGroupMutex.RUnlock()
+	fake.allUserGroupsMutex.RLock()
+	defer fake.allUserGroupsMutex.RUnlock()
 	return fake.invocations
 }
 
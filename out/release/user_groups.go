@@ -34,6 +34,7 @@ func NewUserGroupsUpdater(
 type userGroupsUpdaterClient interface {
 	UpdateRelease(productSlug string, release pivnet.Release) (pivnet.Release, error)
 	AddUserGroup(productSlug string, releaseID int, userGroupID int) error
+	AllUserGroups() ([]pivnet.UserGroup, error)
 }
 
 func (rf UserGroupsUpdater) UpdateUserGroups(release pivnet.Release) (pivnet.Release, error) {
@@ -58,14 +59,12 @@ func (rf UserGroupsUpdater) UpdateUserGroups(release pivnet.Release) (pivnet.Rel
 		}
 
 		if availability == "Selected User Groups Only" {
-			userGroupIDs := rf.metadata.Release.UserGroupIDs
-
-			for _, userGroupIDString := range userGroupIDs {
-				userGroupID, err := strconv.Atoi(userGroupIDString)
-				if err != nil {
-					return pivnet.Release{}, err
-				}
+			userGroupIDs, err := rf.userGroupIDs()
+			if err != nil {
+				return pivnet.Release{}, err
+			}
 
+			for _, userGroupID := range userGroupIDs {
 				rf.logger.Info(fmt.Sprintf(
 					"Adding user group with ID: %d",
 					userGroupID,
@@ -80,3 +79,62 @@ func (rf UserGroupsUpdater) UpdateUserGroups(release pivnet.Release) (pivnet.Rel
 
 	return release, nil
 }
+
+// userGroupIDs returns the IDs of the user groups in the metadata, followed
+// by the IDs of the user groups named in the metadata, without duplicates.
+func (rf UserGroupsUpdater) userGroupIDs() ([]int, error) {
+	var userGroupIDs []int
+	seen := map[int]bool{}
+
+	add := func(userGroupID int) {
+		if !seen[userGroupID] {
+			seen[userGroupID] = true
+			userGroupIDs = append(userGroupIDs, userGroupID)
+		}
+	}
+
+	for _, userGroupIDString := range rf.metadata.Release.UserGroupIDs {
+		userGroupID, err := strconv.Atoi(userGroupIDString)
+		if err != nil {
+			return nil, err
+		}
+
+		add(userGroupID)
+	}
+
+	userGroupNames := rf.metadata.Release.UserGroups
+	if len(userGroupNames) == 0 {
+		return userGroupIDs, nil
+	}
+
+	rf.logger.Info("Resolving user group names")
+
+	userGroups, err := rf.pivnet.AllUserGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	idsByName := map[string][]int{}
+	for _, userGroup := range userGroups {
+		idsByName[userGroup.Name] = append(idsByName[userGroup.Name], userGroup.ID)
+	}
+
+	for _, name := range userGroupNames {
+		ids := idsByName[name]
+
+		switch len(ids) {
+		case 0:
+			return nil, fmt.Errorf("user group '%s' not found", name)
+		case 1:
+			add(ids[0])
+		default:
+			return nil, fmt.Errorf(
+				"user group name '%s' is ambiguous as it matches user groups with IDs: %v - use user_group_ids instead",
+				name,
+				ids,
+			)
+		}
+	}
+
+	return userGroupIDs, nil
+}
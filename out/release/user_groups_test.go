@@ -120,6 +120,71 @@ var _ = Describe("UserGroupsUpdater", func() {
 				Expect(response.Version).To(Equal("another-version"))
 			})
 
+			Context("when user group names are provided", func() {
+				BeforeEach(func() {
+					mdata.Release.UserGroupIDs = []string{"111"}
+					mdata.Release.UserGroups = []string{"Beta Testers", "Some Partner"}
+
+					pivnetClient.AllUserGroupsReturns([]pivnet.UserGroup{
+						{ID: 111, Name: "Beta Testers"},
+						{ID: 333, Name: "Some Partner"},
+						{ID: 444, Name: "Someone Else"},
+					}, nil)
+				})
+
+				It("adds the user groups with those names", func() {
+					_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(pivnetClient.AllUserGroupsCallCount()).To(Equal(1))
+					Expect(pivnetClient.AddUserGroupCallCount()).To(Equal(2))
+
+					_, _, userGroupID := pivnetClient.AddUserGroupArgsForCall(0)
+					Expect(userGroupID).To(Equal(111))
+
+					_, _, userGroupID = pivnetClient.AddUserGroupArgsForCall(1)
+					Expect(userGroupID).To(Equal(333))
+				})
+
+				Context("when no user group has the name", func() {
+					BeforeEach(func() {
+						mdata.Release.UserGroups = []string{"Missing Group"}
+					})
+
+					It("returns an error without adding any user groups", func() {
+						_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+						Expect(err).To(MatchError("user group 'Missing Group' not found"))
+
+						Expect(pivnetClient.AddUserGroupCallCount()).To(BeZero())
+					})
+				})
+
+				Context("when more than one user group has the name", func() {
+					BeforeEach(func() {
+						pivnetClient.AllUserGroupsReturns([]pivnet.UserGroup{
+							{ID: 333, Name: "Some Partner"},
+							{ID: 555, Name: "Some Partner"},
+						}, nil)
+					})
+
+					It("returns an error", func() {
+						_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+						Expect(err).To(MatchError(ContainSubstring("user group name 'Some Partner' is ambiguous")))
+					})
+				})
+
+				Context("when listing user groups fails", func() {
+					BeforeEach(func() {
+						pivnetClient.AllUserGroupsReturns(nil, errors.New("failed to list user groups"))
+					})
+
+					It("returns an error", func() {
+						_, err := userGroupsUpdater.UpdateUserGroups(pivnetRelease)
+						Expect(err).To(MatchError(errors.New("failed to list user groups")))
+					})
+				})
+			})
+
 			Context("when an error occurs", func() {
 				Context("when a user group ID cannpt be converted to a number", func() {
 					BeforeEach(func() {
@@ -0,0 +1,182 @@
+package platform
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+const (
+	Linux   = "linux"
+	Windows = "windows"
+	Darwin  = "darwin"
+
+	AMD64 = "amd64"
+	ARM64 = "arm64"
+)
+
+// OSes are the operating systems which platform_filter can select.
+var OSes = []string{Linux, Windows, Darwin}
+
+// Archs are the architectures which platform_filter can select.
+var Archs = []string{AMD64, ARM64}
+
+// osPlatforms maps the platforms of product files on Pivotal Network to the
+// operating systems they run on. Platforms which are not specific to an
+// operating system, e.g. BOSH or Generic, are omitted.
+var osPlatforms = map[string]string{
+	"Apt-Get":        Linux,
+	"CentOS":         Linux,
+	"Linux":          Linux,
+	"Oracle Linux":   Linux,
+	"RHEL":           Linux,
+	"SLES":           Linux,
+	"Ubuntu":         Linux,
+	"Yum":            Linux,
+	"MSI":            Windows,
+	"Windows":        Windows,
+	"Windows Server": Windows,
+	"Brew":           Darwin,
+	"OS X":           Darwin,
+}
+
+// osTokens maps the words of file names to the operating systems they
+// indicate, e.g. pivnet-windows-amd64.exe.
+var osTokens = map[string]string{
+	"linux":   Linux,
+	"windows": Windows,
+	"win":     Windows,
+	"win32":   Windows,
+	"win64":   Windows,
+	"exe":     Windows,
+	"msi":     Windows,
+	"darwin":  Darwin,
+	"mac":     Darwin,
+	"macos":   Darwin,
+	"osx":     Darwin,
+	"dmg":     Darwin,
+}
+
+// archTokens maps the words of file names to the architectures they
+// indicate. x86_64 and x86-64 are rewritten to amd64 before the file name is
+// split into words.
+var archTokens = map[string]string{
+	"amd64":   AMD64,
+	"x64":     AMD64,
+	"arm64":   ARM64,
+	"aarch64": ARM64,
+}
+
+// Platform is an operating system and, optionally, an architecture, e.g.
+// linux/amd64 or windows.
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// Parse parses a platform_filter of the form os or os/arch.
+func Parse(filter string) (Platform, error) {
+	parts := strings.SplitN(strings.ToLower(filter), "/", 2)
+
+	p := Platform{OS: parts[0]}
+	if len(parts) == 2 {
+		p.Arch = parts[1]
+	}
+
+	if !contains(OSes, p.OS) {
+		return Platform{}, fmt.Errorf(
+			"platform '%s' must have an os of: ['%s']",
+			filter,
+			strings.Join(OSes, "', '"),
+		)
+	}
+
+	if len(parts) == 2 && !contains(Archs, p.Arch) {
+		return Platform{}, fmt.Errorf(
+			"platform '%s' must have an arch of: ['%s']",
+			filter,
+			strings.Join(Archs, "', '"),
+		)
+	}
+
+	return p, nil
+}
+
+func (p Platform) String() string {
+	if p.Arch == "" {
+		return p.OS
+	}
+
+	return p.OS + "/" + p.Arch
+}
+
+// Matches returns whether the product file can be used on the platform.
+// The operating system of a file is taken from its platforms on Pivotal
+// Network, or otherwise from the words of its file name, and its architecture
+// from the words of its file name. Files which indicate no operating system
+// or architecture, and files which are not software, e.g. documentation,
+// match every platform.
+func (p Platform) Matches(productFile pivnet.ProductFile) bool {
+	if productFile.FileType != "" && productFile.FileType != pivnet.FileTypeSoftware {
+		return true
+	}
+
+	words := fileNameWords(productFile.AWSObjectKey)
+
+	oses := map[string]bool{}
+	for _, platform := range productFile.Platforms {
+		if os, ok := osPlatforms[platform]; ok {
+			oses[os] = true
+		}
+	}
+
+	if len(oses) == 0 {
+		for _, word := range words {
+			if os, ok := osTokens[word]; ok {
+				oses[os] = true
+			}
+		}
+	}
+
+	if len(oses) > 0 && !oses[p.OS] {
+		return false
+	}
+
+	if p.Arch == "" {
+		return true
+	}
+
+	archs := map[string]bool{}
+	for _, word := range words {
+		if arch, ok := archTokens[word]; ok {
+			archs[arch] = true
+		}
+	}
+
+	return len(archs) == 0 || archs[p.Arch]
+}
+
+// fileNameWords returns the lowercase words of the file name of the AWS
+// object key, split at every character which is not a letter or digit.
+func fileNameWords(awsObjectKey string) []string {
+	parts := strings.Split(awsObjectKey, "/")
+	fileName := strings.ToLower(parts[len(parts)-1])
+
+	fileName = strings.NewReplacer("x86_64", AMD64, "x86-64", AMD64).Replace(fileName)
+
+	return strings.FieldsFunc(fileName, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package platform_test
+
+import (
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/platform"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Platform", func() {
+	Describe("Parse", func() {
+		It("parses an os", func() {
+			p, err := platform.Parse("windows")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(Equal(platform.Platform{OS: platform.Windows}))
+		})
+
+		It("parses an os and arch, ignoring case", func() {
+			p, err := platform.Parse("Linux/AMD64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(Equal(platform.Platform{OS: platform.Linux, Arch: platform.AMD64}))
+			Expect(p.String()).To(Equal("linux/amd64"))
+		})
+
+		Context("when the os is not known", func() {
+			It("returns an error", func() {
+				_, err := platform.Parse("plan9/amd64")
+				Expect(err).To(MatchError("platform 'plan9/amd64' must have an os of: ['linux', 'windows', 'darwin']"))
+			})
+		})
+
+		Context("when the arch is not known", func() {
+			It("returns an error", func() {
+				_, err := platform.Parse("linux/s390x")
+				Expect(err).To(MatchError("platform 'linux/s390x' must have an arch of: ['amd64', 'arm64']"))
+			})
+		})
+	})
+
+	Describe("Matches", func() {
+		var (
+			p platform.Platform
+		)
+
+		BeforeEach(func() {
+			p = platform.Platform{OS: platform.Linux, Arch: platform.AMD64}
+		})
+
+		productFile := func(awsObjectKey string, platforms ...string) pivnet.ProductFile {
+			return pivnet.ProductFile{
+				AWSObjectKey: awsObjectKey,
+				FileType:     pivnet.FileTypeSoftware,
+				Platforms:    platforms,
+			}
+		}
+
+		It("matches files whose names indicate the platform", func() {
+			Expect(p.Matches(productFile("product/tool-linux-amd64"))).To(BeTrue())
+			Expect(p.Matches(productFile("product/tool_Linux_x86_64.tar.gz"))).To(BeTrue())
+		})
+
+		It("does not match files whose names indicate another os", func() {
+			Expect(p.Matches(productFile("product/tool-windows-amd64"))).To(BeFalse())
+			Expect(p.Matches(productFile("product/tool-installer.msi"))).To(BeFalse())
+			Expect(p.Matches(productFile("product/tool-darwin-amd64"))).To(BeFalse())
+		})
+
+		It("does not match files whose names indicate another arch", func() {
+			Expect(p.Matches(productFile("product/tool-linux-arm64"))).To(BeFalse())
+			Expect(p.Matches(productFile("product/tool-linux-aarch64"))).To(BeFalse())
+		})
+
+		It("matches files whose names indicate no platform", func() {
+			Expect(p.Matches(productFile("product/tile-1.2.3.pivotal"))).To(BeTrue())
+		})
+
+		It("matches files whose names indicate only the os", func() {
+			Expect(p.Matches(productFile("product/tool-linux"))).To(BeTrue())
+		})
+
+		It("matches files which are not software", func() {
+			docs := productFile("product/tool-windows-guide.pdf")
+			docs.FileType = pivnet.FileTypeDocumentation
+
+			Expect(p.Matches(docs)).To(BeTrue())
+		})
+
+		Context("when the file has platforms on Pivotal Network", func() {
+			It("uses them rather than the name of the file", func() {
+				Expect(p.Matches(productFile("product/tool.zip", "Windows"))).To(BeFalse())
+				Expect(p.Matches(productFile("product/tool-win.zip", "Ubuntu"))).To(BeTrue())
+			})
+
+			It("matches if any of them is of the os", func() {
+				Expect(p.Matches(productFile("product/tool.zip", "Windows", "RHEL"))).To(BeTrue())
+			})
+
+			It("falls back to the name of the file if none is specific to an os", func() {
+				Expect(p.Matches(productFile("product/tool-windows.zip", "Generic"))).To(BeFalse())
+			})
+		})
+
+		Context("when the platform has no arch", func() {
+			BeforeEach(func() {
+				p = platform.Platform{OS: platform.Linux}
+			})
+
+			It("matches files of any arch", func() {
+				Expect(p.Matches(productFile("product/tool-linux-arm64"))).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+// DefaultInterval is the minimum time between two logged progress updates.
+const DefaultInterval = 5 * time.Second
+
+// LogWriter logs the progress output of go-pivnet. The output redraws a
+// single terminal line using carriage returns, which Concourse does not
+// render, so each redrawn line is logged separately and at most once per
+// interval. Lines ending in a newline are always logged.
+type LogWriter struct {
+	logger   logger.Logger
+	interval time.Duration
+
+	mu         sync.Mutex
+	buf        []byte
+	lastLine   string
+	lastLogged time.Time
+}
+
+func NewLogWriter(logger logger.Logger, interval time.Duration) *LogWriter {
+	return &LogWriter{
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+func (w *LogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+
+		terminator := w.buf[i]
+		line := strings.TrimSpace(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+
+		w.log(line, terminator == '\n')
+	}
+
+	return len(p), nil
+}
+
+func (w *LogWriter) log(line string, force bool) {
+	if line == "" || line == w.lastLine {
+		return
+	}
+
+	now := time.Now()
+	if !force && now.Sub(w.lastLogged) < w.interval {
+		return
+	}
+
+	w.logger.Info(line)
+	w.lastLine = line
+	w.lastLogged = now
+}
@@ -0,0 +1,77 @@
+package progress_test
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/progress"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("LogWriter", func() {
+	var (
+		logBuffer *gbytes.Buffer
+		interval  time.Duration
+
+		writer *progress.LogWriter
+	)
+
+	BeforeEach(func() {
+		logBuffer = gbytes.NewBuffer()
+		interval = 0
+	})
+
+	JustBeforeEach(func() {
+		logger := log.New(logBuffer, "", 0)
+		writer = progress.NewLogWriter(logshim.NewLogShim(logger, logger, false), interval)
+	})
+
+	It("logs each redrawn line", func() {
+		n, err := writer.Write([]byte("\r10 MiB / 100 MiB\r20 MiB / 100 MiB"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(34))
+
+		_, err = writer.Write([]byte("\r100 MiB / 100 MiB\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(logBuffer).To(gbytes.Say("10 MiB / 100 MiB"))
+		Expect(logBuffer).To(gbytes.Say("20 MiB / 100 MiB"))
+		Expect(logBuffer).To(gbytes.Say("100 MiB / 100 MiB"))
+	})
+
+	It("does not log a line until it is complete", func() {
+		_, err := writer.Write([]byte("10 MiB"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(logBuffer.Contents()).To(BeEmpty())
+	})
+
+	It("does not log the same line twice in a row", func() {
+		_, err := writer.Write([]byte("10 MiB\r10 MiB\r10 MiB\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(strings.Count(string(logBuffer.Contents()), "10 MiB")).To(Equal(1))
+	})
+
+	Context("when lines are redrawn within the interval", func() {
+		BeforeEach(func() {
+			interval = time.Hour
+		})
+
+		It("only logs the first of them and lines ending in a newline", func() {
+			_, err := writer.Write([]byte("10 MiB\r20 MiB\r30 MiB\rdone\n"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logBuffer).To(gbytes.Say("10 MiB"))
+			Expect(logBuffer).To(gbytes.Say("done"))
+
+			Expect(string(logBuffer.Contents())).NotTo(ContainSubstring("20 MiB"))
+			Expect(string(logBuffer.Contents())).NotTo(ContainSubstring("30 MiB"))
+		})
+	})
+})
@@ -0,0 +1,196 @@
+package publishlock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+//go:generate counterfeiter --fake-name FakeStore . store
+type store interface {
+	Read(remotePath string) ([]byte, bool, error)
+	Write(remotePath string, content []byte) error
+	Delete(remotePath string) error
+}
+
+// lock is the content of the lock object.
+type lock struct {
+	Owner      string    `json:"owner"`
+	Token      string    `json:"token"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// Locker guards publishing a release with a lock object, so that only one
+// put publishes a given version at a time.
+//
+// The store does not support conditional writes, so the lock is acquired by
+// writing the lock object and then reading it back after the poll interval.
+// Of several puts racing to acquire the lock, only the last to write it sees
+// its own token and proceeds.
+type Locker struct {
+	store        store
+	key          string
+	owner        string
+	token        string
+	expiry       time.Duration
+	wait         time.Duration
+	pollInterval time.Duration
+	logger       logger.Logger
+}
+
+type Config struct {
+	Store store
+	// Key is the path of the lock object in the store.
+	Key string
+	// Owner describes the put holding the lock in log and error messages.
+	Owner string
+	// Expiry is the age after which a lock is considered stale, e.g. because
+	// the put holding it was aborted, and may be broken.
+	Expiry time.Duration
+	// Wait is how long to wait for a lock held by another put to be released.
+	Wait         time.Duration
+	PollInterval time.Duration
+	Logger       logger.Logger
+}
+
+func NewLocker(config Config) (*Locker, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Locker{
+		store:        config.Store,
+		key:          config.Key,
+		owner:        config.Owner,
+		token:        hex.EncodeToString(b),
+		expiry:       config.Expiry,
+		wait:         config.Wait,
+		pollInterval: config.PollInterval,
+		logger:       config.Logger,
+	}, nil
+}
+
+// Acquire takes the lock, breaking it if it is stale. If the lock is held by
+// another put it waits up to the configured wait for it to be released
+// before returning an error.
+func (l Locker) Acquire() error {
+	l.logger.Info(fmt.Sprintf("Acquiring publish lock: '%s'", l.key))
+
+	deadline := time.Now().Add(l.wait)
+
+	for {
+		held, err := l.read()
+		if err != nil {
+			return err
+		}
+
+		if held != nil && held.Token != l.token {
+			expiresAt := held.AcquiredAt.Add(l.expiry)
+
+			if time.Now().Before(expiresAt) {
+				if !time.Now().Before(deadline) {
+					return fmt.Errorf(
+						"publish lock '%s' is held by %s since %s and expires at %s",
+						l.key,
+						held.Owner,
+						held.AcquiredAt.Format(time.RFC3339),
+						expiresAt.Format(time.RFC3339),
+					)
+				}
+
+				l.logger.Info(fmt.Sprintf(
+					"Waiting for publish lock held by %s since %s",
+					held.Owner,
+					held.AcquiredAt.Format(time.RFC3339),
+				))
+
+				time.Sleep(l.pollInterval)
+				continue
+			}
+
+			l.logger.Info(fmt.Sprintf(
+				"Breaking stale publish lock held by %s since %s",
+				held.Owner,
+				held.AcquiredAt.Format(time.RFC3339),
+			))
+		}
+
+		content, err := json.Marshal(lock{
+			Owner:      l.owner,
+			Token:      l.token,
+			AcquiredAt: time.Now().UTC(),
+		})
+		if err != nil {
+			// Untested as it is too hard to force json.Marshal to return an error
+			return err
+		}
+
+		err = l.store.Write(l.key, content)
+		if err != nil {
+			return err
+		}
+
+		// Give any put which read the lock at the same time the chance to
+		// overwrite it before confirming that it is still ours.
+		time.Sleep(l.pollInterval)
+
+		held, err = l.read()
+		if err != nil {
+			return err
+		}
+
+		if held != nil && held.Token == l.token {
+			l.logger.Info(fmt.Sprintf("Acquired publish lock: '%s'", l.key))
+			return nil
+		}
+
+		l.logger.Info("Publish lock was taken by another put while acquiring it")
+	}
+}
+
+// Release deletes the lock if it is still held by this put. A lock which has
+// since been broken as stale and taken by another put is left in place.
+func (l Locker) Release() error {
+	held, err := l.read()
+	if err != nil {
+		return err
+	}
+
+	if held == nil || held.Token != l.token {
+		l.logger.Info(fmt.Sprintf("Publish lock '%s' is no longer held - not releasing", l.key))
+		return nil
+	}
+
+	l.logger.Info(fmt.Sprintf("Releasing publish lock: '%s'", l.key))
+
+	return l.store.Delete(l.key)
+}
+
+func (l Locker) read() (*lock, error) {
+	content, found, err := l.store.Read(l.key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	var held lock
+	err = json.Unmarshal(content, &held)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"publish lock '%s' could not be parsed - delete it to continue: %s",
+			l.key,
+			err.Error(),
+		)
+	}
+
+	return &held, nil
+}
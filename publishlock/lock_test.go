@@ -0,0 +1,261 @@
+package publishlock_test
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/publishlock"
+	"github.com/pivotal-cf/pivnet-resource/publishlock/publishlockfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Locker", func() {
+	const key = "product-files/some-product/locks/1.2.3.lock"
+
+	var (
+		fakeStore  *publishlockfakes.FakeStore
+		fakeLogger logger.Logger
+
+		objectsMutex sync.Mutex
+		objects      map[string][]byte
+
+		expiry time.Duration
+		wait   time.Duration
+
+		locker *publishlock.Locker
+	)
+
+	heldLock := func(owner string, acquiredAt time.Time) []byte {
+		b, err := json.Marshal(map[string]interface{}{
+			"owner":       owner,
+			"token":       "some-other-token",
+			"acquired_at": acquiredAt,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return b
+	}
+
+	BeforeEach(func() {
+		fakeStore = &publishlockfakes.FakeStore{}
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		objects = map[string][]byte{}
+
+		fakeStore.ReadStub = func(remotePath string) ([]byte, bool, error) {
+			objectsMutex.Lock()
+			defer objectsMutex.Unlock()
+
+			content, found := objects[remotePath]
+			return content, found, nil
+		}
+		fakeStore.WriteStub = func(remotePath string, content []byte) error {
+			objectsMutex.Lock()
+			defer objectsMutex.Unlock()
+
+			objects[remotePath] = content
+			return nil
+		}
+		fakeStore.DeleteStub = func(remotePath string) error {
+			objectsMutex.Lock()
+			defer objectsMutex.Unlock()
+
+			delete(objects, remotePath)
+			return nil
+		}
+
+		expiry = time.Hour
+		wait = 0
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		locker, err = publishlock.NewLocker(publishlock.Config{
+			Store:        fakeStore,
+			Key:          key,
+			Owner:        "some-pipeline/some-job #1",
+			Expiry:       expiry,
+			Wait:         wait,
+			PollInterval: 10 * time.Millisecond,
+			Logger:       fakeLogger,
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Acquire", func() {
+		It("writes the lock object", func() {
+			err := locker.Acquire()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.WriteCallCount()).To(Equal(1))
+			remotePath, content := fakeStore.WriteArgsForCall(0)
+			Expect(remotePath).To(Equal(key))
+
+			var written map[string]interface{}
+			err = json.Unmarshal(content, &written)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written["owner"]).To(Equal("some-pipeline/some-job #1"))
+			Expect(written["token"]).NotTo(BeEmpty())
+			Expect(written["acquired_at"]).NotTo(BeEmpty())
+		})
+
+		Context("when the lock is held by another put", func() {
+			BeforeEach(func() {
+				objects[key] = heldLock("other-pipeline/other-job #7", time.Now())
+			})
+
+			It("returns an error naming the holder", func() {
+				err := locker.Acquire()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is held by other-pipeline/other-job #7"))
+
+				Expect(fakeStore.WriteCallCount()).To(Equal(0))
+			})
+
+			Context("when waiting for the lock", func() {
+				BeforeEach(func() {
+					wait = time.Second
+
+					go func() {
+						defer GinkgoRecover()
+						time.Sleep(50 * time.Millisecond)
+
+						objectsMutex.Lock()
+						defer objectsMutex.Unlock()
+						delete(objects, key)
+					}()
+				})
+
+				It("acquires the lock once it is released", func() {
+					err := locker.Acquire()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeStore.WriteCallCount()).To(Equal(1))
+				})
+			})
+		})
+
+		Context("when the lock held by another put is stale", func() {
+			BeforeEach(func() {
+				objects[key] = heldLock("other-pipeline/other-job #7", time.Now().Add(-2*time.Hour))
+			})
+
+			It("breaks the lock and acquires it", func() {
+				err := locker.Acquire()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeStore.WriteCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when another put takes the lock while it is being acquired", func() {
+			BeforeEach(func() {
+				fakeStore.WriteStub = func(remotePath string, content []byte) error {
+					objectsMutex.Lock()
+					defer objectsMutex.Unlock()
+
+					if fakeStore.WriteCallCount() == 1 {
+						objects[remotePath] = heldLock("other-pipeline/other-job #7", time.Now())
+						return nil
+					}
+
+					objects[remotePath] = content
+					return nil
+				}
+			})
+
+			It("returns an error naming the put which took the lock", func() {
+				err := locker.Acquire()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("is held by other-pipeline/other-job #7"))
+			})
+		})
+
+		Context("when the lock object cannot be parsed", func() {
+			BeforeEach(func() {
+				objects[key] = []byte("not json")
+			})
+
+			It("returns an error", func() {
+				err := locker.Acquire()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("could not be parsed"))
+			})
+		})
+
+		Context("when reading the lock object returns an error", func() {
+			BeforeEach(func() {
+				fakeStore.ReadStub = nil
+				fakeStore.ReadReturns(nil, false, errors.New("some read error"))
+			})
+
+			It("returns the error", func() {
+				err := locker.Acquire()
+				Expect(err).To(MatchError("some read error"))
+			})
+		})
+
+		Context("when writing the lock object returns an error", func() {
+			BeforeEach(func() {
+				fakeStore.WriteStub = nil
+				fakeStore.WriteReturns(errors.New("some write error"))
+			})
+
+			It("returns the error", func() {
+				err := locker.Acquire()
+				Expect(err).To(MatchError("some write error"))
+			})
+		})
+	})
+
+	Describe("Release", func() {
+		It("deletes the lock object once acquired", func() {
+			err := locker.Acquire()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = locker.Release()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.DeleteCallCount()).To(Equal(1))
+			Expect(fakeStore.DeleteArgsForCall(0)).To(Equal(key))
+			Expect(objects).NotTo(HaveKey(key))
+		})
+
+		Context("when the lock has been taken by another put", func() {
+			It("leaves the lock object in place", func() {
+				err := locker.Acquire()
+				Expect(err).NotTo(HaveOccurred())
+
+				objects[key] = heldLock("other-pipeline/other-job #7", time.Now())
+
+				err = locker.Release()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeStore.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when deleting the lock object returns an error", func() {
+			BeforeEach(func() {
+				fakeStore.DeleteStub = nil
+				fakeStore.DeleteReturns(errors.New("some delete error"))
+			})
+
+			It("returns the error", func() {
+				err := locker.Acquire()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = locker.Release()
+				Expect(err).To(MatchError("some delete error"))
+			})
+		})
+	})
+})
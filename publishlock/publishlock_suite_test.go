@@ -0,0 +1,13 @@
+package publishlock_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPublishLock(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PublishLock Suite")
+}
@@ -0,0 +1,169 @@
+// This file was generated by counterfeiter
+package publishlockfakes
+
+import (
+	"sync"
+)
+
+type FakeStore struct {
+	ReadStub        func(remotePath string) ([]byte, bool, error)
+	readMutex       sync.RWMutex
+	readArgsForCall []struct {
+		remotePath string
+	}
+	readReturns struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}
+	WriteStub        func(remotePath string, content []byte) error
+	writeMutex       sync.RWMutex
+	writeArgsForCall []struct {
+		remotePath string
+		content    []byte
+	}
+	writeReturns struct {
+		result1 error
+	}
+	DeleteStub        func(remotePath string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		remotePath string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStore) Read(remotePath string) ([]byte, bool, error) {
+	fake.readMutex.Lock()
+	fake.readArgsForCall = append(fake.readArgsForCall, struct {
+		remotePath string
+	}{remotePath})
+	fake.recordInvocation("Read", []interface{}{remotePath})
+	fake.readMutex.Unlock()
+	if fake.ReadStub != nil {
+		return fake.ReadStub(remotePath)
+	} else {
+		return fake.readReturns.result1, fake.readReturns.result2, fake.readReturns.result3
+	}
+}
+
+func (fake *FakeStore) ReadCallCount() int {
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	return len(fake.readArgsForCall)
+}
+
+func (fake *FakeStore) ReadArgsForCall(i int) string {
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	return fake.readArgsForCall[i].remotePath
+}
+
+func (fake *FakeStore) ReadReturns(result1 []byte, result2 bool, result3 error) {
+	fake.ReadStub = nil
+	fake.readReturns = struct {
+		result1 []byte
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeStore) Write(remotePath string, content []byte) error {
+	var contentCopy []byte
+	if content != nil {
+		contentCopy = make([]byte, len(content))
+		copy(contentCopy, content)
+	}
+	fake.writeMutex.Lock()
+	fake.writeArgsForCall = append(fake.writeArgsForCall, struct {
+		remotePath string
+		content    []byte
+	}{remotePath, contentCopy})
+	fake.recordInvocation("Write", []interface{}{remotePath, contentCopy})
+	fake.writeMutex.Unlock()
+	if fake.WriteStub != nil {
+		return fake.WriteStub(remotePath, content)
+	} else {
+		return fake.writeReturns.result1
+	}
+}
+
+func (fake *FakeStore) WriteCallCount() int {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return len(fake.writeArgsForCall)
+}
+
+func (fake *FakeStore) WriteArgsForCall(i int) (string, []byte) {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return fake.writeArgsForCall[i].remotePath, fake.writeArgsForCall[i].content
+}
+
+func (fake *FakeStore) WriteReturns(result1 error) {
+	fake.WriteStub = nil
+	fake.writeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) Delete(remotePath string) error {
+	fake.deleteMutex.Lock()
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		remotePath string
+	}{remotePath})
+	fake.recordInvocation("Delete", []interface{}{remotePath})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(remotePath)
+	} else {
+		return fake.deleteReturns.result1
+	}
+}
+
+func (fake *FakeStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeStore) DeleteArgsForCall(i int) string {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.deleteArgsForCall[i].remotePath
+}
+
+func (fake *FakeStore) DeleteReturns(result1 error) {
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
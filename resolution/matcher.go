@@ -0,0 +1,449 @@
+package resolution
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+// availabilityTiers ranks the availabilities of releases on Pivotal Network
+// from the least to the most broad. Admins Only releases are not yet released.
+var availabilityTiers = map[string]int{
+	"Admins Only":               0,
+	"Selected User Groups Only": 1,
+	"All Users":                 2,
+}
+
+var sourceAvailabilityTiers = map[concourse.Availability]int{
+	concourse.AvailabilityAdminsOnly:         0,
+	concourse.AvailabilitySelectedUserGroups: 1,
+	concourse.AvailabilityAllUsers:           2,
+}
+
+//go:generate counterfeiter --fake-name FakeFilter . filter
+type filter interface {
+	ReleasesByReleaseType(releases []pivnet.Release, releaseType pivnet.ReleaseType) ([]pivnet.Release, error)
+	ReleasesByVersion(releases []pivnet.Release, version string) ([]pivnet.Release, error)
+	ReleasesByVersionConstraint(releases []pivnet.Release, constraint string) ([]pivnet.Release, error)
+}
+
+//go:generate counterfeiter --fake-name FakeSorter . sorter
+type sorter interface {
+	SortBySemver([]pivnet.Release) ([]pivnet.Release, error)
+	SortByReleaseDate([]pivnet.Release) ([]pivnet.Release, error)
+	SampleBySemver([]pivnet.Release, concourse.Sample) ([]pivnet.Release, error)
+}
+
+//go:generate counterfeiter --fake-name FakeReleaseLister . releaseLister
+type releaseLister interface {
+	ReleaseTypes() ([]pivnet.ReleaseType, error)
+	ReleasesForProductSlug(string) ([]pivnet.Release, error)
+	RecentReleasesForProductSlug(productSlug string, limit int) ([]pivnet.Release, error)
+}
+
+// Matcher finds the releases which satisfy the configuration of a source,
+// i.e. the releases whose versions check emits.
+type Matcher struct {
+	logger        logger.Logger
+	filter        filter
+	releaseLister releaseLister
+	semverSorter  sorter
+}
+
+func NewMatcher(
+	logger logger.Logger,
+	filter filter,
+	releaseLister releaseLister,
+	semverSorter sorter,
+) *Matcher {
+	return &Matcher{
+		logger:        logger,
+		filter:        filter,
+		releaseLister: releaseLister,
+		semverSorter:  semverSorter,
+	}
+}
+
+// Matching returns the releases that satisfy the release_type, channel,
+// product_version, version_constraint and sample configuration of the source,
+// newest first. Releases less broadly available than availability, or only
+// available to admins unless include_unreleased is set, are excluded, as are
+// releases dated before released_after. It also returns false if the product
+// has no releases at all, as opposed to none which satisfy the configuration.
+//
+// If fast_check is set the release type is not validated, so that the
+// releases are the only thing listed, as they are by Pinned.
+func (m Matcher) Matching(source concourse.Source) ([]pivnet.Release, bool, error) {
+	return m.matching(source, !source.FastCheck)
+}
+
+// Recent returns, of only the limit most recent releases of the product, those
+// that satisfy the configuration of the source, as Matching does. The release
+// type is not validated, so that a single request is made if limit releases
+// fit on one page.
+func (m Matcher) Recent(source concourse.Source, limit int) ([]pivnet.Release, error) {
+	if source.CheckLimit == 0 || source.CheckLimit > limit {
+		source.CheckLimit = limit
+	}
+
+	releases, _, err := m.matching(source, false)
+	return releases, err
+}
+
+func (m Matcher) matching(source concourse.Source, validateReleaseType bool) ([]pivnet.Release, bool, error) {
+	releaseType := source.ReleaseType
+
+	if validateReleaseType {
+		err := m.validateReleaseType(releaseType)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, t := range source.ChannelReleaseTypes {
+			err := m.validateReleaseType(t)
+			if err != nil {
+				return nil, false, fmt.Errorf("channel '%s': %s", source.Channel, err.Error())
+			}
+		}
+	}
+
+	productSlug := source.ProductSlug
+
+	m.logger.Info("Getting all releases")
+	releases, err := m.listReleases(productSlug, source.CheckLimit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, previousSlug := range source.PreviousSlugs {
+		if len(releases) > 0 {
+			break
+		}
+
+		m.logger.Info(fmt.Sprintf("No releases found - getting all releases for previous product slug: '%s'", previousSlug))
+		releases, err = m.listReleases(previousSlug, source.CheckLimit)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	// A product whose only releases are unreleased, or less broadly available
+	// than the source requires, is treated as having no releases yet, as it is
+	// by tokens which cannot see them.
+	minimumTier, reason := minimumAvailabilityTier(source)
+	if minimumTier > 0 {
+		available := make([]pivnet.Release, 0, len(releases))
+		for _, r := range releases {
+			if availabilityTier(r.Availability) >= minimumTier {
+				available = append(available, r)
+			}
+		}
+
+		m.logExcluded(releases, available, reason)
+		releases = available
+	}
+
+	// Likewise a product whose releases all predate released_after is treated
+	// as having no releases yet, so that a new pipeline starts from its next
+	// release rather than failing.
+	if source.ReleasedAfter != "" {
+		releasedAfter, err := time.Parse(concourse.ReleaseDateLayout, source.ReleasedAfter)
+		if err != nil {
+			return nil, false, fmt.Errorf("released_after '%s' is not a date: %s", source.ReleasedAfter, err.Error())
+		}
+
+		m.logger.Info(fmt.Sprintf("Filtering all releases by release date on or after: '%s'", source.ReleasedAfter))
+		recent := make([]pivnet.Release, 0, len(releases))
+		for _, r := range releases {
+			if !releasedBefore(r, releasedAfter) {
+				recent = append(recent, r)
+			}
+		}
+
+		m.logExcluded(releases, recent, fmt.Sprintf("released before released_after: '%s'", source.ReleasedAfter))
+		releases = recent
+	}
+
+	if len(releases) == 0 {
+		return nil, false, nil
+	}
+
+	for _, r := range releases {
+		m.logger.Debug(fmt.Sprintf(
+			"Considering release: '%s' (ID: %d, release type: '%s')",
+			r.Version,
+			r.ID,
+			r.ReleaseType,
+		))
+	}
+
+	if releaseType != "" {
+		m.logger.Info(fmt.Sprintf("Filtering all releases by release type: '%s'", releaseType))
+		filtered, err := m.filter.ReleasesByReleaseType(
+			releases,
+			pivnet.ReleaseType(releaseType),
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		m.logExcluded(releases, filtered, fmt.Sprintf("release type is not: '%s'", releaseType))
+		releases = filtered
+	}
+
+	if len(source.ChannelReleaseTypes) > 0 {
+		m.logger.Info(fmt.Sprintf("Filtering all releases by the release types of channel: '%s'", source.Channel))
+		filtered := make([]pivnet.Release, 0, len(releases))
+		for _, r := range releases {
+			if containsString(source.ChannelReleaseTypes, string(r.ReleaseType)) {
+				filtered = append(filtered, r)
+			}
+		}
+
+		m.logExcluded(releases, filtered, fmt.Sprintf("release type is not in channel: '%s'", source.Channel))
+		releases = filtered
+	}
+
+	version := source.ProductVersion
+	if version != "" {
+		m.logger.Info(fmt.Sprintf("Filtering all releases by product version: '%s'", version))
+		filtered, err := m.filter.ReleasesByVersion(releases, version)
+		if err != nil {
+			return nil, false, err
+		}
+
+		m.logExcluded(releases, filtered, fmt.Sprintf("version does not match product_version: '%s'", version))
+		releases = filtered
+	}
+
+	constraint := source.VersionConstraint
+	if constraint != "" {
+		m.logger.Info(fmt.Sprintf("Filtering all releases by version constraint: '%s'", constraint))
+		filtered, err := m.filter.ReleasesByVersionConstraint(releases, constraint)
+		if err != nil {
+			return nil, false, err
+		}
+
+		m.logExcluded(releases, filtered, fmt.Sprintf("version does not satisfy version_constraint: '%s'", constraint))
+		releases = filtered
+	}
+
+	switch source.SortBy {
+	case concourse.SortBySemver:
+		m.logger.Info("Sorting all releases by semver")
+		releases, err = m.semverSorter.SortBySemver(releases)
+		if err != nil {
+			return nil, false, err
+		}
+	case concourse.SortByReleaseDate:
+		m.logger.Info("Sorting all releases by release date")
+		releases, err = m.semverSorter.SortByReleaseDate(releases)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if source.Sample != concourse.SampleNone {
+		m.logger.Info(fmt.Sprintf("Sampling all releases by %s version", source.Sample))
+		sampled, err := m.semverSorter.SampleBySemver(releases, source.Sample)
+		if err != nil {
+			return nil, false, err
+		}
+
+		m.logExcluded(releases, sampled, fmt.Sprintf("not the oldest release of its %s version", source.Sample))
+		releases = sampled
+	}
+
+	if source.CheckLimit > 0 && len(releases) > source.CheckLimit {
+		m.logExcluded(releases, releases[:source.CheckLimit], fmt.Sprintf("beyond check_limit: %d", source.CheckLimit))
+		releases = releases[:source.CheckLimit]
+	}
+
+	return releases, true, nil
+}
+
+// listReleases lists only the most recent releases of the product if a limit
+// is provided, so that products with thousands of releases are not listed in
+// full, and all of them otherwise.
+func (m Matcher) listReleases(productSlug string, limit int) ([]pivnet.Release, error) {
+	if limit > 0 {
+		m.logger.Info(fmt.Sprintf("Limiting releases to the %d most recent", limit))
+		return m.releaseLister.RecentReleasesForProductSlug(productSlug, limit)
+	}
+
+	return m.releaseLister.ReleasesForProductSlug(productSlug)
+}
+
+// Pinned returns the release with exactly the pinned version, looking
+// under each previous slug in turn if the product slug has no such release.
+func (m Matcher) Pinned(source concourse.Source) (pivnet.Release, error) {
+	if !source.FastCheck {
+		err := m.validateReleaseType(source.ReleaseType)
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+	}
+
+	m.logger.Info(fmt.Sprintf("Finding pinned version: '%s'", source.PinnedVersion))
+
+	slugs := append([]string{source.ProductSlug}, source.PreviousSlugs...)
+	for _, slug := range slugs {
+		releases, err := m.releaseLister.ReleasesForProductSlug(slug)
+		if err != nil {
+			return pivnet.Release{}, err
+		}
+
+		for _, r := range releases {
+			if r.Version != source.PinnedVersion {
+				continue
+			}
+
+			if source.ReleaseType != "" && string(r.ReleaseType) != source.ReleaseType {
+				return pivnet.Release{}, fmt.Errorf(
+					"pinned_version '%s' has release type: '%s', not: '%s'",
+					source.PinnedVersion,
+					r.ReleaseType,
+					source.ReleaseType,
+				)
+			}
+
+			return r, nil
+		}
+	}
+
+	return pivnet.Release{}, fmt.Errorf("cannot find pinned_version '%s'", source.PinnedVersion)
+}
+
+// Latest returns the version of the newest release that satisfies the
+// configuration of the source, i.e. the version the first check of a new
+// pipeline would emit, or of the release with the pinned version.
+func (m Matcher) Latest(source concourse.Source) (concourse.Version, error) {
+	m.logger.Info("Resolving latest version")
+
+	if source.PinnedVersion != "" {
+		r, err := m.Pinned(source)
+		if err != nil {
+			return concourse.Version{}, err
+		}
+
+		return concourse.Version{
+			ProductVersion: r.Version,
+			ReleaseType:    string(r.ReleaseType),
+		}, nil
+	}
+
+	releases, _, err := m.Matching(source)
+	if err != nil {
+		return concourse.Version{}, err
+	}
+
+	if len(releases) == 0 {
+		return concourse.Version{}, fmt.Errorf("cannot find specified release")
+	}
+
+	v := Version(releases[0])
+
+	m.logger.Info(fmt.Sprintf("Latest version: %s", v))
+
+	return concourse.Version{
+		ProductVersion: v,
+		ReleaseType:    string(releases[0].ReleaseType),
+	}, nil
+}
+
+// minimumAvailabilityTier returns the tier of the least broad availability of
+// the releases which satisfy the source, along with the reason other releases
+// are excluded. Unless availability or include_unreleased is set, only
+// releases which are not yet released are excluded.
+func minimumAvailabilityTier(source concourse.Source) (int, string) {
+	if source.Availability != "" {
+		return sourceAvailabilityTiers[source.Availability], fmt.Sprintf("availability is below: '%s'", source.Availability)
+	}
+
+	if source.IncludeUnreleased {
+		return 0, ""
+	}
+
+	return 1, "availability is 'Admins Only' - set include_unreleased to include it"
+}
+
+// releasedBefore returns whether the release was released before the date.
+// Releases without a release date, e.g. from a local source which does not
+// record it, are treated as recent.
+func releasedBefore(release pivnet.Release, date time.Time) bool {
+	releaseDate, err := time.Parse(concourse.ReleaseDateLayout, release.ReleaseDate)
+	if err != nil {
+		return false
+	}
+
+	return releaseDate.Before(date)
+}
+
+// availabilityTier returns the tier of the availability of a release.
+// Releases without a known availability, e.g. from a local source which does
+// not record it, are treated as available to all users.
+func availabilityTier(availability string) int {
+	tier, ok := availabilityTiers[availability]
+	if !ok {
+		return availabilityTiers["All Users"]
+	}
+
+	return tier
+}
+
+// logExcluded logs each release in before that is not in after, along with
+// the reason it was filtered out.
+func (m Matcher) logExcluded(before []pivnet.Release, after []pivnet.Release, reason string) {
+	kept := map[int]bool{}
+	for _, r := range after {
+		kept[r.ID] = true
+	}
+
+	for _, r := range before {
+		if !kept[r.ID] {
+			m.logger.Debug(fmt.Sprintf(
+				"Excluded release: '%s' (ID: %d) - %s",
+				r.Version,
+				r.ID,
+				reason,
+			))
+		}
+	}
+}
+
+func (m Matcher) validateReleaseType(releaseType string) error {
+	m.logger.Info(fmt.Sprintf("Validating release type: '%s'", releaseType))
+	releaseTypes, err := m.releaseLister.ReleaseTypes()
+	if err != nil {
+		return err
+	}
+
+	releaseTypesAsStrings := make([]string, len(releaseTypes))
+	for i, r := range releaseTypes {
+		releaseTypesAsStrings[i] = string(r)
+	}
+
+	if releaseType != "" && !containsString(releaseTypesAsStrings, releaseType) {
+		releaseTypesPrintable := fmt.Sprintf("['%s']", strings.Join(releaseTypesAsStrings, "', '"))
+		return fmt.Errorf(
+			"provided release type: '%s' must be one of: %s",
+			releaseType,
+			releaseTypesPrintable,
+		)
+	}
+
+	return nil
+}
+
+func containsString(strings []string, str string) bool {
+	for _, s := range strings {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,514 @@
+package resolution_test
+
+import (
+	"errors"
+	"log"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
+	"github.com/pivotal-cf/pivnet-resource/resolution/resolutionfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Matcher", func() {
+	var (
+		fakeLogger logger.Logger
+
+		fakeFilter        *resolutionfakes.FakeFilter
+		fakeSorter        *resolutionfakes.FakeSorter
+		fakeReleaseLister *resolutionfakes.FakeReleaseLister
+
+		releases []pivnet.Release
+		source   concourse.Source
+
+		matcher *resolution.Matcher
+	)
+
+	BeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		releases = []pivnet.Release{
+			{ID: 3, Version: "1.3.0", ReleaseType: "Minor Release", SoftwareFilesUpdatedAt: "fingerprint-3"},
+			{ID: 2, Version: "1.2.1#hotfix", ReleaseType: "Security Release", SoftwareFilesUpdatedAt: "fingerprint-2"},
+			{ID: 1, Version: "1.2.0", ReleaseType: "Minor Release"},
+		}
+
+		source = concourse.Source{ProductSlug: "some-product"}
+
+		fakeFilter = &resolutionfakes.FakeFilter{}
+		fakeSorter = &resolutionfakes.FakeSorter{}
+		fakeReleaseLister = &resolutionfakes.FakeReleaseLister{}
+
+		fakeReleaseLister.ReleaseTypesReturns([]pivnet.ReleaseType{"Minor Release", "Security Release"}, nil)
+
+		matcher = resolution.NewMatcher(fakeLogger, fakeFilter, fakeReleaseLister, fakeSorter)
+	})
+
+	JustBeforeEach(func() {
+		fakeReleaseLister.ReleasesForProductSlugReturns(releases, nil)
+	})
+
+	Describe("Matching", func() {
+		It("returns all releases of the product", func() {
+			returned, found, err := matcher.Matching(source)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(found).To(BeTrue())
+			Expect(returned).To(Equal(releases))
+		})
+
+		It("returns releases whose versions the resolver resolves to the same releases", func() {
+			returned, _, err := matcher.Matching(source)
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeReleaseGetter := &resolutionfakes.FakeReleaseGetter{}
+			fakeReleaseGetter.GetReleaseStub = func(productSlug string, version string) (pivnet.Release, error) {
+				for _, r := range releases {
+					if r.Version == version {
+						return r, nil
+					}
+				}
+
+				Fail("unexpected version: " + version)
+				return pivnet.Release{}, nil
+			}
+			resolver := resolution.NewResolver(fakeLogger, fakeReleaseGetter)
+
+			for _, r := range returned {
+				resolved, _, err := resolver.Release(source.ProductSlug, nil, resolution.Version(r))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resolved).To(Equal(r))
+			}
+		})
+
+		Context("when a release is only available to admins", func() {
+			BeforeEach(func() {
+				releases[0].Availability = "Admins Only"
+				releases[1].Availability = "All Users"
+			})
+
+			It("excludes it by default", func() {
+				returned, found, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(found).To(BeTrue())
+				Expect(returned).To(Equal(releases[1:]))
+			})
+
+			Context("when include_unreleased is true", func() {
+				BeforeEach(func() {
+					source.IncludeUnreleased = true
+				})
+
+				It("includes it", func() {
+					returned, _, err := matcher.Matching(source)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(returned).To(Equal(releases))
+				})
+			})
+
+			Context("when include_unreleased is false", func() {
+				BeforeEach(func() {
+					source.IncludeUnreleased = false
+				})
+
+				It("excludes it", func() {
+					returned, found, err := matcher.Matching(source)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(found).To(BeTrue())
+					Expect(returned).To(Equal(releases[1:]))
+				})
+			})
+
+			Context("when every release is only available to admins", func() {
+				BeforeEach(func() {
+					releases = releases[:1]
+				})
+
+				It("returns false", func() {
+					_, found, err := matcher.Matching(source)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
+		Context("when an availability is provided", func() {
+			BeforeEach(func() {
+				releases[0].Availability = "Admins Only"
+				releases[1].Availability = "Selected User Groups Only"
+				releases[2].Availability = "All Users"
+			})
+
+			It("excludes releases which are less broadly available", func() {
+				source.Availability = concourse.AvailabilityAllUsers
+				returned, _, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(returned).To(Equal(releases[2:]))
+
+				source.Availability = concourse.AvailabilitySelectedUserGroups
+				returned, _, err = matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(returned).To(Equal(releases[1:]))
+
+				source.Availability = concourse.AvailabilityAdminsOnly
+				returned, _, err = matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(returned).To(Equal(releases))
+			})
+		})
+
+		Context("when released_after is provided", func() {
+			BeforeEach(func() {
+				releases[0].ReleaseDate = "2023-03-01"
+				releases[1].ReleaseDate = "2023-01-01"
+				releases[2].ReleaseDate = "2022-12-31"
+
+				source.ReleasedAfter = "2023-01-01"
+			})
+
+			It("excludes releases released before the date", func() {
+				returned, found, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(found).To(BeTrue())
+				Expect(returned).To(Equal(releases[:2]))
+			})
+
+			Context("when a release has no release date", func() {
+				BeforeEach(func() {
+					releases[2].ReleaseDate = ""
+				})
+
+				It("includes it", func() {
+					returned, _, err := matcher.Matching(source)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(returned).To(Equal(releases))
+				})
+			})
+
+			Context("when every release was released before the date", func() {
+				BeforeEach(func() {
+					source.ReleasedAfter = "2024-01-01"
+				})
+
+				It("returns false", func() {
+					_, found, err := matcher.Matching(source)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(found).To(BeFalse())
+				})
+			})
+
+			Context("when the date is not a date", func() {
+				BeforeEach(func() {
+					source.ReleasedAfter = "last year"
+				})
+
+				It("returns an error", func() {
+					_, _, err := matcher.Matching(source)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("released_after 'last year' is not a date"))
+				})
+			})
+		})
+
+		Context("when a release type is provided", func() {
+			BeforeEach(func() {
+				source.ReleaseType = "Minor Release"
+				fakeFilter.ReleasesByReleaseTypeReturns([]pivnet.Release{releases[0], releases[2]}, nil)
+			})
+
+			It("filters the releases by release type", func() {
+				returned, _, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal([]pivnet.Release{releases[0], releases[2]}))
+
+				_, invokedReleaseType := fakeFilter.ReleasesByReleaseTypeArgsForCall(0)
+				Expect(invokedReleaseType).To(Equal(pivnet.ReleaseType("Minor Release")))
+			})
+
+			Context("when the release type is not known", func() {
+				BeforeEach(func() {
+					source.ReleaseType = "Alpha Release"
+				})
+
+				It("returns an error", func() {
+					_, _, err := matcher.Matching(source)
+					Expect(err).To(MatchError("provided release type: 'Alpha Release' must be one of: ['Minor Release', 'Security Release']"))
+				})
+			})
+
+			Context("when fast_check is set", func() {
+				BeforeEach(func() {
+					source.FastCheck = true
+				})
+
+				It("filters the releases without listing the release types", func() {
+					returned, _, err := matcher.Matching(source)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(returned).To(Equal([]pivnet.Release{releases[0], releases[2]}))
+					Expect(fakeReleaseLister.ReleaseTypesCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("when a channel restricts the release types", func() {
+			BeforeEach(func() {
+				source.Channel = "some-channel"
+				source.ChannelReleaseTypes = []string{"Security Release"}
+			})
+
+			It("returns only the releases of those release types", func() {
+				returned, _, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal([]pivnet.Release{releases[1]}))
+			})
+
+			Context("when a release type of the channel is not known", func() {
+				BeforeEach(func() {
+					source.ChannelReleaseTypes = []string{"Security Release", "Alpha Release"}
+				})
+
+				It("returns an error", func() {
+					_, _, err := matcher.Matching(source)
+					Expect(err).To(MatchError(ContainSubstring("channel 'some-channel': provided release type: 'Alpha Release'")))
+				})
+			})
+		})
+
+		Context("when a version constraint is provided", func() {
+			BeforeEach(func() {
+				source.VersionConstraint = "~> 1.3"
+				fakeFilter.ReleasesByVersionConstraintReturns([]pivnet.Release{releases[0]}, nil)
+			})
+
+			It("filters the releases by version constraint", func() {
+				returned, _, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal([]pivnet.Release{releases[0]}))
+
+				invokedReleases, invokedConstraint := fakeFilter.ReleasesByVersionConstraintArgsForCall(0)
+				Expect(invokedReleases).To(Equal(releases))
+				Expect(invokedConstraint).To(Equal("~> 1.3"))
+			})
+
+			Context("when filtering returns an error", func() {
+				BeforeEach(func() {
+					fakeFilter.ReleasesByVersionConstraintReturns(nil, errors.New("filter error"))
+				})
+
+				It("returns the error", func() {
+					_, _, err := matcher.Matching(source)
+					Expect(err).To(MatchError("filter error"))
+				})
+			})
+		})
+
+		Context("when sort_by is semver", func() {
+			BeforeEach(func() {
+				source.SortBy = concourse.SortBySemver
+				fakeSorter.SortBySemverReturns([]pivnet.Release{releases[2]}, nil)
+			})
+
+			It("sorts the releases", func() {
+				returned, _, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal([]pivnet.Release{releases[2]}))
+			})
+		})
+
+		Context("when sort_by is release_date", func() {
+			BeforeEach(func() {
+				source.SortBy = concourse.SortByReleaseDate
+				fakeSorter.SortByReleaseDateReturns([]pivnet.Release{releases[1], releases[0]}, nil)
+			})
+
+			It("sorts the releases by release date", func() {
+				returned, _, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal([]pivnet.Release{releases[1], releases[0]}))
+				Expect(fakeSorter.SortByReleaseDateCallCount()).To(Equal(1))
+				Expect(fakeSorter.SortBySemverCallCount()).To(Equal(0))
+			})
+
+			Context("when sorting returns an error", func() {
+				BeforeEach(func() {
+					fakeSorter.SortByReleaseDateReturns(nil, errors.New("sort error"))
+				})
+
+				It("returns the error", func() {
+					_, _, err := matcher.Matching(source)
+					Expect(err).To(MatchError("sort error"))
+				})
+			})
+		})
+
+		Context("when a check limit is provided", func() {
+			BeforeEach(func() {
+				source.CheckLimit = 2
+			})
+
+			JustBeforeEach(func() {
+				fakeReleaseLister.RecentReleasesForProductSlugReturns(releases[:2], nil)
+			})
+
+			It("lists only the most recent releases", func() {
+				returned, _, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal(releases[:2]))
+
+				Expect(fakeReleaseLister.ReleasesForProductSlugCallCount()).To(Equal(0))
+				Expect(fakeReleaseLister.RecentReleasesForProductSlugCallCount()).To(Equal(1))
+				productSlug, limit := fakeReleaseLister.RecentReleasesForProductSlugArgsForCall(0)
+				Expect(productSlug).To(Equal("some-product"))
+				Expect(limit).To(Equal(2))
+			})
+
+			Context("when more releases than the limit are returned", func() {
+				JustBeforeEach(func() {
+					fakeReleaseLister.RecentReleasesForProductSlugReturns(releases, nil)
+				})
+
+				It("returns at most the limit", func() {
+					returned, _, err := matcher.Matching(source)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(returned).To(Equal(releases[:2]))
+				})
+			})
+
+			Context("when listing the releases returns an error", func() {
+				JustBeforeEach(func() {
+					fakeReleaseLister.RecentReleasesForProductSlugReturns(nil, errors.New("list error"))
+				})
+
+				It("returns the error", func() {
+					_, _, err := matcher.Matching(source)
+					Expect(err).To(MatchError("list error"))
+				})
+			})
+		})
+
+		Context("when the product has no releases", func() {
+			BeforeEach(func() {
+				releases = nil
+			})
+
+			It("returns false", func() {
+				_, found, err := matcher.Matching(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Pinned", func() {
+		BeforeEach(func() {
+			source.PinnedVersion = "1.2.0"
+		})
+
+		It("returns the release of the pinned version", func() {
+			returned, err := matcher.Pinned(source)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(returned).To(Equal(releases[2]))
+		})
+
+		Context("when no release has the pinned version", func() {
+			BeforeEach(func() {
+				source.PinnedVersion = "9.9.9"
+			})
+
+			It("returns an error", func() {
+				_, err := matcher.Pinned(source)
+				Expect(err).To(MatchError("cannot find pinned_version '9.9.9'"))
+			})
+		})
+	})
+
+	Describe("Latest", func() {
+		It("returns the version of the newest release and its release type", func() {
+			v, err := matcher.Latest(source)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(v).To(Equal(concourse.Version{
+				ProductVersion: "1.3.0#fingerprint-3",
+				ReleaseType:    "Minor Release",
+			}))
+		})
+
+		Context("when a product version is provided", func() {
+			BeforeEach(func() {
+				source.ProductVersion = "1.2.*"
+				fakeFilter.ReleasesByVersionReturns([]pivnet.Release{releases[1], releases[2]}, nil)
+			})
+
+			It("returns the version of the newest release with that version", func() {
+				v, err := matcher.Latest(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(v.ProductVersion).To(Equal("1.2.1#hotfix#fingerprint-2"))
+
+				_, invokedVersion := fakeFilter.ReleasesByVersionArgsForCall(0)
+				Expect(invokedVersion).To(Equal("1.2.*"))
+			})
+		})
+
+		Context("when a pinned version is provided", func() {
+			BeforeEach(func() {
+				source.PinnedVersion = "1.2.0"
+			})
+
+			It("returns the pinned version", func() {
+				v, err := matcher.Latest(source)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(v).To(Equal(concourse.Version{
+					ProductVersion: "1.2.0",
+					ReleaseType:    "Minor Release",
+				}))
+			})
+		})
+
+		Context("when no releases match", func() {
+			BeforeEach(func() {
+				releases = []pivnet.Release{}
+			})
+
+			It("returns an error", func() {
+				_, err := matcher.Latest(source)
+				Expect(err).To(MatchError("cannot find specified release"))
+			})
+		})
+
+		Context("when listing releases returns an error", func() {
+			JustBeforeEach(func() {
+				fakeReleaseLister.ReleasesForProductSlugReturns(nil, errors.New("list error"))
+			})
+
+			It("returns the error", func() {
+				_, err := matcher.Latest(source)
+				Expect(err).To(MatchError("list error"))
+			})
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package resolution
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+// ProductFilesFingerprint returns a fingerprint of the product files of a
+// release, computed from the ID and checksum of each file, so that it
+// changes when files are added, removed or replaced under the same version.
+// The order of the files does not affect it. The SHA256 of a file is used if
+// it has one, and its MD5 otherwise.
+func ProductFilesFingerprint(productFiles []pivnet.ProductFile) string {
+	files := make([]pivnet.ProductFile, len(productFiles))
+	copy(files, productFiles)
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ID < files[j].ID
+	})
+
+	h := sha256.New()
+	for _, f := range files {
+		checksum := f.SHA256
+		if checksum == "" {
+			checksum = f.MD5
+		}
+
+		fmt.Fprintf(h, "%d:%s\n", f.ID, checksum)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
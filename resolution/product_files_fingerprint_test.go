@@ -0,0 +1,44 @@
+package resolution_test
+
+import (
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ProductFilesFingerprint", func() {
+	var (
+		productFiles []pivnet.ProductFile
+	)
+
+	BeforeEach(func() {
+		productFiles = []pivnet.ProductFile{
+			{ID: 1, SHA256: "some-sha256"},
+			{ID: 2, MD5: "some-md5"},
+		}
+	})
+
+	It("returns a short fingerprint", func() {
+		Expect(resolution.ProductFilesFingerprint(productFiles)).To(MatchRegexp("^[0-9a-f]{16}$"))
+	})
+
+	It("does not depend on the order of the files", func() {
+		reordered := []pivnet.ProductFile{productFiles[1], productFiles[0]}
+
+		Expect(resolution.ProductFilesFingerprint(reordered)).To(Equal(resolution.ProductFilesFingerprint(productFiles)))
+	})
+
+	It("changes when a file is added", func() {
+		added := append([]pivnet.ProductFile{{ID: 3, SHA256: "other-sha256"}}, productFiles...)
+
+		Expect(resolution.ProductFilesFingerprint(added)).NotTo(Equal(resolution.ProductFilesFingerprint(productFiles)))
+	})
+
+	It("changes when a file is replaced", func() {
+		replaced := []pivnet.ProductFile{productFiles[0], {ID: 2, MD5: "other-md5"}}
+
+		Expect(resolution.ProductFilesFingerprint(replaced)).NotTo(Equal(resolution.ProductFilesFingerprint(productFiles)))
+	})
+})
@@ -0,0 +1,125 @@
+// This file was generated by counterfeiter
+package resolutionfakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+type FakeReleaseGetter struct {
+	GetReleaseStub        func(productSlug string, version string) (go_pivnet.Release, error)
+	getReleaseMutex       sync.RWMutex
+	getReleaseArgsForCall []struct {
+		productSlug string
+		version     string
+	}
+	getReleaseReturns struct {
+		result1 go_pivnet.Release
+		result2 error
+	}
+	GetReleaseByIDStub        func(productSlug string, releaseID int) (go_pivnet.Release, error)
+	getReleaseByIDMutex       sync.RWMutex
+	getReleaseByIDArgsForCall []struct {
+		productSlug string
+		releaseID   int
+	}
+	getReleaseByIDReturns struct {
+		result1 go_pivnet.Release
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeReleaseGetter) GetRelease(productSlug string, version string) (go_pivnet.Release, error) {
+	fake.getReleaseMutex.Lock()
+	fake.getReleaseArgsForCall = append(fake.getReleaseArgsForCall, struct {
+		productSlug string
+		version     string
+	}{productSlug, version})
+	fake.recordInvocation("GetRelease", []interface{}{productSlug, version})
+	fake.getReleaseMutex.Unlock()
+	if fake.GetReleaseStub != nil {
+		return fake.GetReleaseStub(productSlug, version)
+	} else {
+		return fake.getReleaseReturns.result1, fake.getReleaseReturns.result2
+	}
+}
+
+func (fake *FakeReleaseGetter) GetReleaseCallCount() int {
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	return len(fake.getReleaseArgsForCall)
+}
+
+func (fake *FakeReleaseGetter) GetReleaseArgsForCall(i int) (string, string) {
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	return fake.getReleaseArgsForCall[i].productSlug, fake.getReleaseArgsForCall[i].version
+}
+
+func (fake *FakeReleaseGetter) GetReleaseReturns(result1 go_pivnet.Release, result2 error) {
+	fake.GetReleaseStub = nil
+	fake.getReleaseReturns = struct {
+		result1 go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReleaseGetter) GetReleaseByID(productSlug string, releaseID int) (go_pivnet.Release, error) {
+	fake.getReleaseByIDMutex.Lock()
+	fake.getReleaseByIDArgsForCall = append(fake.getReleaseByIDArgsForCall, struct {
+		productSlug string
+		releaseID   int
+	}{productSlug, releaseID})
+	fake.recordInvocation("GetReleaseByID", []interface{}{productSlug, releaseID})
+	fake.getReleaseByIDMutex.Unlock()
+	if fake.GetReleaseByIDStub != nil {
+		return fake.GetReleaseByIDStub(productSlug, releaseID)
+	} else {
+		return fake.getReleaseByIDReturns.result1, fake.getReleaseByIDReturns.result2
+	}
+}
+
+func (fake *FakeReleaseGetter) GetReleaseByIDCallCount() int {
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
+	return len(fake.getReleaseByIDArgsForCall)
+}
+
+func (fake *FakeReleaseGetter) GetReleaseByIDArgsForCall(i int) (string, int) {
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
+	return fake.getReleaseByIDArgsForCall[i].productSlug, fake.getReleaseByIDArgsForCall[i].releaseID
+}
+
+func (fake *FakeReleaseGetter) GetReleaseByIDReturns(result1 go_pivnet.Release, result2 error) {
+	fake.GetReleaseByIDStub = nil
+	fake.getReleaseByIDReturns = struct {
+		result1 go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReleaseGetter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getReleaseMutex.RLock()
+	defer fake.getReleaseMutex.RUnlock()
+	fake.getReleaseByIDMutex.RLock()
+	defer fake.getReleaseByIDMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeReleaseGetter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
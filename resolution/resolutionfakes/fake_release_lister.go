@@ -0,0 +1,158 @@
+// This file was generated by counterfeiter
+package resolutionfakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+type FakeReleaseLister struct {
+	ReleaseTypesStub        func() ([]go_pivnet.ReleaseType, error)
+	releaseTypesMutex       sync.RWMutex
+	releaseTypesArgsForCall []struct{}
+	releaseTypesReturns     struct {
+		result1 []go_pivnet.ReleaseType
+		result2 error
+	}
+	ReleasesForProductSlugStub        func(string) ([]go_pivnet.Release, error)
+	releasesForProductSlugMutex       sync.RWMutex
+	releasesForProductSlugArgsForCall []struct {
+		arg1 string
+	}
+	releasesForProductSlugReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	RecentReleasesForProductSlugStub        func(productSlug string, limit int) ([]go_pivnet.Release, error)
+	recentReleasesForProductSlugMutex       sync.RWMutex
+	recentReleasesForProductSlugArgsForCall []struct {
+		productSlug string
+		limit       int
+	}
+	recentReleasesForProductSlugReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeReleaseLister) ReleaseTypes() ([]go_pivnet.ReleaseType, error) {
+	fake.releaseTypesMutex.Lock()
+	fake.releaseTypesArgsForCall = append(fake.releaseTypesArgsForCall, struct{}{})
+	fake.recordInvocation("ReleaseTypes", []interface{}{})
+	fake.releaseTypesMutex.Unlock()
+	if fake.ReleaseTypesStub != nil {
+		return fake.ReleaseTypesStub()
+	} else {
+		return fake.releaseTypesReturns.result1, fake.releaseTypesReturns.result2
+	}
+}
+
+func (fake *FakeReleaseLister) ReleaseTypesCallCount() int {
+	fake.releaseTypesMutex.RLock()
+	defer fake.releaseTypesMutex.RUnlock()
+	return len(fake.releaseTypesArgsForCall)
+}
+
+func (fake *FakeReleaseLister) ReleaseTypesReturns(result1 []go_pivnet.ReleaseType, result2 error) {
+	fake.ReleaseTypesStub = nil
+	fake.releaseTypesReturns = struct {
+		result1 []go_pivnet.ReleaseType
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReleaseLister) ReleasesForProductSlug(arg1 string) ([]go_pivnet.Release, error) {
+	fake.releasesForProductSlugMutex.Lock()
+	fake.releasesForProductSlugArgsForCall = append(fake.releasesForProductSlugArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ReleasesForProductSlug", []interface{}{arg1})
+	fake.releasesForProductSlugMutex.Unlock()
+	if fake.ReleasesForProductSlugStub != nil {
+		return fake.ReleasesForProductSlugStub(arg1)
+	} else {
+		return fake.releasesForProductSlugReturns.result1, fake.releasesForProductSlugReturns.result2
+	}
+}
+
+func (fake *FakeReleaseLister) ReleasesForProductSlugCallCount() int {
+	fake.releasesForProductSlugMutex.RLock()
+	defer fake.releasesForProductSlugMutex.RUnlock()
+	return len(fake.releasesForProductSlugArgsForCall)
+}
+
+func (fake *FakeReleaseLister) ReleasesForProductSlugArgsForCall(i int) string {
+	fake.releasesForProductSlugMutex.RLock()
+	defer fake.releasesForProductSlugMutex.RUnlock()
+	return fake.releasesForProductSlugArgsForCall[i].arg1
+}
+
+func (fake *FakeReleaseLister) ReleasesForProductSlugReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.ReleasesForProductSlugStub = nil
+	fake.releasesForProductSlugReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReleaseLister) RecentReleasesForProductSlug(productSlug string, limit int) ([]go_pivnet.Release, error) {
+	fake.recentReleasesForProductSlugMutex.Lock()
+	fake.recentReleasesForProductSlugArgsForCall = append(fake.recentReleasesForProductSlugArgsForCall, struct {
+		productSlug string
+		limit       int
+	}{productSlug, limit})
+	fake.recordInvocation("RecentReleasesForProductSlug", []interface{}{productSlug, limit})
+	fake.recentReleasesForProductSlugMutex.Unlock()
+	if fake.RecentReleasesForProductSlugStub != nil {
+		return fake.RecentReleasesForProductSlugStub(productSlug, limit)
+	} else {
+		return fake.recentReleasesForProductSlugReturns.result1, fake.recentReleasesForProductSlugReturns.result2
+	}
+}
+
+func (fake *FakeReleaseLister) RecentReleasesForProductSlugCallCount() int {
+	fake.recentReleasesForProductSlugMutex.RLock()
+	defer fake.recentReleasesForProductSlugMutex.RUnlock()
+	return len(fake.recentReleasesForProductSlugArgsForCall)
+}
+
+func (fake *FakeReleaseLister) RecentReleasesForProductSlugArgsForCall(i int) (string, int) {
+	fake.recentReleasesForProductSlugMutex.RLock()
+	defer fake.recentReleasesForProductSlugMutex.RUnlock()
+	return fake.recentReleasesForProductSlugArgsForCall[i].productSlug, fake.recentReleasesForProductSlugArgsForCall[i].limit
+}
+
+func (fake *FakeReleaseLister) RecentReleasesForProductSlugReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.RecentReleasesForProductSlugStub = nil
+	fake.recentReleasesForProductSlugReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeReleaseLister) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.releaseTypesMutex.RLock()
+	defer fake.releaseTypesMutex.RUnlock()
+	fake.releasesForProductSlugMutex.RLock()
+	defer fake.releasesForProductSlugMutex.RUnlock()
+	fake.recentReleasesForProductSlugMutex.RLock()
+	defer fake.recentReleasesForProductSlugMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeReleaseLister) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
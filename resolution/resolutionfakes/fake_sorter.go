@@ -0,0 +1,184 @@
+// This file was generated by counterfeiter
+package resolutionfakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+type FakeSorter struct {
+	SortBySemverStub        func([]go_pivnet.Release) ([]go_pivnet.Release, error)
+	sortBySemverMutex       sync.RWMutex
+	sortBySemverArgsForCall []struct {
+		arg1 []go_pivnet.Release
+	}
+	sortBySemverReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	SampleBySemverStub        func(arg1 []go_pivnet.Release, arg2 concourse.Sample) ([]go_pivnet.Release, error)
+	sampleBySemverMutex       sync.RWMutex
+	sampleBySemverArgsForCall []struct {
+		arg1 []go_pivnet.Release
+		arg2 concourse.Sample
+	}
+	sampleBySemverReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	SortByReleaseDateStub        func([]go_pivnet.Release) ([]go_pivnet.Release, error)
+	sortByReleaseDateMutex       sync.RWMutex
+	sortByReleaseDateArgsForCall []struct {
+		arg1 []go_pivnet.Release
+	}
+	sortByReleaseDateReturns struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSorter) SortBySemver(arg1 []go_pivnet.Release) ([]go_pivnet.Release, error) {
+	var arg1Copy []go_pivnet.Release
+	if arg1 != nil {
+		arg1Copy = make([]go_pivnet.Release, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.sortBySemverMutex.Lock()
+	fake.sortBySemverArgsForCall = append(fake.sortBySemverArgsForCall, struct {
+		arg1 []go_pivnet.Release
+	}{arg1Copy})
+	fake.recordInvocation("SortBySemver", []interface{}{arg1Copy})
+	fake.sortBySemverMutex.Unlock()
+	if fake.SortBySemverStub != nil {
+		return fake.SortBySemverStub(arg1)
+	} else {
+		return fake.sortBySemverReturns.result1, fake.sortBySemverReturns.result2
+	}
+}
+
+func (fake *FakeSorter) SortBySemverCallCount() int {
+	fake.sortBySemverMutex.RLock()
+	defer fake.sortBySemverMutex.RUnlock()
+	return len(fake.sortBySemverArgsForCall)
+}
+
+func (fake *FakeSorter) SortBySemverArgsForCall(i int) []go_pivnet.Release {
+	fake.sortBySemverMutex.RLock()
+	defer fake.sortBySemverMutex.RUnlock()
+	return fake.sortBySemverArgsForCall[i].arg1
+}
+
+func (fake *FakeSorter) SortBySemverReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.SortBySemverStub = nil
+	fake.sortBySemverReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSorter) SampleBySemver(arg1 []go_pivnet.Release, arg2 concourse.Sample) ([]go_pivnet.Release, error) {
+	var arg1Copy []go_pivnet.Release
+	if arg1 != nil {
+		arg1Copy = make([]go_pivnet.Release, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.sampleBySemverMutex.Lock()
+	fake.sampleBySemverArgsForCall = append(fake.sampleBySemverArgsForCall, struct {
+		arg1 []go_pivnet.Release
+		arg2 concourse.Sample
+	}{arg1Copy, arg2})
+	fake.recordInvocation("SampleBySemver", []interface{}{arg1Copy, arg2})
+	fake.sampleBySemverMutex.Unlock()
+	if fake.SampleBySemverStub != nil {
+		return fake.SampleBySemverStub(arg1, arg2)
+	} else {
+		return fake.sampleBySemverReturns.result1, fake.sampleBySemverReturns.result2
+	}
+}
+
+func (fake *FakeSorter) SampleBySemverCallCount() int {
+	fake.sampleBySemverMutex.RLock()
+	defer fake.sampleBySemverMutex.RUnlock()
+	return len(fake.sampleBySemverArgsForCall)
+}
+
+func (fake *FakeSorter) SampleBySemverArgsForCall(i int) ([]go_pivnet.Release, concourse.Sample) {
+	fake.sampleBySemverMutex.RLock()
+	defer fake.sampleBySemverMutex.RUnlock()
+	return fake.sampleBySemverArgsForCall[i].arg1, fake.sampleBySemverArgsForCall[i].arg2
+}
+
+func (fake *FakeSorter) SampleBySemverReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.SampleBySemverStub = nil
+	fake.sampleBySemverReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSorter) SortByReleaseDate(arg1 []go_pivnet.Release) ([]go_pivnet.Release, error) {
+	var arg1Copy []go_pivnet.Release
+	if arg1 != nil {
+		arg1Copy = make([]go_pivnet.Release, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.sortByReleaseDateMutex.Lock()
+	fake.sortByReleaseDateArgsForCall = append(fake.sortByReleaseDateArgsForCall, struct {
+		arg1 []go_pivnet.Release
+	}{arg1Copy})
+	fake.recordInvocation("SortByReleaseDate", []interface{}{arg1Copy})
+	fake.sortByReleaseDateMutex.Unlock()
+	if fake.SortByReleaseDateStub != nil {
+		return fake.SortByReleaseDateStub(arg1)
+	} else {
+		return fake.sortByReleaseDateReturns.result1, fake.sortByReleaseDateReturns.result2
+	}
+}
+
+func (fake *FakeSorter) SortByReleaseDateCallCount() int {
+	fake.sortByReleaseDateMutex.RLock()
+	defer fake.sortByReleaseDateMutex.RUnlock()
+	return len(fake.sortByReleaseDateArgsForCall)
+}
+
+func (fake *FakeSorter) SortByReleaseDateArgsForCall(i int) []go_pivnet.Release {
+	fake.sortByReleaseDateMutex.RLock()
+	defer fake.sortByReleaseDateMutex.RUnlock()
+	return fake.sortByReleaseDateArgsForCall[i].arg1
+}
+
+func (fake *FakeSorter) SortByReleaseDateReturns(result1 []go_pivnet.Release, result2 error) {
+	fake.SortByReleaseDateStub = nil
+	fake.sortByReleaseDateReturns = struct {
+		result1 []go_pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSorter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.sortBySemverMutex.RLock()
+	defer fake.sortBySemverMutex.RUnlock()
+	fake.sampleBySemverMutex.RLock()
+	defer fake.sampleBySemverMutex.RUnlock()
+	fake.sortByReleaseDateMutex.RLock()
+	defer fake.sortByReleaseDateMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeSorter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
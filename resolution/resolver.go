@@ -0,0 +1,142 @@
+package resolution
+
+import (
+	"fmt"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+//go:generate counterfeiter --fake-name FakeReleaseGetter . releaseGetter
+type releaseGetter interface {
+	GetRelease(productSlug string, version string) (pivnet.Release, error)
+	GetReleaseByID(productSlug string, releaseID int) (pivnet.Release, error)
+}
+
+// Resolver resolves the versions emitted by check, with or without a
+// fingerprint, to their releases.
+type Resolver struct {
+	logger        logger.Logger
+	releaseGetter releaseGetter
+}
+
+func NewResolver(logger logger.Logger, releaseGetter releaseGetter) *Resolver {
+	return &Resolver{
+		logger:        logger,
+		releaseGetter: releaseGetter,
+	}
+}
+
+// Release returns the release of the version along with the product slug it
+// was found under. As check falls back to each of the previous slugs in
+// turn, so does Release if the release cannot be found for the product slug.
+// If the version has a fingerprint, it must match the fingerprint of the
+// release, as Pivotal Network does not support downloading old versions of a
+// release.
+func (r Resolver) Release(
+	productSlug string,
+	previousSlugs []string,
+	productVersion string,
+) (pivnet.Release, string, error) {
+	version, fingerprint := SplitVersion(productVersion)
+	if fingerprint == "" {
+		r.logger.Info("No fingerprint provided; continuing without it")
+	}
+
+	r.logger.Info(fmt.Sprintf(
+		"Getting release for product slug: '%s' and product version: '%s'",
+		productSlug,
+		version,
+	))
+
+	release, foundSlug, err := r.getRelease(productSlug, previousSlugs, func(slug string) (pivnet.Release, error) {
+		return r.releaseGetter.GetRelease(slug, version)
+	})
+	if err != nil {
+		return pivnet.Release{}, "", err
+	}
+
+	err = checkFingerprint(release, fingerprint)
+	if err != nil {
+		return pivnet.Release{}, "", err
+	}
+
+	return release, foundSlug, nil
+}
+
+// ReleaseByID returns the release with the ID, e.g. the release_id of a
+// version emitted by check, along with the product slug it was found under.
+// Unlike the version, the ID of a release never changes, so the release is
+// found even if its version has since been edited or reused. The fingerprint
+// of the product version, if any, must still match, as with Release.
+func (r Resolver) ReleaseByID(
+	productSlug string,
+	previousSlugs []string,
+	releaseID int,
+	productVersion string,
+) (pivnet.Release, string, error) {
+	_, fingerprint := SplitVersion(productVersion)
+
+	r.logger.Info(fmt.Sprintf(
+		"Getting release for product slug: '%s' and release ID: %d",
+		productSlug,
+		releaseID,
+	))
+
+	release, foundSlug, err := r.getRelease(productSlug, previousSlugs, func(slug string) (pivnet.Release, error) {
+		return r.releaseGetter.GetReleaseByID(slug, releaseID)
+	})
+	if err != nil {
+		return pivnet.Release{}, "", err
+	}
+
+	err = checkFingerprint(release, fingerprint)
+	if err != nil {
+		return pivnet.Release{}, "", err
+	}
+
+	return release, foundSlug, nil
+}
+
+func checkFingerprint(release pivnet.Release, fingerprint string) error {
+	if fingerprint == "" {
+		return nil
+	}
+
+	actualFingerprint := release.SoftwareFilesUpdatedAt
+	if actualFingerprint != fingerprint {
+		return fmt.Errorf(
+			"provided fingerprint: '%s' does not match actual fingerprint (from pivnet): '%s' - %s",
+			fingerprint,
+			actualFingerprint,
+			"pivnet does not support downloading old versions of a release",
+		)
+	}
+
+	return nil
+}
+
+func (r Resolver) getRelease(
+	productSlug string,
+	previousSlugs []string,
+	get func(slug string) (pivnet.Release, error),
+) (pivnet.Release, string, error) {
+	release, err := get(productSlug)
+	if err == nil {
+		return release, productSlug, nil
+	}
+
+	for _, previousSlug := range previousSlugs {
+		r.logger.Info(fmt.Sprintf(
+			"Release not found - getting release for previous product slug: '%s'",
+			previousSlug,
+		))
+
+		release, previousErr := get(previousSlug)
+		if previousErr == nil {
+			return release, previousSlug, nil
+		}
+	}
+
+	return pivnet.Release{}, "", err
+}
@@ -0,0 +1,171 @@
+package resolution_test
+
+import (
+	"errors"
+	"log"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
+	"github.com/pivotal-cf/pivnet-resource/resolution/resolutionfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resolver", func() {
+	var (
+		fakeReleaseGetter *resolutionfakes.FakeReleaseGetter
+
+		release pivnet.Release
+
+		resolver *resolution.Resolver
+	)
+
+	BeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger := logshim.NewLogShim(logger, logger, true)
+
+		release = pivnet.Release{
+			ID:                     1234,
+			Version:                "1.2.3",
+			SoftwareFilesUpdatedAt: "some-fingerprint",
+		}
+
+		fakeReleaseGetter = &resolutionfakes.FakeReleaseGetter{}
+		fakeReleaseGetter.GetReleaseReturns(release, nil)
+		fakeReleaseGetter.GetReleaseByIDReturns(release, nil)
+
+		resolver = resolution.NewResolver(fakeLogger, fakeReleaseGetter)
+	})
+
+	Describe("Release", func() {
+		It("returns the release of the version emitted by check", func() {
+			returned, productSlug, err := resolver.Release("some-product", nil, resolution.Version(release))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(returned).To(Equal(release))
+			Expect(productSlug).To(Equal("some-product"))
+
+			Expect(fakeReleaseGetter.GetReleaseCallCount()).To(Equal(1))
+			invokedSlug, invokedVersion := fakeReleaseGetter.GetReleaseArgsForCall(0)
+			Expect(invokedSlug).To(Equal("some-product"))
+			Expect(invokedVersion).To(Equal("1.2.3"))
+		})
+
+		Context("when the version has no fingerprint", func() {
+			It("returns the release without comparing fingerprints", func() {
+				returned, _, err := resolver.Release("some-product", nil, "1.2.3")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal(release))
+			})
+		})
+
+		Context("when the release version contains the fingerprint delimiter", func() {
+			BeforeEach(func() {
+				release.Version = "1.2.3#build-4"
+				fakeReleaseGetter.GetReleaseReturns(release, nil)
+			})
+
+			It("gets the release of the whole version", func() {
+				_, _, err := resolver.Release("some-product", nil, resolution.Version(release))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, invokedVersion := fakeReleaseGetter.GetReleaseArgsForCall(0)
+				Expect(invokedVersion).To(Equal("1.2.3#build-4"))
+			})
+		})
+
+		Context("when the fingerprint does not match the release", func() {
+			It("returns an error", func() {
+				_, _, err := resolver.Release("some-product", nil, "1.2.3#other-fingerprint")
+				Expect(err).To(MatchError("provided fingerprint: 'other-fingerprint' does not match actual fingerprint (from pivnet): 'some-fingerprint' - pivnet does not support downloading old versions of a release"))
+			})
+		})
+
+		Context("when the release is only found under a previous slug", func() {
+			BeforeEach(func() {
+				fakeReleaseGetter.GetReleaseStub = func(productSlug string, version string) (pivnet.Release, error) {
+					if productSlug == "previous-product-2" {
+						return release, nil
+					}
+
+					return pivnet.Release{}, errors.New("some release error")
+				}
+			})
+
+			It("returns the release along with the previous slug", func() {
+				returned, productSlug, err := resolver.Release(
+					"some-product",
+					[]string{"previous-product-1", "previous-product-2"},
+					"1.2.3",
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal(release))
+				Expect(productSlug).To(Equal("previous-product-2"))
+				Expect(fakeReleaseGetter.GetReleaseCallCount()).To(Equal(3))
+			})
+
+			Context("when it is not found under any of them", func() {
+				It("returns the error for the product slug", func() {
+					_, _, err := resolver.Release("some-product", []string{"previous-product-1"}, "1.2.3")
+					Expect(err).To(MatchError("some release error"))
+				})
+			})
+		})
+	})
+
+	Describe("ReleaseByID", func() {
+		It("returns the release with the ID without getting it by version", func() {
+			returned, productSlug, err := resolver.ReleaseByID("some-product", nil, 1234, resolution.Version(release))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(returned).To(Equal(release))
+			Expect(productSlug).To(Equal("some-product"))
+
+			Expect(fakeReleaseGetter.GetReleaseCallCount()).To(Equal(0))
+			Expect(fakeReleaseGetter.GetReleaseByIDCallCount()).To(Equal(1))
+			invokedSlug, invokedReleaseID := fakeReleaseGetter.GetReleaseByIDArgsForCall(0)
+			Expect(invokedSlug).To(Equal("some-product"))
+			Expect(invokedReleaseID).To(Equal(1234))
+		})
+
+		Context("when the version of the release has been edited", func() {
+			It("still returns the release", func() {
+				returned, _, err := resolver.ReleaseByID("some-product", nil, 1234, "1.2.2#some-fingerprint")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal(release))
+			})
+		})
+
+		Context("when the fingerprint does not match the release", func() {
+			It("returns an error", func() {
+				_, _, err := resolver.ReleaseByID("some-product", nil, 1234, "1.2.3#other-fingerprint")
+				Expect(err).To(MatchError("provided fingerprint: 'other-fingerprint' does not match actual fingerprint (from pivnet): 'some-fingerprint' - pivnet does not support downloading old versions of a release"))
+			})
+		})
+
+		Context("when the release is only found under a previous slug", func() {
+			BeforeEach(func() {
+				fakeReleaseGetter.GetReleaseByIDStub = func(productSlug string, releaseID int) (pivnet.Release, error) {
+					if productSlug == "previous-product" {
+						return release, nil
+					}
+
+					return pivnet.Release{}, errors.New("some release error")
+				}
+			})
+
+			It("returns the release along with the previous slug", func() {
+				returned, productSlug, err := resolver.ReleaseByID("some-product", []string{"previous-product"}, 1234, "1.2.3")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(returned).To(Equal(release))
+				Expect(productSlug).To(Equal("previous-product"))
+			})
+		})
+	})
+})
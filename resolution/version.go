@@ -0,0 +1,21 @@
+package resolution
+
+import (
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/versions"
+)
+
+// Version returns the version of the release as emitted by check, i.e. with
+// the fingerprint of its software files if it has one.
+func Version(release pivnet.Release) string {
+	// versions.CombineVersionAndFingerprint never returns an error.
+	v, _ := versions.CombineVersionAndFingerprint(release.Version, release.SoftwareFilesUpdatedAt)
+	return v
+}
+
+// SplitVersion returns the release version and fingerprint of a version
+// emitted by check, as split by versions.SplitVersion. A version without a
+// fingerprint, e.g. a pinned_version, is returned with an empty fingerprint.
+func SplitVersion(productVersion string) (string, string) {
+	return versions.SplitVersion(productVersion)
+}
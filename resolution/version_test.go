@@ -0,0 +1,47 @@
+package resolution_test
+
+import (
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/resolution"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Version", func() {
+	It("returns the version with the fingerprint of the release", func() {
+		release := pivnet.Release{Version: "1.2.3", SoftwareFilesUpdatedAt: "some-fingerprint"}
+		Expect(resolution.Version(release)).To(Equal("1.2.3#some-fingerprint"))
+	})
+
+	Context("when the release has no fingerprint", func() {
+		It("returns the version alone", func() {
+			release := pivnet.Release{Version: "1.2.3"}
+			Expect(resolution.Version(release)).To(Equal("1.2.3"))
+		})
+	})
+})
+
+var _ = Describe("SplitVersion", func() {
+	It("returns the version and fingerprint", func() {
+		version, fingerprint := resolution.SplitVersion("1.2.3#some-fingerprint")
+		Expect(version).To(Equal("1.2.3"))
+		Expect(fingerprint).To(Equal("some-fingerprint"))
+	})
+
+	It("is the inverse of Version", func() {
+		release := pivnet.Release{Version: "1.2.3#build-4", SoftwareFilesUpdatedAt: "some-fingerprint"}
+
+		version, fingerprint := resolution.SplitVersion(resolution.Version(release))
+		Expect(version).To(Equal("1.2.3#build-4"))
+		Expect(fingerprint).To(Equal("some-fingerprint"))
+	})
+
+	Context("when the version has no fingerprint", func() {
+		It("returns the version with an empty fingerprint", func() {
+			version, fingerprint := resolution.SplitVersion("1.2.3")
+			Expect(version).To(Equal("1.2.3"))
+			Expect(fingerprint).To(BeEmpty())
+		})
+	})
+})
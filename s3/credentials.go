@@ -0,0 +1,288 @@
+package s3
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/sts"
+	pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+const (
+	// DefaultRoleSessionName is the session name of assumed roles when none
+	// is configured.
+	DefaultRoleSessionName = "pivnet-resource"
+
+	// FederationTokenRefreshInterval is how long federation tokens are used
+	// before a new one is generated, well within the lifetime of the tokens.
+	FederationTokenRefreshInterval = 30 * time.Minute
+
+	// expiryWindow is how long before they expire that credentials are
+	// refreshed, so that they do not expire during a request.
+	expiryWindow = 1 * time.Minute
+
+	credentialsProviderErrorCode = "CredentialsProviderError"
+)
+
+// Credentials are the AWS credentials with which the client authenticates to
+// S3. Expiration is zero for credentials which do not expire.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+}
+
+//go:generate counterfeiter --fake-name FakeCredentialsProvider . CredentialsProvider
+
+// CredentialsProvider provides the credentials with which the client
+// authenticates to S3. Retrieve is invoked again once the previously
+// retrieved credentials have expired.
+type CredentialsProvider interface {
+	Retrieve() (Credentials, error)
+}
+
+//go:generate counterfeiter --fake-name FakeSTSClient . stsClient
+type stsClient interface {
+	AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error)
+	AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error)
+}
+
+//go:generate counterfeiter --fake-name FakeFederationTokenClient . federationTokenClient
+type federationTokenClient interface {
+	GetFederationToken(productSlug string) (pivnet.FederationToken, error)
+}
+
+type StaticCredentialsProvider struct {
+	credentials Credentials
+}
+
+// NewStaticCredentialsProvider returns a provider of the provided
+// credentials, which never expire.
+func NewStaticCredentialsProvider(accessKeyID, secretAccessKey, sessionToken string) StaticCredentialsProvider {
+	return StaticCredentialsProvider{
+		credentials: Credentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			SessionToken:    sessionToken,
+		},
+	}
+}
+
+func (p StaticCredentialsProvider) Retrieve() (Credentials, error) {
+	return p.credentials, nil
+}
+
+type EnvCredentialsProvider struct {
+	getenv func(string) string
+}
+
+// NewEnvCredentialsProvider returns a provider of the credentials in the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment
+// variables, as looked up by getenv, e.g. os.Getenv.
+func NewEnvCredentialsProvider(getenv func(string) string) EnvCredentialsProvider {
+	return EnvCredentialsProvider{
+		getenv: getenv,
+	}
+}
+
+func (p EnvCredentialsProvider) Retrieve() (Credentials, error) {
+	accessKeyID := p.getenv("AWS_ACCESS_KEY_ID")
+	if accessKeyID == "" {
+		return Credentials{}, fmt.Errorf("AWS_ACCESS_KEY_ID must be set")
+	}
+
+	secretAccessKey := p.getenv("AWS_SECRET_ACCESS_KEY")
+	if secretAccessKey == "" {
+		return Credentials{}, fmt.Errorf("AWS_SECRET_ACCESS_KEY must be set")
+	}
+
+	return Credentials{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    p.getenv("AWS_SESSION_TOKEN"),
+	}, nil
+}
+
+type FederationTokenProvider struct {
+	client      federationTokenClient
+	productSlug string
+	now         func() time.Time
+
+	next *pivnet.FederationToken
+}
+
+// NewFederationTokenProvider returns a provider of the credentials of
+// Pivotal Network federation tokens for the product. The initial token,
+// which has typically already been generated to find the bucket, is
+// provided first. Subsequent tokens are generated as each expires.
+func NewFederationTokenProvider(client federationTokenClient, productSlug string, initial pivnet.FederationToken) *FederationTokenProvider {
+	return &FederationTokenProvider{
+		client:      client,
+		productSlug: productSlug,
+		now:         time.Now,
+
+		next: &initial,
+	}
+}
+
+func (p *FederationTokenProvider) Retrieve() (Credentials, error) {
+	var token pivnet.FederationToken
+	if p.next != nil {
+		token = *p.next
+		p.next = nil
+	} else {
+		var err error
+		token, err = p.client.GetFederationToken(p.productSlug)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to generate federation token: %s", err.Error())
+		}
+	}
+
+	return Credentials{
+		AccessKeyID:     token.AccessKeyID,
+		SecretAccessKey: token.SecretAccessKey,
+		SessionToken:    token.SessionToken,
+		Expiration:      p.now().Add(FederationTokenRefreshInterval),
+	}, nil
+}
+
+type AssumeRoleProvider struct {
+	sts             stsClient
+	roleARN         string
+	roleSessionName string
+	externalID      string
+}
+
+// NewAssumeRoleProvider returns a provider of the credentials of the role,
+// assumed using the sts client, which must itself be authenticated, e.g.
+// with the credentials of a StaticCredentialsProvider.
+func NewAssumeRoleProvider(stsClient stsClient, roleARN, roleSessionName, externalID string) AssumeRoleProvider {
+	if roleSessionName == "" {
+		roleSessionName = DefaultRoleSessionName
+	}
+
+	return AssumeRoleProvider{
+		sts:             stsClient,
+		roleARN:         roleARN,
+		roleSessionName: roleSessionName,
+		externalID:      externalID,
+	}
+}
+
+func (p AssumeRoleProvider) Retrieve() (Credentials, error) {
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(p.roleARN),
+		RoleSessionName: aws.String(p.roleSessionName),
+	}
+	if p.externalID != "" {
+		input.ExternalId = aws.String(p.externalID)
+	}
+
+	output, err := p.sts.AssumeRole(input)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to assume role '%s': %s", p.roleARN, err.Error())
+	}
+
+	return stsCredentials(output.Credentials), nil
+}
+
+type WebIdentityProvider struct {
+	sts             stsClient
+	roleARN         string
+	roleSessionName string
+	tokenFile       string
+}
+
+// NewWebIdentityProvider returns a provider of the credentials of the role,
+// assumed using the web identity token in tokenFile, e.g. a projected
+// service account token. The file is read on each retrieval as the token is
+// rotated. The sts client need not be authenticated.
+func NewWebIdentityProvider(stsClient stsClient, roleARN, roleSessionName, tokenFile string) WebIdentityProvider {
+	if roleSessionName == "" {
+		roleSessionName = DefaultRoleSessionName
+	}
+
+	return WebIdentityProvider{
+		sts:             stsClient,
+		roleARN:         roleARN,
+		roleSessionName: roleSessionName,
+		tokenFile:       tokenFile,
+	}
+}
+
+func (p WebIdentityProvider) Retrieve() (Credentials, error) {
+	token, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read web identity token: %s", err.Error())
+	}
+
+	output, err := p.sts.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(p.roleARN),
+		RoleSessionName:  aws.String(p.roleSessionName),
+		WebIdentityToken: aws.String(strings.TrimSpace(string(token))),
+	})
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to assume role '%s' with web identity: %s", p.roleARN, err.Error())
+	}
+
+	return stsCredentials(output.Credentials), nil
+}
+
+func stsCredentials(c *sts.Credentials) Credentials {
+	if c == nil {
+		return Credentials{}
+	}
+
+	return Credentials{
+		AccessKeyID:     aws.StringValue(c.AccessKeyId),
+		SecretAccessKey: aws.StringValue(c.SecretAccessKey),
+		SessionToken:    aws.StringValue(c.SessionToken),
+		Expiration:      aws.TimeValue(c.Expiration),
+	}
+}
+
+// awsCredentialsProvider adapts a CredentialsProvider to the provider
+// interface of the AWS SDK, so that credentials are refreshed by the SDK
+// shortly before they expire.
+type awsCredentialsProvider struct {
+	provider   CredentialsProvider
+	now        func() time.Time
+	expiration time.Time
+}
+
+func newAWSCredentials(provider CredentialsProvider) *credentials.Credentials {
+	return credentials.NewCredentials(&awsCredentialsProvider{
+		provider: provider,
+		now:      time.Now,
+	})
+}
+
+func (p *awsCredentialsProvider) Retrieve() (credentials.Value, error) {
+	c, err := p.provider.Retrieve()
+	if err != nil {
+		return credentials.Value{}, awserr.New(credentialsProviderErrorCode, "failed to retrieve credentials", err)
+	}
+
+	p.expiration = c.Expiration
+
+	return credentials.Value{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		ProviderName:    "pivnet-resource",
+	}, nil
+}
+
+func (p *awsCredentialsProvider) IsExpired() bool {
+	if p.expiration.IsZero() {
+		return false
+	}
+
+	return !p.now().Before(p.expiration.Add(-expiryWindow))
+}
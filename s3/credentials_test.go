@@ -0,0 +1,305 @@
+package s3_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/pivnet-resource/s3"
+	"github.com/pivotal-cf/pivnet-resource/s3/s3fakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Credentials providers", func() {
+	var (
+		expiration     time.Time
+		stsCredentials *sts.Credentials
+	)
+
+	BeforeEach(func() {
+		expiration = time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		stsCredentials = &sts.Credentials{
+			AccessKeyId:     aws.String("some-role-access-key-id"),
+			SecretAccessKey: aws.String("some-role-secret-access-key"),
+			SessionToken:    aws.String("some-role-session-token"),
+			Expiration:      aws.Time(expiration),
+		}
+	})
+
+	Describe("StaticCredentialsProvider", func() {
+		It("provides the credentials, which do not expire", func() {
+			provider := s3.NewStaticCredentialsProvider("some-access-key-id", "some-secret-access-key", "some-session-token")
+
+			credentials, err := provider.Retrieve()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(Equal(s3.Credentials{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				SessionToken:    "some-session-token",
+			}))
+		})
+	})
+
+	Describe("EnvCredentialsProvider", func() {
+		var env map[string]string
+
+		BeforeEach(func() {
+			env = map[string]string{
+				"AWS_ACCESS_KEY_ID":     "some-access-key-id",
+				"AWS_SECRET_ACCESS_KEY": "some-secret-access-key",
+				"AWS_SESSION_TOKEN":     "some-session-token",
+			}
+		})
+
+		retrieve := func() (s3.Credentials, error) {
+			return s3.NewEnvCredentialsProvider(func(key string) string {
+				return env[key]
+			}).Retrieve()
+		}
+
+		It("provides the credentials in the environment", func() {
+			credentials, err := retrieve()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(Equal(s3.Credentials{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				SessionToken:    "some-session-token",
+			}))
+		})
+
+		Context("when the session token is not set", func() {
+			BeforeEach(func() {
+				delete(env, "AWS_SESSION_TOKEN")
+			})
+
+			It("provides the credentials without a session token", func() {
+				credentials, err := retrieve()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(credentials.SessionToken).To(BeEmpty())
+			})
+		})
+
+		Context("when the access key ID is not set", func() {
+			BeforeEach(func() {
+				delete(env, "AWS_ACCESS_KEY_ID")
+			})
+
+			It("returns an error", func() {
+				_, err := retrieve()
+				Expect(err).To(MatchError("AWS_ACCESS_KEY_ID must be set"))
+			})
+		})
+
+		Context("when the secret access key is not set", func() {
+			BeforeEach(func() {
+				delete(env, "AWS_SECRET_ACCESS_KEY")
+			})
+
+			It("returns an error", func() {
+				_, err := retrieve()
+				Expect(err).To(MatchError("AWS_SECRET_ACCESS_KEY must be set"))
+			})
+		})
+	})
+
+	Describe("FederationTokenProvider", func() {
+		var (
+			fakeClient *s3fakes.FakeFederationTokenClient
+			provider   *s3.FederationTokenProvider
+		)
+
+		BeforeEach(func() {
+			fakeClient = &s3fakes.FakeFederationTokenClient{}
+			fakeClient.GetFederationTokenReturns(pivnet.FederationToken{
+				AccessKeyID:     "some-new-access-key-id",
+				SecretAccessKey: "some-new-secret-access-key",
+				SessionToken:    "some-new-session-token",
+			}, nil)
+
+			provider = s3.NewFederationTokenProvider(fakeClient, "some-product-slug", pivnet.FederationToken{
+				AccessKeyID:     "some-access-key-id",
+				SecretAccessKey: "some-secret-access-key",
+				SessionToken:    "some-session-token",
+			})
+		})
+
+		It("provides the initial token first, then generates new tokens", func() {
+			credentials, err := provider.Retrieve()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials.AccessKeyID).To(Equal("some-access-key-id"))
+			Expect(credentials.SecretAccessKey).To(Equal("some-secret-access-key"))
+			Expect(credentials.SessionToken).To(Equal("some-session-token"))
+			Expect(credentials.Expiration).To(BeTemporally("~", time.Now().Add(s3.FederationTokenRefreshInterval), time.Second))
+
+			Expect(fakeClient.GetFederationTokenCallCount()).To(Equal(0))
+
+			credentials, err = provider.Retrieve()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials.AccessKeyID).To(Equal("some-new-access-key-id"))
+			Expect(credentials.SecretAccessKey).To(Equal("some-new-secret-access-key"))
+			Expect(credentials.SessionToken).To(Equal("some-new-session-token"))
+
+			Expect(fakeClient.GetFederationTokenCallCount()).To(Equal(1))
+			Expect(fakeClient.GetFederationTokenArgsForCall(0)).To(Equal("some-product-slug"))
+		})
+
+		Context("when generating a token returns an error", func() {
+			BeforeEach(func() {
+				fakeClient.GetFederationTokenReturns(pivnet.FederationToken{}, errors.New("some token error"))
+			})
+
+			It("returns an error", func() {
+				_, err := provider.Retrieve()
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = provider.Retrieve()
+				Expect(err).To(MatchError("failed to generate federation token: some token error"))
+			})
+		})
+	})
+
+	Describe("AssumeRoleProvider", func() {
+		var fakeSTS *s3fakes.FakeSTSClient
+
+		BeforeEach(func() {
+			fakeSTS = &s3fakes.FakeSTSClient{}
+			fakeSTS.AssumeRoleReturns(&sts.AssumeRoleOutput{Credentials: stsCredentials}, nil)
+		})
+
+		It("provides the credentials of the assumed role", func() {
+			provider := s3.NewAssumeRoleProvider(fakeSTS, "some-role-arn", "some-session-name", "some-external-id")
+
+			credentials, err := provider.Retrieve()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(Equal(s3.Credentials{
+				AccessKeyID:     "some-role-access-key-id",
+				SecretAccessKey: "some-role-secret-access-key",
+				SessionToken:    "some-role-session-token",
+				Expiration:      expiration,
+			}))
+
+			Expect(fakeSTS.AssumeRoleCallCount()).To(Equal(1))
+			input := fakeSTS.AssumeRoleArgsForCall(0)
+			Expect(aws.StringValue(input.RoleArn)).To(Equal("some-role-arn"))
+			Expect(aws.StringValue(input.RoleSessionName)).To(Equal("some-session-name"))
+			Expect(aws.StringValue(input.ExternalId)).To(Equal("some-external-id"))
+		})
+
+		Context("when no session name or external ID is provided", func() {
+			It("uses the default session name and no external ID", func() {
+				provider := s3.NewAssumeRoleProvider(fakeSTS, "some-role-arn", "", "")
+
+				_, err := provider.Retrieve()
+				Expect(err).NotTo(HaveOccurred())
+
+				input := fakeSTS.AssumeRoleArgsForCall(0)
+				Expect(aws.StringValue(input.RoleSessionName)).To(Equal(s3.DefaultRoleSessionName))
+				Expect(input.ExternalId).To(BeNil())
+			})
+		})
+
+		Context("when assuming the role returns an error", func() {
+			BeforeEach(func() {
+				fakeSTS.AssumeRoleReturns(nil, errors.New("some sts error"))
+			})
+
+			It("returns an error", func() {
+				provider := s3.NewAssumeRoleProvider(fakeSTS, "some-role-arn", "", "")
+
+				_, err := provider.Retrieve()
+				Expect(err).To(MatchError("failed to assume role 'some-role-arn': some sts error"))
+			})
+		})
+	})
+
+	Describe("WebIdentityProvider", func() {
+		var (
+			fakeSTS   *s3fakes.FakeSTSClient
+			tempDir   string
+			tokenFile string
+			provider  s3.WebIdentityProvider
+		)
+
+		BeforeEach(func() {
+			fakeSTS = &s3fakes.FakeSTSClient{}
+			fakeSTS.AssumeRoleWithWebIdentityReturns(&sts.AssumeRoleWithWebIdentityOutput{Credentials: stsCredentials}, nil)
+
+			var err error
+			tempDir, err = ioutil.TempDir("", "pivnet-resource-s3-test")
+			Expect(err).NotTo(HaveOccurred())
+
+			tokenFile = filepath.Join(tempDir, "token")
+			err = ioutil.WriteFile(tokenFile, []byte("some-web-identity-token\n"), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+
+			provider = s3.NewWebIdentityProvider(fakeSTS, "some-role-arn", "", tokenFile)
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		It("provides the credentials of the role assumed with the token", func() {
+			credentials, err := provider.Retrieve()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(credentials).To(Equal(s3.Credentials{
+				AccessKeyID:     "some-role-access-key-id",
+				SecretAccessKey: "some-role-secret-access-key",
+				SessionToken:    "some-role-session-token",
+				Expiration:      expiration,
+			}))
+
+			Expect(fakeSTS.AssumeRoleWithWebIdentityCallCount()).To(Equal(1))
+			input := fakeSTS.AssumeRoleWithWebIdentityArgsForCall(0)
+			Expect(aws.StringValue(input.RoleArn)).To(Equal("some-role-arn"))
+			Expect(aws.StringValue(input.RoleSessionName)).To(Equal(s3.DefaultRoleSessionName))
+			Expect(aws.StringValue(input.WebIdentityToken)).To(Equal("some-web-identity-token"))
+		})
+
+		It("reads the token again on each retrieval", func() {
+			_, err := provider.Retrieve()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = ioutil.WriteFile(tokenFile, []byte("some-rotated-token"), os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = provider.Retrieve()
+			Expect(err).NotTo(HaveOccurred())
+
+			input := fakeSTS.AssumeRoleWithWebIdentityArgsForCall(1)
+			Expect(aws.StringValue(input.WebIdentityToken)).To(Equal("some-rotated-token"))
+		})
+
+		Context("when the token file cannot be read", func() {
+			BeforeEach(func() {
+				provider = s3.NewWebIdentityProvider(fakeSTS, "some-role-arn", "", filepath.Join(tempDir, "missing"))
+			})
+
+			It("returns an error", func() {
+				_, err := provider.Retrieve()
+				Expect(err).To(MatchError(ContainSubstring("failed to read web identity token")))
+
+				Expect(fakeSTS.AssumeRoleWithWebIdentityCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when assuming the role returns an error", func() {
+			BeforeEach(func() {
+				fakeSTS.AssumeRoleWithWebIdentityReturns(nil, errors.New("some sts error"))
+			})
+
+			It("returns an error", func() {
+				_, err := provider.Retrieve()
+				Expect(err).To(MatchError("failed to assume role 'some-role-arn' with web identity: some sts error"))
+			})
+		})
+	})
+})
@@ -0,0 +1,170 @@
+package s3
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+type ErrorClass string
+
+const (
+	ErrorClassThrottling  ErrorClass = "throttling"
+	ErrorClassCredentials ErrorClass = "credentials"
+	ErrorClassNetwork     ErrorClass = "network"
+	ErrorClassClient      ErrorClass = "client"
+	ErrorClassUnknown     ErrorClass = "unknown"
+)
+
+// DefaultRetryBudget is the total number of retries a client will make
+// across all of its uploads when no budget is configured.
+const DefaultRetryBudget = 5
+
+// RetryPolicy describes how many times an upload failing with a given class
+// of error is retried, and how long to wait before the first retry.
+// The wait doubles with each subsequent retry.
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// RetryPolicies maps each error class to its retry policy. Credential and
+// other client errors are never retried as repeating the request cannot
+// succeed.
+var RetryPolicies = map[ErrorClass]RetryPolicy{
+	ErrorClassThrottling:  {MaxRetries: 5, Backoff: 2 * time.Second},
+	ErrorClassNetwork:     {MaxRetries: 3, Backoff: 1 * time.Second},
+	ErrorClassUnknown:     {MaxRetries: 1, Backoff: 1 * time.Second},
+	ErrorClassCredentials: {MaxRetries: 0},
+	ErrorClassClient:      {MaxRetries: 0},
+}
+
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"ProvisionedThroughputExceededException": true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+}
+
+var credentialsCodes = map[string]bool{
+	"InvalidAccessKeyId":    true,
+	"SignatureDoesNotMatch": true,
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+	"InvalidToken":          true,
+	"TokenRefreshRequired":  true,
+	"AccessDenied":          true,
+	"AuthFailure":           true,
+	"RequestExpired":        true,
+	"NoCredentialProviders": true,
+
+	credentialsProviderErrorCode: true,
+}
+
+var networkCodes = map[string]bool{
+	"RequestError":   true,
+	"RequestTimeout": true,
+}
+
+// regionCodes are returned when a request is sent to a region other than the
+// one in which the bucket is located.
+var regionCodes = map[string]bool{
+	"PermanentRedirect":            true,
+	"AuthorizationHeaderMalformed": true,
+	"BucketRegionError":            true,
+}
+
+// ClassifyError determines the class of an error returned while uploading
+// to S3. Errors wrapping other errors, e.g. multipart upload failures, are
+// classified by their cause when their own code is not recognised.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassUnknown
+	}
+
+	if awsErr, ok := err.(awserr.Error); ok {
+		code := awsErr.Code()
+
+		switch {
+		case throttlingCodes[code]:
+			return ErrorClassThrottling
+		case credentialsCodes[code]:
+			return ErrorClassCredentials
+		case networkCodes[code]:
+			return ErrorClassNetwork
+		case regionCodes[code]:
+			return ErrorClassClient
+		}
+
+		if reqErr, ok := err.(awserr.RequestFailure); ok {
+			if class, ok := classifyStatusCode(reqErr.StatusCode()); ok {
+				return class
+			}
+		}
+
+		if awsErr.OrigErr() != nil {
+			return ClassifyError(awsErr.OrigErr())
+		}
+
+		return ErrorClassUnknown
+	}
+
+	if _, ok := err.(net.Error); ok {
+		return ErrorClassNetwork
+	}
+
+	return ErrorClassUnknown
+}
+
+// IsRegionError reports whether the error was caused by sending a request to
+// a region other than the one in which the bucket is located.
+func IsRegionError(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	if regionCodes[awsErr.Code()] {
+		return true
+	}
+
+	if reqErr, ok := err.(awserr.RequestFailure); ok && reqErr.StatusCode() == http.StatusMovedPermanently {
+		return true
+	}
+
+	if awsErr.OrigErr() != nil {
+		return IsRegionError(awsErr.OrigErr())
+	}
+
+	return false
+}
+
+func classifyStatusCode(statusCode int) (ErrorClass, bool) {
+	switch {
+	case statusCode == http.StatusMovedPermanently:
+		return ErrorClassClient, true
+	case statusCode == http.StatusTooManyRequests,
+		statusCode == http.StatusServiceUnavailable:
+		return ErrorClassThrottling, true
+	case statusCode == http.StatusUnauthorized,
+		statusCode == http.StatusForbidden:
+		return ErrorClassCredentials, true
+	case statusCode == http.StatusRequestTimeout,
+		statusCode == http.StatusBadGateway,
+		statusCode == http.StatusGatewayTimeout:
+		return ErrorClassNetwork, true
+	case statusCode >= 400 && statusCode < 500:
+		return ErrorClassClient, true
+	}
+
+	return "", false
+}
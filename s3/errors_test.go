@@ -0,0 +1,115 @@
+package s3_test
+
+import (
+	"errors"
+	"net"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/s3"
+)
+
+var _ = Describe("ClassifyError", func() {
+	It("classifies throttling codes as throttling", func() {
+		err := awserr.New("SlowDown", "slow down", nil)
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassThrottling))
+	})
+
+	It("classifies invalid access keys as credentials", func() {
+		err := awserr.New("InvalidAccessKeyId", "bad key", nil)
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassCredentials))
+	})
+
+	It("classifies expired tokens as credentials", func() {
+		err := awserr.New("ExpiredToken", "expired", nil)
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassCredentials))
+	})
+
+	It("classifies failures to retrieve credentials as credentials", func() {
+		err := awserr.New("CredentialsProviderError", "failed to retrieve credentials", errors.New("some error"))
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassCredentials))
+	})
+
+	It("classifies request errors as network", func() {
+		err := awserr.New("RequestError", "send request failed", nil)
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassNetwork))
+	})
+
+	It("classifies 429 responses as throttling", func() {
+		err := awserr.NewRequestFailure(awserr.New("SomeCode", "", nil), 429, "some-request-id")
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassThrottling))
+	})
+
+	It("classifies 403 responses as credentials", func() {
+		err := awserr.NewRequestFailure(awserr.New("SomeCode", "", nil), 403, "some-request-id")
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassCredentials))
+	})
+
+	It("classifies other 4xx responses as client", func() {
+		err := awserr.NewRequestFailure(awserr.New("NoSuchBucket", "", nil), 404, "some-request-id")
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassClient))
+	})
+
+	It("classifies 500 responses as unknown", func() {
+		err := awserr.NewRequestFailure(awserr.New("InternalError", "", nil), 500, "some-request-id")
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassUnknown))
+	})
+
+	It("classifies wrapped errors by their cause", func() {
+		err := awserr.New("MultipartUpload", "upload failed", awserr.New("AccessDenied", "denied", nil))
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassCredentials))
+	})
+
+	It("classifies net errors as network", func() {
+		err := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassNetwork))
+	})
+
+	It("classifies other errors as unknown", func() {
+		err := errors.New("some error")
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassUnknown))
+	})
+
+	It("classifies region redirects as client", func() {
+		err := awserr.NewRequestFailure(awserr.New("PermanentRedirect", "", nil), 301, "some-request-id")
+		Expect(s3.ClassifyError(err)).To(Equal(s3.ErrorClassClient))
+	})
+
+	It("does not retry credential or client errors", func() {
+		Expect(s3.RetryPolicies[s3.ErrorClassCredentials].MaxRetries).To(Equal(0))
+		Expect(s3.RetryPolicies[s3.ErrorClassClient].MaxRetries).To(Equal(0))
+	})
+
+	It("retries throttling and network errors", func() {
+		Expect(s3.RetryPolicies[s3.ErrorClassThrottling].MaxRetries).To(BeNumerically(">", 0))
+		Expect(s3.RetryPolicies[s3.ErrorClassNetwork].MaxRetries).To(BeNumerically(">", 0))
+	})
+})
+
+var _ = Describe("IsRegionError", func() {
+	It("detects permanent redirects", func() {
+		err := awserr.New("PermanentRedirect", "redirect", nil)
+		Expect(s3.IsRegionError(err)).To(BeTrue())
+	})
+
+	It("detects 301 responses", func() {
+		err := awserr.NewRequestFailure(awserr.New("SomeCode", "", nil), 301, "some-request-id")
+		Expect(s3.IsRegionError(err)).To(BeTrue())
+	})
+
+	It("detects requests signed for the wrong region", func() {
+		err := awserr.NewRequestFailure(awserr.New("AuthorizationHeaderMalformed", "", nil), 400, "some-request-id")
+		Expect(s3.IsRegionError(err)).To(BeTrue())
+	})
+
+	It("detects wrapped region errors", func() {
+		err := awserr.New("MultipartUpload", "upload failed", awserr.New("PermanentRedirect", "redirect", nil))
+		Expect(s3.IsRegionError(err)).To(BeTrue())
+	})
+
+	It("does not detect other errors", func() {
+		Expect(s3.IsRegionError(awserr.New("AccessDenied", "denied", nil))).To(BeFalse())
+		Expect(s3.IsRegionError(errors.New("some error"))).To(BeFalse())
+	})
+})
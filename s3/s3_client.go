@@ -1,24 +1,63 @@
 package s3
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awss3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/concourse/s3-resource"
 	"github.com/pivotal-cf/go-pivnet/logger"
 )
 
+const (
+	StorageClassStandard           = "STANDARD"
+	StorageClassStandardIA         = "STANDARD_IA"
+	StorageClassIntelligentTiering = "INTELLIGENT_TIERING"
+)
+
+var StorageClasses = []string{
+	StorageClassStandard,
+	StorageClassStandardIA,
+	StorageClassIntelligentTiering,
+}
+
+// StatsRecorder records the uploads of a client, e.g. to report the
+// performance of a put.
+type StatsRecorder interface {
+	RecordUpload(bytes int64, duration time.Duration)
+	RecordRetry()
+}
+
 type Client struct {
-	bucket          string
+	bucket       string
+	storageClass string
 
 	logger logger.Logger
 	stderr io.Writer
+	stats  StatsRecorder
 
-	s3client s3resource.S3Client
+	awsConfig *aws.Config
+
+	retriesRemaining *int
+	sleep            func(time.Duration)
 }
 
 type NewClientConfig struct {
+	// CredentialsProvider provides the credentials of the client. If it is
+	// nil the AccessKeyID, SecretAccessKey and SessionToken are used, or no
+	// credentials if they are empty.
+	CredentialsProvider CredentialsProvider
+
 	AccessKeyID     string
 	SecretAccessKey string
 	SessionToken    string
@@ -28,6 +67,16 @@ type NewClientConfig struct {
 	Logger            logger.Logger
 	Stderr            io.Writer
 	SkipSSLValidation bool
+	Transport         *http.Transport
+	StorageClass      string
+	RetryBudget       int
+
+	// UseDualStack makes requests to the dual-stack endpoints of S3, which
+	// are reachable over IPv6 as well as IPv4, e.g. from IPv6-only workers.
+	UseDualStack bool
+
+	// Stats records the uploads of the client if it is not nil.
+	Stats StatsRecorder
 }
 
 func NewClient(config NewClientConfig) *Client {
@@ -44,17 +93,37 @@ func NewClient(config NewClientConfig) *Client {
 		config.SkipSSLValidation,
 	)
 
-	s3client := s3resource.NewS3Client(
-		config.Stderr,
-		awsConfig,
-		false,
-	)
+	if config.CredentialsProvider != nil {
+		awsConfig.Credentials = newAWSCredentials(config.CredentialsProvider)
+	}
+
+	if config.UseDualStack {
+		awsConfig.UseDualStack = aws.Bool(true)
+	}
+
+	if config.Transport != nil {
+		awsConfig.HTTPClient = &http.Client{Transport: config.Transport}
+	}
+
+	// Retries are handled by the client according to the class of error
+	// rather than by the SDK, which retries credential errors too.
+	awsConfig.MaxRetries = aws.Int(0)
+
+	retryBudget := config.RetryBudget
+	if retryBudget == 0 {
+		retryBudget = DefaultRetryBudget
+	}
 
 	return &Client{
-		bucket:          config.Bucket,
-		stderr:          config.Stderr,
-		logger:          config.Logger,
-		s3client:        s3client,
+		bucket:       config.Bucket,
+		storageClass: config.StorageClass,
+		stderr:       config.Stderr,
+		logger:       config.Logger,
+		stats:        config.Stats,
+		awsConfig:    awsConfig,
+
+		retriesRemaining: &retryBudget,
+		sleep:            time.Sleep,
 	}
 }
 
@@ -80,8 +149,6 @@ func (c Client) Upload(fileGlob string, to string, sourcesDir string) error {
 	localPath := matches[0]
 	remotePath := filepath.Join(to, filepath.Base(localPath))
 
-	options := s3resource.NewUploadFileOptions()
-
 	c.logger.Info(fmt.Sprintf(
 		"Uploading %s to s3://%s/%s",
 		localPath,
@@ -89,12 +156,23 @@ func (c Client) Upload(fileGlob string, to string, sourcesDir string) error {
 		remotePath,
 	))
 
-	_, err = c.s3client.UploadFile(
-		c.bucket,
-		remotePath,
-		localPath,
-		options,
-	)
+	start := time.Now()
+
+	err = c.withRetries(func() error {
+		if c.storageClass != "" {
+			return c.uploadWithStorageClass(localPath, remotePath)
+		}
+
+		options := s3resource.NewUploadFileOptions()
+
+		_, err := c.s3Client().UploadFile(
+			c.bucket,
+			remotePath,
+			localPath,
+			options,
+		)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -102,6 +180,15 @@ func (c Client) Upload(fileGlob string, to string, sourcesDir string) error {
 	// the s3client does not append a new-line to its output
 	fmt.Fprintln(c.stderr)
 
+	if c.stats != nil {
+		info, err := os.Stat(localPath)
+		if err != nil {
+			return err
+		}
+
+		c.stats.RecordUpload(info.Size(), time.Since(start))
+	}
+
 	c.logger.Info(fmt.Sprintf(
 		"Successfully uploaded '%s' to 's3://%s/%s'",
 		localPath,
@@ -111,3 +198,190 @@ func (c Client) Upload(fileGlob string, to string, sourcesDir string) error {
 
 	return nil
 }
+
+// Delete removes the object at remotePath from the bucket.
+func (c Client) Delete(remotePath string) error {
+	c.logger.Info(fmt.Sprintf(
+		"Deleting s3://%s/%s",
+		c.bucket,
+		remotePath,
+	))
+
+	return c.withRegionDiscovery(func() error {
+		_, err := awss3.New(session.New(c.awsConfig)).DeleteObject(&awss3.DeleteObjectInput{
+			Bucket: aws.String(c.bucket),
+			Key:    aws.String(remotePath),
+		})
+		return err
+	})
+}
+
+// Read returns the content of the object at remotePath. It returns false if
+// there is no such object.
+func (c Client) Read(remotePath string) ([]byte, bool, error) {
+	var content []byte
+	var found bool
+
+	err := c.withRegionDiscovery(func() error {
+		output, err := awss3.New(session.New(c.awsConfig)).GetObject(&awss3.GetObjectInput{
+			Bucket: aws.String(c.bucket),
+			Key:    aws.String(remotePath),
+		})
+		if err != nil {
+			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NoSuchKey" {
+				found = false
+				return nil
+			}
+			return err
+		}
+		defer output.Body.Close()
+
+		content, err = ioutil.ReadAll(output.Body)
+		if err != nil {
+			return err
+		}
+
+		found = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return content, found, nil
+}
+
+// Write creates or replaces the object at remotePath with content.
+func (c Client) Write(remotePath string, content []byte) error {
+	return c.withRegionDiscovery(func() error {
+		_, err := awss3.New(session.New(c.awsConfig)).PutObject(&awss3.PutObjectInput{
+			Bucket: aws.String(c.bucket),
+			Key:    aws.String(remotePath),
+			Body:   bytes.NewReader(content),
+			ACL:    aws.String("private"),
+		})
+		return err
+	})
+}
+
+// uploadWithStorageClass uploads the file directly via the AWS SDK as the
+// s3resource client does not support setting the storage class of an object.
+func (c Client) uploadWithStorageClass(localPath string, remotePath string) error {
+	c.logger.Info(fmt.Sprintf("Using storage class: '%s'", c.storageClass))
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	uploader := s3manager.NewUploader(session.New(c.awsConfig))
+
+	_, err = uploader.Upload(&s3manager.UploadInput{
+		Bucket:       aws.String(c.bucket),
+		Key:          aws.String(remotePath),
+		Body:         file,
+		ACL:          aws.String("private"),
+		StorageClass: aws.String(c.storageClass),
+	})
+	return err
+}
+
+// withRetries invokes upload, retrying failures according to the retry
+// policy for their class until either the policy or the client's retry
+// budget is exhausted.
+func (c Client) withRetries(upload func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := c.withRegionDiscovery(upload)
+		if err == nil {
+			return nil
+		}
+
+		class := ClassifyError(err)
+		policy := RetryPolicies[class]
+
+		c.logger.Info(fmt.Sprintf(
+			"Upload to s3 failed with %s error: %s",
+			class,
+			err.Error(),
+		))
+
+		if attempt >= policy.MaxRetries {
+			if policy.MaxRetries == 0 {
+				c.logger.Info(fmt.Sprintf("Not retrying %s errors", class))
+			}
+			return fmt.Errorf("upload to s3 failed with %s error: %s", class, err.Error())
+		}
+
+		if *c.retriesRemaining <= 0 {
+			c.logger.Info("S3 retry budget exhausted")
+			return fmt.Errorf("upload to s3 failed with %s error: %s", class, err.Error())
+		}
+		*c.retriesRemaining--
+
+		if c.stats != nil {
+			c.stats.RecordRetry()
+		}
+
+		backoff := policy.Backoff << uint(attempt)
+		c.logger.Info(fmt.Sprintf(
+			"Retrying in %s (retry %d of %d for %s errors, %d retries left in budget)",
+			backoff,
+			attempt+1,
+			policy.MaxRetries,
+			class,
+			*c.retriesRemaining,
+		))
+		c.sleep(backoff)
+	}
+}
+
+// withRegionDiscovery invokes request and, if it fails because the bucket is
+// not in the configured region, discovers the region of the bucket and
+// invokes request again in that region. The discovered region is kept for
+// all subsequent requests of the client.
+func (c Client) withRegionDiscovery(request func() error) error {
+	err := request()
+	if err == nil || !IsRegionError(err) {
+		return err
+	}
+
+	configuredRegion := aws.StringValue(c.awsConfig.Region)
+
+	region, discoverErr := s3manager.GetBucketRegion(
+		aws.BackgroundContext(),
+		session.New(c.awsConfig),
+		c.bucket,
+		configuredRegion,
+	)
+	if discoverErr != nil {
+		c.logger.Info(fmt.Sprintf(
+			"Could not discover region of bucket '%s': %s",
+			c.bucket,
+			discoverErr.Error(),
+		))
+		return err
+	}
+
+	if region == configuredRegion {
+		return err
+	}
+
+	c.logger.Info(fmt.Sprintf(
+		"Bucket '%s' is in region '%s', not the configured region '%s' - retrying in region '%s'",
+		c.bucket,
+		region,
+		configuredRegion,
+		region,
+	))
+
+	c.awsConfig.Region = aws.String(region)
+
+	return request()
+}
+
+// s3Client returns a client for the current region of the bucket, which
+// may have changed since the client was created.
+func (c Client) s3Client() s3resource.S3Client {
+	return s3resource.NewS3Client(c.stderr, c.awsConfig, false)
+}
@@ -0,0 +1,66 @@
+// This file was generated by counterfeiter
+package s3fakes
+
+import (
+	"sync"
+
+	"github.com/pivotal-cf/pivnet-resource/s3"
+)
+
+type FakeCredentialsProvider struct {
+	RetrieveStub        func() (s3.Credentials, error)
+	retrieveMutex       sync.RWMutex
+	retrieveArgsForCall []struct{}
+	retrieveReturns     struct {
+		result1 s3.Credentials
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCredentialsProvider) Retrieve() (s3.Credentials, error) {
+	fake.retrieveMutex.Lock()
+	fake.retrieveArgsForCall = append(fake.retrieveArgsForCall, struct{}{})
+	fake.recordInvocation("Retrieve", []interface{}{})
+	fake.retrieveMutex.Unlock()
+	if fake.RetrieveStub != nil {
+		return fake.RetrieveStub()
+	} else {
+		return fake.retrieveReturns.result1, fake.retrieveReturns.result2
+	}
+}
+
+func (fake *FakeCredentialsProvider) RetrieveCallCount() int {
+	fake.retrieveMutex.RLock()
+	defer fake.retrieveMutex.RUnlock()
+	return len(fake.retrieveArgsForCall)
+}
+
+func (fake *FakeCredentialsProvider) RetrieveReturns(result1 s3.Credentials, result2 error) {
+	fake.RetrieveStub = nil
+	fake.retrieveReturns = struct {
+		result1 s3.Credentials
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCredentialsProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.retrieveMutex.RLock()
+	defer fake.retrieveMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCredentialsProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
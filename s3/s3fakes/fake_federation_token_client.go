@@ -0,0 +1,76 @@
+// This file was generated by counterfeiter
+package s3fakes
+
+import (
+	"sync"
+
+	go_pivnet "github.com/pivotal-cf/go-pivnet"
+)
+
+type FakeFederationTokenClient struct {
+	GetFederationTokenStub        func(productSlug string) (go_pivnet.FederationToken, error)
+	getFederationTokenMutex       sync.RWMutex
+	getFederationTokenArgsForCall []struct {
+		productSlug string
+	}
+	getFederationTokenReturns struct {
+		result1 go_pivnet.FederationToken
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeFederationTokenClient) GetFederationToken(productSlug string) (go_pivnet.FederationToken, error) {
+	fake.getFederationTokenMutex.Lock()
+	fake.getFederationTokenArgsForCall = append(fake.getFederationTokenArgsForCall, struct {
+		productSlug string
+	}{productSlug})
+	fake.recordInvocation("GetFederationToken", []interface{}{productSlug})
+	fake.getFederationTokenMutex.Unlock()
+	if fake.GetFederationTokenStub != nil {
+		return fake.GetFederationTokenStub(productSlug)
+	} else {
+		return fake.getFederationTokenReturns.result1, fake.getFederationTokenReturns.result2
+	}
+}
+
+func (fake *FakeFederationTokenClient) GetFederationTokenCallCount() int {
+	fake.getFederationTokenMutex.RLock()
+	defer fake.getFederationTokenMutex.RUnlock()
+	return len(fake.getFederationTokenArgsForCall)
+}
+
+func (fake *FakeFederationTokenClient) GetFederationTokenArgsForCall(i int) string {
+	fake.getFederationTokenMutex.RLock()
+	defer fake.getFederationTokenMutex.RUnlock()
+	return fake.getFederationTokenArgsForCall[i].productSlug
+}
+
+func (fake *FakeFederationTokenClient) GetFederationTokenReturns(result1 go_pivnet.FederationToken, result2 error) {
+	fake.GetFederationTokenStub = nil
+	fake.getFederationTokenReturns = struct {
+		result1 go_pivnet.FederationToken
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeFederationTokenClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getFederationTokenMutex.RLock()
+	defer fake.getFederationTokenMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeFederationTokenClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,121 @@
+// This file was generated by counterfeiter
+package s3fakes
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+type FakeSTSClient struct {
+	AssumeRoleStub        func(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error)
+	assumeRoleMutex       sync.RWMutex
+	assumeRoleArgsForCall []struct {
+		input *sts.AssumeRoleInput
+	}
+	assumeRoleReturns struct {
+		result1 *sts.AssumeRoleOutput
+		result2 error
+	}
+	AssumeRoleWithWebIdentityStub        func(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error)
+	assumeRoleWithWebIdentityMutex       sync.RWMutex
+	assumeRoleWithWebIdentityArgsForCall []struct {
+		input *sts.AssumeRoleWithWebIdentityInput
+	}
+	assumeRoleWithWebIdentityReturns struct {
+		result1 *sts.AssumeRoleWithWebIdentityOutput
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSTSClient) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	fake.assumeRoleMutex.Lock()
+	fake.assumeRoleArgsForCall = append(fake.assumeRoleArgsForCall, struct {
+		input *sts.AssumeRoleInput
+	}{input})
+	fake.recordInvocation("AssumeRole", []interface{}{input})
+	fake.assumeRoleMutex.Unlock()
+	if fake.AssumeRoleStub != nil {
+		return fake.AssumeRoleStub(input)
+	} else {
+		return fake.assumeRoleReturns.result1, fake.assumeRoleReturns.result2
+	}
+}
+
+func (fake *FakeSTSClient) AssumeRoleCallCount() int {
+	fake.assumeRoleMutex.RLock()
+	defer fake.assumeRoleMutex.RUnlock()
+	return len(fake.assumeRoleArgsForCall)
+}
+
+func (fake *FakeSTSClient) AssumeRoleArgsForCall(i int) *sts.AssumeRoleInput {
+	fake.assumeRoleMutex.RLock()
+	defer fake.assumeRoleMutex.RUnlock()
+	return fake.assumeRoleArgsForCall[i].input
+}
+
+func (fake *FakeSTSClient) AssumeRoleReturns(result1 *sts.AssumeRoleOutput, result2 error) {
+	fake.AssumeRoleStub = nil
+	fake.assumeRoleReturns = struct {
+		result1 *sts.AssumeRoleOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSTSClient) AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	fake.assumeRoleWithWebIdentityMutex.Lock()
+	fake.assumeRoleWithWebIdentityArgsForCall = append(fake.assumeRoleWithWebIdentityArgsForCall, struct {
+		input *sts.AssumeRoleWithWebIdentityInput
+	}{input})
+	fake.recordInvocation("AssumeRoleWithWebIdentity", []interface{}{input})
+	fake.assumeRoleWithWebIdentityMutex.Unlock()
+	if fake.AssumeRoleWithWebIdentityStub != nil {
+		return fake.AssumeRoleWithWebIdentityStub(input)
+	} else {
+		return fake.assumeRoleWithWebIdentityReturns.result1, fake.assumeRoleWithWebIdentityReturns.result2
+	}
+}
+
+func (fake *FakeSTSClient) AssumeRoleWithWebIdentityCallCount() int {
+	fake.assumeRoleWithWebIdentityMutex.RLock()
+	defer fake.assumeRoleWithWebIdentityMutex.RUnlock()
+	return len(fake.assumeRoleWithWebIdentityArgsForCall)
+}
+
+func (fake *FakeSTSClient) AssumeRoleWithWebIdentityArgsForCall(i int) *sts.AssumeRoleWithWebIdentityInput {
+	fake.assumeRoleWithWebIdentityMutex.RLock()
+	defer fake.assumeRoleWithWebIdentityMutex.RUnlock()
+	return fake.assumeRoleWithWebIdentityArgsForCall[i].input
+}
+
+func (fake *FakeSTSClient) AssumeRoleWithWebIdentityReturns(result1 *sts.AssumeRoleWithWebIdentityOutput, result2 error) {
+	fake.AssumeRoleWithWebIdentityStub = nil
+	fake.assumeRoleWithWebIdentityReturns = struct {
+		result1 *sts.AssumeRoleWithWebIdentityOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSTSClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.assumeRoleMutex.RLock()
+	defer fake.assumeRoleMutex.RUnlock()
+	fake.assumeRoleWithWebIdentityMutex.RLock()
+	defer fake.assumeRoleWithWebIdentityMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeSTSClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -0,0 +1,71 @@
+package sbom
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// FileType is the file type of the product files of SBOMs, so that they are
+// labeled consistently whichever format they are in.
+const FileType = "Software Bill of Materials"
+
+const (
+	SPDX      = "SPDX"
+	CycloneDX = "CycloneDX"
+)
+
+// sniffLength is the length of the start of a file which is read to detect
+// its format. The fields identifying each format precede the potentially
+// large lists of components.
+const sniffLength = 64 * 1024
+
+// markers are checked in order. CycloneDX documents are checked first as
+// they may refer to SPDX license identifiers.
+var markers = []struct {
+	format string
+	marker []byte
+}{
+	// JSON documents
+	{CycloneDX, []byte(`"bomFormat"`)},
+	// XML documents
+	{CycloneDX, []byte("cyclonedx.org/schema/bom")},
+	// JSON and YAML documents
+	{SPDX, []byte("spdxVersion")},
+	// Tag-value documents
+	{SPDX, []byte("SPDXVersion:")},
+	// RDF/XML documents
+	{SPDX, []byte("spdx.org/rdf/terms")},
+}
+
+type Detector struct{}
+
+func NewDetector() Detector {
+	return Detector{}
+}
+
+// Detect returns the format of the SBOM at path, either SPDX or CycloneDX,
+// or an error if it is in neither.
+func (d Detector) Detect(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	start := make([]byte, sniffLength)
+	n, err := io.ReadFull(f, start)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	start = start[:n]
+
+	for _, m := range markers {
+		if bytes.Contains(start, m.marker) {
+			return m.format, nil
+		}
+	}
+
+	return "", fmt.Errorf("'%s' is not an SPDX or CycloneDX SBOM", path)
+}
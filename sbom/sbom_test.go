@@ -0,0 +1,76 @@
+package sbom_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/sbom"
+)
+
+var _ = Describe("Detector", func() {
+	var (
+		dir      string
+		detector sbom.Detector
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "sbom")
+		Expect(err).NotTo(HaveOccurred())
+
+		detector = sbom.NewDetector()
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	detect := func(name string, contents string) (string, error) {
+		path := filepath.Join(dir, name)
+		err := ioutil.WriteFile(path, []byte(contents), 0644)
+		Expect(err).NotTo(HaveOccurred())
+
+		return detector.Detect(path)
+	}
+
+	It("detects SPDX JSON documents", func() {
+		format, err := detect("some.spdx.json", `{"spdxVersion": "SPDX-2.3", "packages": []}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal(sbom.SPDX))
+	})
+
+	It("detects SPDX tag-value documents", func() {
+		format, err := detect("some.spdx", "SPDXVersion: SPDX-2.3\nDataLicense: CC0-1.0\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal(sbom.SPDX))
+	})
+
+	It("detects CycloneDX JSON documents", func() {
+		format, err := detect("some.cdx.json", `{"bomFormat": "CycloneDX", "specVersion": "1.5"}`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal(sbom.CycloneDX))
+	})
+
+	It("detects CycloneDX XML documents", func() {
+		format, err := detect("some.cdx.xml", `<?xml version="1.0"?><bom xmlns="http://cyclonedx.org/schema/bom/1.5"></bom>`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal(sbom.CycloneDX))
+	})
+
+	Context("when the file is not an SBOM", func() {
+		It("returns an error", func() {
+			_, err := detect("some.json", `{"name": "not an sbom"}`)
+			Expect(err).To(MatchError(ContainSubstring("is not an SPDX or CycloneDX SBOM")))
+		})
+	})
+
+	Context("when the file does not exist", func() {
+		It("returns an error", func() {
+			_, err := detector.Detect(filepath.Join(dir, "missing.json"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,64 @@
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CleanResult is the result of a scan which passed without output.
+const CleanResult = "clean"
+
+type Scanner struct {
+	command    string
+	sourcesDir string
+}
+
+// NewScanner returns a Scanner which scans files by running command with the
+// path of the file as its last argument, e.g. 'clamdscan --no-summary'. The
+// command is run by sh, so it may be a pipeline or script. An empty command
+// disables scanning.
+func NewScanner(command string, sourcesDir string) Scanner {
+	return Scanner{
+		command:    command,
+		sourcesDir: sourcesDir,
+	}
+}
+
+// Scan runs the scan command on exactGlob in sourcesDir and returns its result,
+// i.e. the last line of its output, or CleanResult if it had none. A command
+// which exits non-zero, e.g. because it detected malware, fails the scan. If no
+// command is configured nothing is scanned and the returned result is empty.
+func (s Scanner) Scan(exactGlob string) (string, error) {
+	if s.command == "" {
+		return "", nil
+	}
+
+	// The path is passed as a positional parameter rather than interpolated
+	// into the command, so that it is not interpreted by the shell.
+	cmd := exec.Command("sh", "-c", s.command+` "$1"`, "scan_command", filepath.Join(s.sourcesDir, exactGlob))
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("scan of '%s' failed (%s): %s", exactGlob, err, strings.TrimSpace(output.String()))
+	}
+
+	return lastLine(output.String()), nil
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+
+	last := strings.TrimSpace(lines[len(lines)-1])
+	if last == "" {
+		return CleanResult
+	}
+
+	return last
+}
@@ -0,0 +1,13 @@
+package scan_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Scan Suite")
+}
@@ -0,0 +1,89 @@
+package scan_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/pivnet-resource/scan"
+)
+
+var _ = Describe("Scanner", func() {
+	var (
+		sourcesDir string
+		command    string
+
+		scanner scan.Scanner
+	)
+
+	BeforeEach(func() {
+		var err error
+		sourcesDir, err = ioutil.TempDir("", "scan")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.MkdirAll(filepath.Join(sourcesDir, "some dir"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(sourcesDir, "some dir", "file;name"), []byte("some-content"), 0644)
+		Expect(err).NotTo(HaveOccurred())
+
+		command = "cat"
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(sourcesDir)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		scanner = scan.NewScanner(command, sourcesDir)
+	})
+
+	It("runs the command on the file and returns the last line of its output", func() {
+		result, err := scanner.Scan("some dir/file;name")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result).To(Equal("some-content"))
+	})
+
+	Context("when the command has no output", func() {
+		BeforeEach(func() {
+			command = "test -f"
+		})
+
+		It("returns the clean result", func() {
+			result, err := scanner.Scan("some dir/file;name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result).To(Equal(scan.CleanResult))
+		})
+	})
+
+	Context("when the command fails", func() {
+		BeforeEach(func() {
+			command = `f() { echo "$1: Eicar-Signature FOUND"; exit 1; }; f`
+		})
+
+		It("returns an error with its output", func() {
+			_, err := scanner.Scan("some dir/file;name")
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(ContainSubstring("scan of 'some dir/file;name' failed (exit status 1)"))
+			Expect(err.Error()).To(ContainSubstring("file;name: Eicar-Signature FOUND"))
+		})
+	})
+
+	Context("when no command is configured", func() {
+		BeforeEach(func() {
+			command = ""
+		})
+
+		It("does not scan the file", func() {
+			result, err := scanner.Scan("some dir/file;name")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,241 @@
+package schema
+
+import (
+	"github.com/pivotal-cf/pivnet-resource/checksums"
+	"github.com/pivotal-cf/pivnet-resource/compression"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/s3"
+)
+
+var (
+	CheckRequest = &Schema{
+		Type:        "object",
+		Description: "Request read by check.",
+		Required:    []string{"source"},
+		Properties: map[string]*Schema{
+			"source":  source,
+			"version": version,
+		},
+	}
+
+	InRequest = &Schema{
+		Type:        "object",
+		Description: "Request read by in.",
+		Required:    []string{"source"},
+		Properties: map[string]*Schema{
+			"source":  source,
+			"version": version,
+			"params":  inParams,
+		},
+	}
+
+	OutRequest = &Schema{
+		Type:        "object",
+		Description: "Request read by out.",
+		Required:    []string{"source"},
+		Properties: map[string]*Schema{
+			"source": source,
+			"params": outParams,
+		},
+	}
+)
+
+var source = &Schema{
+	Type:                 "object",
+	Description:          "Configuration of the resource.",
+	Required:             []string{"product_slug"},
+	AdditionalProperties: false,
+	Properties: map[string]*Schema{
+		"api_token":                 str("Pivotal Network legacy API token or UAA refresh token."),
+		"api_token_file":            str("Path of a file containing the api_token, e.g. a mounted secret."),
+		"product_slug":              str("Name of the product on Pivotal Network."),
+		"product_version":           str("Regex which versions must match."),
+		"version_constraint":        str("Comma-separated semver clauses which versions must satisfy, e.g. '~> 2.4' or '>= 1.8, < 2.0'."),
+		"released_after":            str("Date, as YYYY-MM-DD, before which releases are ignored by check."),
+		"pinned_version":            str("Exact version which check always emits, e.g. a known-good release."),
+		"endpoint":                  withDefault(str("Pivotal Network endpoint."), "https://network.pivotal.io"),
+		"release_type":              str("Release type which releases must have."),
+		"channel":                   str("Name of a channel, e.g. stable or edge, combining release types, availability and version constraint."),
+		"sort_by":                   withDefault(enum("Order of the versions returned by check.", string(concourse.SortByNone), string(concourse.SortBySemver), string(concourse.SortByReleaseDate)), string(concourse.SortByNone)),
+		"check_limit":               nonNegative("Number of the most recent releases which check lists and returns versions of, or 0 for all releases."),
+		"availability":              enum("Least broad availability of the releases whose versions check emits.", string(concourse.AvailabilityAllUsers), string(concourse.AvailabilitySelectedUserGroups), string(concourse.AvailabilityAdminsOnly)),
+		"include_unreleased":        withDefault(boolean("Also emit versions of releases which are only available to admins, which are otherwise excluded."), false),
+		"fast_check":                boolean("List only the releases of the product in check, without validating release_type or making requests per release."),
+		"webhook_fast_path":         boolean("Check only the most recent releases, with a single request, when they include the version of the check."),
+		"sample":                    enum("Only emit a new version from check when this part of the semver version changes.", string(concourse.SamplePatch), string(concourse.SampleMinor)),
+		"skip_ssl_verification":     boolean("Skip verification of the Pivotal Network SSL certificate."),
+		"copy_metadata":             boolean("Copy metadata from the latest All Users release within the minor on put."),
+		"verbose":                   boolean("Enable verbose logging."),
+		"max_idle_conns":            nonNegative("Maximum number of idle connections across all hosts."),
+		"max_idle_conns_per_host":   nonNegative("Maximum number of idle connections per host."),
+		"max_conns_per_host":        nonNegative("Maximum number of connections per host."),
+		"tls_handshake_timeout":     nonNegative("TLS handshake timeout in seconds."),
+		"previous_slugs":            stringArray("Slugs the product was previously published under."),
+		"mirror_endpoints":          stringArray("Endpoints of mirrors of Pivotal Network from which get downloads files, in order, if the endpoint fails."),
+		"strict_slug":               boolean("Fail rather than follow redirects to the canonical slug of a moved product."),
+		"one_per_release_type":      boolean("Emit only the latest version of each release type from check."),
+		"version_metadata":          boolean("Include the release type and EULA slug in the versions emitted by check, get and put."),
+		"version_release_id":        boolean("Include the ID of each release in the versions emitted by check, get and put."),
+		"eula_fingerprint":          boolean("Include the EULA slug in the versions emitted by check, get and put, so that swapping the EULA of a release produces a new version."),
+		"product_files_fingerprint": boolean("Include a fingerprint of the product files of each release in the versions emitted by check, get and put."),
+		"local_source":              str("Local directory to read releases from instead of Pivotal Network."),
+		"gpg_private_key":           str("ASCII-armored GPG private key with which put signs each uploaded file."),
+		"gpg_private_key_file":      str("Path of a file containing the gpg_private_key, e.g. a mounted secret."),
+		"gpg_passphrase":            str("Passphrase of the gpg_private_key."),
+		"fips":                      boolean("Restrict TLS and checksums to FIPS-approved algorithms, failing if a file could only be verified by MD5."),
+		"download_cache_dir":        str("Worker-local directory in which downloaded files are cached by SHA256."),
+		"check_cache_dir":           str("Directory in which check caches responses to make later requests conditional."),
+		"checksum_algorithms": {
+			Type:        "array",
+			Description: "Additional checksum algorithms to compute for product files.",
+			Items:       enum("Checksum algorithm.", checksums.Algorithms...),
+		},
+		"s3_targets": {
+			Type:                 "object",
+			Description:          "Buckets, keyed by name, to which file groups can be routed on put.",
+			AdditionalProperties: s3Target,
+		},
+	},
+}
+
+var s3Target = &Schema{
+	Type:                 "object",
+	Description:          "Bucket to which the files of a file group are uploaded.",
+	Required:             []string{"bucket", "region"},
+	AdditionalProperties: false,
+	Properties: map[string]*Schema{
+		"bucket":                 str("Name of the bucket."),
+		"region":                 str("Region of the bucket."),
+		"prefix":                 str("Prefix of the uploaded files. Defaults to the prefix of the product on Pivotal Network."),
+		"access_key_id":          str("AWS access key ID with write access to the bucket."),
+		"secret_access_key":      str("AWS secret access key."),
+		"access_key_id_file":     str("Path of a file containing the access_key_id, e.g. a mounted secret."),
+		"secret_access_key_file": str("Path of a file containing the secret_access_key, e.g. a mounted secret."),
+	},
+}
+
+var version = &Schema{
+	Type:        "object",
+	Description: "Version emitted by check.",
+	Properties: map[string]*Schema{
+		"product_version": str("Version of the release, with its fingerprint."),
+		"release_type":    str("Release type of the release."),
+		"eula_slug":       str("Slug of the EULA of the release."),
+		"release_id":      str("ID of the release, which get prefers over the product version."),
+	},
+}
+
+var inParams = &Schema{
+	Type:                 "object",
+	Description:          "Parameters of get.",
+	AdditionalProperties: false,
+	Properties: map[string]*Schema{
+		"globs":                 globs,
+		"unpack":                boolean("Unpack downloaded archives."),
+		"output_events":         boolean("Write structured progress events to events.jsonl."),
+		"bundle":                boolean("Write a bundle for transfer to an air-gapped environment."),
+		"bosh_release_metadata": boolean("Record the release.MF of downloaded BOSH releases in the metadata."),
+		"allow_partial":         boolean("Succeed even if some product files fail to download."),
+		"eula_action":           withDefault(enum("Whether to accept the EULA or write it to disk for review.", "accept", "preview"), "accept"),
+		"accept":                boolean("Accept the EULA when eula_action is preview."),
+		"head_bytes":            nonNegative("Download only the first head_bytes bytes of each product file."),
+		"zip_members":           stringArray("Extract only the members of each zip product file matching these patterns, using ranged reads."),
+		"platform_filter":       str("Download only the product files for this platform, as os or os/arch, e.g. linux/amd64."),
+		"dry_run":               boolean("List the product files which would be downloaded, with their sizes, without downloading them."),
+		"compress":              enum("Compress each downloaded file with this format to reduce the size of the volume.", compression.Formats...),
+		"stemcell":              stemcell,
+	},
+}
+
+var stemcell = &Schema{
+	Type:                 "object",
+	Description:          "Stemcell, among those the release depends on, to download into the stemcell subdirectory.",
+	Required:             []string{"os"},
+	AdditionalProperties: false,
+	Properties: map[string]*Schema{
+		"os":          str("Operating system of the stemcell, e.g. ubuntu-jammy for the stemcells-ubuntu-jammy product."),
+		"min_version": str("Lowest version of the stemcell which may be downloaded, e.g. 1.200."),
+		"globs":       stringArray("Globs of the stemcell files to download, e.g. the stemcell for one IaaS. All files are downloaded if omitted."),
+	},
+}
+
+var globs = &Schema{
+	Description: "Globs of the product files to download, either as a list or as a map of each glob to the subdirectory in which to place the files it matches. All files are downloaded if omitted.",
+	OneOf: []*Schema{
+		{Type: "array", Items: &Schema{Type: "string"}},
+		{Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+	},
+}
+
+var outParams = &Schema{
+	Type:                 "object",
+	Description:          "Parameters of put.",
+	AdditionalProperties: false,
+	Properties: map[string]*Schema{
+		"file_glob":                           str("Glob matching the files to upload."),
+		"file_glob_exclusions":                stringArray("Globs of files matching file_glob which are not uploaded."),
+		"follow_symlinks":                     withDefault(boolean("Upload the files which symlinks matching file_glob link to, rather than skipping the symlinks."), true),
+		"metadata_file":                       str("Path of the metadata file."),
+		"override":                            boolean("Re-upload releases which already exist."),
+		"require_version_greater_than_latest": boolean("Refuse to create a release whose version is not greater than the latest."),
+		"storage_class":                       enum("S3 storage class of uploaded files.", s3.StorageClasses...),
+		"docs_url_template":                   str("Template for the docs URL of uploaded files."),
+		"chunk_manifest_threshold":            nonNegative("Size in bytes above which a chunk manifest is published for a file."),
+		"chunk_manifest_chunk_size":           withDefault(nonNegative("Size in bytes of each chunk in a chunk manifest."), 67108864),
+		"bundle":                              str("Path of a bundle to republish."),
+		"s3_retry_budget":                     withDefault(nonNegative("Total number of retries of failed S3 uploads."), 5),
+		"use_dualstack":                       boolean("Upload to the dual-stack S3 endpoints, which are reachable over IPv6."),
+		"verify_publish":                      boolean("Verify the published release matches the request."),
+		"snapshot_file":                       str("Path, relative to the sources directory, to which the release as recorded by Pivotal Network is written after it is published."),
+		"cleanup_staging_objects":             boolean("Delete uploaded files from the bucket once Pivotal Network has ingested them."),
+		"ingestion_timeout":                   withDefault(nonNegative("Seconds to wait for Pivotal Network to ingest each uploaded file."), 3600),
+		"attach_concurrency":                  withDefault(nonNegative("Number of product files attached to the release, and awaited, at a time."), 1),
+		"expected_file_count":                 nonNegative("Number of files which file_glob must match for the release to be created."),
+		"publish_lock":                        boolean("Hold a lock on the release version while publishing it."),
+		"publish_lock_expiry":                 withDefault(nonNegative("Seconds after which a publish lock is stale and may be broken."), 7200),
+		"publish_lock_wait":                   nonNegative("Seconds to wait for a publish lock held by another put."),
+		"resume":                              boolean("Resume an interrupted put of the same version rather than creating the release again."),
+		"scan_command":                        str("Command run with the path of each file before upload, e.g. to scan for malware."),
+		"auto_included_files":                 boolean("Set the included files of archives without included_files in the metadata to their top-level contents."),
+		"version_prefix":                      str("Prefix applied to the version in the metadata file."),
+		"version_suffix":                      str("Suffix applied to the version in the metadata file, e.g. -LTS."),
+		"description_template":                str("Go template from which the release description is rendered, replacing the description in the metadata file."),
+		"description_files": {
+			Type:                 "object",
+			Description:          "Paths of input files, keyed by name, whose contents the description template can reference as .Files.<name>.",
+			AdditionalProperties: &Schema{Type: "string"},
+		},
+		"required_fields": {
+			Type:        "array",
+			Description: "Fields of the metadata file which must be provided for the release to be created, e.g. a release checklist.",
+			Items:       enum("Required field.", metadata.RequiredFields...),
+		},
+	},
+}
+
+func str(description string) *Schema {
+	return &Schema{Type: "string", Description: description}
+}
+
+func boolean(description string) *Schema {
+	return &Schema{Type: "boolean", Description: description}
+}
+
+func stringArray(description string) *Schema {
+	return &Schema{Type: "array", Description: description, Items: &Schema{Type: "string"}}
+}
+
+func enum(description string, values ...string) *Schema {
+	return &Schema{Type: "string", Description: description, Enum: values}
+}
+
+func nonNegative(description string) *Schema {
+	zero := 0.0
+	return &Schema{Type: "integer", Description: description, Minimum: &zero}
+}
+
+func withDefault(s *Schema, value interface{}) *Schema {
+	s.Default = value
+	return s
+}
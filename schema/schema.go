@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Flag is the command line flag with which each binary prints the schema of
+// its request and exits, e.g. so that pipeline configuration can be validated
+// offline.
+const Flag = "--schema"
+
+// Schema is the subset of JSON Schema needed to describe the requests
+// accepted by the resource.
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	OneOf       []*Schema          `json:"oneOf,omitempty"`
+
+	// AdditionalProperties is either false, to reject properties which are
+	// not in Properties, or the *Schema which they must match.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
+
+// Decode reads a JSON request from r, validates it against s and, if it is
+// valid, unmarshals it into v. It returns a warning for each field which is
+// not supported, as Validate does.
+func Decode(r io.Reader, s *Schema, v interface{}) ([]string, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw interface{}
+	err = json.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := s.Validate(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return warnings, json.Unmarshal(b, v)
+}
+
+// Print writes the schema to w as indented JSON.
+func Print(w io.Writer, s *Schema) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// unsupportedField is the problem with a field which is not in the
+// properties of an object that does not allow additional properties.
+const unsupportedField = "is not a supported field"
+
+// Validate returns an error listing every way in which the decoded JSON value
+// does not match the schema. Each problem is prefixed with the path of the
+// offending value, e.g. "source.sort_by: must be one of none|semver|release_date".
+//
+// Fields which are not supported, usually typos, are returned as warnings
+// rather than problems for one release cycle, as they were ignored before
+// requests were validated. They will be rejected in a later release.
+func (s *Schema) Validate(value interface{}) ([]string, error) {
+	var problems []string
+	var warnings []string
+	for _, p := range s.validate("", value) {
+		if strings.HasSuffix(p, unsupportedField) {
+			warnings = append(warnings, p+" and is ignored - it will be rejected in a future release")
+			continue
+		}
+		problems = append(problems, p)
+	}
+
+	if len(problems) == 0 {
+		return warnings, nil
+	}
+
+	if len(problems) == 1 {
+		return nil, fmt.Errorf("invalid request: %s", problems[0])
+	}
+
+	return nil, fmt.Errorf("invalid request:\n  - %s", strings.Join(problems, "\n  - "))
+}
+
+func (s *Schema) validate(path string, value interface{}) []string {
+	if value == nil {
+		return nil
+	}
+
+	if len(s.OneOf) > 0 {
+		return s.validateOneOf(path, value)
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{problem(path, "must be an object")}
+		}
+		return s.validateObject(path, object)
+
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{problem(path, "must be an array")}
+		}
+
+		var problems []string
+		for i, item := range array {
+			problems = append(problems, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+		return problems
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{problem(path, "must be a string")}
+		}
+
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			return []string{problem(path, fmt.Sprintf("must be one of %s", strings.Join(s.Enum, "|")))}
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{problem(path, "must be a boolean")}
+		}
+
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return []string{problem(path, "must be an integer")}
+		}
+
+		if s.Minimum != nil && number < *s.Minimum {
+			return []string{problem(path, fmt.Sprintf("must be at least %v", *s.Minimum))}
+		}
+	}
+
+	return nil
+}
+
+// validateOneOf accepts the value if it matches any of the alternatives. If
+// it matches none, the problems are those of the alternative of the same type
+// as the value, if there is one.
+func (s *Schema) validateOneOf(path string, value interface{}) []string {
+	var types []string
+	for _, alternative := range s.OneOf {
+		problems := alternative.validate(path, value)
+		if len(problems) == 0 {
+			return nil
+		}
+
+		if alternative.Type == jsonType(value) {
+			return problems
+		}
+
+		types = append(types, alternative.Type)
+	}
+
+	return []string{problem(path, "must be an "+strings.Join(types, " or an "))}
+}
+
+func (s *Schema) validateObject(path string, object map[string]interface{}) []string {
+	var problems []string
+
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			problems = append(problems, problem(join(path, name), "is required"))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := s.Properties[name]
+		if !ok {
+			switch additional := s.AdditionalProperties.(type) {
+			case bool:
+				if !additional {
+					problems = append(problems, problem(join(path, name), unsupportedField))
+				}
+				continue
+			case *Schema:
+				property = additional
+			default:
+				continue
+			}
+		}
+
+		problems = append(problems, property.validate(join(path, name), object[name])...)
+	}
+
+	return problems
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	}
+	return ""
+}
+
+func join(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func problem(path string, message string) string {
+	if path == "" {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", path, message)
+}
+
+func contains(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
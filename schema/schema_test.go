@@ -0,0 +1,215 @@
+package schema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schema", func() {
+	Describe("Decode", func() {
+		It("decodes a valid request", func() {
+			var input concourse.InRequest
+			_, err := schema.Decode(strings.NewReader(`{
+				"source": {"product_slug": "some-product", "sort_by": "semver"},
+				"version": {"product_version": "1.2.3#some-fingerprint"},
+				"params": {"globs": ["*.pivotal"]}
+			}`), schema.InRequest, &input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(input.Source.ProductSlug).To(Equal("some-product"))
+			Expect(input.Source.SortBy).To(Equal(concourse.SortBySemver))
+			Expect(input.Version.ProductVersion).To(Equal("1.2.3#some-fingerprint"))
+			Expect(input.Params.Globs).To(Equal([]string{"*.pivotal"}))
+		})
+
+		It("decodes globs given as a map of glob to subdirectory", func() {
+			var input concourse.InRequest
+			_, err := schema.Decode(strings.NewReader(`{
+				"source": {"product_slug": "some-product"},
+				"params": {"globs": {"*.pivotal": "tiles", "*.tgz": "stemcells"}}
+			}`), schema.InRequest, &input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(input.Params.Globs).To(Equal([]string{"*.pivotal", "*.tgz"}))
+			Expect(input.Params.GlobSubdirs).To(Equal(map[string]string{
+				"*.pivotal": "tiles",
+				"*.tgz":     "stemcells",
+			}))
+		})
+
+		It("accepts a null version", func() {
+			var input concourse.CheckRequest
+			_, err := schema.Decode(strings.NewReader(`{
+				"source": {"product_slug": "some-product"},
+				"version": null
+			}`), schema.CheckRequest, &input)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error for invalid json", func() {
+			var input concourse.CheckRequest
+			_, err := schema.Decode(strings.NewReader(`{`), schema.CheckRequest, &input)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("decodes a request with unsupported fields and warns of them", func() {
+			var input concourse.CheckRequest
+			warnings, err := schema.Decode(strings.NewReader(`{
+				"source": {"product_slug": "some-product", "sort-by": "semver"}
+			}`), schema.CheckRequest, &input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(input.Source.ProductSlug).To(Equal("some-product"))
+			Expect(warnings).To(Equal([]string{
+				"source.sort-by: is not a supported field and is ignored - it will be rejected in a future release",
+			}))
+		})
+
+		It("does not decode an invalid request", func() {
+			var input concourse.OutRequest
+			_, err := schema.Decode(strings.NewReader(`{
+				"source": {"product_slug": "some-product"},
+				"params": {"file_glob": 7}
+			}`), schema.OutRequest, &input)
+			Expect(err).To(MatchError("invalid request: params.file_glob: must be a string"))
+			Expect(input.Source.ProductSlug).To(BeEmpty())
+		})
+	})
+
+	Describe("Validate", func() {
+		validate := func(s *schema.Schema, request string) error {
+			var raw interface{}
+			_, err := schema.Decode(strings.NewReader(request), &schema.Schema{}, &raw)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = s.Validate(raw)
+			return err
+		}
+
+		It("names the allowed values of an enum", func() {
+			err := validate(schema.CheckRequest, `{"source": {"product_slug": "p", "sort_by": "date"}}`)
+			Expect(err).To(MatchError("invalid request: source.sort_by: must be one of none|semver|release_date"))
+		})
+
+		It("reports missing required fields", func() {
+			err := validate(schema.CheckRequest, `{"source": {}}`)
+			Expect(err).To(MatchError("invalid request: source.product_slug: is required"))
+		})
+
+		It("warns of unsupported fields rather than rejecting them", func() {
+			var raw interface{}
+			_, err := schema.Decode(strings.NewReader(`{"source": {"product_slug": "p", "verbos": true}, "params": {"glob": ["*"]}}`), &schema.Schema{}, &raw)
+			Expect(err).NotTo(HaveOccurred())
+
+			warnings, err := schema.InRequest.Validate(raw)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(Equal([]string{
+				"params.glob: is not a supported field and is ignored - it will be rejected in a future release",
+				"source.verbos: is not a supported field and is ignored - it will be rejected in a future release",
+			}))
+		})
+
+		It("does not warn of unsupported fields of an invalid request", func() {
+			var raw interface{}
+			_, err := schema.Decode(strings.NewReader(`{"source": {"verbos": true}}`), &schema.Schema{}, &raw)
+			Expect(err).NotTo(HaveOccurred())
+
+			warnings, err := schema.InRequest.Validate(raw)
+			Expect(err).To(MatchError("invalid request: source.product_slug: is required"))
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("validates the values of maps against their schema", func() {
+			err := validate(schema.OutRequest, `{"source": {"product_slug": "p", "s3_targets": {"docs": {"region": "r", "access_key_id": "a", "secret_access_key": "s"}}}}`)
+			Expect(err).To(MatchError("invalid request: source.s3_targets.docs.bucket: is required"))
+		})
+
+		It("reports the index of invalid array items", func() {
+			err := validate(schema.CheckRequest, `{"source": {"product_slug": "p", "checksum_algorithms": ["sha512", "md4"]}}`)
+			Expect(err).To(MatchError("invalid request: source.checksum_algorithms[1]: must be one of sha384|sha512"))
+		})
+
+		It("rejects negative and fractional integers", func() {
+			err := validate(schema.OutRequest, `{"source": {"product_slug": "p"}, "params": {"s3_retry_budget": -1, "chunk_manifest_threshold": 1.5}}`)
+			Expect(err).To(MatchError("invalid request:\n" +
+				"  - params.chunk_manifest_threshold: must be an integer\n" +
+				"  - params.s3_retry_budget: must be at least 0"))
+		})
+
+		It("accepts either alternative of a oneOf", func() {
+			Expect(validate(schema.InRequest, `{"source": {"product_slug": "p"}, "params": {"globs": ["*"]}}`)).To(Succeed())
+			Expect(validate(schema.InRequest, `{"source": {"product_slug": "p"}, "params": {"globs": {"*": "dir"}}}`)).To(Succeed())
+		})
+
+		It("reports the problems of the oneOf alternative of the same type", func() {
+			err := validate(schema.InRequest, `{"source": {"product_slug": "p"}, "params": {"globs": {"*": 1}}}`)
+			Expect(err).To(MatchError("invalid request: params.globs.*: must be a string"))
+		})
+
+		It("lists the types of a oneOf for values of another type", func() {
+			err := validate(schema.InRequest, `{"source": {"product_slug": "p"}, "params": {"globs": "*"}}`)
+			Expect(err).To(MatchError("invalid request: params.globs: must be an array or an object"))
+		})
+
+		It("lists every problem", func() {
+			err := validate(schema.InRequest, `{"source": {"verbose": "yes"}, "params": {"unpack": 1}}`)
+			Expect(err).To(MatchError("invalid request:\n" +
+				"  - params.unpack: must be a boolean\n" +
+				"  - source.product_slug: is required\n" +
+				"  - source.verbose: must be a boolean"))
+		})
+	})
+
+	Describe("Print", func() {
+		It("writes the schema as JSON with descriptions and defaults", func() {
+			buffer := &bytes.Buffer{}
+			err := schema.Print(buffer, schema.OutRequest)
+			Expect(err).NotTo(HaveOccurred())
+
+			var printed map[string]interface{}
+			err = json.Unmarshal(buffer.Bytes(), &printed)
+			Expect(err).NotTo(HaveOccurred())
+
+			properties := printed["properties"].(map[string]interface{})
+			params := properties["params"].(map[string]interface{})
+			retryBudget := params["properties"].(map[string]interface{})["s3_retry_budget"].(map[string]interface{})
+
+			Expect(retryBudget["description"]).To(Equal("Total number of retries of failed S3 uploads."))
+			Expect(retryBudget["default"]).To(Equal(5.0))
+			Expect(params["additionalProperties"]).To(Equal(false))
+		})
+	})
+
+	Describe("requests", func() {
+		expectPropertiesFor := func(s *schema.Schema, v interface{}) {
+			t := reflect.TypeOf(v)
+			for i := 0; i < t.NumField(); i++ {
+				name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+				if name == "-" {
+					continue
+				}
+				Expect(s.Properties).To(HaveKey(name), "missing schema for %s.%s", t.Name(), name)
+			}
+		}
+
+		It("describes every field of the source", func() {
+			expectPropertiesFor(schema.CheckRequest.Properties["source"], concourse.Source{})
+		})
+
+		It("describes every in param", func() {
+			expectPropertiesFor(schema.InRequest.Properties["params"], concourse.InParams{})
+		})
+
+		It("describes every out param", func() {
+			expectPropertiesFor(schema.OutRequest.Properties["params"], concourse.OutParams{})
+		})
+	})
+})
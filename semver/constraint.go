@@ -0,0 +1,115 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+var clauseRegexp = regexp.MustCompile(`^(~>|>=|<=|!=|=|>|<)?\s*(\S+)$`)
+
+// Constraint is a set of version clauses, e.g. '>= 1.8, < 2.0', all of which
+// a version must satisfy.
+type Constraint struct {
+	expr    string
+	clauses []clause
+}
+
+type clause struct {
+	op      string
+	version semver.Version
+}
+
+// ParseConstraint parses a comma-separated list of clauses, each of which is
+// an operator (=, !=, >, >=, <, <= or ~>) followed by a version. A clause
+// without an operator is an exact match. Versions may omit their minor and
+// patch parts, e.g. 2.4 is 2.4.0.
+//
+// The pessimistic operator ~> allows only the last part given to increase,
+// e.g. '~> 2.4' is '>= 2.4.0, < 3.0.0' and '~> 2.4.1' is '>= 2.4.1, < 2.5.0'.
+// Prereleases of the upper bound, e.g. 3.0.0-rc.1, are not allowed either.
+func ParseConstraint(expr string) (Constraint, error) {
+	c := Constraint{expr: expr}
+
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+
+		matches := clauseRegexp.FindStringSubmatch(part)
+		if matches == nil {
+			return Constraint{}, fmt.Errorf("invalid clause: '%s'", part)
+		}
+
+		op, versionStr := matches[1], matches[2]
+		if op == "" {
+			op = "="
+		}
+
+		v, err := semver.Parse(padVersion(versionStr))
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid version in clause '%s': %s", part, err.Error())
+		}
+
+		if op != "~>" {
+			c.clauses = append(c.clauses, clause{op: op, version: v})
+			continue
+		}
+
+		if len(v.Pre) > 0 || len(v.Build) > 0 {
+			return Constraint{}, fmt.Errorf("invalid clause: '%s': ~> requires a version without prerelease or build metadata", part)
+		}
+
+		upper := semver.Version{
+			Pre: []semver.PRVersion{{VersionNum: 0, IsNum: true}},
+		}
+		switch len(strings.Split(versionStr, ".")) {
+		case 1, 2:
+			upper.Major = v.Major + 1
+		default:
+			upper.Major = v.Major
+			upper.Minor = v.Minor + 1
+		}
+
+		c.clauses = append(c.clauses,
+			clause{op: ">=", version: v},
+			clause{op: "<", version: upper},
+		)
+	}
+
+	return c, nil
+}
+
+// Check returns true if the version satisfies every clause of the
+// constraint.
+func (c Constraint) Check(v semver.Version) bool {
+	for _, cl := range c.clauses {
+		cmp := v.Compare(cl.version)
+
+		var ok bool
+		switch cl.op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Constraint) String() string {
+	return c.expr
+}
@@ -0,0 +1,102 @@
+package semver_test
+
+import (
+	bsemver "github.com/blang/semver"
+	"github.com/pivotal-cf/pivnet-resource/semver"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Constraint", func() {
+	var (
+		expr string
+	)
+
+	satisfies := func(version string) bool {
+		c, err := semver.ParseConstraint(expr)
+		Expect(err).NotTo(HaveOccurred())
+
+		return c.Check(bsemver.MustParse(version))
+	}
+
+	Context("when the constraint has no operator", func() {
+		BeforeEach(func() {
+			expr = "2.4"
+		})
+
+		It("matches only the exact version", func() {
+			Expect(satisfies("2.4.0")).To(BeTrue())
+			Expect(satisfies("2.4.1")).To(BeFalse())
+		})
+	})
+
+	Context("when the constraint has comparison operators", func() {
+		It("compares versions", func() {
+			expr = "!= 2.4"
+			Expect(satisfies("2.4.0")).To(BeFalse())
+			Expect(satisfies("2.4.1")).To(BeTrue())
+
+			expr = ">2.4"
+			Expect(satisfies("2.4.0")).To(BeFalse())
+			Expect(satisfies("2.4.1")).To(BeTrue())
+
+			expr = "<= 2.4"
+			Expect(satisfies("2.4.0")).To(BeTrue())
+			Expect(satisfies("2.4.1")).To(BeFalse())
+		})
+	})
+
+	Context("when the constraint has several clauses", func() {
+		BeforeEach(func() {
+			expr = ">= 1.8, < 2.0"
+		})
+
+		It("matches versions which satisfy every clause", func() {
+			Expect(satisfies("1.7.9")).To(BeFalse())
+			Expect(satisfies("1.8.0")).To(BeTrue())
+			Expect(satisfies("1.9.3")).To(BeTrue())
+			Expect(satisfies("2.0.0")).To(BeFalse())
+		})
+	})
+
+	Context("when the constraint is pessimistic", func() {
+		BeforeEach(func() {
+			expr = "~> 2.4"
+		})
+
+		It("matches versions up to the next major version", func() {
+			Expect(satisfies("2.3.9")).To(BeFalse())
+			Expect(satisfies("2.4.0")).To(BeTrue())
+			Expect(satisfies("2.9.3")).To(BeTrue())
+			Expect(satisfies("3.0.0-rc.1")).To(BeFalse())
+			Expect(satisfies("3.0.0")).To(BeFalse())
+		})
+
+		Context("when the version has a patch part", func() {
+			BeforeEach(func() {
+				expr = "~> 2.4.1"
+			})
+
+			It("matches versions up to the next minor version", func() {
+				Expect(satisfies("2.4.0")).To(BeFalse())
+				Expect(satisfies("2.4.7")).To(BeTrue())
+				Expect(satisfies("2.5.0")).To(BeFalse())
+			})
+		})
+	})
+
+	Context("when the constraint is invalid", func() {
+		It("returns an error", func() {
+			_, err := semver.ParseConstraint(">= 1.8,")
+			Expect(err).To(MatchError("invalid clause: ''"))
+
+			_, err = semver.ParseConstraint("~> two")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("invalid version in clause '~> two': "))
+
+			_, err = semver.ParseConstraint("~> 2.4-rc.1")
+			Expect(err).To(MatchError("invalid clause: '~> 2.4-rc.1': ~> requires a version without prerelease or build metadata"))
+		})
+	})
+})
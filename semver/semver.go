@@ -17,7 +17,9 @@ func NewSemverConverter(logger logger.Logger) *SemverConverter {
 }
 
 // ToValidSemver attempts to return the input as valid semver.
-// If the input fails to parse as semver, it appends .0 or .0.0 to the input and retries
+// If the input fails to parse as semver, it appends .0 or .0.0 to the
+// major.minor.patch part of the input, before any prerelease or build
+// metadata (e.g. 2.4-rc.1 becomes 2.4.0-rc.1), and retries.
 // If this is still not valid semver, it returns an error
 func (s SemverConverter) ToValidSemver(input string) (semver.Version, error) {
 	v, err := semver.Parse(input)
@@ -29,15 +31,7 @@ func (s SemverConverter) ToValidSemver(input string) (semver.Version, error) {
 		"failed to parse semver: '%s', appending zeros and trying again",
 		input,
 	))
-	maybeSemver := input
-
-	segs := strings.SplitN(maybeSemver, ".", 3)
-	switch len(segs) {
-	case 2:
-		maybeSemver += ".0"
-	case 1:
-		maybeSemver += ".0.0"
-	}
+	maybeSemver := padVersion(input)
 
 	v, err = semver.Parse(maybeSemver)
 	if err == nil {
@@ -51,3 +45,24 @@ func (s SemverConverter) ToValidSemver(input string) (semver.Version, error) {
 
 	return semver.Version{}, err
 }
+
+// padVersion appends .0 or .0.0 to the major.minor.patch part of the input,
+// before any prerelease or build metadata, if it has fewer than three parts.
+func padVersion(input string) string {
+	core := input
+	var suffix string
+	if i := strings.IndexAny(input, "-+"); i >= 0 {
+		core = input[:i]
+		suffix = input[i:]
+	}
+
+	segs := strings.SplitN(core, ".", 3)
+	switch len(segs) {
+	case 2:
+		core += ".0"
+	case 1:
+		core += ".0.0"
+	}
+
+	return core + suffix
+}
@@ -94,6 +94,70 @@ var _ = Describe("SemverConverter", func() {
 			})
 		})
 
+		Context("when the input has two components and a prerelease", func() {
+			BeforeEach(func() {
+				input = "2.4-rc.1"
+			})
+
+			It("adds zeros before the prerelease without error", func() {
+				returned, err := s.ToValidSemver(input)
+				Expect(err).NotTo(HaveOccurred())
+
+				expectedReturned := bsemver.Version{
+					Major: 2,
+					Minor: 4,
+					Patch: 0,
+					Pre: []bsemver.PRVersion{
+						{VersionStr: "rc"},
+						{VersionNum: 1, IsNum: true},
+					},
+				}
+				Expect(returned).To(Equal(expectedReturned))
+			})
+		})
+
+		Context("when the input has one component and build metadata", func() {
+			BeforeEach(func() {
+				input = "2+build.7"
+			})
+
+			It("adds zeros before the build metadata without error", func() {
+				returned, err := s.ToValidSemver(input)
+				Expect(err).NotTo(HaveOccurred())
+
+				expectedReturned := bsemver.Version{
+					Major: 2,
+					Minor: 0,
+					Patch: 0,
+					Build: []string{"build", "7"},
+				}
+				Expect(returned).To(Equal(expectedReturned))
+			})
+		})
+
+		It("orders prereleases before the release", func() {
+			var versions []bsemver.Version
+			for _, v := range []string{"2.4.0-rc.1", "2.4.0-rc.2", "2.4.0-rc.10", "2.4.0", "2.4.1-rc.1"} {
+				returned, err := s.ToValidSemver(v)
+				Expect(err).NotTo(HaveOccurred())
+				versions = append(versions, returned)
+			}
+
+			for i := 1; i < len(versions); i++ {
+				Expect(versions[i-1].LT(versions[i])).To(BeTrue())
+			}
+		})
+
+		It("ignores build metadata when ordering", func() {
+			withBuild, err := s.ToValidSemver("2.4.0+build.7")
+			Expect(err).NotTo(HaveOccurred())
+
+			withoutBuild, err := s.ToValidSemver("2.4.0")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(withBuild.Compare(withoutBuild)).To(Equal(0))
+		})
+
 		Context("when a version has more than 3 components", func() {
 			BeforeEach(func() {
 				input = "1.2.3.4"
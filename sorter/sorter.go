@@ -2,10 +2,12 @@ package sorter
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/blang/semver"
 	pivnet "github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
 )
 
 //go:generate counterfeiter --fake-name FakeSemverConverter . semverConverter
@@ -27,13 +29,15 @@ func NewSorter(logger logger.Logger, semverConverter semverConverter) *Sorter {
 
 // SortBySemver returns the provided releases, ordered by semantic versioning,
 // in descending order i.e. [4.2.3, 1.2.1, 1.2.0]
+// Releases with equal versions, e.g. the same version under different release
+// types, are ordered by release date and then by ID, both descending, so that
+// the order never depends on the order of the provided releases.
 // If a version cannot be parsed as semantic versioning, this is logged to stdout
 // and that release is not returned. No error is returned in this case.
 // Therefore the number of returned releases may be fewer than the number of
 // provided releases.
 func (s Sorter) SortBySemver(input []pivnet.Release) ([]pivnet.Release, error) {
-	var versions []semver.Version
-	versionsToReleases := make(map[string]pivnet.Release)
+	var parsed []semverRelease
 
 	for _, release := range input {
 		asSemver, err := s.semverConverter.ToValidSemver(release.Version)
@@ -45,31 +49,112 @@ func (s Sorter) SortBySemver(input []pivnet.Release) ([]pivnet.Release, error) {
 			continue
 		}
 
-		versionsToReleases[asSemver.String()] = release
-		versions = append(versions, asSemver)
+		parsed = append(parsed, semverRelease{
+			release: release,
+			version: asSemver,
+		})
 	}
 
-	semver.Sort(versions)
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return parsed[i].newerThan(parsed[j])
+	})
 
-	sortedStrings := toStrings(versions)
+	sortedReleases := make([]pivnet.Release, len(parsed))
+	for i, p := range parsed {
+		sortedReleases[i] = p.release
+	}
+
+	return sortedReleases, nil
+}
 
-	sortedReleases := make([]pivnet.Release, len(sortedStrings))
+// SortByReleaseDate returns the provided releases ordered by release date,
+// newest first, for products whose versions do not increase monotonically.
+// Releases with the same release date are ordered by ID, descending, so that
+// the order never depends on the order of the provided releases. Releases
+// without a release date are ordered last.
+func (s Sorter) SortByReleaseDate(input []pivnet.Release) ([]pivnet.Release, error) {
+	sorted := make([]pivnet.Release, len(input))
+	copy(sorted, input)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		// Release dates are formatted as YYYY-MM-DD so compare lexically.
+		if sorted[i].ReleaseDate != sorted[j].ReleaseDate {
+			return sorted[i].ReleaseDate > sorted[j].ReleaseDate
+		}
+
+		return sorted[i].ID > sorted[j].ID
+	})
+
+	return sorted, nil
+}
 
-	count := len(sortedStrings)
-	for i, v := range sortedStrings {
-		// perform reversal so we return descending, not ascending
-		sortedReleases[count-i-1] = versionsToReleases[v]
+// SampleBySemver returns, of the provided releases ordered newest first, only
+// the oldest release of each major.minor (concourse.SampleMinor) or
+// major.minor.patch (concourse.SamplePatch) version, keeping their order.
+// As the returned release of a version does not change when newer releases of
+// the same version are added, a new release is only returned once that part
+// of the version changes.
+// Releases whose versions cannot be parsed as semantic versioning are logged
+// and not returned, as for SortBySemver.
+func (s Sorter) SampleBySemver(input []pivnet.Release, sample concourse.Sample) ([]pivnet.Release, error) {
+	var key func(v semver.Version) string
+	switch sample {
+	case concourse.SampleMinor:
+		key = func(v semver.Version) string {
+			return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+		}
+	case concourse.SamplePatch:
+		key = func(v semver.Version) string {
+			return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+		}
+	default:
+		return nil, fmt.Errorf(
+			"sample must be one of: ['%s', '%s']",
+			concourse.SamplePatch,
+			concourse.SampleMinor,
+		)
 	}
 
-	return sortedReleases, nil
+	seen := map[string]bool{}
+	var sampled []pivnet.Release
+	for i := len(input) - 1; i >= 0; i-- {
+		release := input[i]
+
+		asSemver, err := s.semverConverter.ToValidSemver(release.Version)
+		if err != nil {
+			s.logger.Info(fmt.Sprintf(
+				"failed to parse release version as semver: '%s'",
+				release.Version,
+			))
+			continue
+		}
+
+		k := key(asSemver)
+		if seen[k] {
+			continue
+		}
+
+		seen[k] = true
+		sampled = append([]pivnet.Release{release}, sampled...)
+	}
+
+	return sampled, nil
 }
 
-func toStrings(input semver.Versions) []string {
-	strings := make([]string, len(input))
+type semverRelease struct {
+	release pivnet.Release
+	version semver.Version
+}
+
+func (r semverRelease) newerThan(other semverRelease) bool {
+	if c := r.version.Compare(other.version); c != 0 {
+		return c > 0
+	}
 
-	for i, v := range input {
-		strings[i] = v.String()
+	// Release dates are formatted as YYYY-MM-DD so compare lexically.
+	if r.release.ReleaseDate != other.release.ReleaseDate {
+		return r.release.ReleaseDate > other.release.ReleaseDate
 	}
 
-	return strings
+	return r.release.ID > other.release.ID
 }
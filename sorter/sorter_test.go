@@ -7,6 +7,7 @@ import (
 	bsemver "github.com/blang/semver"
 	"github.com/pivotal-cf/go-pivnet"
 	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
 	"github.com/pivotal-cf/pivnet-resource/sorter"
 	"github.com/pivotal-cf/pivnet-resource/sorter/sorterfakes"
 
@@ -62,6 +63,17 @@ var _ = Describe("Sorter", func() {
 				}, nil
 			case "2.4.1":
 				return bsemver.Version{Major: 2, Minor: 4, Patch: 1}, nil
+			case "2.4.0":
+				return bsemver.Version{Major: 2, Minor: 4}, nil
+			case "2.4.0-rc.1":
+				return bsemver.Version{
+					Major: 2,
+					Minor: 4,
+					Pre: []bsemver.PRVersion{
+						{VersionStr: "rc"},
+						{VersionNum: 1, IsNum: true},
+					},
+				}, nil
 			default:
 				panic(fmt.Sprintf("unrecognized input: %s", input))
 			}
@@ -83,6 +95,47 @@ var _ = Describe("Sorter", func() {
 				[]string{"2.4.1", "2.4.1-edge.12", "2.4.1-edge.11", "2.0.0", "1.0.0"}))
 		})
 
+		It("orders release candidates before the release they precede", func() {
+			input := releasesWithVersions(
+				"2.4.0", "2.4.0-rc.1", "2.0.0",
+			)
+
+			returned, err := s.SortBySemver(input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsFromReleases(returned)).To(Equal(
+				[]string{"2.4.0", "2.4.0-rc.1", "2.0.0"}))
+		})
+
+		Context("when releases have equal versions", func() {
+			var input []pivnet.Release
+
+			BeforeEach(func() {
+				input = []pivnet.Release{
+					{ID: 1, Version: "2.1.0", ReleaseType: "Minor Release", ReleaseDate: "2017-01-01"},
+					{ID: 2, Version: "2.1", ReleaseType: "Beta Release", ReleaseDate: "2017-02-01"},
+					{ID: 4, Version: "2.1.0", ReleaseType: "Alpha Release", ReleaseDate: "2017-01-01"},
+					{ID: 3, Version: "2.0.0", ReleaseType: "Minor Release", ReleaseDate: "2017-03-01"},
+				}
+			})
+
+			It("keeps all of them, ordered by release date and then ID", func() {
+				returned, err := s.SortBySemver(input)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(idsFromReleases(returned)).To(Equal([]int{2, 4, 1, 3}))
+			})
+
+			It("returns the same order regardless of the input order", func() {
+				reversed := []pivnet.Release{input[3], input[2], input[1], input[0]}
+
+				returned, err := s.SortBySemver(reversed)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(idsFromReleases(returned)).To(Equal([]int{2, 4, 1, 3}))
+			})
+		})
+
 		Context("when parsing a version as semver fails", func() {
 			It("ignores that value", func() {
 				input := releasesWithVersions(
@@ -111,6 +164,109 @@ var _ = Describe("Sorter", func() {
 			})
 		})
 	})
+
+	Describe("SortByReleaseDate", func() {
+		It("sorts by release date, newest first", func() {
+			input := []pivnet.Release{
+				{ID: 1, Version: "2.0.0", ReleaseDate: "2017-01-01"},
+				{ID: 2, Version: "1.9.0", ReleaseDate: "2017-03-01"},
+				{ID: 3, Version: "build-42", ReleaseDate: "2017-02-01"},
+			}
+
+			returned, err := s.SortByReleaseDate(input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(idsFromReleases(returned)).To(Equal([]int{2, 3, 1}))
+		})
+
+		It("orders releases with the same release date by ID and those without one last", func() {
+			input := []pivnet.Release{
+				{ID: 1, ReleaseDate: ""},
+				{ID: 2, ReleaseDate: "2017-01-01"},
+				{ID: 3, ReleaseDate: "2017-01-01"},
+			}
+
+			returned, err := s.SortByReleaseDate(input)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(idsFromReleases(returned)).To(Equal([]int{3, 2, 1}))
+		})
+	})
+
+	Describe("SampleBySemver", func() {
+		var input []pivnet.Release
+
+		BeforeEach(func() {
+			input = releasesWithVersions(
+				"2.4.1", "2.4.1-edge.12", "2.4.1-edge.11", "2.1.0", "2.0.0", "1.0.0",
+			)
+		})
+
+		It("keeps the oldest release of each minor version", func() {
+			returned, err := s.SampleBySemver(input, concourse.SampleMinor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsFromReleases(returned)).To(Equal(
+				[]string{"2.4.1-edge.11", "2.1.0", "2.0.0", "1.0.0"}))
+		})
+
+		It("keeps the oldest release of each patch version", func() {
+			returned, err := s.SampleBySemver(input, concourse.SamplePatch)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(versionsFromReleases(returned)).To(Equal(
+				[]string{"2.4.1-edge.11", "2.1.0", "2.0.0", "1.0.0"}))
+		})
+
+		It("does not change the sampled releases when newer releases are added", func() {
+			before, err := s.SampleBySemver(input[1:], concourse.SampleMinor)
+			Expect(err).NotTo(HaveOccurred())
+
+			after, err := s.SampleBySemver(input, concourse.SampleMinor)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(after).To(Equal(before))
+		})
+
+		Context("when releases differ only in their patch version", func() {
+			BeforeEach(func() {
+				input = releasesWithVersions("2.4.1", "2.4.0")
+			})
+
+			It("collapses them when sampling by minor", func() {
+				returned, err := s.SampleBySemver(input, concourse.SampleMinor)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(versionsFromReleases(returned)).To(Equal([]string{"2.4.0"}))
+			})
+
+			It("keeps both when sampling by patch", func() {
+				returned, err := s.SampleBySemver(input, concourse.SamplePatch)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(versionsFromReleases(returned)).To(Equal([]string{"2.4.1", "2.4.0"}))
+			})
+		})
+
+		Context("when parsing a version as semver fails", func() {
+			It("ignores that value", func() {
+				returned, err := s.SampleBySemver(
+					releasesWithVersions("2.4.1", "not-semver"),
+					concourse.SampleMinor,
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(versionsFromReleases(returned)).To(Equal([]string{"2.4.1"}))
+			})
+		})
+
+		Context("when the sample is not known", func() {
+			It("returns an error", func() {
+				_, err := s.SampleBySemver(input, concourse.Sample("major"))
+				Expect(err).To(MatchError("sample must be one of: ['patch', 'minor']"))
+			})
+		})
+	})
 })
 
 func releasesWithVersions(versions ...string) []pivnet.Release {
@@ -128,3 +284,11 @@ func versionsFromReleases(releases []pivnet.Release) []string {
 	}
 	return versions
 }
+
+func idsFromReleases(releases []pivnet.Release) []int {
+	var ids []int
+	for _, release := range releases {
+		ids = append(ids, release.ID)
+	}
+	return ids
+}
@@ -0,0 +1,204 @@
+package sparse
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/pivnet-resource/filenames"
+)
+
+//go:generate counterfeiter --fake-name FakeClient . client
+type client interface {
+	ProductFileDownloadURL(productSlug string, releaseID int, productFileID int) (string, error)
+}
+
+// Downloader downloads only part of each product file using HTTP range
+// requests: either the first headBytes bytes, or the members of a zip
+// archive matching zipMembers, located through its central directory.
+type Downloader struct {
+	client      client
+	httpClient  *http.Client
+	downloadDir string
+	logger      logger.Logger
+	headBytes   int64
+	zipMembers  []string
+}
+
+func NewDownloader(
+	client client,
+	httpClient *http.Client,
+	downloadDir string,
+	logger logger.Logger,
+	headBytes int64,
+	zipMembers []string,
+) *Downloader {
+	return &Downloader{
+		client:      client,
+		httpClient:  httpClient,
+		downloadDir: downloadDir,
+		logger:      logger,
+		headBytes:   headBytes,
+		zipMembers:  zipMembers,
+	}
+}
+
+// Download writes part of each of the product files to its local file name.
+// When zip members are configured the local file name is a directory
+// containing the matching members at their paths within the archive.
+// Otherwise it is a file containing the first headBytes bytes.
+//
+// As with a full download, a file which fails does not prevent the remaining
+// files from being downloaded; its error is returned keyed by product file ID.
+func (d Downloader) Download(
+	pfs []pivnet.ProductFile,
+	productSlug string,
+	releaseID int,
+) ([]string, map[int]error, error) {
+	err := os.MkdirAll(d.downloadDir, os.ModePerm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	localNames := filenames.ForProductFiles(pfs)
+
+	var fileNames []string
+	failures := map[int]error{}
+	for _, pf := range pfs {
+		downloadPath := filepath.Join(d.downloadDir, localNames[pf.ID])
+
+		err := d.downloadFile(pf, productSlug, releaseID, downloadPath)
+		if err != nil {
+			d.logger.Info(fmt.Sprintf("Sparse download of '%s' failed: %s", pf.Name, err.Error()))
+			failures[pf.ID] = err
+			os.RemoveAll(downloadPath)
+			continue
+		}
+
+		fileNames = append(fileNames, downloadPath)
+	}
+
+	return fileNames, failures, nil
+}
+
+func (d Downloader) downloadFile(
+	pf pivnet.ProductFile,
+	productSlug string,
+	releaseID int,
+	downloadPath string,
+) error {
+	r, err := newRangeReader(d.httpClient, func() (string, error) {
+		return d.client.ProductFileDownloadURL(productSlug, releaseID, pf.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(d.zipMembers) > 0 {
+		d.logger.Info(fmt.Sprintf(
+			"Extracting members of '%s' matching %s to directory: '%s'",
+			pf.Name,
+			strings.Join(d.zipMembers, ", "),
+			downloadPath,
+		))
+
+		return d.extractZipMembers(r, downloadPath)
+	}
+
+	d.logger.Info(fmt.Sprintf(
+		"Downloading first %d bytes of '%s' to file: '%s'",
+		d.headBytes,
+		pf.Name,
+		downloadPath,
+	))
+
+	return d.writeHead(r, downloadPath)
+}
+
+func (d Downloader) writeHead(r *rangeReader, downloadPath string) error {
+	file, err := os.Create(downloadPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	length := d.headBytes
+	if length > r.Size() {
+		length = r.Size()
+	}
+
+	_, err = io.Copy(file, io.NewSectionReader(r, 0, length))
+	return err
+}
+
+func (d Downloader) extractZipMembers(r *rangeReader, dir string) error {
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		return fmt.Errorf("failed to read zip central directory: %s", err.Error())
+	}
+
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !d.matchesMember(f.Name) {
+			continue
+		}
+
+		cleaned := path.Clean(f.Name)
+		if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return fmt.Errorf("zip member '%s' is outside of the archive", f.Name)
+		}
+
+		d.logger.Debug(fmt.Sprintf("Extracting zip member: '%s'", f.Name))
+
+		err = extractZipMember(f, filepath.Join(dir, filepath.FromSlash(cleaned)))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d Downloader) matchesMember(name string) bool {
+	for _, pattern := range d.zipMembers {
+		matched, err := path.Match(pattern, name)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func extractZipMember(f *zip.File, destination string) error {
+	err := os.MkdirAll(filepath.Dir(destination), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	file, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, rc)
+	return err
+}
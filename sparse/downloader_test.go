@@ -0,0 +1,304 @@
+package sparse_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/sparse"
+	"github.com/pivotal-cf/pivnet-resource/sparse/sparsefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Downloader", func() {
+	var (
+		fakeClient *sparsefakes.FakeClient
+		fakeLogger logger.Logger
+		server     *httptest.Server
+		dir        string
+
+		contentMutex sync.Mutex
+		content      []byte
+		rangeHeaders []string
+		ignoreRange  bool
+
+		headBytes  int64
+		zipMembers []string
+
+		productFiles []pivnet.ProductFile
+
+		d *sparse.Downloader
+	)
+
+	BeforeEach(func() {
+		fakeClient = &sparsefakes.FakeClient{}
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		fakeLogger = logshim.NewLogShim(logger, logger, true)
+
+		rangeHeaders = nil
+		ignoreRange = false
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentMutex.Lock()
+			defer contentMutex.Unlock()
+
+			rangeHeaders = append(rangeHeaders, r.Header.Get("Range"))
+
+			if r.URL.Path == "/expired" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			if ignoreRange {
+				w.Write(content)
+				return
+			}
+
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+		}))
+
+		fakeClient.ProductFileDownloadURLReturns(server.URL+"/signed", nil)
+
+		var err error
+		dir, err = ioutil.TempDir("", "pivnet-resource")
+		Expect(err).NotTo(HaveOccurred())
+
+		headBytes = 0
+		zipMembers = nil
+
+		productFiles = []pivnet.ProductFile{
+			{
+				ID:           1337,
+				Name:         "Some Tile",
+				AWSObjectKey: "product-files/some-tile.pivotal",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+
+		err := os.RemoveAll(dir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		d = sparse.NewDownloader(fakeClient, http.DefaultClient, dir, fakeLogger, headBytes, zipMembers)
+	})
+
+	Context("when head bytes are configured", func() {
+		BeforeEach(func() {
+			content = []byte("some-manifest-header and the rest of a large file")
+			headBytes = 20
+		})
+
+		It("writes only the first bytes of the file", func() {
+			files, failures, err := d.Download(productFiles, "some-product-slug", 1234)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(BeEmpty())
+
+			Expect(files).To(Equal([]string{filepath.Join(dir, "some-tile.pivotal")}))
+
+			b, err := ioutil.ReadFile(files[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal("some-manifest-header"))
+
+			for _, h := range rangeHeaders {
+				Expect(h).To(HavePrefix("bytes="))
+			}
+		})
+
+		It("gets the download URL of each product file", func() {
+			_, _, err := d.Download(productFiles, "some-product-slug", 1234)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.ProductFileDownloadURLCallCount()).To(Equal(1))
+			slug, releaseID, productFileID := fakeClient.ProductFileDownloadURLArgsForCall(0)
+			Expect(slug).To(Equal("some-product-slug"))
+			Expect(releaseID).To(Equal(1234))
+			Expect(productFileID).To(Equal(1337))
+		})
+
+		Context("when the file is shorter than the head bytes", func() {
+			BeforeEach(func() {
+				headBytes = 1024
+			})
+
+			It("writes the whole file", func() {
+				files, _, err := d.Download(productFiles, "some-product-slug", 1234)
+				Expect(err).NotTo(HaveOccurred())
+
+				b, err := ioutil.ReadFile(files[0])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(b).To(Equal(content))
+			})
+		})
+	})
+
+	Context("when zip members are configured", func() {
+		BeforeEach(func() {
+			buf := &bytes.Buffer{}
+			zw := zip.NewWriter(buf)
+
+			members := map[string]string{
+				"metadata/some-tile.yml": "name: some-tile",
+				"releases/huge.tgz":      strings.Repeat("x", 4*1024*1024),
+				"migrations/v1/one.js":   "migrate()",
+			}
+			for _, name := range []string{"metadata/some-tile.yml", "releases/huge.tgz", "migrations/v1/one.js"} {
+				w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = w.Write([]byte(members[name]))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(zw.Close()).To(Succeed())
+			content = buf.Bytes()
+
+			zipMembers = []string{"metadata/*.yml"}
+		})
+
+		It("extracts the matching members into a directory named after the file", func() {
+			files, failures, err := d.Download(productFiles, "some-product-slug", 1234)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(BeEmpty())
+
+			Expect(files).To(Equal([]string{filepath.Join(dir, "some-tile.pivotal")}))
+
+			b, err := ioutil.ReadFile(filepath.Join(dir, "some-tile.pivotal", "metadata", "some-tile.yml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal("name: some-tile"))
+
+			_, err = os.Stat(filepath.Join(dir, "some-tile.pivotal", "releases"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			_, err = os.Stat(filepath.Join(dir, "some-tile.pivotal", "migrations"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("does not read the members which do not match", func() {
+			_, _, err := d.Download(productFiles, "some-product-slug", 1234)
+			Expect(err).NotTo(HaveOccurred())
+
+			// The size probe, the central directory at the end of the
+			// archive and the start of the archive holding the matching
+			// member; the bulk of the 4 MiB member is never requested.
+			Expect(len(rangeHeaders)).To(BeNumerically("<=", 3))
+			Expect(rangeHeaders[0]).To(Equal("bytes=0-0"))
+		})
+
+		Context("when the file is not a zip archive", func() {
+			BeforeEach(func() {
+				content = []byte("not a zip archive")
+			})
+
+			It("returns the failure keyed by product file ID", func() {
+				files, failures, err := d.Download(productFiles, "some-product-slug", 1234)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(files).To(BeEmpty())
+				Expect(failures).To(HaveKey(1337))
+				Expect(failures[1337].Error()).To(ContainSubstring("zip central directory"))
+
+				_, err = os.Stat(filepath.Join(dir, "some-tile.pivotal"))
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("when the download URL expires soon", func() {
+		BeforeEach(func() {
+			content = []byte("some-manifest-header and the rest of a large file")
+			headBytes = 20
+
+			expires := time.Now().Add(10 * time.Second).Unix()
+			fakeClient.ProductFileDownloadURLReturns(fmt.Sprintf("%s/signed?Expires=%d", server.URL, expires), nil)
+		})
+
+		It("gets the download URL again before each request", func() {
+			files, failures, err := d.Download(productFiles, "some-product-slug", 1234)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(BeEmpty())
+
+			b, err := ioutil.ReadFile(files[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal("some-manifest-header"))
+
+			Expect(fakeClient.ProductFileDownloadURLCallCount()).To(Equal(len(rangeHeaders)))
+		})
+	})
+
+	Context("when the download URL is rejected", func() {
+		BeforeEach(func() {
+			content = []byte("some-manifest-header and the rest of a large file")
+			headBytes = 20
+
+			calls := 0
+			fakeClient.ProductFileDownloadURLStub = func(string, int, int) (string, error) {
+				calls++
+				if calls == 1 {
+					return server.URL + "/expired", nil
+				}
+				return server.URL + "/signed", nil
+			}
+		})
+
+		It("gets the download URL again and retries the request", func() {
+			files, failures, err := d.Download(productFiles, "some-product-slug", 1234)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(BeEmpty())
+
+			b, err := ioutil.ReadFile(files[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal("some-manifest-header"))
+
+			Expect(fakeClient.ProductFileDownloadURLCallCount()).To(Equal(2))
+		})
+	})
+
+	Context("when getting the download URL returns an error", func() {
+		BeforeEach(func() {
+			headBytes = 10
+			fakeClient.ProductFileDownloadURLReturns("", errors.New("download url error"))
+		})
+
+		It("returns the failure keyed by product file ID", func() {
+			files, failures, err := d.Download(productFiles, "some-product-slug", 1234)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(files).To(BeEmpty())
+			Expect(failures).To(Equal(map[int]error{1337: errors.New("download url error")}))
+		})
+	})
+
+	Context("when the server does not support range requests", func() {
+		BeforeEach(func() {
+			content = []byte("the whole file")
+			headBytes = 10
+			ignoreRange = true
+		})
+
+		It("returns the failure keyed by product file ID", func() {
+			_, failures, err := d.Download(productFiles, "some-product-slug", 1234)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(failures).To(HaveKey(1337))
+			Expect(failures[1337].Error()).To(ContainSubstring("status code 200"))
+		})
+	})
+})
@@ -0,0 +1,234 @@
+package sparse
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// minReadSize is the smallest range requested at a time. Reads are small
+	// and mostly sequential when walking a zip central directory or
+	// decompressing a member, so reading ahead avoids a request per read.
+	minReadSize = 1024 * 1024
+
+	// urlExpiryMargin is how long before its expiry a signed URL is resolved
+	// again, so that a range request is never made with an expired URL.
+	urlExpiryMargin = time.Minute
+)
+
+// rangeReader reads a remote file with HTTP range requests. It buffers the
+// most recently requested range and is not safe for concurrent use.
+//
+// The signed URL of the file is resolved when it is first needed, and again
+// when it nears its expiry or is rejected, as reading the members of a large
+// archive can take longer than the URL is valid for.
+type rangeReader struct {
+	httpClient *http.Client
+	resolveURL func() (string, error)
+	now        func() time.Time
+	size       int64
+
+	url       string
+	expiresAt time.Time
+
+	bufferOffset int64
+	buffer       []byte
+}
+
+// newRangeReader determines the size of the remote file with a single byte
+// range request.
+func newRangeReader(httpClient *http.Client, resolveURL func() (string, error)) (*rangeReader, error) {
+	r := &rangeReader{
+		httpClient: httpClient,
+		resolveURL: resolveURL,
+		now:        time.Now,
+	}
+
+	resp, err := r.get(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	contentRange := resp.Header.Get("Content-Range")
+	i := strings.LastIndex(contentRange, "/")
+	if i == -1 {
+		return nil, fmt.Errorf("invalid Content-Range: '%s'", contentRange)
+	}
+
+	r.size, err = strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Range: '%s'", contentRange)
+	}
+
+	return r, nil
+}
+
+func (r *rangeReader) Size() int64 {
+	return r.size
+}
+
+func (r *rangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	end := off + int64(len(p))
+	if end > r.size {
+		end = r.size
+	}
+
+	if off < r.bufferOffset || end > r.bufferOffset+int64(len(r.buffer)) {
+		err := r.fill(off, int64(len(p)))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buffer[off-r.bufferOffset:end-r.bufferOffset])
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// fill replaces the buffer with the range starting at off of at least
+// minReadSize bytes, or the remainder of the file if that is shorter.
+func (r *rangeReader) fill(off int64, length int64) error {
+	if length < minReadSize {
+		length = minReadSize
+	}
+
+	end := off + length
+	if end > r.size {
+		end = r.size
+	}
+
+	resp, err := r.get(off, end-1)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	buffer := make([]byte, end-off)
+	_, err = io.ReadFull(resp.Body, buffer)
+	if err != nil {
+		return err
+	}
+
+	r.bufferOffset = off
+	r.buffer = buffer
+
+	return nil
+}
+
+func (r *rangeReader) get(first int64, last int64) (*http.Response, error) {
+	resp, err := r.request(first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signed URL may be rejected before its expiry is reached, e.g. due
+	// to clock skew, or have no known expiry, so it is resolved again and the
+	// request retried once.
+	if resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		r.url = ""
+
+		resp, err = r.request(first, last)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return nil, fmt.Errorf(
+			"range request for bytes %d-%d returned status code %d, expected %d",
+			first,
+			last,
+			resp.StatusCode,
+			http.StatusPartialContent,
+		)
+	}
+
+	return resp, nil
+}
+
+func (r *rangeReader) request(first int64, last int64) (*http.Response, error) {
+	signedURL, err := r.signedURL()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", signedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", first, last))
+
+	return r.httpClient.Do(req)
+}
+
+// signedURL returns the signed URL of the file, resolving it if it has not
+// been resolved yet or expires within urlExpiryMargin.
+func (r *rangeReader) signedURL() (string, error) {
+	if r.url != "" && (r.expiresAt.IsZero() || r.now().Add(urlExpiryMargin).Before(r.expiresAt)) {
+		return r.url, nil
+	}
+
+	signedURL, err := r.resolveURL()
+	if err != nil {
+		return "", err
+	}
+
+	r.url = signedURL
+	r.expiresAt = urlExpiry(signedURL)
+
+	return signedURL, nil
+}
+
+// urlExpiry returns the time at which the signed URL expires, from the
+// X-Amz-Date and X-Amz-Expires parameters of an AWS Signature Version 4 URL
+// or the Expires parameter of a Version 2 or CloudFront URL. It returns the
+// zero time if the expiry is not known.
+func urlExpiry(signedURL string) time.Time {
+	u, err := url.Parse(signedURL)
+	if err != nil {
+		return time.Time{}
+	}
+
+	query := u.Query()
+
+	if date, expires := query.Get("X-Amz-Date"), query.Get("X-Amz-Expires"); date != "" && expires != "" {
+		signedAt, err := time.Parse("20060102T150405Z", date)
+		if err != nil {
+			return time.Time{}
+		}
+
+		seconds, err := strconv.Atoi(expires)
+		if err != nil {
+			return time.Time{}
+		}
+
+		return signedAt.Add(time.Duration(seconds) * time.Second)
+	}
+
+	if expires := query.Get("Expires"); expires != "" {
+		unix, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return time.Time{}
+		}
+
+		return time.Unix(unix, 0)
+	}
+
+	return time.Time{}
+}
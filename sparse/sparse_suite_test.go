@@ -0,0 +1,13 @@
+package sparse_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSparse(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Sparse Suite")
+}
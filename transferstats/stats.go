@@ -0,0 +1,71 @@
+package transferstats
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+)
+
+// Stats records the uploads of a put, so that its performance can be
+// compared across releases from the metadata of the put.
+type Stats struct {
+	mu *sync.Mutex
+
+	files    int
+	bytes    int64
+	retries  int
+	duration time.Duration
+}
+
+func NewStats() *Stats {
+	return &Stats{
+		mu: &sync.Mutex{},
+	}
+}
+
+// RecordUpload records a successful upload of a file of size bytes, which
+// took duration including any retries.
+func (s *Stats) RecordUpload(bytes int64, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files++
+	s.bytes += bytes
+	s.duration += duration
+}
+
+// RecordRetry records a retry of a failed upload.
+func (s *Stats) RecordRetry() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.retries++
+}
+
+// Metadata returns the recorded statistics, along with the wall time of the
+// whole put. The throughput is of the uploads alone, so it is not affected by
+// the time spent creating the release or waiting for ingestion.
+func (s *Stats) Metadata(wallTime time.Duration) []concourse.Metadata {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return []concourse.Metadata{
+		{Name: "publish_duration", Value: wallTime.Round(time.Second).String()},
+		{Name: "publish_files", Value: strconv.Itoa(s.files)},
+		{Name: "publish_bytes", Value: strconv.FormatInt(s.bytes, 10)},
+		{Name: "publish_retries", Value: strconv.Itoa(s.retries)},
+		{Name: "publish_throughput", Value: throughput(s.bytes, s.duration)},
+	}
+}
+
+func throughput(bytes int64, duration time.Duration) string {
+	if bytes == 0 || duration <= 0 {
+		return "0.00 MiB/s"
+	}
+
+	mebibytesPerSecond := float64(bytes) / (1 << 20) / duration.Seconds()
+	return fmt.Sprintf("%.2f MiB/s", mebibytesPerSecond)
+}
@@ -0,0 +1,70 @@
+package transferstats_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/transferstats"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats", func() {
+	var (
+		stats *transferstats.Stats
+	)
+
+	BeforeEach(func() {
+		stats = transferstats.NewStats()
+	})
+
+	It("returns the recorded uploads and retries as metadata", func() {
+		stats.RecordUpload(3<<20, 2*time.Second)
+		stats.RecordUpload(1<<20, 2*time.Second)
+		stats.RecordRetry()
+
+		Expect(stats.Metadata(90 * time.Second)).To(Equal([]concourse.Metadata{
+			{Name: "publish_duration", Value: "1m30s"},
+			{Name: "publish_files", Value: "2"},
+			{Name: "publish_bytes", Value: "4194304"},
+			{Name: "publish_retries", Value: "1"},
+			{Name: "publish_throughput", Value: "1.00 MiB/s"},
+		}))
+	})
+
+	It("rounds the wall time to the second", func() {
+		metadata := stats.Metadata(1500 * time.Millisecond)
+		Expect(metadata[0]).To(Equal(concourse.Metadata{Name: "publish_duration", Value: "2s"}))
+	})
+
+	Context("when nothing was uploaded", func() {
+		It("returns zero stats", func() {
+			Expect(stats.Metadata(time.Second)).To(Equal([]concourse.Metadata{
+				{Name: "publish_duration", Value: "1s"},
+				{Name: "publish_files", Value: "0"},
+				{Name: "publish_bytes", Value: "0"},
+				{Name: "publish_retries", Value: "0"},
+				{Name: "publish_throughput", Value: "0.00 MiB/s"},
+			}))
+		})
+	})
+
+	It("records uploads concurrently", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stats.RecordUpload(1, time.Millisecond)
+				stats.RecordRetry()
+			}()
+		}
+		wg.Wait()
+
+		metadata := stats.Metadata(time.Second)
+		Expect(metadata).To(ContainElement(concourse.Metadata{Name: "publish_files", Value: "100"}))
+		Expect(metadata).To(ContainElement(concourse.Metadata{Name: "publish_retries", Value: "100"}))
+	})
+})
@@ -0,0 +1,13 @@
+package transferstats_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTransferStats(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "TransferStats Suite")
+}
@@ -1,5 +1,10 @@
 package uploader
 
+import (
+	"fmt"
+	"strings"
+)
+
 //go:generate counterfeiter --fake-name FakeS3PrefixFetcher . S3PrefixFetcher
 type S3PrefixFetcher interface {
 	S3PrefixForProductSlug(productSlug string) (string, error)
@@ -12,11 +17,27 @@ type PrefixFetcher struct {
 
 func NewPrefixFetcher(fetcher S3PrefixFetcher, productSlug string) PrefixFetcher {
 	return PrefixFetcher{
-		productSlug: productSlug,
+		productSlug:     productSlug,
 		s3PrefixFetcher: fetcher,
 	}
 }
 
+// GetPrefix returns the S3 directory configured for the product on Pivotal
+// Network, under which its files must be uploaded to be ingested. A product
+// without one cannot be uploaded to, so is an error rather than uploading to
+// the root of the bucket.
 func (pf *PrefixFetcher) GetPrefix() (string, error) {
-	return pf.s3PrefixFetcher.S3PrefixForProductSlug(pf.productSlug)
+	prefix, err := pf.s3PrefixFetcher.S3PrefixForProductSlug(pf.productSlug)
+	if err != nil {
+		return "", fmt.Errorf("could not find the S3 directory of product '%s': %s", pf.productSlug, err)
+	}
+
+	if strings.Trim(prefix, "/") == "" {
+		return "", fmt.Errorf(
+			"product '%s' has no S3 directory on Pivotal Network - it must be configured for the product before files can be uploaded",
+			pf.productSlug,
+		)
+	}
+
+	return prefix, nil
 }
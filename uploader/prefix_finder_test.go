@@ -1,6 +1,8 @@
 package uploader_test
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/pivotal-cf/pivnet-resource/uploader"
@@ -9,17 +11,43 @@ import (
 
 var _ = Describe("PrefixFetcher", func() {
 	Context("GetPrefix", func() {
+		var (
+			fakeS3PrefixFetcher *uploaderfakes.FakeS3PrefixFetcher
+			prefixFetcher       PrefixFetcher
+		)
+
+		BeforeEach(func() {
+			fakeS3PrefixFetcher = &uploaderfakes.FakeS3PrefixFetcher{}
+			prefixFetcher = NewPrefixFetcher(fakeS3PrefixFetcher, "product-slug")
+		})
+
 		It("returns the product file prefix", func() {
-			productSlug := "product-slug"
 			productPrefix := "/my-product/file-prefix"
-			fakeS3PrefixFetcher := &uploaderfakes.FakeS3PrefixFetcher{}
 			fakeS3PrefixFetcher.S3PrefixForProductSlugReturns(productPrefix, nil)
 
-			prefixFetcher := NewPrefixFetcher(fakeS3PrefixFetcher, productSlug)
 			prefix, err := prefixFetcher.GetPrefix()
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(prefix).To(Equal(productPrefix))
+			Expect(fakeS3PrefixFetcher.S3PrefixForProductSlugArgsForCall(0)).To(Equal("product-slug"))
+		})
+
+		Context("when the product has no S3 directory", func() {
+			It("returns an error", func() {
+				fakeS3PrefixFetcher.S3PrefixForProductSlugReturns("/", nil)
+
+				_, err := prefixFetcher.GetPrefix()
+				Expect(err).To(MatchError(ContainSubstring("product 'product-slug' has no S3 directory on Pivotal Network")))
+			})
+		})
+
+		Context("when the product cannot be found", func() {
+			It("returns an error", func() {
+				fakeS3PrefixFetcher.S3PrefixForProductSlugReturns("", errors.New("some product error"))
+
+				_, err := prefixFetcher.GetPrefix()
+				Expect(err).To(MatchError("could not find the S3 directory of product 'product-slug': some product error"))
+			})
 		})
 	})
-})
\ No newline at end of file
+})
@@ -0,0 +1,68 @@
+package uploader
+
+import (
+	"fmt"
+
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+)
+
+// Router uploads the files of file groups routed to an S3 target with the
+// client for that target, and every other file with the default client.
+type Router struct {
+	defaultClient *Client
+	targetClients map[string]*Client
+	metadata      metadata.Metadata
+}
+
+func NewRouter(
+	defaultClient *Client,
+	targetClients map[string]*Client,
+	metadata metadata.Metadata,
+) *Router {
+	return &Router{
+		defaultClient: defaultClient,
+		targetClients: targetClients,
+		metadata:      metadata,
+	}
+}
+
+func (r Router) UploadFile(exactGlob string) error {
+	client, err := r.clientFor(exactGlob)
+	if err != nil {
+		return err
+	}
+
+	return client.UploadFile(exactGlob)
+}
+
+func (r Router) DeleteFile(exactGlob string) error {
+	client, err := r.clientFor(exactGlob)
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteFile(exactGlob)
+}
+
+func (r Router) ComputeAWSObjectKey(exactGlob string) (string, string, error) {
+	client, err := r.clientFor(exactGlob)
+	if err != nil {
+		return "", "", err
+	}
+
+	return client.ComputeAWSObjectKey(exactGlob)
+}
+
+func (r Router) clientFor(exactGlob string) (*Client, error) {
+	target := r.metadata.S3TargetForFile(exactGlob)
+	if target == "" {
+		return r.defaultClient, nil
+	}
+
+	client, ok := r.targetClients[target]
+	if !ok {
+		return nil, fmt.Errorf("s3 target '%s' for file '%s' is not configured in source.s3_targets", target, exactGlob)
+	}
+
+	return client, nil
+}
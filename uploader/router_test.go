@@ -0,0 +1,95 @@
+package uploader_test
+
+import (
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/uploader"
+	"github.com/pivotal-cf/pivnet-resource/uploader/uploaderfakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Router", func() {
+	var (
+		fakeDefaultTransport *uploaderfakes.FakeTransport
+		fakeDocsTransport    *uploaderfakes.FakeTransport
+
+		targetClients map[string]*uploader.Client
+		mdata         metadata.Metadata
+
+		router *uploader.Router
+	)
+
+	BeforeEach(func() {
+		fakeDefaultTransport = &uploaderfakes.FakeTransport{}
+		fakeDocsTransport = &uploaderfakes.FakeTransport{}
+
+		targetClients = map[string]*uploader.Client{
+			"docs": uploader.NewClient(uploader.Config{
+				FilepathPrefix: "docs/my-product-slug",
+				SourcesDir:     "my/temp/dir",
+				Transport:      fakeDocsTransport,
+			}),
+		}
+
+		mdata = metadata.Metadata{
+			ProductFiles: []metadata.ProductFile{
+				{File: "some-docs.pdf", FileGroup: "Documentation"},
+			},
+			FileGroups: []metadata.FileGroup{
+				{Name: "Documentation", S3Target: "docs"},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		defaultClient := uploader.NewClient(uploader.Config{
+			FilepathPrefix: "product-files/my-product-slug",
+			SourcesDir:     "my/temp/dir",
+			Transport:      fakeDefaultTransport,
+		})
+
+		router = uploader.NewRouter(defaultClient, targetClients, mdata)
+	})
+
+	It("uploads files routed to an S3 target with the client for the target", func() {
+		err := router.UploadFile("some-docs.pdf")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeDefaultTransport.UploadCallCount()).To(Equal(0))
+		Expect(fakeDocsTransport.UploadCallCount()).To(Equal(1))
+
+		_, remoteDir, _ := fakeDocsTransport.UploadArgsForCall(0)
+		Expect(remoteDir).To(Equal("docs/my-product-slug/"))
+
+		awsObjectKey, _, err := router.ComputeAWSObjectKey("some-docs.pdf")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(awsObjectKey).To(Equal("docs/my-product-slug/some-docs.pdf"))
+	})
+
+	It("uploads other files with the default client", func() {
+		err := router.UploadFile("some-binary.tgz")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeDefaultTransport.UploadCallCount()).To(Equal(1))
+		Expect(fakeDocsTransport.UploadCallCount()).To(Equal(0))
+
+		awsObjectKey, _, err := router.ComputeAWSObjectKey("some-binary.tgz")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(awsObjectKey).To(Equal("product-files/my-product-slug/some-binary.tgz"))
+	})
+
+	Context("when the S3 target is not configured", func() {
+		BeforeEach(func() {
+			targetClients = map[string]*uploader.Client{}
+		})
+
+		It("returns an error", func() {
+			err := router.UploadFile("some-docs.pdf")
+			Expect(err).To(MatchError("s3 target 'docs' for file 'some-docs.pdf' is not configured in source.s3_targets"))
+
+			_, _, err = router.ComputeAWSObjectKey("some-docs.pdf")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -9,6 +9,7 @@ import (
 //go:generate counterfeiter --fake-name FakeTransport . transport
 type transport interface {
 	Upload(fileGlob string, filepathPrefix string, sourcesDir string) error
+	Delete(remotePath string) error
 }
 
 type Client struct {
@@ -53,6 +54,16 @@ func (c Client) UploadFile(exactGlob string) (error) {
 	return nil
 }
 
+// DeleteFile removes the uploaded file from the bucket.
+func (c Client) DeleteFile(exactGlob string) error {
+	remotePath, _, err := c.ComputeAWSObjectKey(exactGlob)
+	if err != nil {
+		return err
+	}
+
+	return c.transport.Delete(remotePath)
+}
+
 func (c Client) ComputeAWSObjectKey(exactGlob string) (string, string, error) {
 	if exactGlob == "" {
 		return "", "", fmt.Errorf("glob must not be empty")
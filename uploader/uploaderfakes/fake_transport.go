@@ -14,6 +14,14 @@ type FakeTransport struct {
 	uploadReturns struct {
 		result1 error
 	}
+	DeleteStub        func(remotePath string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		remotePath string
+	}
+	deleteReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -53,11 +61,46 @@ func (fake *FakeTransport) UploadReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeTransport) Delete(remotePath string) error {
+	fake.deleteMutex.Lock()
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		remotePath string
+	}{remotePath})
+	fake.recordInvocation("Delete", []interface{}{remotePath})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(remotePath)
+	} else {
+		return fake.deleteReturns.result1
+	}
+}
+
+func (fake *FakeTransport) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeTransport) DeleteArgsForCall(i int) string {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.deleteArgsForCall[i].remotePath
+}
+
+func (fake *FakeTransport) DeleteReturns(result1 error) {
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeTransport) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.uploadMutex.RLock()
 	defer fake.uploadMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
 	return fake.invocations
 }
 
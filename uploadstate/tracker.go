@@ -0,0 +1,160 @@
+package uploadstate
+
+import (
+	"encoding/json"
+	"fmt"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logger"
+)
+
+// FileName is the name of the manifest object in the store.
+const FileName = "uploaded_files.json"
+
+//go:generate counterfeiter --fake-name FakeStore . store
+type store interface {
+	Read(remotePath string) ([]byte, bool, error)
+	Write(remotePath string, content []byte) error
+	Delete(remotePath string) error
+}
+
+// Manifest is the content of the manifest object. It records the release
+// created by a put and each file which has been uploaded and attached to it.
+type Manifest struct {
+	Version   string         `json:"version"`
+	ReleaseID int            `json:"release_id"`
+	Files     []UploadedFile `json:"files"`
+}
+
+type UploadedFile struct {
+	File          string `json:"file"`
+	SHA256        string `json:"sha256"`
+	ProductFileID int    `json:"product_file_id"`
+}
+
+// Tracker records the progress of publishing a release in a manifest, so
+// that a put which is interrupted, e.g. by the eviction of its worker, can be
+// resumed by a later put without uploading the same files again.
+type Tracker struct {
+	store    store
+	key      string
+	logger   logger.Logger
+	manifest *Manifest
+}
+
+type Config struct {
+	Store store
+	// Key is the path of the manifest object in the store.
+	Key    string
+	Logger logger.Logger
+}
+
+func NewTracker(config Config) *Tracker {
+	return &Tracker{
+		store:    config.Store,
+		key:      config.Key,
+		logger:   config.Logger,
+		manifest: &Manifest{},
+	}
+}
+
+// Resume reads the manifest written by a previous put of the version and
+// returns the ID of the release it created. It returns false if there is no
+// manifest, or if it is of a different version.
+func (t *Tracker) Resume(version string) (int, bool, error) {
+	content, found, err := t.store.Read(t.key)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !found {
+		t.logger.Info(fmt.Sprintf("No upload state found at: '%s' - nothing to resume", t.key))
+		return 0, false, nil
+	}
+
+	var manifest Manifest
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		return 0, false, fmt.Errorf(
+			"upload state '%s' could not be parsed - delete it to continue: %s",
+			t.key,
+			err.Error(),
+		)
+	}
+
+	if manifest.Version != version {
+		t.logger.Info(fmt.Sprintf(
+			"Upload state at: '%s' is of version: '%s', not: '%s' - nothing to resume",
+			t.key,
+			manifest.Version,
+			version,
+		))
+		return 0, false, nil
+	}
+
+	t.logger.Info(fmt.Sprintf(
+		"Resuming release: '%s' (ID: %d) with %d files already uploaded",
+		manifest.Version,
+		manifest.ReleaseID,
+		len(manifest.Files),
+	))
+
+	*t.manifest = manifest
+
+	return manifest.ReleaseID, true, nil
+}
+
+// Start begins a new manifest for the release, replacing that of any
+// previous put.
+func (t *Tracker) Start(release pivnet.Release) error {
+	*t.manifest = Manifest{
+		Version:   release.Version,
+		ReleaseID: release.ID,
+	}
+
+	return t.write()
+}
+
+// Uploaded returns the ID of the product file to which the file with the
+// provided SHA256 was uploaded, or false if it has not been uploaded and
+// attached to the release. A file whose content has changed since it was
+// uploaded is not considered uploaded.
+func (t *Tracker) Uploaded(file string, sha256 string) (int, bool) {
+	for _, f := range t.manifest.Files {
+		if f.File == file && f.SHA256 == sha256 {
+			return f.ProductFileID, true
+		}
+	}
+
+	return 0, false
+}
+
+// RecordUploaded adds the file to the manifest once it has been uploaded and
+// attached to the release.
+func (t *Tracker) RecordUploaded(file string, sha256 string, productFileID int) error {
+	t.manifest.Files = append(t.manifest.Files, UploadedFile{
+		File:          file,
+		SHA256:        sha256,
+		ProductFileID: productFileID,
+	})
+
+	return t.write()
+}
+
+// Finish deletes the manifest once the release has been published, as there
+// is nothing left to resume.
+func (t *Tracker) Finish() error {
+	t.logger.Info(fmt.Sprintf("Deleting upload state: '%s'", t.key))
+
+	return t.store.Delete(t.key)
+}
+
+func (t *Tracker) write() error {
+	content, err := json.Marshal(t.manifest)
+	if err != nil {
+		// Untested as it is too hard to force json.Marshal to return an error
+		return err
+	}
+
+	return t.store.Write(t.key, content)
+}
@@ -0,0 +1,203 @@
+package uploadstate_test
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	pivnet "github.com/pivotal-cf/go-pivnet"
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/uploadstate"
+	"github.com/pivotal-cf/pivnet-resource/uploadstate/uploadstatefakes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracker", func() {
+	const key = "product-files/some-product/publish-state/1.2.3/uploaded_files.json"
+
+	var (
+		fakeStore *uploadstatefakes.FakeStore
+		objects   map[string][]byte
+
+		tracker *uploadstate.Tracker
+	)
+
+	written := func() uploadstate.Manifest {
+		var manifest uploadstate.Manifest
+		err := json.Unmarshal(objects[key], &manifest)
+		Expect(err).NotTo(HaveOccurred())
+		return manifest
+	}
+
+	BeforeEach(func() {
+		fakeStore = &uploadstatefakes.FakeStore{}
+
+		objects = map[string][]byte{}
+		fakeStore.ReadStub = func(remotePath string) ([]byte, bool, error) {
+			content, found := objects[remotePath]
+			return content, found, nil
+		}
+		fakeStore.WriteStub = func(remotePath string, content []byte) error {
+			objects[remotePath] = content
+			return nil
+		}
+
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+
+		tracker = uploadstate.NewTracker(uploadstate.Config{
+			Store:  fakeStore,
+			Key:    key,
+			Logger: logshim.NewLogShim(logger, logger, true),
+		})
+	})
+
+	Describe("Start", func() {
+		It("writes a manifest of the release without any files", func() {
+			err := tracker.Start(pivnet.Release{ID: 1234, Version: "1.2.3"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(written()).To(Equal(uploadstate.Manifest{
+				Version:   "1.2.3",
+				ReleaseID: 1234,
+			}))
+		})
+	})
+
+	Describe("RecordUploaded", func() {
+		BeforeEach(func() {
+			err := tracker.Start(pivnet.Release{ID: 1234, Version: "1.2.3"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("adds each file to the manifest", func() {
+			err := tracker.RecordUploaded("some/file", "some-sha256", 11)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = tracker.RecordUploaded("some/other-file", "some-other-sha256", 22)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(written().Files).To(Equal([]uploadstate.UploadedFile{
+				{File: "some/file", SHA256: "some-sha256", ProductFileID: 11},
+				{File: "some/other-file", SHA256: "some-other-sha256", ProductFileID: 22},
+			}))
+
+			productFileID, uploaded := tracker.Uploaded("some/file", "some-sha256")
+			Expect(uploaded).To(BeTrue())
+			Expect(productFileID).To(Equal(11))
+		})
+
+		Context("when writing the manifest returns an error", func() {
+			BeforeEach(func() {
+				fakeStore.WriteStub = nil
+				fakeStore.WriteReturns(errors.New("some write error"))
+			})
+
+			It("returns the error", func() {
+				err := tracker.RecordUploaded("some/file", "some-sha256", 11)
+				Expect(err).To(MatchError("some write error"))
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		BeforeEach(func() {
+			content, err := json.Marshal(uploadstate.Manifest{
+				Version:   "1.2.3",
+				ReleaseID: 1234,
+				Files: []uploadstate.UploadedFile{
+					{File: "some/file", SHA256: "some-sha256", ProductFileID: 11},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			objects[key] = content
+		})
+
+		It("returns the release of the previous put and its uploaded files", func() {
+			releaseID, found, err := tracker.Resume("1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(releaseID).To(Equal(1234))
+
+			productFileID, uploaded := tracker.Uploaded("some/file", "some-sha256")
+			Expect(uploaded).To(BeTrue())
+			Expect(productFileID).To(Equal(11))
+		})
+
+		It("does not consider files whose content has changed uploaded", func() {
+			_, _, err := tracker.Resume("1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, uploaded := tracker.Uploaded("some/file", "some-changed-sha256")
+			Expect(uploaded).To(BeFalse())
+		})
+
+		It("keeps the files of the previous put when recording more", func() {
+			_, _, err := tracker.Resume("1.2.3")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = tracker.RecordUploaded("some/other-file", "some-other-sha256", 22)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(written().Files).To(HaveLen(2))
+		})
+
+		Context("when the manifest is of a different version", func() {
+			It("returns false", func() {
+				_, found, err := tracker.Resume("2.0.0")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+
+				_, uploaded := tracker.Uploaded("some/file", "some-sha256")
+				Expect(uploaded).To(BeFalse())
+			})
+		})
+
+		Context("when there is no manifest", func() {
+			BeforeEach(func() {
+				delete(objects, key)
+			})
+
+			It("returns false", func() {
+				_, found, err := tracker.Resume("1.2.3")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("when the manifest cannot be parsed", func() {
+			BeforeEach(func() {
+				objects[key] = []byte("not json")
+			})
+
+			It("returns an error", func() {
+				_, _, err := tracker.Resume("1.2.3")
+				Expect(err).To(MatchError(ContainSubstring("upload state '" + key + "' could not be parsed - delete it to continue")))
+			})
+		})
+
+		Context("when reading the manifest returns an error", func() {
+			BeforeEach(func() {
+				fakeStore.ReadStub = nil
+				fakeStore.ReadReturns(nil, false, errors.New("some read error"))
+			})
+
+			It("returns the error", func() {
+				_, _, err := tracker.Resume("1.2.3")
+				Expect(err).To(MatchError("some read error"))
+			})
+		})
+	})
+
+	Describe("Finish", func() {
+		It("deletes the manifest", func() {
+			err := tracker.Finish()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.DeleteCallCount()).To(Equal(1))
+			Expect(fakeStore.DeleteArgsForCall(0)).To(Equal(key))
+		})
+	})
+})
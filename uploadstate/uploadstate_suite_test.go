@@ -0,0 +1,13 @@
+package uploadstate_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestUploadState(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "UploadState Suite")
+}
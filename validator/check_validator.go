@@ -17,12 +17,92 @@ func NewCheckValidator(input concourse.CheckRequest) *CheckValidator {
 }
 
 func (v CheckValidator) Validate() error {
-	if v.input.Source.APIToken == "" {
+	if v.input.Source.APIToken == "" && v.input.Source.LocalSource == "" {
 		return fmt.Errorf("%s must be provided", "api_token")
 	}
 
 	if v.input.Source.ProductSlug == "" {
 		return fmt.Errorf("%s must be provided", "product_slug")
 	}
+
+	err := validateConnectionPool(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateProductVersion(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateVersionConstraint(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateReleasedAfter(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateAvailability(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateFastCheck(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	return validatePinnedVersion(v.input.Source)
+}
+
+// validateFastCheck ensures that fast_check, which lists only the releases,
+// is not combined with the options which make a request per release.
+func validateFastCheck(source concourse.Source) error {
+	if source.FastCheck && source.ProductFilesFingerprint {
+		return fmt.Errorf("fast_check cannot be used with product_files_fingerprint")
+	}
+
+	return nil
+}
+
+// validateAvailability ensures that include_unreleased, which includes
+// releases only available to admins, is not combined with an availability
+// which excludes them.
+func validateAvailability(source concourse.Source) error {
+	if !source.IncludeUnreleased || source.Availability == "" || source.Availability == concourse.AvailabilityAdminsOnly {
+		return nil
+	}
+
+	return fmt.Errorf("%s cannot be used with %s: '%s'", "include_unreleased", "availability", source.Availability)
+}
+
+// validatePinnedVersion ensures that the options which select among several
+// versions are not combined with pinned_version, which emits only one.
+func validatePinnedVersion(source concourse.Source) error {
+	if source.PinnedVersion == "" {
+		return nil
+	}
+
+	conflicts := []struct {
+		key string
+		set bool
+	}{
+		{"channel", source.Channel != ""},
+		{"product_version", source.ProductVersion != ""},
+		{"version_constraint", source.VersionConstraint != ""},
+		{"released_after", source.ReleasedAfter != ""},
+		{"one_per_release_type", source.OnePerReleaseType},
+		{"sample", source.Sample != concourse.SampleNone},
+	}
+
+	for _, c := range conflicts {
+		if c.set {
+			return fmt.Errorf("%s cannot be used with %s", "pinned_version", c.key)
+		}
+	}
+
 	return nil
 }
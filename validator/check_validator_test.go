@@ -13,20 +13,34 @@ var _ = Describe("Check Validator", func() {
 		checkRequest concourse.CheckRequest
 		v            *validator.CheckValidator
 
-		apiToken    string
-		productSlug string
+		apiToken     string
+		productSlug  string
+		maxIdleConns int
+
+		pinnedVersion     string
+		productVersion    string
+		versionConstraint string
 	)
 
 	BeforeEach(func() {
 		apiToken = "some-api-token"
 		productSlug = "some-productSlug"
+		maxIdleConns = 0
+
+		pinnedVersion = ""
+		productVersion = ""
+		versionConstraint = ""
 	})
 
 	JustBeforeEach(func() {
 		checkRequest = concourse.CheckRequest{
 			Source: concourse.Source{
-				APIToken:    apiToken,
-				ProductSlug: productSlug,
+				APIToken:          apiToken,
+				ProductSlug:       productSlug,
+				MaxIdleConns:      maxIdleConns,
+				PinnedVersion:     pinnedVersion,
+				ProductVersion:    productVersion,
+				VersionConstraint: versionConstraint,
 			},
 		}
 		v = validator.NewCheckValidator(checkRequest)
@@ -67,4 +81,145 @@ var _ = Describe("Check Validator", func() {
 			Expect(err.Error()).To(MatchRegexp(".*product_slug.*provided"))
 		})
 	})
+
+	Context("when a negative connection pool size is provided", func() {
+		BeforeEach(func() {
+			maxIdleConns = -1
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(".*max_idle_conns.*negative"))
+		})
+	})
+
+	Context("when a product version is provided", func() {
+		BeforeEach(func() {
+			productVersion = `^1\.8\..*`
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when it is not a valid regex", func() {
+			BeforeEach(func() {
+				productVersion = "1.8.(*"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("product_version '1.8.(*' is not a valid regex: "))
+			})
+		})
+	})
+
+	Context("when a version constraint is provided", func() {
+		BeforeEach(func() {
+			versionConstraint = ">= 1.8, < 2.0"
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when it is not valid", func() {
+			BeforeEach(func() {
+				versionConstraint = ">= 1.8, <<"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("version_constraint '>= 1.8, <<' is not valid: invalid clause: '<<'"))
+			})
+		})
+	})
+
+	Context("when released_after is provided", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.ReleasedAfter = "2023-01-01"
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when it is not a date", func() {
+			JustBeforeEach(func() {
+				checkRequest.Source.ReleasedAfter = "01/01/2023"
+				v = validator.NewCheckValidator(checkRequest)
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("released_after '01/01/2023' must be a date, e.g. '2023-01-01'"))
+			})
+		})
+	})
+
+	Context("when include_unreleased is combined with an availability which excludes unreleased releases", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.IncludeUnreleased = true
+			checkRequest.Source.Availability = concourse.AvailabilityAllUsers
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("include_unreleased cannot be used with availability: 'all_users'"))
+		})
+	})
+
+	Context("when fast_check is combined with product_files_fingerprint", func() {
+		JustBeforeEach(func() {
+			checkRequest.Source.FastCheck = true
+			checkRequest.Source.ProductFilesFingerprint = true
+			v = validator.NewCheckValidator(checkRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("fast_check cannot be used with product_files_fingerprint"))
+		})
+	})
+
+	Context("when a pinned version is provided", func() {
+		BeforeEach(func() {
+			pinnedVersion = "1.2.3"
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when a product version is also provided", func() {
+			BeforeEach(func() {
+				productVersion = `1\.2\..*`
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("pinned_version cannot be used with product_version"))
+			})
+		})
+
+		Context("when a channel is also provided", func() {
+			JustBeforeEach(func() {
+				checkRequest.Source.Channel = "stable"
+				v = validator.NewCheckValidator(checkRequest)
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("pinned_version cannot be used with channel"))
+			})
+		})
+	})
 })
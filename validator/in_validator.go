@@ -2,8 +2,18 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/pivotal-cf/pivnet-resource/checksums"
+	"github.com/pivotal-cf/pivnet-resource/compression"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/globs"
+	"github.com/pivotal-cf/pivnet-resource/platform"
+	"github.com/pivotal-cf/pivnet-resource/semver"
 )
 
 type InValidator struct {
@@ -17,7 +27,7 @@ func NewInValidator(input concourse.InRequest) *InValidator {
 }
 
 func (v InValidator) Validate() error {
-	if v.input.Source.APIToken == "" {
+	if v.input.Source.APIToken == "" && v.input.Source.LocalSource == "" {
 		return fmt.Errorf("%s must be provided", "api_token")
 	}
 
@@ -25,8 +35,193 @@ func (v InValidator) Validate() error {
 		return fmt.Errorf("%s must be provided", "product_slug")
 	}
 
-	if v.input.Version.ProductVersion == "" {
-		return fmt.Errorf("%s must be provided", "product_version")
+	err := validateConnectionPool(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateProductVersion(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateVersionConstraint(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateReleasedAfter(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = checksums.Validate(v.input.Source.ChecksumAlgorithms)
+	if err != nil {
+		return err
+	}
+
+	_, err = globs.NewPatterns(v.input.Params.Globs)
+	if err != nil {
+		return fmt.Errorf("%s are invalid: %s", "globs", err.Error())
+	}
+
+	err = validateGlobSubdirs(v.input.Params.GlobSubdirs)
+	if err != nil {
+		return err
+	}
+
+	err = validateSparseDownload(v.input)
+	if err != nil {
+		return err
+	}
+
+	err = validateCompress(v.input.Params)
+	if err != nil {
+		return err
+	}
+
+	if v.input.Params.PlatformFilter != "" {
+		_, err = platform.Parse(v.input.Params.PlatformFilter)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = validateMirrorEndpoints(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = validateStemcell(v.input.Params.Stemcell)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func validateStemcell(stemcell *concourse.StemcellParams) error {
+	if stemcell == nil {
+		return nil
+	}
+
+	if stemcell.OS == "" {
+		return fmt.Errorf("%s must be provided", "stemcell.os")
+	}
+
+	if stemcell.MinVersion != "" {
+		_, err := semver.ParseConstraint(">= " + stemcell.MinVersion)
+		if err != nil {
+			return fmt.Errorf("%s '%s' is not valid: %s", "stemcell.min_version", stemcell.MinVersion, err.Error())
+		}
+	}
+
+	_, err := globs.NewPatterns(stemcell.Globs)
+	if err != nil {
+		return fmt.Errorf("%s are invalid: %s", "stemcell.globs", err.Error())
+	}
+
+	return nil
+}
+
+func validateMirrorEndpoints(source concourse.Source) error {
+	if len(source.MirrorEndpoints) == 0 {
+		return nil
+	}
+
+	if source.LocalSource != "" {
+		return fmt.Errorf("%s cannot be used with %s", "mirror_endpoints", "local_source")
+	}
+
+	for _, endpoint := range source.MirrorEndpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("mirror endpoint '%s' must be an http or https URL", endpoint)
+		}
+	}
+
+	return nil
+}
+
+func validateCompress(params concourse.InParams) error {
+	if params.Compress == "" {
+		return nil
+	}
+
+	err := compression.Validate(params.Compress)
+	if err != nil {
+		return err
+	}
+
+	// Zip members are written as directories, so cannot be compressed.
+	if params.Unpack || params.Bundle || len(params.ZipMembers) > 0 {
+		return fmt.Errorf("%s cannot be used with %s, %s or %s", "compress", "unpack", "bundle", "zip_members")
+	}
+
+	return nil
+}
+
+func validateSparseDownload(input concourse.InRequest) error {
+	params := input.Params
+
+	if params.HeadBytes < 0 {
+		return fmt.Errorf("%s must not be negative", "head_bytes")
+	}
+
+	if !params.SparseDownload() {
+		return nil
+	}
+
+	if params.HeadBytes > 0 && len(params.ZipMembers) > 0 {
+		return fmt.Errorf("only one of %s and %s may be provided", "head_bytes", "zip_members")
+	}
+
+	for _, pattern := range params.ZipMembers {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return fmt.Errorf("zip member pattern '%s' is invalid: %s", pattern, err.Error())
+		}
+	}
+
+	if params.Unpack || params.BOSHReleaseMetadata {
+		return fmt.Errorf(
+			"%s and %s require whole files so cannot be used with %s or %s",
+			"unpack",
+			"bosh_release_metadata",
+			"head_bytes",
+			"zip_members",
+		)
+	}
+
+	if input.Source.LocalSource != "" {
+		return fmt.Errorf("%s and %s cannot be used with %s", "head_bytes", "zip_members", "local_source")
+	}
+
+	return nil
+}
+
+func validateGlobSubdirs(globSubdirs map[string]string) error {
+	patterns := make([]string, 0, len(globSubdirs))
+	for glob := range globSubdirs {
+		patterns = append(patterns, glob)
+	}
+	sort.Strings(patterns)
+
+	for _, glob := range patterns {
+		if strings.HasPrefix(glob, globs.ExclusionPrefix) {
+			return fmt.Errorf("exclusion '%s' cannot have a subdirectory - use a list of globs instead", glob)
+		}
+
+		subdir := globSubdirs[glob]
+		cleaned := filepath.Clean(subdir)
+
+		if filepath.IsAbs(subdir) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+			return fmt.Errorf(
+				"subdirectory '%s' of glob '%s' must be a relative path within the working directory",
+				subdir,
+				glob,
+			)
+		}
 	}
 
 	return nil
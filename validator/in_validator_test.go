@@ -15,21 +15,57 @@ var _ = Describe("In Validator", func() {
 		apiToken    string
 		productSlug string
 		version     string
+		localSource string
+		algorithms  []string
+		globs       []string
+		globSubdirs map[string]string
+		headBytes   int64
+		zipMembers  []string
+		unpack      bool
+		compress    string
+		platform    string
+
+		mirrorEndpoints []string
+		stemcell        *concourse.StemcellParams
 	)
 
 	BeforeEach(func() {
 		apiToken = "some-api-token"
 		productSlug = "some-productSlug"
 		version = "some-product-version"
+		localSource = ""
+		algorithms = nil
+		globs = nil
+		globSubdirs = nil
+		headBytes = 0
+		zipMembers = nil
+		unpack = false
+		compress = ""
+		platform = ""
+		mirrorEndpoints = nil
+		stemcell = nil
 	})
 
 	JustBeforeEach(func() {
 		inRequest = concourse.InRequest{
 			Source: concourse.Source{
-				APIToken:    apiToken,
-				ProductSlug: productSlug,
+				APIToken:           apiToken,
+				ProductSlug:        productSlug,
+				LocalSource:        localSource,
+				ChecksumAlgorithms: algorithms,
+				MirrorEndpoints:    mirrorEndpoints,
+			},
+			Params: concourse.InParams{
+				Globs:       globs,
+				GlobSubdirs: globSubdirs,
+				HeadBytes:   headBytes,
+				ZipMembers:  zipMembers,
+				Unpack:      unpack,
+				Compress:    compress,
+
+				PlatformFilter: platform,
+				Stemcell:       stemcell,
 			},
-			Params: concourse.InParams{},
 			Version: concourse.Version{
 				ProductVersion: version,
 			},
@@ -56,6 +92,18 @@ var _ = Describe("In Validator", func() {
 		})
 	})
 
+	Context("when no API token is provided but a local source is", func() {
+		BeforeEach(func() {
+			apiToken = ""
+			localSource = "/some/local/source"
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Context("when UAA refresh token or legacy API token is provided", func() {
 		It("returns without error", func() {
 			err := v.Validate()
@@ -80,10 +128,280 @@ var _ = Describe("In Validator", func() {
 			version = ""
 		})
 
+		It("returns without error so that the latest release can be fetched", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when an unsupported checksum algorithm is provided", func() {
+		BeforeEach(func() {
+			algorithms = []string{"sha512", "some-algorithm"}
+		})
+
 		It("returns an error", func() {
 			err := v.Validate()
 			Expect(err).NotTo(BeNil())
-			Expect(err.Error()).To(MatchRegexp(".*product_version.*provided"))
+			Expect(err.Error()).To(MatchRegexp(".*some-algorithm.*must be one of"))
+		})
+	})
+
+	Context("when globs with exclusions are provided", func() {
+		BeforeEach(func() {
+			globs = []string{"*.pivotal", "!*-beta.pivotal"}
+		})
+
+		It("returns without error", func() {
+			Expect(v.Validate()).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when a glob is malformed", func() {
+		BeforeEach(func() {
+			globs = []string{"*.pivotal", "!["}
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("globs are invalid: syntax error in pattern"))
+		})
+	})
+
+	Context("when the subdirectory of a glob is within the working directory", func() {
+		BeforeEach(func() {
+			globSubdirs = map[string]string{"*.pivotal": "tiles/nested"}
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when the subdirectory of a glob is outside the working directory", func() {
+		BeforeEach(func() {
+			globSubdirs = map[string]string{"*.pivotal": "tiles/../../elsewhere"}
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("subdirectory 'tiles/../../elsewhere' of glob '*.pivotal' must be a relative path within the working directory"))
+		})
+	})
+
+	Context("when an exclusion is given a subdirectory", func() {
+		BeforeEach(func() {
+			globSubdirs = map[string]string{"*.pivotal": "tiles", "!*-beta.pivotal": "beta"}
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("exclusion '!*-beta.pivotal' cannot have a subdirectory - use a list of globs instead"))
+		})
+	})
+
+	Context("when the subdirectory of a glob is absolute", func() {
+		BeforeEach(func() {
+			globSubdirs = map[string]string{"*.pivotal": "/tmp"}
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when zip members are provided", func() {
+		BeforeEach(func() {
+			zipMembers = []string{"metadata/*.yml"}
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when head bytes are also provided", func() {
+			BeforeEach(func() {
+				headBytes = 1024
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("only one of head_bytes and zip_members may be provided"))
+			})
+		})
+
+		Context("when a zip member pattern is invalid", func() {
+			BeforeEach(func() {
+				zipMembers = []string{"metadata/[*.yml"}
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("zip member pattern 'metadata/[*.yml' is invalid"))
+			})
+		})
+
+		Context("when unpack is also provided", func() {
+			BeforeEach(func() {
+				unpack = true
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("require whole files"))
+			})
+		})
+
+		Context("when a local source is provided", func() {
+			BeforeEach(func() {
+				localSource = "/some/local/source"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("head_bytes and zip_members cannot be used with local_source"))
+			})
+		})
+	})
+
+	Context("when head bytes are negative", func() {
+		BeforeEach(func() {
+			headBytes = -1
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("head_bytes must not be negative"))
+		})
+	})
+
+	Context("when compress is provided", func() {
+		BeforeEach(func() {
+			compress = "zstd"
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the format is not supported", func() {
+			BeforeEach(func() {
+				compress = "bzip2"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("compress format 'bzip2' must be one of: ['gzip', 'zstd']"))
+			})
+		})
+
+		Context("when unpack is also provided", func() {
+			BeforeEach(func() {
+				unpack = true
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("compress cannot be used with unpack, bundle or zip_members"))
+			})
+		})
+	})
+
+	Context("when platform_filter is provided", func() {
+		BeforeEach(func() {
+			platform = "linux/amd64"
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the platform is not known", func() {
+			BeforeEach(func() {
+				platform = "plan9"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("platform 'plan9' must have an os of: ['linux', 'windows', 'darwin']"))
+			})
+		})
+	})
+
+	Context("when mirror endpoints are provided", func() {
+		BeforeEach(func() {
+			mirrorEndpoints = []string{"https://pivnet-mirror.example.com"}
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when an endpoint is not a URL", func() {
+			BeforeEach(func() {
+				mirrorEndpoints = []string{"pivnet-mirror.example.com"}
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("mirror endpoint 'pivnet-mirror.example.com' must be an http or https URL"))
+			})
+		})
+
+		Context("when a local source is provided", func() {
+			BeforeEach(func() {
+				localSource = "/some/local/source"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("mirror_endpoints cannot be used with local_source"))
+			})
+		})
+	})
+
+	Context("when a stemcell is provided", func() {
+		BeforeEach(func() {
+			stemcell = &concourse.StemcellParams{
+				OS:         "ubuntu-jammy",
+				MinVersion: "1.200",
+				Globs:      []string{"*vsphere*"},
+			}
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when no os is provided", func() {
+			BeforeEach(func() {
+				stemcell.OS = ""
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("stemcell.os must be provided"))
+			})
+		})
+
+		Context("when the minimum version is not a version", func() {
+			BeforeEach(func() {
+				stemcell.MinVersion = "latest"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("stemcell.min_version 'latest' is not valid"))
+			})
 		})
 	})
 })
@@ -2,8 +2,14 @@ package validator
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/pivotal-cf/pivnet-resource/checksums"
 	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/globs"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/s3"
 )
 
 type OutValidator struct {
@@ -25,5 +31,114 @@ func (v OutValidator) Validate() error {
 		return fmt.Errorf("%s must be provided", "product_slug")
 	}
 
+	err := validateConnectionPool(v.input.Source)
+	if err != nil {
+		return err
+	}
+
+	err = checksums.Validate(v.input.Source.ChecksumAlgorithms)
+	if err != nil {
+		return err
+	}
+
+	err = metadata.ValidateRequiredFields(v.input.Params.RequiredFields)
+	if err != nil {
+		return err
+	}
+
+	storageClass := v.input.Params.StorageClass
+	if storageClass != "" && !containsString(s3.StorageClasses, storageClass) {
+		return fmt.Errorf(
+			"%s must be one of: ['%s']",
+			"storage_class",
+			strings.Join(s3.StorageClasses, "', '"),
+		)
+	}
+
+	if v.input.Params.ChunkManifestThreshold < 0 {
+		return fmt.Errorf("%s must not be negative", "chunk_manifest_threshold")
+	}
+
+	if v.input.Params.ChunkManifestChunkSize < 0 {
+		return fmt.Errorf("%s must not be negative", "chunk_manifest_chunk_size")
+	}
+
+	if v.input.Params.S3RetryBudget < 0 {
+		return fmt.Errorf("%s must not be negative", "s3_retry_budget")
+	}
+
+	if v.input.Source.GPGPassphrase != "" && v.input.Source.GPGPrivateKey == "" {
+		return fmt.Errorf("%s must be provided with %s", "gpg_private_key", "gpg_passphrase")
+	}
+
+	if v.input.Params.ExpectedFileCount < 0 {
+		return fmt.Errorf("%s must not be negative", "expected_file_count")
+	}
+
+	if v.input.Params.PublishLockExpiry < 0 {
+		return fmt.Errorf("%s must not be negative", "publish_lock_expiry")
+	}
+
+	if v.input.Params.PublishLockWait < 0 {
+		return fmt.Errorf("%s must not be negative", "publish_lock_wait")
+	}
+
+	if len(v.input.Params.DescriptionFiles) > 0 && v.input.Params.DescriptionTemplate == "" {
+		return fmt.Errorf("%s must be provided with %s", "description_template", "description_files")
+	}
+
+	for _, exclusion := range v.input.Params.FileGlobExclusions {
+		_, err := globs.NewPatterns([]string{globs.ExclusionPrefix + exclusion})
+		if err != nil {
+			return fmt.Errorf("%s are invalid: %s", "file_glob_exclusions", err.Error())
+		}
+	}
+
+	// The version is used in the S3 key of the publish lock, so affixes must
+	// not introduce path separators.
+	affixes := []struct {
+		key   string
+		value string
+	}{
+		{"version_prefix", v.input.Params.VersionPrefix},
+		{"version_suffix", v.input.Params.VersionSuffix},
+	}
+
+	for _, a := range affixes {
+		if strings.ContainsAny(a.value, " \t\n/") {
+			return fmt.Errorf("%s must not contain whitespace or '/'", a.key)
+		}
+	}
+
+	return validateS3Targets(v.input.Source.S3Targets)
+}
+
+func validateS3Targets(targets map[string]concourse.S3Target) error {
+	var names []string
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := targets[name]
+
+		required := []struct {
+			key   string
+			value string
+		}{
+			{"bucket", target.Bucket},
+			{"region", target.Region},
+			{"access_key_id", target.AccessKeyID},
+			{"secret_access_key", target.SecretAccessKey},
+		}
+
+		for _, r := range required {
+			if r.value == "" {
+				return fmt.Errorf("s3_targets.%s.%s must be provided", name, r.key)
+			}
+		}
+	}
+
 	return nil
 }
@@ -10,9 +10,19 @@ import (
 var _ = Describe("Out Validator", func() {
 	var (
 
-		apiToken         string
-		productSlug      string
-		fileGlob         string
+		apiToken          string
+		productSlug       string
+		fileGlob          string
+		storageClass      string
+		chunkThreshold    int64
+		s3RetryBudget     int
+		expectedFileCount int
+		publishLockExpiry int
+		publishLockWait   int
+		versionSuffix     string
+		exclusions        []string
+		requiredFields    []string
+		s3Targets         map[string]concourse.S3Target
 
 		outRequest concourse.OutRequest
 		v          *validator.OutValidator
@@ -23,6 +33,16 @@ var _ = Describe("Out Validator", func() {
 		productSlug = "some-product"
 
 		fileGlob = ""
+		storageClass = ""
+		chunkThreshold = 0
+		s3RetryBudget = 0
+		expectedFileCount = 0
+		publishLockExpiry = 0
+		publishLockWait = 0
+		versionSuffix = ""
+		exclusions = nil
+		requiredFields = nil
+		s3Targets = nil
 	})
 
 	JustBeforeEach(func() {
@@ -30,9 +50,19 @@ var _ = Describe("Out Validator", func() {
 			Source: concourse.Source{
 				APIToken:        apiToken,
 				ProductSlug:     productSlug,
+				S3Targets:       s3Targets,
 			},
 			Params: concourse.OutParams{
 				FileGlob:       fileGlob,
+				StorageClass:   storageClass,
+				ChunkManifestThreshold: chunkThreshold,
+				S3RetryBudget:          s3RetryBudget,
+				ExpectedFileCount:      expectedFileCount,
+				PublishLockExpiry:      publishLockExpiry,
+				PublishLockWait:        publishLockWait,
+				VersionSuffix:          versionSuffix,
+				FileGlobExclusions:     exclusions,
+				RequiredFields:         requiredFields,
 			},
 		}
 
@@ -86,4 +116,205 @@ var _ = Describe("Out Validator", func() {
 		})
 	})
 
+	Context("when a valid storage class is provided", func() {
+		BeforeEach(func() {
+			storageClass = "STANDARD_IA"
+		})
+
+		It("returns without error", func() {
+			err := v.Validate()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when an invalid storage class is provided", func() {
+		BeforeEach(func() {
+			storageClass = "GLACIER"
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(MatchRegexp(".*storage_class.*one of"))
+		})
+	})
+
+	Context("when a negative chunk manifest threshold is provided", func() {
+		BeforeEach(func() {
+			chunkThreshold = -1
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(MatchRegexp(".*chunk_manifest_threshold.*not be negative"))
+		})
+	})
+
+	Context("when a negative s3 retry budget is provided", func() {
+		BeforeEach(func() {
+			s3RetryBudget = -1
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+
+			Expect(err.Error()).To(MatchRegexp(".*s3_retry_budget.*not be negative"))
+		})
+	})
+
+	Context("when description files are provided without a description template", func() {
+		JustBeforeEach(func() {
+			outRequest.Params.DescriptionFiles = map[string]string{"changelog": "repo/CHANGELOG.md"}
+			v = validator.NewOutValidator(outRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("description_template must be provided with description_files"))
+		})
+	})
+
+	Context("when a gpg passphrase is provided without a private key", func() {
+		JustBeforeEach(func() {
+			outRequest.Source.GPGPassphrase = "some-passphrase"
+			v = validator.NewOutValidator(outRequest)
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("gpg_private_key must be provided with gpg_passphrase"))
+		})
+	})
+
+	Context("when a negative expected file count is provided", func() {
+		BeforeEach(func() {
+			expectedFileCount = -1
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("expected_file_count must not be negative"))
+		})
+	})
+
+	Context("when required fields are provided", func() {
+		BeforeEach(func() {
+			requiredFields = []string{"release_notes_url", "doc_file"}
+		})
+
+		It("returns without error", func() {
+			Expect(v.Validate()).NotTo(HaveOccurred())
+		})
+
+		Context("when a field cannot be required", func() {
+			BeforeEach(func() {
+				requiredFields = []string{"release_notes"}
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError(ContainSubstring("required field 'release_notes' must be one of")))
+			})
+		})
+	})
+
+	Context("when a negative publish lock expiry is provided", func() {
+		BeforeEach(func() {
+			publishLockExpiry = -1
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("publish_lock_expiry must not be negative"))
+		})
+	})
+
+	Context("when a negative publish lock wait is provided", func() {
+		BeforeEach(func() {
+			publishLockWait = -1
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("publish_lock_wait must not be negative"))
+		})
+	})
+
+	Context("when a file glob exclusion is malformed", func() {
+		BeforeEach(func() {
+			exclusions = []string{"*.sha256", "["}
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(MatchError("file_glob_exclusions are invalid: syntax error in pattern"))
+		})
+	})
+
+	Context("when a version suffix is provided", func() {
+		BeforeEach(func() {
+			versionSuffix = "-LTS"
+		})
+
+		It("returns without error", func() {
+			Expect(v.Validate()).NotTo(HaveOccurred())
+		})
+
+		Context("when it contains whitespace", func() {
+			BeforeEach(func() {
+				versionSuffix = " LTS"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("version_suffix must not contain whitespace or '/'"))
+			})
+		})
+
+		Context("when it contains a slash", func() {
+			BeforeEach(func() {
+				versionSuffix = "/LTS"
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("version_suffix must not contain whitespace or '/'"))
+			})
+		})
+	})
+
+	Context("when an s3 target is provided", func() {
+		BeforeEach(func() {
+			s3Targets = map[string]concourse.S3Target{
+				"docs": {
+					Bucket:          "some-bucket",
+					Region:          "some-region",
+					AccessKeyID:     "some-access-key-id",
+					SecretAccessKey: "some-secret-access-key",
+				},
+			}
+		})
+
+		It("returns without error", func() {
+			Expect(v.Validate()).NotTo(HaveOccurred())
+		})
+
+		Context("when the s3 target has no credentials", func() {
+			BeforeEach(func() {
+				s3Targets["docs"] = concourse.S3Target{
+					Bucket: "some-bucket",
+					Region: "some-region",
+				}
+			})
+
+			It("returns an error", func() {
+				err := v.Validate()
+				Expect(err).To(MatchError("s3_targets.docs.access_key_id must be provided"))
+			})
+		})
+	})
 })
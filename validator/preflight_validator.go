@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+)
+
+type semverConverter interface {
+	ToValidSemver(string) (semver.Version, error)
+}
+
+// PreflightValidator checks the contents of the sources directory against
+// the metadata so that a put fails before anything is created remotely.
+type PreflightValidator struct {
+	input           concourse.OutRequest
+	m               metadata.Metadata
+	sourcesDir      string
+	semverConverter semverConverter
+}
+
+func NewPreflightValidator(
+	input concourse.OutRequest,
+	m metadata.Metadata,
+	sourcesDir string,
+	semverConverter semverConverter,
+) *PreflightValidator {
+	return &PreflightValidator{
+		input:           input,
+		m:               m,
+		sourcesDir:      sourcesDir,
+		semverConverter: semverConverter,
+	}
+}
+
+// Validate returns an error describing every problem found, rather than
+// only the first, so that they can all be fixed at once.
+func (v PreflightValidator) Validate() error {
+	var problems []string
+
+	if v.m.Release == nil {
+		problems = append(problems, fmt.Sprintf("missing required value %q", "release"))
+	} else {
+		problems = append(problems, v.validateRelease()...)
+	}
+
+	for _, pf := range v.m.ProductFiles {
+		problem := v.validateProductFile(pf)
+		if problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	for _, fg := range v.m.FileGroups {
+		if fg.S3Target == "" {
+			continue
+		}
+
+		if _, ok := v.input.Source.S3Targets[fg.S3Target]; !ok {
+			problems = append(problems, fmt.Sprintf(
+				"s3 target '%s' of file group '%s' is not configured in source.s3_targets",
+				fg.S3Target,
+				fg.Name,
+			))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf(
+			"pre-flight validation failed:\n  - %s",
+			strings.Join(problems, "\n  - "),
+		)
+	}
+
+	return nil
+}
+
+func (v PreflightValidator) validateRelease() []string {
+	var problems []string
+
+	version := v.m.Release.Version
+	if version == "" {
+		problems = append(problems, fmt.Sprintf("missing required value %q", "version"))
+	} else if strings.Contains(version, "/") || strings.Contains(version, "..") {
+		// The version is used in the S3 keys of the publish lock and upload
+		// state, which it must not escape.
+		problems = append(problems, fmt.Sprintf("version '%s' must not contain '/' or '..'", version))
+	} else if v.input.Source.SortBy == concourse.SortBySemver {
+		_, err := v.semverConverter.ToValidSemver(version)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf(
+				"version '%s' could not be parsed as semver: %s",
+				version,
+				err.Error(),
+			))
+		}
+	}
+
+	if v.m.Release.ReleaseType == "" {
+		problems = append(problems, fmt.Sprintf("missing required value %q", "release_type"))
+	}
+
+	if v.m.Release.EULASlug == "" {
+		problems = append(problems, fmt.Sprintf("missing required value %q", "eula_slug"))
+	}
+
+	return problems
+}
+
+func (v PreflightValidator) validateProductFile(pf metadata.ProductFile) string {
+	if pf.File == "" {
+		return "empty value for file"
+	}
+
+	matches, err := filepath.Glob(filepath.Join(v.sourcesDir, pf.File))
+	if err != nil {
+		return fmt.Sprintf("product file '%s' is not a valid pattern: %s", pf.File, err.Error())
+	}
+
+	switch len(matches) {
+	case 0:
+		return fmt.Sprintf("product file '%s' does not exist in sources dir", pf.File)
+	case 1:
+	default:
+		return fmt.Sprintf(
+			"product file '%s' matches more than one file: %v",
+			pf.File,
+			matches,
+		)
+	}
+
+	info, err := os.Stat(matches[0])
+	if err != nil {
+		return fmt.Sprintf("product file '%s' could not be read: %s", pf.File, err.Error())
+	}
+
+	if info.IsDir() {
+		return fmt.Sprintf("product file '%s' is a directory", pf.File)
+	}
+
+	return ""
+}
@@ -0,0 +1,192 @@
+package validator_test
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/go-pivnet/logshim"
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/metadata"
+	"github.com/pivotal-cf/pivnet-resource/semver"
+	"github.com/pivotal-cf/pivnet-resource/validator"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Preflight Validator", func() {
+	var (
+		sourcesDir string
+		sortBy     concourse.SortBy
+		m          metadata.Metadata
+
+		v *validator.PreflightValidator
+	)
+
+	BeforeEach(func() {
+		var err error
+		sourcesDir, err = ioutil.TempDir("", "pivnet-resource-preflight")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = os.MkdirAll(filepath.Join(sourcesDir, "files"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ioutil.WriteFile(filepath.Join(sourcesDir, "files", "file-1.zip"), []byte("some-content"), os.ModePerm)
+		Expect(err).NotTo(HaveOccurred())
+
+		sortBy = concourse.SortByNone
+
+		m = metadata.Metadata{
+			Release: &metadata.Release{
+				Version:     "1.2.3",
+				ReleaseType: "All-In-One",
+				EULASlug:    "some-eula",
+			},
+			ProductFiles: []metadata.ProductFile{
+				{File: "files/file-1.zip"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(sourcesDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		logger := log.New(GinkgoWriter, "", log.LstdFlags)
+		ls := logshim.NewLogShim(logger, logger, true)
+
+		input := concourse.OutRequest{
+			Source: concourse.Source{
+				SortBy: sortBy,
+			},
+		}
+
+		v = validator.NewPreflightValidator(input, m, sourcesDir, semver.NewSemverConverter(ls))
+	})
+
+	It("returns without error", func() {
+		Expect(v.Validate()).NotTo(HaveOccurred())
+	})
+
+	Context("when a product file does not exist", func() {
+		BeforeEach(func() {
+			m.ProductFiles = append(m.ProductFiles, metadata.ProductFile{File: "files/missing.zip"})
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'files/missing.zip' does not exist"))
+		})
+	})
+
+	Context("when a product file matches more than one file", func() {
+		BeforeEach(func() {
+			err := ioutil.WriteFile(filepath.Join(sourcesDir, "files", "file-2.zip"), nil, os.ModePerm)
+			Expect(err).NotTo(HaveOccurred())
+
+			m.ProductFiles = []metadata.ProductFile{{File: "files/file-*.zip"}}
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("matches more than one file"))
+		})
+	})
+
+	Context("when a product file is a directory", func() {
+		BeforeEach(func() {
+			m.ProductFiles = []metadata.ProductFile{{File: "files"}}
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'files' is a directory"))
+		})
+	})
+
+	Context("when sorting by semver and the version is not semver", func() {
+		BeforeEach(func() {
+			sortBy = concourse.SortBySemver
+			m.Release.Version = "not-semver"
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("could not be parsed as semver"))
+		})
+	})
+
+	Context("when the version contains a path separator", func() {
+		BeforeEach(func() {
+			m.Release.Version = "1.2.3/../../other"
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("version '1.2.3/../../other' must not contain '/' or '..'"))
+		})
+	})
+
+	Context("when the version contains '..'", func() {
+		BeforeEach(func() {
+			m.Release.Version = "1.2.3..4"
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must not contain '/' or '..'"))
+		})
+	})
+
+	Context("when a file group is routed to an s3 target which is not configured", func() {
+		BeforeEach(func() {
+			m.FileGroups = []metadata.FileGroup{
+				{Name: "Documentation", S3Target: "docs"},
+			}
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("s3 target 'docs' of file group 'Documentation' is not configured"))
+		})
+	})
+
+	Context("when the release is missing", func() {
+		BeforeEach(func() {
+			m.Release = nil
+		})
+
+		It("returns an error", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`"release"`))
+		})
+	})
+
+	Context("when several values are invalid", func() {
+		BeforeEach(func() {
+			m.Release.ReleaseType = ""
+			m.Release.EULASlug = ""
+			m.ProductFiles = append(m.ProductFiles, metadata.ProductFile{File: "files/missing.zip"})
+		})
+
+		It("reports all of them", func() {
+			err := v.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`"release_type"`))
+			Expect(err.Error()).To(ContainSubstring(`"eula_slug"`))
+			Expect(err.Error()).To(ContainSubstring("'files/missing.zip' does not exist"))
+		})
+	})
+})
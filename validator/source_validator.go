@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pivotal-cf/pivnet-resource/concourse"
+	"github.com/pivotal-cf/pivnet-resource/semver"
+)
+
+func validateConnectionPool(source concourse.Source) error {
+	if source.MaxIdleConns < 0 {
+		return fmt.Errorf("%s must not be negative", "max_idle_conns")
+	}
+
+	if source.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("%s must not be negative", "max_idle_conns_per_host")
+	}
+
+	if source.MaxConnsPerHost < 0 {
+		return fmt.Errorf("%s must not be negative", "max_conns_per_host")
+	}
+
+	if source.TLSHandshakeTimeout < 0 {
+		return fmt.Errorf("%s must not be negative", "tls_handshake_timeout")
+	}
+
+	return nil
+}
+
+// validateProductVersion ensures that product_version, which check matches
+// against the version of each release, is a valid regex.
+func validateProductVersion(source concourse.Source) error {
+	_, err := regexp.Compile(source.ProductVersion)
+	if err != nil {
+		return fmt.Errorf("%s '%s' is not a valid regex: %s", "product_version", source.ProductVersion, err.Error())
+	}
+
+	return nil
+}
+
+// validateVersionConstraint ensures that version_constraint, which check
+// requires the version of each release to satisfy, can be parsed.
+func validateVersionConstraint(source concourse.Source) error {
+	if source.VersionConstraint == "" {
+		return nil
+	}
+
+	_, err := semver.ParseConstraint(source.VersionConstraint)
+	if err != nil {
+		return fmt.Errorf("%s '%s' is not valid: %s", "version_constraint", source.VersionConstraint, err.Error())
+	}
+
+	return nil
+}
+
+// validateReleasedAfter ensures that released_after, before which check
+// ignores releases, is a date.
+func validateReleasedAfter(source concourse.Source) error {
+	if source.ReleasedAfter == "" {
+		return nil
+	}
+
+	_, err := time.Parse(concourse.ReleaseDateLayout, source.ReleasedAfter)
+	if err != nil {
+		return fmt.Errorf("%s '%s' must be a date, e.g. '2023-01-01'", "released_after", source.ReleasedAfter)
+	}
+
+	return nil
+}
+
+func containsString(strings []string, str string) bool {
+	for _, s := range strings {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
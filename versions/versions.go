@@ -9,6 +9,12 @@ const (
 	fingerprintDelimiter = "#"
 )
 
+// Since returns the versions, newest first, down to and including the
+// provided version, i.e. every version released since it. If no version is
+// exactly the provided one because its release was re-published with a new
+// fingerprint, the versions since the one with the same version and another
+// fingerprint are returned. Failing that, only the newest version is
+// returned.
 func Since(versions []string, since string) ([]string, error) {
 	for i, v := range versions {
 		if v == since {
@@ -16,6 +22,15 @@ func Since(versions []string, since string) ([]string, error) {
 		}
 	}
 
+	sinceVersion, sinceFingerprint := SplitVersion(since)
+	if sinceFingerprint != "" {
+		for i, v := range versions {
+			if version, _ := SplitVersion(v); version == sinceVersion {
+				return versions[:i+1], nil
+			}
+		}
+	}
+
 	return versions[:1], nil
 }
 
@@ -43,6 +58,20 @@ func CombineVersionAndFingerprint(version string, fingerprint string) (string, e
 	return combineVersionAndFingerprint(version, fingerprint), nil
 }
 
+// SplitVersion returns the release version and fingerprint of a version
+// emitted by check. Fingerprints never contain the delimiter, so the version
+// is split at its last occurrence, which keeps release versions containing
+// the delimiter intact. A version without a fingerprint, e.g. a
+// pinned_version, is returned with an empty fingerprint.
+func SplitVersion(versionWithFingerprint string) (string, string) {
+	i := strings.LastIndex(versionWithFingerprint, fingerprintDelimiter)
+	if i < 0 {
+		return versionWithFingerprint, ""
+	}
+
+	return versionWithFingerprint[:i], versionWithFingerprint[i+len(fingerprintDelimiter):]
+}
+
 func combineVersionAndFingerprint(version string, fingerprint string) string {
 	return fmt.Sprintf("%s%s%s", version, fingerprintDelimiter, fingerprint)
 }
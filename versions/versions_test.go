@@ -50,6 +50,19 @@ var _ = Describe("Versions", func() {
 			})
 		})
 
+		Context("when the provided version has another fingerprint", func() {
+			BeforeEach(func() {
+				allVersions = []string{"1.3.2#ghi", "1.2.4#def", "1.2.3#abc"}
+				version = "1.2.4#old"
+			})
+
+			It("returns new versions since the same version", func() {
+				versions, _ := versions.Since(allVersions, version)
+
+				Expect(versions).To(Equal([]string{"1.3.2#ghi", "1.2.4#def"}))
+			})
+		})
+
 		Context("When the version is not present", func() {
 			BeforeEach(func() {
 				allVersions = []string{"1.2.3#abc", "1.3.2#def"}